@@ -8,6 +8,7 @@ import (
 	"github.com/Engls/forum-project2/auth_service/internal/entity"
 	"github.com/Engls/forum-project2/auth_service/internal/repository"
 	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/Engls/forum-project2/auth_service/internal/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
@@ -16,9 +17,21 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
+	"time"
 )
 
+// capturingNotifier запоминает последний отправленный токен сброса пароля
+type capturingNotifier struct {
+	resetToken string
+}
+
+func (n *capturingNotifier) SendPasswordReset(user entity.User, token string, expiresAt time.Time) error {
+	n.resetToken = token
+	return nil
+}
+
 func setupTestDB(t *testing.T) *sqlx.DB {
 	tmpfile, err := os.CreateTemp("", "testdb-*.db")
 	if err != nil {
@@ -36,14 +49,86 @@ func setupTestDB(t *testing.T) *sqlx.DB {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			username TEXT NOT NULL UNIQUE,
 			password TEXT NOT NULL,
-			role TEXT NOT NULL
+			role TEXT NOT NULL,
+			display_name TEXT NOT NULL DEFAULT '',
+			bio TEXT NOT NULL DEFAULT '',
+			avatar_url TEXT NOT NULL DEFAULT '',
+			signature TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE posts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			author_id INTEGER,
+			title TEXT,
+			content TEXT
+		);
+		CREATE TABLE comments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			author_id INTEGER,
+			post_id INTEGER,
+			content TEXT
 		);
 		CREATE TABLE tokens (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			user_id INTEGER NOT NULL,
 			token TEXT NOT NULL,
+			expires_at DATETIME,
+			revoked_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);
+		CREATE TABLE refresh_tokens (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			family_id TEXT NOT NULL,
+			user_agent TEXT,
+			ip_address TEXT,
+			expires_at DATETIME NOT NULL,
+			revoked_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (user_id) REFERENCES users(id)
 		);
+		CREATE TABLE roles (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			description TEXT,
+			priority INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE permissions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			description TEXT
+		);
+		CREATE TABLE role_permissions (
+			role_id INTEGER NOT NULL,
+			permission_id INTEGER NOT NULL,
+			PRIMARY KEY (role_id, permission_id)
+		);
+		CREATE TABLE user_roles (
+			user_id INTEGER NOT NULL,
+			role_id INTEGER NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, role_id)
+		);
+		CREATE TABLE login_failures (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL COLLATE NOCASE,
+			ip_address TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		);
+		CREATE TABLE password_reset_tokens (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			expires_at DATETIME NOT NULL,
+			used_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);
+		INSERT INTO roles (name, priority) VALUES ('user', 10), ('admin', 100);
+		INSERT INTO permissions (name) VALUES ('post.create'), ('role.manage'), ('user.unlock');
+		INSERT INTO role_permissions (role_id, permission_id) VALUES (1, 1), (2, 1), (2, 2), (2, 3);
 	`)
 	if err != nil {
 		t.Fatalf("Failed to create tables: %s", err)
@@ -61,20 +146,32 @@ func TestAuthService_Integration(t *testing.T) {
 
 	authRepo := repository.NewAuthRepository(db, logger)
 	jwtUtil := EnglsJwt.NewJWTUtil("secret")
-	authUsecase := usecase.NewAuthUsecase(authRepo, jwtUtil, logger)
+	notifier := &capturingNotifier{}
+	authUsecase := usecase.NewAuthUsecase(authRepo, jwtUtil, usecase.TokenConfig{}, validation.PasswordPolicy{}, usecase.LoginThrottleConfig{}, notifier, logger)
 	authHandler := http2.NewAuthHandler(authUsecase, jwtUtil, logger)
 
 	r := gin.Default()
+	r.SetTrustedProxies(nil)
 	r.POST("/register", authHandler.Register)
 	r.POST("/login", authHandler.Login)
+	r.POST("/refresh", authHandler.Refresh)
+	r.POST("/logout", authHandler.Logout)
+	r.GET("/me", authHandler.GetMe)
+	r.PATCH("/me", authHandler.UpdateMe)
+	r.POST("/me/password", authHandler.ChangePassword)
+	r.POST("/password-reset/request", authHandler.RequestPasswordReset)
+	r.POST("/password-reset/confirm", authHandler.ConfirmPasswordReset)
+	r.GET("/users/:id", authHandler.GetUser)
+	r.GET("/users/:id/roles", authHandler.GetUserRoles)
+	r.POST("/users/:id/roles", authHandler.GrantRole)
+	r.DELETE("/users/:id/roles/:role", authHandler.RevokeRole)
+	r.POST("/users/:id/unlock", authHandler.UnlockUser)
+
+	var accessToken, refreshToken, rotatedToken string
 
 	t.Run("RegisterUser", func(t *testing.T) {
-		reqBody := entity.RegisterRequest{
-			Username: "testuser",
-			Password: "password",
-			Role:     "user",
-		}
-		reqBodyBytes, _ := json.Marshal(reqBody)
+		// Роль из запроса игнорируется, новый пользователь всегда получает роль user
+		reqBodyBytes := []byte(`{"username":"testuser","password":"password","role":"admin"}`)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(reqBodyBytes))
@@ -85,6 +182,31 @@ func TestAuthService_Integration(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "User registered successfully")
 	})
 
+	t.Run("RegisterValidation", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(`{"username":"a b","password":"123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp entity.ValidationErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		fields := map[string]bool{}
+		for _, f := range resp.Fields {
+			fields[f.Field] = true
+		}
+		assert.True(t, fields["username"])
+		assert.True(t, fields["password"])
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(`{"username":"TestUser","password":"password"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"username"`)
+	})
+
 	t.Run("LoginUser", func(t *testing.T) {
 		reqBody := entity.LoginRequest{
 			Username: "testuser",
@@ -102,5 +224,228 @@ func TestAuthService_Integration(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "role")
 		assert.Contains(t, w.Body.String(), "username")
 		assert.Contains(t, w.Body.String(), "userID")
+
+		var loginResp entity.LoginResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &loginResp))
+		assert.Equal(t, "user", loginResp.Role)
+		accessToken = loginResp.Token
+		refreshToken = loginResp.RefreshToken
+		assert.NotEmpty(t, refreshToken)
+	})
+
+	t.Run("RefreshRotatesToken", func(t *testing.T) {
+		reqBodyBytes, _ := json.Marshal(entity.RefreshRequest{RefreshToken: refreshToken})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/refresh", bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var refreshResp entity.RefreshResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshResp))
+		assert.NotEmpty(t, refreshResp.Token)
+		assert.NotEqual(t, refreshToken, refreshResp.RefreshToken)
+		rotatedToken = refreshResp.RefreshToken
+	})
+
+	t.Run("RefreshReuseRevokesFamily", func(t *testing.T) {
+		for _, token := range []string{refreshToken, rotatedToken} {
+			reqBodyBytes, _ := json.Marshal(entity.RefreshRequest{RefreshToken: token})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/refresh", bytes.NewBuffer(reqBodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		}
+	})
+	t.Run("LogoutRevokesToken", func(t *testing.T) {
+		for _, expected := range []int{http.StatusOK, http.StatusUnauthorized} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/logout", nil)
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, expected, w.Code)
+		}
+	})
+
+	login := func(t *testing.T, username string) string {
+		reqBodyBytes, _ := json.Marshal(entity.LoginRequest{Username: username, Password: "password"})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var loginResp entity.LoginResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &loginResp))
+		return loginResp.Token
+	}
+
+	var secondToken string
+
+	t.Run("GrantRoleRequiresPermission", func(t *testing.T) {
+		assert.NoError(t, authUsecase.Register("seconduser", "password"))
+		secondToken = login(t, "seconduser")
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/users/2/roles", bytes.NewBufferString(`{"role":"admin"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("AdminGrantsAndRevokesRoles", func(t *testing.T) {
+		// Первого администратора назначают напрямую в базе.
+		// Права читаются из базы при каждом запросе, повторный логин не нужен.
+		_, err := db.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (2, 2)")
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/users/1/roles", bytes.NewBufferString(`{"role":"admin"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"userID":1,"roles":["admin","user"]}`, w.Body.String())
+		role, err := authUsecase.GetUserRole("testuser")
+		assert.NoError(t, err)
+		assert.Equal(t, "admin", role)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodDelete, "/users/1/roles/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"userID":1,"roles":["user"]}`, w.Body.String())
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodDelete, "/users/2/roles/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("LoginThrottlingAndUnlock", func(t *testing.T) {
+		badLogin, _ := json.Marshal(entity.LoginRequest{Username: "TESTUSER", Password: "wrong"})
+		// Вторая попытка попадает под ограничение, но ответ тот же, что и на неверный пароль
+		for i := 0; i < 2; i++ {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(badLogin))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Empty(t, w.Header().Get("Retry-After"))
+			assert.JSONEq(t, `{"error":"invalid credentials"}`, w.Body.String())
+		}
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/users/1/unlock", nil)
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var failures int
+		assert.NoError(t, db.Get(&failures, "SELECT COUNT(*) FROM login_failures WHERE username = 'testuser'"))
+		assert.Equal(t, 0, failures)
+	})
+
+	t.Run("SpoofedForwardedForIsThrottled", func(t *testing.T) {
+		// Каждая попытка с новым X-Forwarded-For и новым именем: ограничение по IP все равно
+		// срабатывает, потому что адрес берется из соединения
+		for i, username := range []string{"ghost1", "ghost2"} {
+			badLogin, _ := json.Marshal(entity.LoginRequest{Username: username, Password: "wrong"})
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(badLogin))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Forwarded-For", "198.51.100."+strconv.Itoa(i+1))
+			req.RemoteAddr = "203.0.113.7:40000"
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		}
+
+		var ips []string
+		assert.NoError(t, db.Select(&ips, "SELECT ip_address FROM login_failures WHERE username LIKE 'ghost%'"))
+		// Вторая попытка отклонена до проверки пароля и не записана
+		assert.Equal(t, []string{"203.0.113.7"}, ips)
+	})
+
+	t.Run("UpdateProfileAndReadPublicProfile", func(t *testing.T) {
+		_, err := db.Exec("INSERT INTO posts (author_id, title, content) VALUES (2, 'a', 'b'), (2, 'c', 'd')")
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPatch, "/me", bytes.NewBufferString(`{"displayName":"Second","bio":"Hello"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/users/2", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var profile entity.Profile
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+		assert.Equal(t, "seconduser", profile.Username)
+		assert.Equal(t, "Second", profile.DisplayName)
+		assert.Equal(t, "Hello", profile.Bio)
+		assert.Equal(t, 2, profile.PostCount)
+		assert.Equal(t, 0, profile.CommentCount)
+		assert.False(t, profile.CreatedAt.IsZero())
+	})
+
+	t.Run("ChangePasswordRevokesTokens", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/me/password", bytes.NewBufferString(`{"oldPassword":"password","newPassword":"changed1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+secondToken)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("PasswordResetFlow", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/password-reset/request", bytes.NewBufferString(`{"username":"seconduser"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.NotEmpty(t, notifier.resetToken)
+
+		confirm, _ := json.Marshal(entity.PasswordResetConfirmRequest{Token: notifier.resetToken, NewPassword: "password"})
+		for _, expected := range []int{http.StatusOK, http.StatusBadRequest} {
+			w = httptest.NewRecorder()
+			req, _ = http.NewRequest(http.MethodPost, "/password-reset/confirm", bytes.NewBuffer(confirm))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, expected, w.Code)
+		}
+
+		login(t, "seconduser")
 	})
 }
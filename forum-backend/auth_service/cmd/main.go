@@ -14,8 +14,10 @@ import (
 	_ "github.com/Engls/forum-project2/auth_service/docs"
 	"github.com/Engls/forum-project2/auth_service/internal/config"
 	"github.com/Engls/forum-project2/auth_service/internal/delivery/http"
+	"github.com/Engls/forum-project2/auth_service/internal/notifier"
 	"github.com/Engls/forum-project2/auth_service/internal/repository"
 	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/Engls/forum-project2/auth_service/internal/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
@@ -60,6 +62,11 @@ func main() {
 		logger.Fatal("Failed to ping database", zap.Error(err))
 	}
 
+	// Миграции создают таблицы FTS5 для поиска форума, поэтому сервис собирается с -tags sqlite_fts5.
+	// Без FTS5 сервис не запускается до миграций, чтобы не оставить базу в состоянии dirty
+	if err := repository.CheckFTS5(db); err != nil {
+		logger.Fatal("Database driver does not support full-text search", zap.Error(err))
+	}
 	driver, err := sqlite3.WithInstance(db.DB, &sqlite3.Config{})
 	if err != nil {
 		logger.Fatal("Failed to create migrate driver", zap.Error(err))
@@ -77,7 +84,42 @@ func main() {
 	logger.Info("Migrations applied successfully")
 
 	userRepo := repository.NewAuthRepository(db, logger)
-	userServer := mygrpc.NewUserServer(userRepo)
+	jwtUtil := utils.NewJWTUtil(cfg.JWTSecret)
+	blocklist, err := validation.LoadBlocklist(cfg.PasswordPolicy.BlocklistPath)
+	if err != nil {
+		logger.Warn("Failed to load common password blocklist", zap.Error(err), zap.String("path", cfg.PasswordPolicy.BlocklistPath))
+	}
+	passwordPolicy := validation.PasswordPolicy{
+		MinLength:     cfg.PasswordPolicy.MinLength,
+		RequireUpper:  cfg.PasswordPolicy.RequireUpper,
+		RequireLower:  cfg.PasswordPolicy.RequireLower,
+		RequireDigit:  cfg.PasswordPolicy.RequireDigit,
+		RequireSymbol: cfg.PasswordPolicy.RequireSymbol,
+		Blocklist:     blocklist,
+	}
+	resetNotifier, err := notifier.New(cfg.PasswordResetNotifier, cfg.PasswordResetOutbox, logger)
+	if err != nil {
+		logger.Fatal("Invalid password reset notifier", zap.Error(err))
+	}
+	userUsecase := usecase.NewAuthUsecase(userRepo, jwtUtil, usecase.TokenConfig{
+		AccessTokenTTL:   cfg.AccessTokenTTL,
+		RefreshTokenTTL:  cfg.RefreshTokenTTL,
+		PasswordResetTTL: cfg.PasswordResetTTL,
+	}, passwordPolicy, usecase.LoginThrottleConfig{
+		Window:          cfg.LoginThrottle.Window,
+		BaseDelay:       cfg.LoginThrottle.BaseDelay,
+		MaxDelay:        cfg.LoginThrottle.MaxDelay,
+		MaxFailures:     cfg.LoginThrottle.MaxFailures,
+		MaxIPFailures:   cfg.LoginThrottle.MaxIPFailures,
+		LockoutDuration: cfg.LoginThrottle.LockoutDuration,
+	}, resetNotifier, logger)
+	if cfg.InitialAdmin != "" {
+		// Пользователь может еще не зарегистрироваться: тогда роль выдается при следующем запуске
+		if err := userUsecase.BootstrapAdmin(cfg.InitialAdmin); err != nil {
+			logger.Error("Failed to grant admin role to the initial admin", zap.Error(err), zap.String("INITIAL_ADMIN", cfg.InitialAdmin))
+		}
+	}
+	userServer := mygrpc.NewUserServer(userRepo, userUsecase)
 
 	grpcServer := grpc.NewServer()
 	user.RegisterUserServiceServer(grpcServer, userServer)
@@ -93,14 +135,27 @@ func main() {
 			log.Fatal(err)
 		}
 	}()
-	jwtUtil := utils.NewJWTUtil(cfg.JWTSecret)
-	userUsecase := usecase.NewAuthUsecase(userRepo, jwtUtil, logger)
 	authHandler := http.NewAuthHandler(userUsecase, jwtUtil, logger)
 
+	// Периодически удаляем истекшие токены из базы
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := userUsecase.DeleteExpiredTokens(); err != nil {
+				logger.Error("Failed to delete expired tokens", zap.Error(err))
+			}
+		}
+	}()
+
 	router := gin.Default()
+	// Без списка прокси gin верит X-Forwarded-For от любого клиента, и ограничение попыток входа по IP обходится
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Fatal("Invalid trusted proxies", zap.Error(err), zap.Strings("TRUSTED_PROXIES", cfg.TrustedProxies))
+	}
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"PUT", "PATCH", "POST", "GET"},
+		AllowMethods:     []string{"PUT", "PATCH", "POST", "GET", "DELETE"},
 		AllowHeaders:     []string{"Content-type", "Origin", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -108,6 +163,22 @@ func main() {
 	}))
 	router.POST("/register", authHandler.Register)
 	router.POST("/login", authHandler.Login)
+	router.POST("/refresh", authHandler.Refresh)
+	router.POST("/logout", authHandler.Logout)
+	router.POST("/logout-all", authHandler.LogoutAll)
+
+	router.GET("/me", authHandler.GetMe)
+	router.PATCH("/me", authHandler.UpdateMe)
+	router.POST("/me/password", authHandler.ChangePassword)
+	router.POST("/password-reset/request", authHandler.RequestPasswordReset)
+	router.POST("/password-reset/confirm", authHandler.ConfirmPasswordReset)
+	router.GET("/users/:id", authHandler.GetUser)
+
+	router.GET("/roles", authHandler.ListRoles)
+	router.GET("/users/:id/roles", authHandler.GetUserRoles)
+	router.POST("/users/:id/roles", authHandler.GrantRole)
+	router.DELETE("/users/:id/roles/:role", authHandler.RevokeRole)
+	router.POST("/users/:id/unlock", authHandler.UnlockUser)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -0,0 +1,33 @@
+// Package authz описывает права доступа. Такой же пакет есть в forum_service,
+// список прав в обоих сервисах должен совпадать с таблицей permissions.
+package authz
+
+const (
+	PostCreate      = "post.create"
+	PostUpdateAny   = "post.update.any"
+	PostDeleteAny   = "post.delete.any"
+	CommentCreate   = "comment.create"
+	CommentModerate = "comment.moderate"
+	RoleManage      = "role.manage"
+	UserUnlock      = "user.unlock"
+	PostHistory     = "post.history"
+	CategoryManage  = "category.manage"
+	Vote            = "vote"
+	React           = "react"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Can сообщает, входит ли permission в набор прав пользователя
+func Can(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -2,15 +2,55 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port           string
-	DBPath         string
-	MigrationsPath string
-	JWTSecret      string
+	Port            string
+	DBPath          string
+	MigrationsPath  string
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// PasswordResetTTL - время жизни токена сброса пароля
+	PasswordResetTTL time.Duration
+	// PasswordResetNotifier - способ доставки токенов сброса пароля: disabled (по умолчанию)
+	// или log - только для локальной разработки
+	PasswordResetNotifier string
+	// PasswordResetOutbox - файл, куда notifier log пишет токены сброса пароля
+	PasswordResetOutbox string
+	PasswordPolicy      PasswordPolicyConfig
+	LoginThrottle       LoginThrottleConfig
+	// TrustedProxies - адреса прокси, которым разрешено передавать адрес клиента в X-Forwarded-For.
+	// По умолчанию пусто: адрес клиента для ограничения попыток входа берется из соединения
+	TrustedProxies []string
+	// InitialAdmin - имя пользователя, которому при запуске выдается роль admin.
+	// Другого способа получить первого администратора нет: роль из регистрации не принимается
+	InitialAdmin string
+}
+
+// LoginThrottleConfig - ограничение частоты неудачных попыток входа
+type LoginThrottleConfig struct {
+	Window          time.Duration
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	MaxFailures     int
+	MaxIPFailures   int
+	LockoutDuration time.Duration
+}
+
+// PasswordPolicyConfig - требования к паролям и путь к списку распространенных паролей
+type PasswordPolicyConfig struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	BlocklistPath string
 }
 
 func LoadConfig() (Config, error) {
@@ -21,10 +61,33 @@ func LoadConfig() (Config, error) {
 	}
 
 	cfg := Config{
-		Port:           getEnv("AUTH_SERVICE_PORT", ":8080"),
-		DBPath:         getEnv("DB_PATH", "../db/forum.db"),
-		MigrationsPath: getEnv("AUTH_SERVICE_MIGRATIONS_PATH", "C:\\forum-project\\forum-backend\\auth_service\\migrations"),
-		JWTSecret:      getEnv("JWT_SECRET", "your-secret-key"),
+		Port:                  getEnv("AUTH_SERVICE_PORT", ":8080"),
+		DBPath:                getEnv("DB_PATH", "../db/forum.db"),
+		MigrationsPath:        getEnv("AUTH_SERVICE_MIGRATIONS_PATH", "C:\\forum-project\\forum-backend\\auth_service\\migrations"),
+		JWTSecret:             getEnv("JWT_SECRET", "your-secret-key"),
+		AccessTokenTTL:        getDurationEnv("ACCESS_TOKEN_TTL", time.Hour),
+		RefreshTokenTTL:       getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		PasswordResetTTL:      getDurationEnv("PASSWORD_RESET_TTL", time.Hour),
+		PasswordResetNotifier: getEnv("PASSWORD_RESET_NOTIFIER", "disabled"),
+		PasswordResetOutbox:   getEnv("PASSWORD_RESET_OUTBOX", ""),
+		PasswordPolicy: PasswordPolicyConfig{
+			MinLength:     getIntEnv("PASSWORD_MIN_LENGTH", 8),
+			RequireUpper:  getBoolEnv("PASSWORD_REQUIRE_UPPER", false),
+			RequireLower:  getBoolEnv("PASSWORD_REQUIRE_LOWER", false),
+			RequireDigit:  getBoolEnv("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol: getBoolEnv("PASSWORD_REQUIRE_SYMBOL", false),
+			BlocklistPath: getEnv("PASSWORD_BLOCKLIST_PATH", "common_passwords.txt"),
+		},
+		LoginThrottle: LoginThrottleConfig{
+			Window:          getDurationEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+			BaseDelay:       getDurationEnv("LOGIN_BASE_DELAY", time.Second),
+			MaxDelay:        getDurationEnv("LOGIN_MAX_DELAY", 30*time.Second),
+			MaxFailures:     getIntEnv("LOGIN_MAX_FAILURES", 5),
+			MaxIPFailures:   getIntEnv("LOGIN_MAX_IP_FAILURES", 20),
+			LockoutDuration: getDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		},
+		TrustedProxies: getListEnv("TRUSTED_PROXIES"),
+		InitialAdmin:   getEnv("INITIAL_ADMIN", ""),
 	}
 	return cfg, nil
 }
@@ -36,3 +99,38 @@ func getEnv(key string, defaultValue string) string {
 	}
 	return value
 }
+
+// getListEnv читает список через запятую, пустая переменная дает nil
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -2,17 +2,24 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
 	"github.com/Engls/forum-project2/auth_service/internal/proto"
 	"github.com/Engls/forum-project2/auth_service/internal/repository"
+	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
 )
 
 type UserServer struct {
 	user.UnimplementedUserServiceServer // Важно: встраиваем стандартную реализацию
 	repo                                repository.AuthRepository
+	authUsecase                         usecase.AuthUsecase
 }
 
-func NewUserServer(repo repository.AuthRepository) *UserServer {
-	return &UserServer{repo: repo}
+func NewUserServer(repo repository.AuthRepository, authUsecase usecase.AuthUsecase) *UserServer {
+	return &UserServer{repo: repo, authUsecase: authUsecase}
 }
 
 // GetUsername - реализация метода из proto-файла
@@ -26,3 +33,95 @@ func (s *UserServer) GetUsername(ctx context.Context, req *user.UserRequest) (*u
 		Username: username,
 	}, nil
 }
+
+// maxBatchUsers ограничивает размер одного запроса GetUsers
+const maxBatchUsers = 500
+
+// GetUsers - имена пользователей для списка ID одним запросом
+func (s *UserServer) GetUsers(ctx context.Context, req *user.GetUsersRequest) (*user.GetUsersResponse, error) {
+	if len(req.UserIds) > maxBatchUsers {
+		return nil, status.Errorf(codes.InvalidArgument, "too many user ids: %d > %d", len(req.UserIds), maxBatchUsers)
+	}
+	userIDs := make([]int, len(req.UserIds))
+	for i, id := range req.UserIds {
+		userIDs[i] = int(id)
+	}
+	usernames, err := s.repo.GetUsernamesByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &user.GetUsersResponse{Users: make([]*user.UserInfo, 0, len(usernames))}
+	for id, username := range usernames {
+		resp.Users = append(resp.Users, &user.UserInfo{UserId: int32(id), Username: username})
+	}
+	return resp, nil
+}
+
+// ValidateToken - проверяет, что токен выдан сервисом и не был отозван.
+// Невалидный токен не считается ошибкой RPC: возвращается valid=false.
+func (s *UserServer) ValidateToken(ctx context.Context, req *user.ValidateTokenRequest) (*user.ValidateTokenResponse, error) {
+	token, err := s.authUsecase.ValidateToken(req.Token)
+	if err != nil {
+		if err == usecase.ErrInvalidToken {
+			return &user.ValidateTokenResponse{Valid: false}, nil
+		}
+		return nil, err
+	}
+
+	return &user.ValidateTokenResponse{
+		Valid:       true,
+		UserId:      int32(token.UserID),
+		Role:        token.Role,
+		Username:    token.Username,
+		TokenId:     int32(token.ID),
+		Permissions: token.Permissions,
+	}, nil
+}
+
+// GetUserProfile - карточка автора со статистикой для forum_service
+func (s *UserServer) GetUserProfile(ctx context.Context, req *user.UserRequest) (*user.UserProfileResponse, error) {
+	profile, err := s.authUsecase.GetProfile(int(req.UserId))
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, err
+	}
+	return profileResponse(profile), nil
+}
+
+// GetUserProfiles - карточки авторов для списка ID одним запросом
+func (s *UserServer) GetUserProfiles(ctx context.Context, req *user.GetUsersRequest) (*user.GetUserProfilesResponse, error) {
+	if len(req.UserIds) > maxBatchUsers {
+		return nil, status.Errorf(codes.InvalidArgument, "too many user ids: %d > %d", len(req.UserIds), maxBatchUsers)
+	}
+	userIDs := make([]int, len(req.UserIds))
+	for i, id := range req.UserIds {
+		userIDs[i] = int(id)
+	}
+	profiles, err := s.repo.GetProfilesByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &user.GetUserProfilesResponse{Profiles: make([]*user.UserProfileResponse, len(profiles))}
+	for i, profile := range profiles {
+		resp.Profiles[i] = profileResponse(profile)
+	}
+	return resp, nil
+}
+
+func profileResponse(profile entity.Profile) *user.UserProfileResponse {
+	return &user.UserProfileResponse{
+		UserId:       int32(profile.ID),
+		Username:     profile.Username,
+		Role:         profile.Role,
+		DisplayName:  profile.DisplayName,
+		AvatarUrl:    profile.AvatarURL,
+		Signature:    profile.Signature,
+		PostCount:    int32(profile.PostCount),
+		CommentCount: int32(profile.CommentCount),
+		JoinedAt:     profile.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"errors"
 	utils "github.com/Engls/EnglsJwt"
 	"github.com/Engls/forum-project2/auth_service/internal/entity"
 	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/Engls/forum-project2/auth_service/internal/validation"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -22,13 +25,14 @@ func NewAuthHandler(authUsecase usecase.AuthUsecase, jwtUtil *utils.JWTUtil, log
 
 // Register godoc
 // @Summary Регистрация нового пользователя
-// @Description Создает нового пользователя в системе
+// @Description Создает нового пользователя в системе с ролью user. Имя: 3-32 символа (латиница, цифры, '_', '.', '-'), уникально без учета регистра. Пароль проверяется по политике паролей
 // @Tags Аутентификация
 // @Accept json
 // @Produce json
 // @Param request body entity.RegisterRequest true "Данные для регистрации"
 // @Success 200 {object} entity.RegisterResponse
-// @Failure 400 {object} entity.ErrorResponse
+// @Failure 400 {object} entity.ValidationErrorResponse
+// @Failure 409 {object} entity.ValidationErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -38,7 +42,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.authUsecase.Register(req.Username, req.Password, req.Role); err != nil {
+	if err := h.authUsecase.Register(req.Username, req.Password); err != nil {
+		if h.respondValidationError(c, err) {
+			return
+		}
 		h.logger.Error("Failed to register user", zap.Error(err), zap.String("username", req.Username))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -49,7 +56,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Аутентификация пользователя
-// @Description Вход пользователя в систему и получение токена
+// @Description Вход пользователя в систему и получение токена. После неудачных попыток вход временно ограничивается, ответ при этом не отличается от ответа на неверный пароль
 // @Tags Аутентификация
 // @Accept json
 // @Produce json
@@ -66,9 +73,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	token, err := h.authUsecase.Login(req.Username, req.Password)
+	token, err := h.authUsecase.Login(req.Username, req.Password, deviceInfo(c))
 	if err != nil {
 		h.logger.Error("Failed to login user", zap.Error(err), zap.String("username", req.Username))
+		// Блокировку не выдаем ни кодом, ни заголовками: иначе по ответу видно, что подбор замечен
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -84,6 +92,159 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	refreshToken, err := h.authUsecase.IssueRefreshToken(userId, deviceInfo(c))
+	if err != nil {
+		h.logger.Error("Failed to issue refresh token", zap.Error(err), zap.String("username", req.Username))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	h.logger.Info("User logged in successfully", zap.String("username", req.Username))
-	c.JSON(http.StatusOK, gin.H{"token": token, "role": role, "username": req.Username, "userID": userId})
+	c.JSON(http.StatusOK, gin.H{"token": token, "refreshToken": refreshToken, "role": role, "username": req.Username, "userID": userId})
+}
+
+// Refresh godoc
+// @Summary Обновление токенов
+// @Description Обменивает refresh-токен на новый access-токен. Refresh-токен ротируется при каждом использовании
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body entity.RefreshRequest true "Refresh-токен"
+// @Success 200 {object} entity.RefreshResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req entity.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for refresh", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh token required"})
+		return
+	}
+	token, refreshToken, err := h.authUsecase.Refresh(req.RefreshToken, deviceInfo(c))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) || errors.Is(err, usecase.ErrRefreshTokenReused) {
+			h.logger.Warn("Refresh rejected", zap.Error(err))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to refresh tokens", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": token, "refreshToken": refreshToken})
+}
+
+// Logout godoc
+// @Summary Выход из системы
+// @Description Отзывает текущий access-токен и, если передан, связанный с ним refresh-токен
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entity.LogoutRequest false "Refresh-токен текущей сессии"
+// @Success 200 {object} entity.MessageResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		return
+	}
+	var req entity.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Error("Failed to bind JSON for logout", zap.Error(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := h.authUsecase.Logout(token, req.RefreshToken); err != nil {
+		h.respondTokenError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// LogoutAll godoc
+// @Summary Выход со всех устройств
+// @Description Отзывает все access- и refresh-токены пользователя
+// @Tags Аутентификация
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entity.MessageResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		return
+	}
+	if err := h.authUsecase.LogoutAll(token); err != nil {
+		h.respondTokenError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out from all devices"})
+}
+
+func (h *AuthHandler) respondTokenError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrInvalidToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	h.logger.Error("Failed to revoke tokens", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// respondValidationError пишет ответ с ошибками по полям, если err - ошибка валидации или занятое имя
+func (h *AuthHandler) respondValidationError(c *gin.Context, err error) bool {
+	var validationErr *validation.Error
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, entity.ValidationErrorResponse{Error: "validation failed", Fields: validationErr.Fields})
+	case errors.Is(err, usecase.ErrUsernameTaken):
+		c.JSON(http.StatusConflict, entity.ValidationErrorResponse{
+			Error:  err.Error(),
+			Fields: []entity.FieldError{{Field: "username", Message: "is already taken"}},
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+// authenticate проверяет Bearer-токен, при ошибке сам пишет ответ
+func (h *AuthHandler) authenticate(c *gin.Context) (entity.Token, bool) {
+	token, ok := bearerToken(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		return entity.Token{}, false
+	}
+	stored, err := h.authUsecase.ValidateToken(token)
+	if err != nil {
+		h.respondTokenError(c, err)
+		return entity.Token{}, false
+	}
+	return stored, true
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if authHeader == "" || token == authHeader {
+		return "", false
+	}
+	return token, true
+}
+
+func deviceInfo(c *gin.Context) entity.DeviceInfo {
+	return entity.DeviceInfo{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
 }
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ChangePassword godoc
+// @Summary Сменить пароль
+// @Description Проверяет текущий пароль, устанавливает новый и отзывает все токены пользователя
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entity.ChangePasswordRequest true "Текущий и новый пароль"
+// @Success 200 {object} entity.MessageResponse
+// @Failure 400 {object} entity.ValidationErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/me/password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	token, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	var req entity.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for password change", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.authUsecase.ChangePassword(token.UserID, req.OldPassword, req.NewPassword); err != nil {
+		h.respondPasswordError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
+// RequestPasswordReset godoc
+// @Summary Запросить сброс пароля
+// @Description Выпускает одноразовый токен сброса пароля и отправляет его пользователю. Ответ не зависит от того, существует ли пользователь
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body entity.PasswordResetRequest true "Имя пользователя"
+// @Success 202 {object} entity.MessageResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Failure 503 {object} entity.ErrorResponse "Доставка токенов сброса не настроена"
+// @Router /auth/password-reset/request [post]
+func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
+	var req entity.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for password reset request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
+		return
+	}
+	err := h.authUsecase.RequestPasswordReset(req.Username)
+	if errors.Is(err, usecase.ErrPasswordResetUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Password reset is not available"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to request password reset", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request password reset"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists, password reset instructions have been sent"})
+}
+
+// ConfirmPasswordReset godoc
+// @Summary Подтвердить сброс пароля
+// @Description Гасит токен сброса, устанавливает новый пароль и отзывает все токены пользователя
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body entity.PasswordResetConfirmRequest true "Токен сброса и новый пароль"
+// @Success 200 {object} entity.MessageResponse
+// @Failure 400 {object} entity.ValidationErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/password-reset/confirm [post]
+func (h *AuthHandler) ConfirmPasswordReset(c *gin.Context) {
+	var req entity.PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for password reset confirm", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.authUsecase.ConfirmPasswordReset(req.Token, req.NewPassword); err != nil {
+		h.respondPasswordError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset"})
+}
+
+func (h *AuthHandler) respondPasswordError(c *gin.Context, err error) {
+	if h.respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, usecase.ErrInvalidPassword),
+		errors.Is(err, usecase.ErrInvalidResetToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to update password", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/Engls/forum-project2/auth_service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestAuthHandler_ChangePassword_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("ValidateToken", "valid.jwt.token").Return(entity.Token{UserID: 1}, nil)
+	mockAuthUsecase.On("ChangePassword", 1, "oldpass", "newpass").Return(nil)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.POST("/me/password", authHandler.ChangePassword)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/me/password", bytes.NewBufferString(`{"oldPassword":"oldpass","newPassword":"newpass"}`))
+	req.Header.Set("Authorization", "Bearer valid.jwt.token")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockAuthUsecase.AssertExpectations(t)
+}
+
+func TestAuthHandler_ChangePassword_WrongOldPassword(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("ValidateToken", "valid.jwt.token").Return(entity.Token{UserID: 1}, nil)
+	mockAuthUsecase.On("ChangePassword", 1, "wrong", "newpass").Return(usecase.ErrInvalidPassword)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.POST("/me/password", authHandler.ChangePassword)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/me/password", bytes.NewBufferString(`{"oldPassword":"wrong","newPassword":"newpass"}`))
+	req.Header.Set("Authorization", "Bearer valid.jwt.token")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"invalid password"}`, w.Body.String())
+
+	mockAuthUsecase.AssertExpectations(t)
+}
+
+func TestAuthHandler_RequestPasswordReset_Accepted(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("RequestPasswordReset", "testuser").Return(nil)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.POST("/password-reset/request", authHandler.RequestPasswordReset)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/password-reset/request", bytes.NewBufferString(`{"username":"testuser"}`)))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	mockAuthUsecase.AssertExpectations(t)
+}
+
+func TestAuthHandler_RequestPasswordReset_Unavailable(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("RequestPasswordReset", "testuser").Return(usecase.ErrPasswordResetUnavailable)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.POST("/password-reset/request", authHandler.RequestPasswordReset)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/password-reset/request", bytes.NewBufferString(`{"username":"testuser"}`)))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	mockAuthUsecase.AssertExpectations(t)
+}
+
+func TestAuthHandler_ConfirmPasswordReset_InvalidToken(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("ConfirmPasswordReset", "bad", "newpass").Return(usecase.ErrInvalidResetToken)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.POST("/password-reset/confirm", authHandler.ConfirmPasswordReset)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/password-reset/confirm", bytes.NewBufferString(`{"token":"bad","newPassword":"newpass"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockAuthUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetUser godoc
+// @Summary Профиль пользователя
+// @Description Возвращает публичный профиль пользователя со статистикой
+// @Tags Профиль
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} entity.Profile
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/users/{id} [get]
+func (h *AuthHandler) GetUser(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	h.respondProfile(c, userID)
+}
+
+// GetMe godoc
+// @Summary Мой профиль
+// @Description Возвращает профиль текущего пользователя
+// @Tags Профиль
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entity.Profile
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/me [get]
+func (h *AuthHandler) GetMe(c *gin.Context) {
+	token, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	h.respondProfile(c, token.UserID)
+}
+
+// UpdateMe godoc
+// @Summary Обновить мой профиль
+// @Description Частично обновляет профиль текущего пользователя: отображаемое имя, о себе, аватар и подпись
+// @Tags Профиль
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entity.UpdateProfileRequest true "Изменяемые поля профиля"
+// @Success 200 {object} entity.Profile
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/me [patch]
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+	token, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	var req entity.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for profile update", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	profile, err := h.authUsecase.UpdateProfile(token.UserID, req)
+	if err != nil {
+		h.respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *AuthHandler) respondProfile(c *gin.Context, userID int) {
+	profile, err := h.authUsecase.GetProfile(userID)
+	if err != nil {
+		h.respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *AuthHandler) respondProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidProfile):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to process profile", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/Engls/forum-project2/auth_service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestAuthHandler_GetUser_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("GetProfile", 1).Return(entity.Profile{ID: 1, Username: "testuser", PostCount: 3}, nil)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.GET("/users/:id", authHandler.GetUser)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"postCount":3`)
+
+	mockAuthUsecase.AssertExpectations(t)
+}
+
+func TestAuthHandler_GetUser_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("GetProfile", 42).Return(entity.Profile{}, usecase.ErrUserNotFound)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.GET("/users/:id", authHandler.GetUser)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockAuthUsecase.AssertExpectations(t)
+}
+
+func TestAuthHandler_UpdateMe_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	signature := "Всем добра"
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("ValidateToken", "valid.jwt.token").Return(entity.Token{UserID: 1}, nil)
+	mockAuthUsecase.On("UpdateProfile", 1, entity.UpdateProfileRequest{Signature: &signature}).
+		Return(entity.Profile{ID: 1, Username: "testuser", Signature: signature}, nil)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.PATCH("/me", authHandler.UpdateMe)
+
+	req := httptest.NewRequest(http.MethodPatch, "/me", bytes.NewBufferString(`{"signature":"Всем добра"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer valid.jwt.token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Всем добра")
+
+	mockAuthUsecase.AssertExpectations(t)
+}
+
+func TestAuthHandler_UpdateMe_Unauthorized(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.PATCH("/me", authHandler.UpdateMe)
+
+	req := httptest.NewRequest(http.MethodPatch, "/me", bytes.NewBufferString(`{"bio":"hi"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	mockAuthUsecase.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+}
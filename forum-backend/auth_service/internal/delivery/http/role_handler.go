@@ -0,0 +1,227 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Engls/forum-project2/auth_service/internal/authz"
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ListRoles godoc
+// @Summary Список ролей
+// @Description Возвращает все роли с их правами. Требуется право role.manage
+// @Tags Роли
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.Role
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/roles [get]
+func (h *AuthHandler) ListRoles(c *gin.Context) {
+	if _, ok := h.authorize(c, authz.RoleManage); !ok {
+		return
+	}
+	roles, err := h.authUsecase.ListRoles()
+	if err != nil {
+		h.logger.Error("Failed to list roles", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// GetUserRoles godoc
+// @Summary Роли пользователя
+// @Description Возвращает роли пользователя. Требуется право role.manage
+// @Tags Роли
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} entity.UserRolesResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/users/{id}/roles [get]
+func (h *AuthHandler) GetUserRoles(c *gin.Context) {
+	if _, ok := h.authorize(c, authz.RoleManage); !ok {
+		return
+	}
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	roles, err := h.authUsecase.GetUserRoles(userID)
+	if err != nil {
+		h.respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"userID": userID, "roles": roles})
+}
+
+// GrantRole godoc
+// @Summary Назначить роль
+// @Description Назначает пользователю роль. Требуется право role.manage
+// @Tags Роли
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID пользователя"
+// @Param request body entity.RoleRequest true "Назначаемая роль"
+// @Success 200 {object} entity.UserRolesResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/users/{id}/roles [post]
+func (h *AuthHandler) GrantRole(c *gin.Context) {
+	actor, ok := h.authorize(c, authz.RoleManage)
+	if !ok {
+		return
+	}
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	var req entity.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for role grant", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Role == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role required"})
+		return
+	}
+	if err := h.authUsecase.GrantRole(userID, req.Role); err != nil {
+		h.respondRoleError(c, err)
+		return
+	}
+	h.logger.Info("Role granted", zap.Int("actorID", actor.UserID), zap.Int("userID", userID), zap.String("role", req.Role))
+	h.respondUserRoles(c, userID)
+}
+
+// RevokeRole godoc
+// @Summary Отозвать роль
+// @Description Отзывает у пользователя роль. Требуется право role.manage
+// @Tags Роли
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID пользователя"
+// @Param role path string true "Название роли"
+// @Success 200 {object} entity.UserRolesResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/users/{id}/roles/{role} [delete]
+func (h *AuthHandler) RevokeRole(c *gin.Context) {
+	actor, ok := h.authorize(c, authz.RoleManage)
+	if !ok {
+		return
+	}
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	role := c.Param("role")
+	if err := h.authUsecase.RevokeRole(actor.UserID, userID, role); err != nil {
+		h.respondRoleError(c, err)
+		return
+	}
+	h.logger.Info("Role revoked", zap.Int("actorID", actor.UserID), zap.Int("userID", userID), zap.String("role", role))
+	h.respondUserRoles(c, userID)
+}
+
+// authorize проверяет Bearer-токен и право permission, при отказе сам пишет ответ 401/403
+func (h *AuthHandler) authorize(c *gin.Context, permission string) (entity.Token, bool) {
+	token, ok := bearerToken(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		return entity.Token{}, false
+	}
+	stored, err := h.authUsecase.Authorize(token, permission)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to authorize request", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return entity.Token{}, false
+	}
+	return stored, true
+}
+
+func (h *AuthHandler) respondUserRoles(c *gin.Context, userID int) {
+	roles, err := h.authUsecase.GetUserRoles(userID)
+	if err != nil {
+		h.respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"userID": userID, "roles": roles})
+}
+
+// UnlockUser godoc
+// @Summary Снять блокировку входа
+// @Description Сбрасывает неудачные попытки входа пользователя и снимает временную блокировку. Требуется право user.unlock
+// @Tags Роли
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} entity.MessageResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /auth/users/{id}/unlock [post]
+func (h *AuthHandler) UnlockUser(c *gin.Context) {
+	token, ok := h.authorize(c, authz.UserUnlock)
+	if !ok {
+		return
+	}
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.authUsecase.UnlockUser(userID); err != nil {
+		h.respondRoleError(c, err)
+		return
+	}
+	h.logger.Info("User unlocked", zap.Int("userID", userID), zap.Int("actorID", token.UserID))
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked"})
+}
+
+func (h *AuthHandler) respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound), errors.Is(err, usecase.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrOwnAdminRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to manage roles", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func userIDParam(c *gin.Context) (int, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/Engls/forum-project2/auth_service/internal/usecase"
+	"github.com/Engls/forum-project2/auth_service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestAuthHandler_GrantRole_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("Authorize", "admin.jwt.token", "role.manage").Return(entity.Token{UserID: 1, Role: "admin"}, nil)
+	mockAuthUsecase.On("GrantRole", 2, "moderator").Return(nil)
+	mockAuthUsecase.On("GetUserRoles", 2).Return([]string{"moderator", "user"}, nil)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.POST("/users/:id/roles", authHandler.GrantRole)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/2/roles", bytes.NewBufferString(`{"role":"moderator"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer admin.jwt.token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"userID":2,"roles":["moderator","user"]}`, w.Body.String())
+
+	mockAuthUsecase.AssertExpectations(t)
+}
+
+func TestAuthHandler_GrantRole_Forbidden(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("Authorize", "user.jwt.token", "role.manage").Return(entity.Token{}, usecase.ErrForbidden)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.POST("/users/:id/roles", authHandler.GrantRole)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1/roles", bytes.NewBufferString(`{"role":"admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer user.jwt.token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	mockAuthUsecase.AssertNotCalled(t, "GrantRole", mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_RevokeRole_OwnAdminRole(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuthUsecase.On("Authorize", "admin.jwt.token", "role.manage").Return(entity.Token{UserID: 1, Role: "admin"}, nil)
+	mockAuthUsecase.On("RevokeRole", 1, 1, "admin").Return(usecase.ErrOwnAdminRole)
+
+	authHandler := NewAuthHandler(mockAuthUsecase, nil, logger)
+
+	router := gin.New()
+	router.DELETE("/users/:id/roles/:role", authHandler.RevokeRole)
+
+	req := httptest.NewRequest(http.MethodDelete, "/users/1/roles/admin", nil)
+	req.Header.Set("Authorization", "Bearer admin.jwt.token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "cannot revoke own admin role")
+
+	mockAuthUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Profile - публичный профиль пользователя вместе со статистикой активности
+type Profile struct {
+	ID           int       `db:"id" json:"id" example:"1"`
+	Username     string    `db:"username" json:"username" example:"user123"`
+	Role         string    `db:"role" json:"role" example:"user"`
+	DisplayName  string    `db:"display_name" json:"displayName" example:"Иван"`
+	Bio          string    `db:"bio" json:"bio" example:"Люблю Go"`
+	AvatarURL    string    `db:"avatar_url" json:"avatarURL" example:"https://example.com/avatar.png"`
+	Signature    string    `db:"signature" json:"signature" example:"Всем добра"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	PostCount    int       `db:"post_count" json:"postCount" example:"12"`
+	CommentCount int       `db:"comment_count" json:"commentCount" example:"34"`
+}
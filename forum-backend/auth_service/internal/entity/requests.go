@@ -3,10 +3,43 @@ package entity
 type RegisterRequest struct {
 	Username string `json:"username" example:"user123"`
 	Password string `json:"password" example:"P@ssw0rd"`
-	Role     string `json:"role" example:"user"`
 }
 
 type LoginRequest struct {
 	Username string `json:"username" example:"user123"`
 	Password string `json:"password" example:"P@ssw0rd"`
 }
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" example:"3q2-7wEAAAB0b2tlbg..."`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken" example:"3q2-7wEAAAB0b2tlbg..."`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" example:"P@ssw0rd"`
+	NewPassword string `json:"newPassword" example:"N3wP@ssw0rd"`
+}
+
+type PasswordResetRequest struct {
+	Username string `json:"username" example:"user123"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token" example:"q8Xk3v..."`
+	NewPassword string `json:"newPassword" example:"N3wP@ssw0rd"`
+}
+
+type RoleRequest struct {
+	Role string `json:"role" example:"moderator"`
+}
+
+// UpdateProfileRequest - частичное обновление профиля: не переданные поля не меняются
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"displayName" example:"Иван"`
+	Bio         *string `json:"bio" example:"Люблю Go"`
+	AvatarURL   *string `json:"avatarURL" example:"https://example.com/avatar.png"`
+	Signature   *string `json:"signature" example:"Всем добра"`
+}
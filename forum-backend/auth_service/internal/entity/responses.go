@@ -5,12 +5,38 @@ type RegisterResponse struct {
 }
 
 type LoginResponse struct {
-	Token    string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	Role     string `json:"role" example:"user"`
-	Username string `json:"username" example:"user123"`
-	UserID   int    `json:"userID" example:"1"`
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refreshToken" example:"3q2-7wEAAAB0b2tlbg..."`
+	Role         string `json:"role" example:"user"`
+	Username     string `json:"username" example:"user123"`
+	UserID       int    `json:"userID" example:"1"`
+}
+
+type RefreshResponse struct {
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refreshToken" example:"3q2-7wEAAAB0b2tlbg..."`
+}
+
+type MessageResponse struct {
+	Message string `json:"message" example:"Logged out successfully"`
+}
+
+type UserRolesResponse struct {
+	UserID int      `json:"userID" example:"1"`
+	Roles  []string `json:"roles" example:"user,moderator"`
 }
 
 type ErrorResponse struct {
 	Error string `json:"error" example:"error message"`
 }
+
+type FieldError struct {
+	Field   string `json:"field" example:"username"`
+	Message string `json:"message" example:"is required"`
+}
+
+// ValidationErrorResponse - ошибка валидации с описанием по полям
+type ValidationErrorResponse struct {
+	Error  string       `json:"error" example:"validation failed"`
+	Fields []FieldError `json:"fields"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+type Role struct {
+	ID          int      `db:"id" json:"id" example:"1"`
+	Name        string   `db:"name" json:"name" example:"moderator"`
+	Description string   `db:"description" json:"description" example:"Модератор"`
+	Permissions []string `db:"-" json:"permissions"`
+}
+
+type RolePermission struct {
+	RoleID     int    `db:"role_id"`
+	Permission string `db:"permission"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+type Token struct {
+	ID          int        `db:"id"`
+	UserID      int        `db:"user_id"`
+	Token       string     `db:"token"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+	RevokedAt   *time.Time `db:"revoked_at"`
+	Username    string     `db:"username"`
+	Role        string     `db:"role"`
+	Permissions []string   `db:"-"`
+}
+
+type RefreshToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	FamilyID  string     `db:"family_id"`
+	UserAgent string     `db:"user_agent"`
+	IPAddress string     `db:"ip_address"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// PasswordResetToken - одноразовый токен сброса пароля, хранится только в виде хеша
+type PasswordResetToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// LoginFailureStats - число неудачных попыток входа за окно и время последней из них
+type LoginFailureStats struct {
+	Failures    int       `db:"failures"`
+	LastFailure time.Time `db:"last_failure"`
+}
+
+type DeviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
@@ -0,0 +1,94 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"go.uber.org/zap"
+)
+
+// Notifier доставляет пользователю служебные сообщения (например, токен сброса пароля)
+type Notifier interface {
+	SendPasswordReset(user entity.User, token string, expiresAt time.Time) error
+}
+
+// Способы доставки, которые выбираются в конфигурации (PASSWORD_RESET_NOTIFIER)
+const (
+	KindDisabled = "disabled"
+	KindLog      = "log"
+)
+
+// ErrDisabled - доставка сообщений не настроена
+var ErrDisabled = errors.New("notifications are disabled")
+
+// New возвращает Notifier выбранного способа доставки. По умолчанию доставка отключена:
+// LogNotifier раскрывает токены сброса пароля и годится только для локальной разработки
+func New(kind, path string, logger *zap.Logger) (Notifier, error) {
+	switch kind {
+	case "", KindDisabled:
+		return NewDisabledNotifier(logger), nil
+	case KindLog:
+		logger.Warn("Password reset tokens are written to the log or outbox file, use only for local development")
+		return NewLogNotifier(path, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q, expected %q or %q", kind, KindDisabled, KindLog)
+	}
+}
+
+// DisabledNotifier ничего не отправляет и возвращает ErrDisabled
+type DisabledNotifier struct {
+	logger *zap.Logger
+}
+
+func NewDisabledNotifier(logger *zap.Logger) *DisabledNotifier {
+	return &DisabledNotifier{logger: logger}
+}
+
+func (n *DisabledNotifier) SendPasswordReset(user entity.User, token string, expiresAt time.Time) error {
+	n.logger.Warn("Password reset requested, but notifications are disabled", zap.Int("userID", user.ID))
+	return ErrDisabled
+}
+
+// LogNotifier - реализация для локальной разработки: дописывает сообщения в файл path,
+// а если путь не задан - пишет их в лог вместе с токеном
+type LogNotifier struct {
+	path   string
+	logger *zap.Logger
+	mu     sync.Mutex
+}
+
+func NewLogNotifier(path string, logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{path: path, logger: logger}
+}
+
+func (n *LogNotifier) SendPasswordReset(user entity.User, token string, expiresAt time.Time) error {
+	if n.path == "" {
+		n.logger.Info("Password reset requested",
+			zap.Int("userID", user.ID),
+			zap.String("username", user.Username),
+			zap.String("token", token),
+			zap.Time("expiresAt", expiresAt))
+		return nil
+	}
+	n.logger.Info("Password reset requested", zap.Int("userID", user.ID), zap.String("outbox", n.path))
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		n.logger.Error("Failed to open notification file", zap.Error(err), zap.String("path", n.path))
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s password-reset user=%s token=%s expires=%s\n",
+		time.Now().UTC().Format(time.RFC3339), user.Username, token, expiresAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		n.logger.Error("Failed to write notification", zap.Error(err), zap.String("path", n.path))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNew(t *testing.T) {
+	logger := zap.NewNop()
+
+	n, err := New("", "", logger)
+	assert.NoError(t, err)
+	assert.IsType(t, &DisabledNotifier{}, n)
+	assert.ErrorIs(t, n.SendPasswordReset(entity.User{ID: 1}, "secret-token", time.Now()), ErrDisabled)
+
+	n, err = New(KindLog, "", logger)
+	assert.NoError(t, err)
+	assert.IsType(t, &LogNotifier{}, n)
+
+	_, err = New("smtp", "", logger)
+	assert.Error(t, err)
+}
+
+func TestLogNotifier_OutboxKeepsTokenOutOfLog(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	path := filepath.Join(t.TempDir(), "outbox.txt")
+
+	n := NewLogNotifier(path, zap.New(core))
+	assert.NoError(t, n.SendPasswordReset(entity.User{ID: 1, Username: "alice"}, "secret-token", time.Now()))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "token=secret-token")
+	for _, entry := range logs.All() {
+		for _, field := range entry.Context {
+			assert.NotEqual(t, "secret-token", field.String)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrFTS5Unavailable - драйвер sqlite3 собран без модуля FTS5, нужен тег сборки sqlite_fts5
+var ErrFTS5Unavailable = errors.New("sqlite3 driver is built without FTS5, build with -tags sqlite_fts5")
+
+// CheckFTS5 проверяет, что драйвер поддерживает FTS5. Миграция 012 создает таблицы FTS5:
+// без проверки она падает посреди Up и оставляет базу в состоянии dirty
+func CheckFTS5(db *sqlx.DB) error {
+	// Пробная таблица создается в транзакции и откатывается вместе с ней
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`CREATE VIRTUAL TABLE temp.fts5_probe USING fts5(content)`); err != nil {
+		return fmt.Errorf("%w: %v", ErrFTS5Unavailable, err)
+	}
+	return nil
+}
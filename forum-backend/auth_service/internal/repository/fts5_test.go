@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFTS5(t *testing.T) {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = CheckFTS5(db)
+	if fts5Built {
+		assert.NoError(t, err)
+	} else {
+		assert.ErrorIs(t, err, ErrFTS5Unavailable)
+	}
+}
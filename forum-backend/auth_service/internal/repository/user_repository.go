@@ -3,21 +3,59 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
 	"go.uber.org/zap"
+	"time"
 )
 
+// ErrDuplicate - нарушено ограничение уникальности
+var ErrDuplicate = errors.New("duplicate record")
+
 type DB interface {
 	Exec(query string, args ...any) (sql.Result, error)
 	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
 type AuthRepository interface {
 	Register(user entity.User) error
 	GetUserByUsername(username string) (entity.User, error)
-	SaveToken(userID int, token string) error
+	UsernameExists(username string) (bool, error)
+	GetUserByID(userID int) (entity.User, error)
+	SaveToken(userID int, token string, expiresAt time.Time) error
 	GetUsernameByID(ctx context.Context, userID int) (string, error)
+	GetUsernamesByIDs(userIDs []int) (map[int]string, error)
+	SaveRefreshToken(token entity.RefreshToken) error
+	GetRefreshTokenByHash(tokenHash string) (entity.RefreshToken, error)
+	RevokeRefreshToken(id int) (bool, error)
+	RevokeRefreshTokenFamily(familyID string) error
+	GetToken(token string) (entity.Token, error)
+	RevokeToken(token string) error
+	RevokeAllUserTokens(userID int) error
+	DeleteExpiredTokens(now time.Time) error
+	GetRoles() ([]entity.Role, error)
+	GetRoleByName(name string) (entity.Role, error)
+	GetUserRoles(userID int) ([]string, error)
+	GetUserPermissions(userID int) ([]string, error)
+	AssignRole(userID, roleID int) error
+	RemoveRole(userID, roleID int) error
+	GetProfile(userID int) (entity.Profile, error)
+	// GetProfilesByIDs возвращает профили одним запросом, неизвестные ID пропускаются
+	GetProfilesByIDs(userIDs []int) ([]entity.Profile, error)
+	UpdateProfile(profile entity.Profile) error
+	UpdatePassword(userID int, passwordHash string) error
+	SavePasswordResetToken(token entity.PasswordResetToken) error
+	GetPasswordResetTokenByHash(tokenHash string) (entity.PasswordResetToken, error)
+	UsePasswordResetToken(id int) (bool, error)
+	RecordLoginFailure(username, ipAddress string, at time.Time) error
+	GetUsernameLoginFailures(username string, since time.Time) (entity.LoginFailureStats, error)
+	GetIPLoginFailures(ipAddress string, since time.Time) (entity.LoginFailureStats, error)
+	ClearLoginFailures(username string) error
+	DeleteLoginFailuresBefore(before time.Time) error
 }
 
 type authRepository struct {
@@ -33,6 +71,14 @@ func (r *authRepository) Register(user entity.User) error {
 	_, err := r.db.Exec("INSERT INTO users (username, password, role) VALUES (?, ?, ?)", user.Username, user.Password, user.Role)
 	if err != nil {
 		r.logger.Error("Failed to register user", zap.Error(err), zap.String("username", user.Username))
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return err
+	}
+	_, err = r.db.Exec(assignRoleByNameQuery, user.Role, user.Username)
+	if err != nil {
+		r.logger.Error("Failed to assign role to user", zap.Error(err), zap.String("username", user.Username), zap.String("role", user.Role))
 		return err
 	}
 	r.logger.Info("User registered successfully", zap.String("username", user.Username))
@@ -41,7 +87,7 @@ func (r *authRepository) Register(user entity.User) error {
 
 func (r *authRepository) GetUserByUsername(username string) (entity.User, error) {
 	var user entity.User
-	err := r.db.Get(&user, "SELECT id, username, password, role FROM users WHERE username=?", username)
+	err := r.db.Get(&user, "SELECT id, username, password, role FROM users WHERE username=? COLLATE NOCASE", username)
 	if err != nil {
 		r.logger.Error("Failed to get user by username", zap.Error(err), zap.String("username", username))
 		return user, err
@@ -50,8 +96,35 @@ func (r *authRepository) GetUserByUsername(username string) (entity.User, error)
 	return user, nil
 }
 
-func (r *authRepository) SaveToken(userID int, token string) error {
-	_, err := r.db.Exec("INSERT INTO tokens (user_id, token) VALUES (?, ?)", userID, token)
+// UsernameExists проверяет, занято ли имя без учета регистра
+func (r *authRepository) UsernameExists(username string) (bool, error) {
+	var exists bool
+	err := r.db.Get(&exists, "SELECT EXISTS(SELECT 1 FROM users WHERE username=? COLLATE NOCASE)", username)
+	if err != nil {
+		r.logger.Error("Failed to check username", zap.Error(err), zap.String("username", username))
+		return false, err
+	}
+	return exists, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+func (r *authRepository) GetUserByID(userID int) (entity.User, error) {
+	var user entity.User
+	err := r.db.Get(&user, "SELECT id, username, password, role FROM users WHERE id=?", userID)
+	if err != nil {
+		r.logger.Error("Failed to get user by ID", zap.Error(err), zap.Int("userID", userID))
+		return user, err
+	}
+	r.logger.Info("User retrieved successfully", zap.Int("userID", userID))
+	return user, nil
+}
+
+func (r *authRepository) SaveToken(userID int, token string, expiresAt time.Time) error {
+	_, err := r.db.Exec("INSERT INTO tokens (user_id, token, expires_at) VALUES (?, ?, ?)", userID, token, expiresAt)
 	if err != nil {
 		r.logger.Error("Failed to save token", zap.Error(err), zap.Int("userID", userID))
 		return err
@@ -71,3 +144,379 @@ func (r *authRepository) GetUsernameByID(ctx context.Context, userID int) (strin
 	}
 	return username, nil
 }
+
+// GetUsernamesByIDs загружает имена одним запросом. Неизвестные ID в результат не попадают.
+func (r *authRepository) GetUsernamesByIDs(userIDs []int) (map[int]string, error) {
+	usernames := make(map[int]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return usernames, nil
+	}
+	query, args, err := sqlx.In("SELECT id, username FROM users WHERE id IN (?)", userIDs)
+	if err != nil {
+		r.logger.Error("Failed to build usernames query", zap.Error(err))
+		return nil, err
+	}
+	var users []entity.User
+	if err := r.db.Select(&users, query, args...); err != nil {
+		r.logger.Error("Failed to get usernames", zap.Error(err), zap.Int("count", len(userIDs)))
+		return nil, err
+	}
+	for _, user := range users {
+		usernames[user.ID] = user.Username
+	}
+	return usernames, nil
+}
+
+func (r *authRepository) SaveRefreshToken(token entity.RefreshToken) error {
+	_, err := r.db.Exec(
+		"INSERT INTO refresh_tokens (user_id, token_hash, family_id, user_agent, ip_address, expires_at) VALUES (?, ?, ?, ?, ?, ?)",
+		token.UserID, token.TokenHash, token.FamilyID, token.UserAgent, token.IPAddress, token.ExpiresAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save refresh token", zap.Error(err), zap.Int("userID", token.UserID))
+		return err
+	}
+	r.logger.Info("Refresh token saved successfully", zap.Int("userID", token.UserID), zap.String("familyID", token.FamilyID))
+	return nil
+}
+
+func (r *authRepository) GetRefreshTokenByHash(tokenHash string) (entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.db.Get(&token, "SELECT id, user_id, token_hash, family_id, user_agent, ip_address, expires_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash=?", tokenHash)
+	if err != nil {
+		r.logger.Error("Failed to get refresh token", zap.Error(err))
+		return token, err
+	}
+	return token, nil
+}
+
+// RevokeRefreshToken помечает токен использованным и сообщает, отозвал ли его именно этот вызов,
+// чтобы из двух одновременных обновлений с одним токеном успешным было только одно.
+func (r *authRepository) RevokeRefreshToken(id int) (bool, error) {
+	result, err := r.db.Exec("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL", id)
+	if err != nil {
+		r.logger.Error("Failed to revoke refresh token", zap.Error(err), zap.Int("tokenID", id))
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Failed to get affected rows", zap.Error(err), zap.Int("tokenID", id))
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *authRepository) RevokeRefreshTokenFamily(familyID string) error {
+	_, err := r.db.Exec("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = ? AND revoked_at IS NULL", familyID)
+	if err != nil {
+		r.logger.Error("Failed to revoke refresh token family", zap.Error(err), zap.String("familyID", familyID))
+		return err
+	}
+	r.logger.Warn("Refresh token family revoked", zap.String("familyID", familyID))
+	return nil
+}
+
+func (r *authRepository) GetToken(token string) (entity.Token, error) {
+	var t entity.Token
+	err := r.db.Get(&t, `SELECT t.id, t.user_id, t.token, t.expires_at, t.revoked_at, u.username, u.role
+		FROM tokens t JOIN users u ON u.id = t.user_id WHERE t.token=?`, token)
+	if err != nil {
+		r.logger.Error("Failed to get token", zap.Error(err))
+		return t, err
+	}
+	return t, nil
+}
+
+func (r *authRepository) RevokeToken(token string) error {
+	_, err := r.db.Exec("UPDATE tokens SET revoked_at = CURRENT_TIMESTAMP WHERE token = ? AND revoked_at IS NULL", token)
+	if err != nil {
+		r.logger.Error("Failed to revoke token", zap.Error(err))
+		return err
+	}
+	r.logger.Info("Token revoked successfully")
+	return nil
+}
+
+func (r *authRepository) RevokeAllUserTokens(userID int) error {
+	_, err := r.db.Exec("UPDATE tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL", userID)
+	if err != nil {
+		r.logger.Error("Failed to revoke user tokens", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+	_, err = r.db.Exec("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL", userID)
+	if err != nil {
+		r.logger.Error("Failed to revoke user refresh tokens", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+	r.logger.Info("All user tokens revoked successfully", zap.Int("userID", userID))
+	return nil
+}
+
+func (r *authRepository) DeleteExpiredTokens(now time.Time) error {
+	if _, err := r.db.Exec("DELETE FROM tokens WHERE expires_at < ?", now); err != nil {
+		r.logger.Error("Failed to delete expired tokens", zap.Error(err))
+		return err
+	}
+	if _, err := r.db.Exec("DELETE FROM refresh_tokens WHERE expires_at < ?", now); err != nil {
+		r.logger.Error("Failed to delete expired refresh tokens", zap.Error(err))
+		return err
+	}
+	if _, err := r.db.Exec("DELETE FROM password_reset_tokens WHERE expires_at < ?", now); err != nil {
+		r.logger.Error("Failed to delete expired password reset tokens", zap.Error(err))
+		return err
+	}
+	r.logger.Info("Expired tokens deleted successfully")
+	return nil
+}
+
+const (
+	assignRoleByNameQuery = `INSERT INTO user_roles (user_id, role_id)
+		SELECT u.id, r.id FROM users u JOIN roles r ON r.name = ? WHERE u.username = ?`
+
+	// users.role хранит роль с наибольшим приоритетом: она попадает в JWT и ответ логина
+	syncPrimaryRoleQuery = `UPDATE users SET role = COALESCE((SELECT r.name FROM user_roles ur
+		JOIN roles r ON r.id = ur.role_id WHERE ur.user_id = users.id
+		ORDER BY r.priority DESC LIMIT 1), '') WHERE id = ?`
+)
+
+func (r *authRepository) GetRoles() ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.db.Select(&roles, "SELECT id, name, COALESCE(description, '') AS description FROM roles ORDER BY priority")
+	if err != nil {
+		r.logger.Error("Failed to get roles", zap.Error(err))
+		return nil, err
+	}
+	var rolePermissions []entity.RolePermission
+	err = r.db.Select(&rolePermissions, `SELECT rp.role_id, p.name AS permission FROM role_permissions rp
+		JOIN permissions p ON p.id = rp.permission_id ORDER BY p.name`)
+	if err != nil {
+		r.logger.Error("Failed to get role permissions", zap.Error(err))
+		return nil, err
+	}
+	for i := range roles {
+		roles[i].Permissions = []string{}
+		for _, rp := range rolePermissions {
+			if rp.RoleID == roles[i].ID {
+				roles[i].Permissions = append(roles[i].Permissions, rp.Permission)
+			}
+		}
+	}
+	return roles, nil
+}
+
+func (r *authRepository) GetRoleByName(name string) (entity.Role, error) {
+	var role entity.Role
+	err := r.db.Get(&role, "SELECT id, name, COALESCE(description, '') AS description FROM roles WHERE name=?", name)
+	if err != nil {
+		r.logger.Error("Failed to get role by name", zap.Error(err), zap.String("role", name))
+		return role, err
+	}
+	return role, nil
+}
+
+func (r *authRepository) GetUserRoles(userID int) ([]string, error) {
+	roles := []string{}
+	err := r.db.Select(&roles, `SELECT r.name FROM user_roles ur JOIN roles r ON r.id = ur.role_id
+		WHERE ur.user_id = ? ORDER BY r.priority DESC`, userID)
+	if err != nil {
+		r.logger.Error("Failed to get user roles", zap.Error(err), zap.Int("userID", userID))
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *authRepository) GetUserPermissions(userID int) ([]string, error) {
+	permissions := []string{}
+	err := r.db.Select(&permissions, `SELECT DISTINCT p.name FROM user_roles ur
+		JOIN role_permissions rp ON rp.role_id = ur.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE ur.user_id = ? ORDER BY p.name`, userID)
+	if err != nil {
+		r.logger.Error("Failed to get user permissions", zap.Error(err), zap.Int("userID", userID))
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *authRepository) AssignRole(userID, roleID int) error {
+	_, err := r.db.Exec("INSERT OR IGNORE INTO user_roles (user_id, role_id) VALUES (?, ?)", userID, roleID)
+	if err != nil {
+		r.logger.Error("Failed to assign role", zap.Error(err), zap.Int("userID", userID), zap.Int("roleID", roleID))
+		return err
+	}
+	if _, err := r.db.Exec(syncPrimaryRoleQuery, userID); err != nil {
+		r.logger.Error("Failed to update primary role", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+	r.logger.Info("Role assigned successfully", zap.Int("userID", userID), zap.Int("roleID", roleID))
+	return nil
+}
+
+func (r *authRepository) RemoveRole(userID, roleID int) error {
+	_, err := r.db.Exec("DELETE FROM user_roles WHERE user_id = ? AND role_id = ?", userID, roleID)
+	if err != nil {
+		r.logger.Error("Failed to remove role", zap.Error(err), zap.Int("userID", userID), zap.Int("roleID", roleID))
+		return err
+	}
+	if _, err := r.db.Exec(syncPrimaryRoleQuery, userID); err != nil {
+		r.logger.Error("Failed to update primary role", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+	r.logger.Info("Role removed successfully", zap.Int("userID", userID), zap.Int("roleID", roleID))
+	return nil
+}
+
+// profileColumns - поля профиля со статистикой постов и комментариев
+const profileColumns = `u.id, u.username, u.role, u.display_name, u.bio, u.avatar_url, u.signature, u.created_at,
+		(SELECT COUNT(*) FROM posts p WHERE p.author_id = u.id) AS post_count,
+		(SELECT COUNT(*) FROM comments c WHERE c.author_id = u.id) AS comment_count`
+
+func (r *authRepository) GetProfile(userID int) (entity.Profile, error) {
+	var profile entity.Profile
+	err := r.db.Get(&profile, `SELECT `+profileColumns+` FROM users u WHERE u.id = ?`, userID)
+	if err != nil {
+		r.logger.Error("Failed to get profile", zap.Error(err), zap.Int("userID", userID))
+		return profile, err
+	}
+	return profile, nil
+}
+
+func (r *authRepository) GetProfilesByIDs(userIDs []int) ([]entity.Profile, error) {
+	profiles := []entity.Profile{}
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+	query, args, err := sqlx.In(`SELECT `+profileColumns+` FROM users u WHERE u.id IN (?)`, userIDs)
+	if err != nil {
+		r.logger.Error("Failed to build profiles query", zap.Error(err))
+		return nil, err
+	}
+	if err := r.db.Select(&profiles, query, args...); err != nil {
+		r.logger.Error("Failed to get profiles", zap.Error(err), zap.Int("count", len(userIDs)))
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func (r *authRepository) UpdateProfile(profile entity.Profile) error {
+	_, err := r.db.Exec(`UPDATE users SET display_name = ?, bio = ?, avatar_url = ?, signature = ?,
+		updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		profile.DisplayName, profile.Bio, profile.AvatarURL, profile.Signature, profile.ID)
+	if err != nil {
+		r.logger.Error("Failed to update profile", zap.Error(err), zap.Int("userID", profile.ID))
+		return err
+	}
+	r.logger.Info("Profile updated successfully", zap.Int("userID", profile.ID))
+	return nil
+}
+
+func (r *authRepository) UpdatePassword(userID int, passwordHash string) error {
+	_, err := r.db.Exec("UPDATE users SET password = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", passwordHash, userID)
+	if err != nil {
+		r.logger.Error("Failed to update password", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+	r.logger.Info("Password updated successfully", zap.Int("userID", userID))
+	return nil
+}
+
+// SavePasswordResetToken сохраняет новый токен сброса, погасив ранее выданные неиспользованные токены пользователя
+func (r *authRepository) SavePasswordResetToken(token entity.PasswordResetToken) error {
+	_, err := r.db.Exec("UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = ? AND used_at IS NULL", token.UserID)
+	if err != nil {
+		r.logger.Error("Failed to invalidate previous password reset tokens", zap.Error(err), zap.Int("userID", token.UserID))
+		return err
+	}
+	_, err = r.db.Exec("INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES (?, ?, ?)",
+		token.UserID, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		r.logger.Error("Failed to save password reset token", zap.Error(err), zap.Int("userID", token.UserID))
+		return err
+	}
+	r.logger.Info("Password reset token saved successfully", zap.Int("userID", token.UserID))
+	return nil
+}
+
+func (r *authRepository) GetPasswordResetTokenByHash(tokenHash string) (entity.PasswordResetToken, error) {
+	var token entity.PasswordResetToken
+	err := r.db.Get(&token, "SELECT id, user_id, token_hash, expires_at, used_at, created_at FROM password_reset_tokens WHERE token_hash=?", tokenHash)
+	if err != nil {
+		r.logger.Error("Failed to get password reset token", zap.Error(err))
+		return token, err
+	}
+	return token, nil
+}
+
+// UsePasswordResetToken помечает токен использованным и сообщает, был ли он погашен именно этим вызовом
+func (r *authRepository) UsePasswordResetToken(id int) (bool, error) {
+	result, err := r.db.Exec("UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = ? AND used_at IS NULL", id)
+	if err != nil {
+		r.logger.Error("Failed to use password reset token", zap.Error(err), zap.Int("tokenID", id))
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Failed to get affected rows", zap.Error(err), zap.Int("tokenID", id))
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *authRepository) RecordLoginFailure(username, ipAddress string, at time.Time) error {
+	_, err := r.db.Exec("INSERT INTO login_failures (username, ip_address, created_at) VALUES (?, ?, ?)", username, ipAddress, at)
+	if err != nil {
+		r.logger.Error("Failed to record login failure", zap.Error(err), zap.String("username", username))
+		return err
+	}
+	return nil
+}
+
+const (
+	usernameLoginFailuresQuery = `SELECT f.created_at AS last_failure,
+		(SELECT COUNT(*) FROM login_failures WHERE username = ? AND created_at > ?) AS failures
+		FROM login_failures f WHERE f.username = ? AND f.created_at > ? ORDER BY f.created_at DESC LIMIT 1`
+
+	ipLoginFailuresQuery = `SELECT f.created_at AS last_failure,
+		(SELECT COUNT(*) FROM login_failures WHERE ip_address = ? AND created_at > ?) AS failures
+		FROM login_failures f WHERE f.ip_address = ? AND f.created_at > ? ORDER BY f.created_at DESC LIMIT 1`
+)
+
+// GetUsernameLoginFailures считает неудачные попытки входа под именем username начиная с since
+func (r *authRepository) GetUsernameLoginFailures(username string, since time.Time) (entity.LoginFailureStats, error) {
+	return r.getLoginFailures(usernameLoginFailuresQuery, username, since)
+}
+
+// GetIPLoginFailures считает неудачные попытки входа с адреса ipAddress начиная с since
+func (r *authRepository) GetIPLoginFailures(ipAddress string, since time.Time) (entity.LoginFailureStats, error) {
+	return r.getLoginFailures(ipLoginFailuresQuery, ipAddress, since)
+}
+
+func (r *authRepository) getLoginFailures(query, key string, since time.Time) (entity.LoginFailureStats, error) {
+	var stats entity.LoginFailureStats
+	err := r.db.Get(&stats, query, key, since, key, since)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.LoginFailureStats{}, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to get login failures", zap.Error(err))
+		return stats, err
+	}
+	return stats, nil
+}
+
+func (r *authRepository) ClearLoginFailures(username string) error {
+	_, err := r.db.Exec("DELETE FROM login_failures WHERE username = ?", username)
+	if err != nil {
+		r.logger.Error("Failed to clear login failures", zap.Error(err), zap.String("username", username))
+		return err
+	}
+	return nil
+}
+
+func (r *authRepository) DeleteLoginFailuresBefore(before time.Time) error {
+	if _, err := r.db.Exec("DELETE FROM login_failures WHERE created_at < ?", before); err != nil {
+		r.logger.Error("Failed to delete old login failures", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Engls/forum-project2/auth_service/internal/entity"
 	"github.com/Engls/forum-project2/auth_service/mocks"
+	"github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -20,6 +23,7 @@ func TestAuthRepository_Register_Success(t *testing.T) {
 	user := entity.User{Username: "testuser", Password: "hashedpassword", Role: "user"}
 
 	mockDB.On("Exec", "INSERT INTO users (username, password, role) VALUES (?, ?, ?)", user.Username, user.Password, user.Role).Return(sql.Result(nil), nil)
+	mockDB.On("Exec", assignRoleByNameQuery, user.Role, user.Username).Return(sql.Result(nil), nil)
 
 	authRepo := NewAuthRepository(mockDB, logger)
 
@@ -30,6 +34,26 @@ func TestAuthRepository_Register_Success(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestAuthRepository_Register_Duplicate(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	user := entity.User{Username: "TestUser", Password: "hashedpassword", Role: "user"}
+
+	mockDB.On("Exec", "INSERT INTO users (username, password, role) VALUES (?, ?, ?)", user.Username, user.Password, user.Role).
+		Return(nil, sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique})
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	err := authRepo.Register(user)
+
+	assert.ErrorIs(t, err, ErrDuplicate)
+
+	mockDB.AssertExpectations(t)
+}
+
 func TestAuthRepository_Register_Failure(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
@@ -57,7 +81,7 @@ func TestAuthRepository_GetUserByUsername_Success(t *testing.T) {
 
 	user := entity.User{ID: 1, Username: "testuser", Password: "hashedpassword", Role: "user"}
 
-	mockDB.On("Get", mock.Anything, "SELECT id, username, password, role FROM users WHERE username=?", user.Username).Run(func(args mock.Arguments) {
+	mockDB.On("Get", mock.Anything, "SELECT id, username, password, role FROM users WHERE username=? COLLATE NOCASE", user.Username).Run(func(args mock.Arguments) {
 		dest := args.Get(0).(*entity.User)
 		*dest = user
 	}).Return(nil)
@@ -80,7 +104,7 @@ func TestAuthRepository_GetUserByUsername_Failure(t *testing.T) {
 
 	username := "testuser"
 
-	mockDB.On("Get", mock.Anything, "SELECT id, username, password, role FROM users WHERE username=?", username).Return(errors.New("failed to get user by username"))
+	mockDB.On("Get", mock.Anything, "SELECT id, username, password, role FROM users WHERE username=? COLLATE NOCASE", username).Return(errors.New("failed to get user by username"))
 
 	authRepo := NewAuthRepository(mockDB, logger)
 
@@ -100,12 +124,13 @@ func TestAuthRepository_SaveToken_Success(t *testing.T) {
 
 	userID := 1
 	token := "valid.jwt.token"
+	expiresAt := time.Now().Add(time.Hour)
 
-	mockDB.On("Exec", "INSERT INTO tokens (user_id, token) VALUES (?, ?)", userID, token).Return(sql.Result(nil), nil)
+	mockDB.On("Exec", "INSERT INTO tokens (user_id, token, expires_at) VALUES (?, ?, ?)", userID, token, expiresAt).Return(sql.Result(nil), nil)
 
 	authRepo := NewAuthRepository(mockDB, logger)
 
-	err := authRepo.SaveToken(userID, token)
+	err := authRepo.SaveToken(userID, token, expiresAt)
 
 	assert.NoError(t, err)
 
@@ -120,14 +145,221 @@ func TestAuthRepository_SaveToken_Failure(t *testing.T) {
 
 	userID := 1
 	token := "valid.jwt.token"
+	expiresAt := time.Now().Add(time.Hour)
 
-	mockDB.On("Exec", "INSERT INTO tokens (user_id, token) VALUES (?, ?)", userID, token).Return(nil, errors.New("failed to save token"))
+	mockDB.On("Exec", "INSERT INTO tokens (user_id, token, expires_at) VALUES (?, ?, ?)", userID, token, expiresAt).Return(nil, errors.New("failed to save token"))
 
 	authRepo := NewAuthRepository(mockDB, logger)
 
-	err := authRepo.SaveToken(userID, token)
+	err := authRepo.SaveToken(userID, token, expiresAt)
 
 	assert.Error(t, err)
 
 	mockDB.AssertExpectations(t)
 }
+
+func TestAuthRepository_RevokeRefreshToken_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	tokenID := 1
+
+	mockDB.On("Exec", "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL", tokenID).Return(driver.RowsAffected(1), nil)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	revoked, err := authRepo.RevokeRefreshToken(tokenID)
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthRepository_RevokeRefreshToken_AlreadyRevoked(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	tokenID := 1
+
+	mockDB.On("Exec", "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL", tokenID).Return(driver.RowsAffected(0), nil)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	revoked, err := authRepo.RevokeRefreshToken(tokenID)
+
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthRepository_GetUserPermissions_Success(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	mockDB.On("Select", mock.AnythingOfType("*[]string"), mock.AnythingOfType("string"), 1).
+		Run(func(args mock.Arguments) {
+			dest := args.Get(0).(*[]string)
+			*dest = []string{"comment.create", "post.create"}
+		}).Return(nil)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	permissions, err := authRepo.GetUserPermissions(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"comment.create", "post.create"}, permissions)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthRepository_AssignRole_Success(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	mockDB.On("Exec", "INSERT OR IGNORE INTO user_roles (user_id, role_id) VALUES (?, ?)", 2, 3).Return(driver.RowsAffected(1), nil)
+	mockDB.On("Exec", syncPrimaryRoleQuery, 2).Return(driver.RowsAffected(1), nil)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	err := authRepo.AssignRole(2, 3)
+
+	assert.NoError(t, err)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthRepository_UpdateProfile_Failure(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	profile := entity.Profile{ID: 1, DisplayName: "Test", Bio: "bio", AvatarURL: "https://example.com/a.png", Signature: "sig"}
+
+	mockDB.On("Exec", mock.AnythingOfType("string"), profile.DisplayName, profile.Bio, profile.AvatarURL, profile.Signature, profile.ID).
+		Return(nil, errors.New("database is locked"))
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	err := authRepo.UpdateProfile(profile)
+
+	assert.Error(t, err)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthRepository_GetUsernamesByIDs_Success(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	mockDB.On("Select", mock.AnythingOfType("*[]entity.User"), "SELECT id, username FROM users WHERE id IN (?, ?, ?)", 1, 2, 3).
+		Run(func(args mock.Arguments) {
+			dest := args.Get(0).(*[]entity.User)
+			*dest = []entity.User{{ID: 1, Username: "alice"}, {ID: 3, Username: "bob"}}
+		}).Return(nil)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	usernames, err := authRepo.GetUsernamesByIDs([]int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "alice", 3: "bob"}, usernames)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthRepository_GetUsernamesByIDs_Empty(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	usernames, err := authRepo.GetUsernamesByIDs(nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, usernames)
+
+	mockDB.AssertNotCalled(t, "Select", mock.Anything, mock.Anything)
+}
+
+func TestAuthRepository_GetProfilesByIDs_Success(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	mockDB.On("Select", mock.AnythingOfType("*[]entity.Profile"), "SELECT "+profileColumns+" FROM users u WHERE u.id IN (?, ?)", 1, 2).
+		Run(func(args mock.Arguments) {
+			dest := args.Get(0).(*[]entity.Profile)
+			*dest = []entity.Profile{{ID: 1, Username: "alice", PostCount: 3}}
+		}).Return(nil)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	profiles, err := authRepo.GetProfilesByIDs([]int{1, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Profile{{ID: 1, Username: "alice", PostCount: 3}}, profiles)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthRepository_GetProfilesByIDs_Empty(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	profiles, err := authRepo.GetProfilesByIDs(nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, profiles)
+
+	mockDB.AssertNotCalled(t, "Select", mock.Anything, mock.Anything)
+}
+
+func TestAuthRepository_UsePasswordResetToken_AlreadyUsed(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	mockDB.On("Exec", "UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = ? AND used_at IS NULL", 5).
+		Return(driver.RowsAffected(0), nil)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	used, err := authRepo.UsePasswordResetToken(5)
+
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthRepository_GetUsernameLoginFailures_NoFailures(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockDB.On("Get", mock.AnythingOfType("*entity.LoginFailureStats"), usernameLoginFailuresQuery, "testuser", since, "testuser", since).
+		Return(sql.ErrNoRows)
+
+	authRepo := NewAuthRepository(mockDB, logger)
+
+	stats, err := authRepo.GetUsernameLoginFailures("testuser", since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.LoginFailureStats{}, stats)
+
+	mockDB.AssertExpectations(t)
+}
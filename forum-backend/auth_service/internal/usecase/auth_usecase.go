@@ -1,74 +1,784 @@
 package usecase
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	utils "github.com/Engls/EnglsJwt"
+	"github.com/Engls/forum-project2/auth_service/internal/authz"
 	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/Engls/forum-project2/auth_service/internal/notifier"
 	"github.com/Engls/forum-project2/auth_service/internal/repository"
+	"github.com/Engls/forum-project2/auth_service/internal/validation"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrForbidden           = errors.New("insufficient permissions")
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrOwnAdminRole        = errors.New("cannot revoke own admin role")
+	ErrInvalidProfile      = errors.New("invalid profile")
+	ErrInvalidPassword     = errors.New("invalid password")
+	ErrUsernameTaken       = errors.New("username already taken")
+	ErrInvalidResetToken   = errors.New("invalid or expired password reset token")
+	// ErrPasswordResetUnavailable - доставка токенов сброса пароля не настроена
+	ErrPasswordResetUnavailable = errors.New("password reset is not available")
+)
+
+const (
+	defaultAccessTokenTTL   = time.Hour
+	defaultRefreshTokenTTL  = 30 * 24 * time.Hour
+	defaultPasswordResetTTL = time.Hour
+
+	defaultLoginFailureWindow  = 15 * time.Minute
+	defaultLoginBaseDelay      = time.Second
+	defaultLoginMaxDelay       = 30 * time.Second
+	defaultLoginMaxFailures    = 5
+	defaultLoginMaxIPFailures  = 20
+	defaultLoginLockoutTimeout = 15 * time.Minute
+
+	maxDisplayNameLength = 50
+	maxBioLength         = 500
+	maxAvatarURLLength   = 500
+	maxSignatureLength   = 200
 )
 
 type AuthUsecase interface {
-	Register(username, password, role string) error
-	Login(username, password string) (string, error)
+	Register(username, password string) error
+	Login(username, password string, device entity.DeviceInfo) (string, error)
 	GetUserRole(username string) (string, error)
+	IssueRefreshToken(userID int, device entity.DeviceInfo) (string, error)
+	Refresh(refreshToken string, device entity.DeviceInfo) (string, string, error)
+	ValidateToken(token string) (entity.Token, error)
+	Logout(token, refreshToken string) error
+	LogoutAll(token string) error
+	DeleteExpiredTokens() error
+	Authorize(token, permission string) (entity.Token, error)
+	ListRoles() ([]entity.Role, error)
+	GetUserRoles(userID int) ([]string, error)
+	GrantRole(userID int, role string) error
+	// BootstrapAdmin выдает роль admin пользователю username, повторный вызов ничего не меняет
+	BootstrapAdmin(username string) error
+	RevokeRole(actorID, userID int, role string) error
+	GetProfile(userID int) (entity.Profile, error)
+	UpdateProfile(userID int, req entity.UpdateProfileRequest) (entity.Profile, error)
+	ChangePassword(userID int, oldPassword, newPassword string) error
+	RequestPasswordReset(username string) error
+	ConfirmPasswordReset(token, newPassword string) error
+	UnlockUser(userID int) error
+}
+
+// TokenConfig задает время жизни access, refresh токенов и токенов сброса пароля.
+// Нулевые значения заменяются значениями по умолчанию.
+type TokenConfig struct {
+	AccessTokenTTL   time.Duration
+	RefreshTokenTTL  time.Duration
+	PasswordResetTTL time.Duration
+}
+
+// LoginThrottleConfig задает защиту от перебора паролей. После каждой неудачной попытки
+// следующая разрешена не раньше чем через BaseDelay*2^(n-1) (но не больше MaxDelay),
+// а после MaxFailures неудач за Window вход блокируется на LockoutDuration.
+// Для IP-адреса действуют те же правила с порогом MaxIPFailures.
+// Нулевые значения заменяются значениями по умолчанию.
+type LoginThrottleConfig struct {
+	Window          time.Duration
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	MaxFailures     int
+	MaxIPFailures   int
+	LockoutDuration time.Duration
+}
+
+// ThrottledError возвращается, когда вход временно запрещен. Текст совпадает с ErrInvalidCredentials,
+// чтобы по ответу нельзя было отличить блокировку от неверного пароля.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return ErrInvalidCredentials.Error()
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return ErrInvalidCredentials
 }
 
 type authUsecase struct {
 	authRepo repository.AuthRepository
 	jwtUtil  *utils.JWTUtil
+	tokenCfg TokenConfig
+	policy   validation.PasswordPolicy
+	throttle LoginThrottleConfig
+	notifier notifier.Notifier
 	logger   *zap.Logger
 }
 
-func NewAuthUsecase(authRepo repository.AuthRepository, jwtUtil *utils.JWTUtil, logger *zap.Logger) AuthUsecase {
-	return &authUsecase{authRepo: authRepo, jwtUtil: jwtUtil, logger: logger}
+// NewAuthUsecase создает usecase. Если notifier не задан, сброс пароля недоступен.
+func NewAuthUsecase(
+	authRepo repository.AuthRepository,
+	jwtUtil *utils.JWTUtil,
+	tokenCfg TokenConfig,
+	policy validation.PasswordPolicy,
+	throttle LoginThrottleConfig,
+	n notifier.Notifier,
+	logger *zap.Logger,
+) AuthUsecase {
+	if tokenCfg.AccessTokenTTL <= 0 {
+		tokenCfg.AccessTokenTTL = defaultAccessTokenTTL
+	}
+	if tokenCfg.RefreshTokenTTL <= 0 {
+		tokenCfg.RefreshTokenTTL = defaultRefreshTokenTTL
+	}
+	if tokenCfg.PasswordResetTTL <= 0 {
+		tokenCfg.PasswordResetTTL = defaultPasswordResetTTL
+	}
+	if throttle.Window <= 0 {
+		throttle.Window = defaultLoginFailureWindow
+	}
+	if throttle.BaseDelay <= 0 {
+		throttle.BaseDelay = defaultLoginBaseDelay
+	}
+	if throttle.MaxDelay <= 0 {
+		throttle.MaxDelay = defaultLoginMaxDelay
+	}
+	if throttle.MaxFailures <= 0 {
+		throttle.MaxFailures = defaultLoginMaxFailures
+	}
+	if throttle.MaxIPFailures <= 0 {
+		throttle.MaxIPFailures = defaultLoginMaxIPFailures
+	}
+	if throttle.LockoutDuration <= 0 {
+		throttle.LockoutDuration = defaultLoginLockoutTimeout
+	}
+	if n == nil {
+		n = notifier.NewDisabledNotifier(logger)
+	}
+	return &authUsecase{
+		authRepo: authRepo,
+		jwtUtil:  jwtUtil,
+		tokenCfg: tokenCfg,
+		policy:   policy,
+		throttle: throttle,
+		notifier: n,
+		logger:   logger,
+	}
 }
 
-func (u *authUsecase) Register(username, password, role string) error {
+// Register проверяет имя и пароль и создает пользователя с ролью по умолчанию.
+// Остальные роли выдает администратор.
+func (u *authUsecase) Register(username, password string) error {
+	errs := &validation.Error{}
+	validation.ValidateUsername(username, errs)
+	u.policy.ValidatePassword("password", password, username, errs)
+	if err := errs.Err(); err != nil {
+		u.logger.Warn("Registration rejected", zap.Error(err), zap.String("username", username))
+		return err
+	}
+	exists, err := u.authRepo.UsernameExists(username)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrUsernameTaken
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		u.logger.Error("Failed to hash password", zap.Error(err), zap.String("username", username))
 		return err
 	}
-	user := entity.User{Username: username, Password: string(hashedPassword), Role: role}
+	user := entity.User{Username: username, Password: string(hashedPassword), Role: authz.RoleUser}
 	if err := u.authRepo.Register(user); err != nil {
 		u.logger.Error("Failed to register user", zap.Error(err), zap.String("username", username))
+		if errors.Is(err, repository.ErrDuplicate) {
+			return ErrUsernameTaken
+		}
 		return err
 	}
 	u.logger.Info("User registered successfully", zap.String("username", username))
 	return nil
 }
 
-func (u *authUsecase) Login(username, password string) (string, error) {
+// Login проверяет учетные данные с учетом ограничения частоты неудачных попыток
+// по имени пользователя и по IP-адресу.
+func (u *authUsecase) Login(username, password string, device entity.DeviceInfo) (string, error) {
+	now := time.Now().UTC()
+	retryAfter, err := u.loginRetryAfter(username, device.IPAddress, now)
+	if err != nil {
+		return "", err
+	}
+	if retryAfter > 0 {
+		u.logger.Warn("Login throttled",
+			zap.String("username", username),
+			zap.String("ip", device.IPAddress),
+			zap.Duration("retryAfter", retryAfter))
+		return "", &ThrottledError{RetryAfter: retryAfter}
+	}
+
 	user, err := u.authRepo.GetUserByUsername(username)
 	if err != nil {
 		u.logger.Error("Failed to get user by username", zap.Error(err), zap.String("username", username))
-		return "", errors.New("invalid credentials")
+		u.recordLoginFailure(username, device.IPAddress, now)
+		return "", ErrInvalidCredentials
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		u.logger.Error("Invalid password", zap.String("username", username))
-		return "", errors.New("invalid credentials")
+		u.recordLoginFailure(username, device.IPAddress, now)
+		return "", ErrInvalidCredentials
 	}
-	token, err := u.jwtUtil.GenerateToken(user.ID, user.Role)
-	if err != nil {
-		u.logger.Error("Failed to generate token", zap.Error(err), zap.String("username", username))
+	if err := u.authRepo.ClearLoginFailures(user.Username); err != nil {
 		return "", err
 	}
-	if err := u.authRepo.SaveToken(user.ID, token); err != nil {
-		u.logger.Error("Failed to save token", zap.Error(err), zap.String("username", username))
+	token, err := u.issueAccessToken(user)
+	if err != nil {
+		u.logger.Error("Failed to issue access token", zap.Error(err), zap.String("username", username))
 		return "", err
 	}
 	u.logger.Info("User logged in successfully", zap.String("username", username))
 	return token, nil
 }
 
+// loginRetryAfter возвращает, сколько еще нужно ждать до следующей попытки входа
+func (u *authUsecase) loginRetryAfter(username, ipAddress string, now time.Time) (time.Duration, error) {
+	since := now.Add(-u.throttle.Window)
+	userStats, err := u.authRepo.GetUsernameLoginFailures(username, since)
+	if err != nil {
+		return 0, err
+	}
+	retryAfter := u.backoff(userStats, u.throttle.MaxFailures, now)
+	if ipAddress != "" {
+		ipStats, err := u.authRepo.GetIPLoginFailures(ipAddress, since)
+		if err != nil {
+			return 0, err
+		}
+		if ipRetryAfter := u.backoff(ipStats, u.throttle.MaxIPFailures, now); ipRetryAfter > retryAfter {
+			retryAfter = ipRetryAfter
+		}
+	}
+	return retryAfter, nil
+}
+
+func (u *authUsecase) backoff(stats entity.LoginFailureStats, maxFailures int, now time.Time) time.Duration {
+	if stats.Failures == 0 {
+		return 0
+	}
+	var delay time.Duration
+	if stats.Failures >= maxFailures {
+		delay = u.throttle.LockoutDuration
+	} else {
+		delay = u.throttle.BaseDelay << (stats.Failures - 1)
+		if delay > u.throttle.MaxDelay || delay <= 0 {
+			delay = u.throttle.MaxDelay
+		}
+	}
+	if wait := stats.LastFailure.Add(delay).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+func (u *authUsecase) recordLoginFailure(username, ipAddress string, now time.Time) {
+	if err := u.authRepo.RecordLoginFailure(username, ipAddress, now); err != nil {
+		u.logger.Error("Failed to record login failure", zap.Error(err), zap.String("username", username))
+	}
+}
+
+// UnlockUser снимает блокировку входа, сбрасывая неудачные попытки пользователя
+func (u *authUsecase) UnlockUser(userID int) error {
+	user, err := u.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := u.authRepo.ClearLoginFailures(user.Username); err != nil {
+		return err
+	}
+	u.logger.Info("User login unlocked", zap.Int("userID", userID))
+	return nil
+}
+
+// IssueRefreshToken начинает новое семейство refresh-токенов при входе.
+func (u *authUsecase) IssueRefreshToken(userID int, device entity.DeviceInfo) (string, error) {
+	familyID, err := generateRandomString(16)
+	if err != nil {
+		u.logger.Error("Failed to generate token family", zap.Error(err), zap.Int("userID", userID))
+		return "", err
+	}
+	return u.saveRefreshToken(userID, familyID, device)
+}
+
+// Refresh обменивает refresh-токен на новый access-токен и заменяет сам refresh-токен.
+// Повторное предъявление уже замененного токена отзывает все его семейство.
+func (u *authUsecase) Refresh(refreshToken string, device entity.DeviceInfo) (string, string, error) {
+	stored, err := u.authRepo.GetRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		u.logger.Warn("Unknown refresh token", zap.Error(err))
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		u.logger.Warn("Refresh token reuse detected",
+			zap.Int("userID", stored.UserID),
+			zap.String("familyID", stored.FamilyID),
+			zap.String("ip", device.IPAddress))
+		if err := u.authRepo.RevokeRefreshTokenFamily(stored.FamilyID); err != nil {
+			return "", "", err
+		}
+		return "", "", ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		u.logger.Warn("Refresh token expired", zap.Int("userID", stored.UserID))
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	revoked, err := u.authRepo.RevokeRefreshToken(stored.ID)
+	if err != nil {
+		return "", "", err
+	}
+	if !revoked {
+		// Кто-то успел использовать этот токен между чтением и ротацией.
+		u.logger.Warn("Concurrent refresh token reuse detected", zap.String("familyID", stored.FamilyID))
+		if err := u.authRepo.RevokeRefreshTokenFamily(stored.FamilyID); err != nil {
+			return "", "", err
+		}
+		return "", "", ErrRefreshTokenReused
+	}
+
+	user, err := u.authRepo.GetUserByID(stored.UserID)
+	if err != nil {
+		u.logger.Error("Failed to get user for refresh", zap.Error(err), zap.Int("userID", stored.UserID))
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	accessToken, err := u.issueAccessToken(user)
+	if err != nil {
+		u.logger.Error("Failed to issue access token", zap.Error(err), zap.Int("userID", user.ID))
+		return "", "", err
+	}
+
+	newRefreshToken, err := u.saveRefreshToken(user.ID, stored.FamilyID, device)
+	if err != nil {
+		return "", "", err
+	}
+
+	u.logger.Info("Tokens refreshed successfully", zap.Int("userID", user.ID))
+	return accessToken, newRefreshToken, nil
+}
+
 func (u *authUsecase) GetUserRole(username string) (string, error) {
 	user, err := u.authRepo.GetUserByUsername(username)
 	if err != nil {
 		u.logger.Error("Failed to get user role", zap.Error(err), zap.String("username", username))
-		return "", errors.New("invalid credentials")
+		return "", ErrInvalidCredentials
 	}
 	u.logger.Info("User role retrieved successfully", zap.String("username", username), zap.String("role", user.Role))
 	return user.Role, nil
 }
+
+// ValidateToken проверяет подпись JWT, что токен выпущен этим сервисом и с тех пор
+// не отозван и не истек. Заодно загружаются права владельца.
+func (u *authUsecase) ValidateToken(token string) (entity.Token, error) {
+	stored, err := u.validateToken(token)
+	if err != nil {
+		return entity.Token{}, err
+	}
+	permissions, err := u.authRepo.GetUserPermissions(stored.UserID)
+	if err != nil {
+		u.logger.Error("Failed to get user permissions", zap.Error(err), zap.Int("userID", stored.UserID))
+		return entity.Token{}, err
+	}
+	stored.Permissions = permissions
+	return stored, nil
+}
+
+func (u *authUsecase) validateToken(token string) (entity.Token, error) {
+	userID, err := u.jwtUtil.GetUserIDFromToken(token)
+	if err != nil {
+		u.logger.Warn("Invalid token signature", zap.Error(err))
+		return entity.Token{}, ErrInvalidToken
+	}
+	stored, err := u.authRepo.GetToken(token)
+	if err != nil {
+		u.logger.Warn("Unknown token", zap.Error(err), zap.Int("userID", userID))
+		return entity.Token{}, ErrInvalidToken
+	}
+	if stored.UserID != userID {
+		u.logger.Warn("Token user mismatch", zap.Int("userID", userID), zap.Int("storedUserID", stored.UserID))
+		return entity.Token{}, ErrInvalidToken
+	}
+	if stored.RevokedAt != nil {
+		u.logger.Warn("Token revoked", zap.Int("userID", userID))
+		return entity.Token{}, ErrInvalidToken
+	}
+	if stored.ExpiresAt != nil && time.Now().After(*stored.ExpiresAt) {
+		u.logger.Warn("Token expired", zap.Int("userID", userID))
+		return entity.Token{}, ErrInvalidToken
+	}
+	return stored, nil
+}
+
+// Authorize проверяет токен и наличие у его владельца указанного права
+func (u *authUsecase) Authorize(token, permission string) (entity.Token, error) {
+	stored, err := u.ValidateToken(token)
+	if err != nil {
+		return entity.Token{}, err
+	}
+	if !authz.Can(stored.Permissions, permission) {
+		u.logger.Warn("Permission denied", zap.Int("userID", stored.UserID), zap.String("permission", permission))
+		return entity.Token{}, ErrForbidden
+	}
+	return stored, nil
+}
+
+// Logout отзывает access-токен и, если он передан, семейство refresh-токена, выпущенного вместе с ним.
+func (u *authUsecase) Logout(token, refreshToken string) error {
+	stored, err := u.validateToken(token)
+	if err != nil {
+		return err
+	}
+	if err := u.authRepo.RevokeToken(token); err != nil {
+		u.logger.Error("Failed to revoke token", zap.Error(err), zap.Int("userID", stored.UserID))
+		return err
+	}
+	if refreshToken != "" {
+		refresh, err := u.authRepo.GetRefreshTokenByHash(hashToken(refreshToken))
+		if err == nil && refresh.UserID == stored.UserID {
+			if err := u.authRepo.RevokeRefreshTokenFamily(refresh.FamilyID); err != nil {
+				return err
+			}
+		}
+	}
+	u.logger.Info("User logged out successfully", zap.Int("userID", stored.UserID))
+	return nil
+}
+
+func (u *authUsecase) LogoutAll(token string) error {
+	stored, err := u.validateToken(token)
+	if err != nil {
+		return err
+	}
+	if err := u.authRepo.RevokeAllUserTokens(stored.UserID); err != nil {
+		u.logger.Error("Failed to revoke all user tokens", zap.Error(err), zap.Int("userID", stored.UserID))
+		return err
+	}
+	u.logger.Info("User logged out from all devices", zap.Int("userID", stored.UserID))
+	return nil
+}
+
+func (u *authUsecase) DeleteExpiredTokens() error {
+	now := time.Now().UTC()
+	if err := u.authRepo.DeleteExpiredTokens(now); err != nil {
+		return err
+	}
+	return u.authRepo.DeleteLoginFailuresBefore(now.Add(-u.throttle.Window))
+}
+
+func (u *authUsecase) ListRoles() ([]entity.Role, error) {
+	return u.authRepo.GetRoles()
+}
+
+func (u *authUsecase) GetUserRoles(userID int) ([]string, error) {
+	if _, err := u.getUser(userID); err != nil {
+		return nil, err
+	}
+	return u.authRepo.GetUserRoles(userID)
+}
+
+func (u *authUsecase) GrantRole(userID int, role string) error {
+	if _, err := u.getUser(userID); err != nil {
+		return err
+	}
+	r, err := u.getRole(role)
+	if err != nil {
+		return err
+	}
+	if err := u.authRepo.AssignRole(userID, r.ID); err != nil {
+		u.logger.Error("Failed to grant role", zap.Error(err), zap.Int("userID", userID), zap.String("role", role))
+		return err
+	}
+	u.logger.Info("Role granted", zap.Int("userID", userID), zap.String("role", role))
+	return nil
+}
+
+func (u *authUsecase) BootstrapAdmin(username string) error {
+	user, err := u.authRepo.GetUserByUsername(username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return u.GrantRole(user.ID, authz.RoleAdmin)
+}
+
+// RevokeRole отзывает роль. Администратор не может снять роль admin сам с себя,
+// чтобы в системе не остаться без администраторов по ошибке.
+func (u *authUsecase) RevokeRole(actorID, userID int, role string) error {
+	if actorID == userID && role == authz.RoleAdmin {
+		return ErrOwnAdminRole
+	}
+	if _, err := u.getUser(userID); err != nil {
+		return err
+	}
+	r, err := u.getRole(role)
+	if err != nil {
+		return err
+	}
+	if err := u.authRepo.RemoveRole(userID, r.ID); err != nil {
+		u.logger.Error("Failed to revoke role", zap.Error(err), zap.Int("userID", userID), zap.String("role", role))
+		return err
+	}
+	u.logger.Info("Role revoked", zap.Int("userID", userID), zap.String("role", role))
+	return nil
+}
+
+func (u *authUsecase) GetProfile(userID int) (entity.Profile, error) {
+	profile, err := u.authRepo.GetProfile(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return profile, ErrUserNotFound
+	}
+	return profile, err
+}
+
+func (u *authUsecase) UpdateProfile(userID int, req entity.UpdateProfileRequest) (entity.Profile, error) {
+	if err := validateProfile(req); err != nil {
+		return entity.Profile{}, err
+	}
+	profile, err := u.GetProfile(userID)
+	if err != nil {
+		return entity.Profile{}, err
+	}
+	if req.DisplayName != nil {
+		profile.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.Bio != nil {
+		profile.Bio = strings.TrimSpace(*req.Bio)
+	}
+	if req.AvatarURL != nil {
+		profile.AvatarURL = strings.TrimSpace(*req.AvatarURL)
+	}
+	if req.Signature != nil {
+		profile.Signature = strings.TrimSpace(*req.Signature)
+	}
+	if err := u.authRepo.UpdateProfile(profile); err != nil {
+		u.logger.Error("Failed to update profile", zap.Error(err), zap.Int("userID", userID))
+		return entity.Profile{}, err
+	}
+	u.logger.Info("Profile updated", zap.Int("userID", userID))
+	return profile, nil
+}
+
+func validateProfile(req entity.UpdateProfileRequest) error {
+	checkLength := func(field string, value *string, max int) error {
+		if value != nil && utf8.RuneCountInString(strings.TrimSpace(*value)) > max {
+			return fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidProfile, field, max)
+		}
+		return nil
+	}
+	if err := checkLength("displayName", req.DisplayName, maxDisplayNameLength); err != nil {
+		return err
+	}
+	if err := checkLength("bio", req.Bio, maxBioLength); err != nil {
+		return err
+	}
+	if err := checkLength("avatarURL", req.AvatarURL, maxAvatarURLLength); err != nil {
+		return err
+	}
+	if err := checkLength("signature", req.Signature, maxSignatureLength); err != nil {
+		return err
+	}
+	if req.AvatarURL != nil && strings.TrimSpace(*req.AvatarURL) != "" {
+		parsed, err := url.Parse(strings.TrimSpace(*req.AvatarURL))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%w: avatarURL must be an http(s) URL", ErrInvalidProfile)
+		}
+	}
+	return nil
+}
+
+// ChangePassword меняет пароль после проверки старого и отзывает все токены пользователя
+func (u *authUsecase) ChangePassword(userID int, oldPassword, newPassword string) error {
+	user, err := u.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+		u.logger.Warn("Invalid old password", zap.Int("userID", userID))
+		return ErrInvalidPassword
+	}
+	if err := u.setPassword(user, newPassword); err != nil {
+		return err
+	}
+	u.logger.Info("Password changed", zap.Int("userID", userID))
+	return nil
+}
+
+// RequestPasswordReset выпускает одноразовый токен сброса и отправляет его через notifier.
+// Для неизвестного пользователя ошибка не возвращается, чтобы не раскрывать существование аккаунтов.
+func (u *authUsecase) RequestPasswordReset(username string) error {
+	user, err := u.authRepo.GetUserByUsername(username)
+	if errors.Is(err, sql.ErrNoRows) {
+		u.logger.Warn("Password reset requested for unknown user", zap.String("username", username))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	token, err := generateRandomString(32)
+	if err != nil {
+		u.logger.Error("Failed to generate password reset token", zap.Error(err), zap.Int("userID", user.ID))
+		return err
+	}
+	expiresAt := time.Now().UTC().Add(u.tokenCfg.PasswordResetTTL)
+	err = u.authRepo.SavePasswordResetToken(entity.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	if err := u.notifier.SendPasswordReset(user, token, expiresAt); err != nil {
+		if errors.Is(err, notifier.ErrDisabled) {
+			return ErrPasswordResetUnavailable
+		}
+		u.logger.Error("Failed to send password reset", zap.Error(err), zap.Int("userID", user.ID))
+		return err
+	}
+	return nil
+}
+
+// ConfirmPasswordReset гасит токен сброса, устанавливает новый пароль и отзывает все токены пользователя
+func (u *authUsecase) ConfirmPasswordReset(token, newPassword string) error {
+	stored, err := u.authRepo.GetPasswordResetTokenByHash(hashToken(token))
+	if err != nil {
+		u.logger.Warn("Unknown password reset token", zap.Error(err))
+		return ErrInvalidResetToken
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		u.logger.Warn("Password reset token used or expired", zap.Int("userID", stored.UserID))
+		return ErrInvalidResetToken
+	}
+	user, err := u.getUser(stored.UserID)
+	if err != nil {
+		return err
+	}
+	if err := u.validateNewPassword(user, newPassword); err != nil {
+		return err
+	}
+	used, err := u.authRepo.UsePasswordResetToken(stored.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+	if err := u.setPassword(user, newPassword); err != nil {
+		return err
+	}
+	u.logger.Info("Password reset completed", zap.Int("userID", stored.UserID))
+	return nil
+}
+
+func (u *authUsecase) validateNewPassword(user entity.User, password string) error {
+	errs := &validation.Error{}
+	u.policy.ValidatePassword("newPassword", password, user.Username, errs)
+	return errs.Err()
+}
+
+func (u *authUsecase) setPassword(user entity.User, password string) error {
+	if err := u.validateNewPassword(user, password); err != nil {
+		return err
+	}
+	userID := user.ID
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		u.logger.Error("Failed to hash password", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+	if err := u.authRepo.UpdatePassword(userID, string(hashedPassword)); err != nil {
+		return err
+	}
+	if err := u.authRepo.RevokeAllUserTokens(userID); err != nil {
+		u.logger.Error("Failed to revoke tokens after password change", zap.Error(err), zap.Int("userID", userID))
+		return err
+	}
+	return nil
+}
+
+func (u *authUsecase) getUser(userID int) (entity.User, error) {
+	user, err := u.authRepo.GetUserByID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (u *authUsecase) getRole(name string) (entity.Role, error) {
+	role, err := u.authRepo.GetRoleByName(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return role, ErrRoleNotFound
+	}
+	return role, err
+}
+
+func (u *authUsecase) issueAccessToken(user entity.User) (string, error) {
+	token, err := u.jwtUtil.GenerateToken(user.ID, user.Role)
+	if err != nil {
+		return "", err
+	}
+	if err := u.authRepo.SaveToken(user.ID, token, time.Now().UTC().Add(u.tokenCfg.AccessTokenTTL)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (u *authUsecase) saveRefreshToken(userID int, familyID string, device entity.DeviceInfo) (string, error) {
+	token, err := generateRandomString(32)
+	if err != nil {
+		u.logger.Error("Failed to generate refresh token", zap.Error(err), zap.Int("userID", userID))
+		return "", err
+	}
+	err = u.authRepo.SaveRefreshToken(entity.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		FamilyID:  familyID,
+		UserAgent: device.UserAgent,
+		IPAddress: device.IPAddress,
+		ExpiresAt: time.Now().UTC().Add(u.tokenCfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		u.logger.Error("Failed to save refresh token", zap.Error(err), zap.Int("userID", userID))
+		return "", err
+	}
+	return token, nil
+}
+
+func generateRandomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Refresh-токены хранятся в базе только в виде SHA-256 хеша.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,11 +1,15 @@
 package usecase
 
 import (
+	"database/sql"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Engls/EnglsJwt"
 	"github.com/Engls/forum-project2/auth_service/internal/entity"
+	"github.com/Engls/forum-project2/auth_service/internal/validation"
 	"github.com/Engls/forum-project2/auth_service/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -22,13 +26,15 @@ func TestAuthUsecase_Register_Success(t *testing.T) {
 
 	username := "testuser"
 	password := "password"
-	role := "user"
 
-	mockAuthRepo.On("Register", mock.AnythingOfType("entity.User")).Return(nil)
+	mockAuthRepo.On("UsernameExists", username).Return(false, nil)
+	mockAuthRepo.On("Register", mock.MatchedBy(func(user entity.User) bool {
+		return user.Username == username && user.Role == "user"
+	})).Return(nil)
 
-	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, logger)
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
 
-	err := authUsecase.Register(username, password, role)
+	err := authUsecase.Register(username, password)
 
 	assert.NoError(t, err)
 
@@ -44,19 +50,60 @@ func TestAuthUsecase_Register_Failure(t *testing.T) {
 
 	username := "testuser"
 	password := "password"
-	role := "user"
 
+	mockAuthRepo.On("UsernameExists", username).Return(false, nil)
 	mockAuthRepo.On("Register", mock.AnythingOfType("entity.User")).Return(errors.New("failed to register user"))
 
-	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, logger)
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
 
-	err := authUsecase.Register(username, password, role)
+	err := authUsecase.Register(username, password)
 
 	assert.Error(t, err)
 
 	mockAuthRepo.AssertExpectations(t)
 }
 
+func TestAuthUsecase_Register_ValidationFailed(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	policy := validation.PasswordPolicy{RequireDigit: true, Blocklist: map[string]struct{}{"password1": {}}}
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, policy, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.Register("x\n", "Password1")
+
+	var validationErr *validation.Error
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []entity.FieldError{
+		{Field: "username", Message: "must be between 3 and 32 characters"},
+		{Field: "username", Message: "may contain only latin letters, digits, '_', '.' and '-' and must start with a letter or digit"},
+		{Field: "password", Message: "is too common"},
+	}, validationErr.Fields)
+
+	mockAuthRepo.AssertNotCalled(t, "Register", mock.Anything)
+}
+
+func TestAuthUsecase_Register_UsernameTaken(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("UsernameExists", "TestUser").Return(true, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.Register("TestUser", "password")
+
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+
+	mockAuthRepo.AssertNotCalled(t, "Register", mock.Anything)
+}
+
 func TestAuthUsecase_Login_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
@@ -69,12 +116,15 @@ func TestAuthUsecase_Login_Success(t *testing.T) {
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	user := entity.User{ID: 1, Username: username, Password: string(hashedPassword), Role: "user"}
 
+	mockAuthRepo.On("GetUsernameLoginFailures", username, mock.AnythingOfType("time.Time")).Return(entity.LoginFailureStats{}, nil)
+	mockAuthRepo.On("GetIPLoginFailures", "10.0.0.1", mock.AnythingOfType("time.Time")).Return(entity.LoginFailureStats{}, nil)
 	mockAuthRepo.On("GetUserByUsername", username).Return(user, nil)
-	mockAuthRepo.On("SaveToken", user.ID, mock.Anything).Return(nil)
+	mockAuthRepo.On("ClearLoginFailures", username).Return(nil)
+	mockAuthRepo.On("SaveToken", user.ID, mock.Anything, mock.Anything).Return(nil)
 
-	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, logger)
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
 
-	resultToken, err := authUsecase.Login(username, password)
+	resultToken, err := authUsecase.Login(username, password, entity.DeviceInfo{IPAddress: "10.0.0.1"})
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, resultToken)
@@ -92,11 +142,14 @@ func TestAuthUsecase_Login_Failure_InvalidCredentials(t *testing.T) {
 	username := "testuser"
 	password := "password"
 
+	mockAuthRepo.On("GetUsernameLoginFailures", username, mock.AnythingOfType("time.Time")).Return(entity.LoginFailureStats{}, nil)
+	mockAuthRepo.On("GetIPLoginFailures", "10.0.0.1", mock.AnythingOfType("time.Time")).Return(entity.LoginFailureStats{}, nil)
 	mockAuthRepo.On("GetUserByUsername", username).Return(entity.User{}, errors.New("user not found"))
+	mockAuthRepo.On("RecordLoginFailure", username, "10.0.0.1", mock.AnythingOfType("time.Time")).Return(nil)
 
-	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, logger)
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
 
-	resultToken, err := authUsecase.Login(username, password)
+	resultToken, err := authUsecase.Login(username, password, entity.DeviceInfo{IPAddress: "10.0.0.1"})
 
 	assert.Error(t, err)
 	assert.Equal(t, "", resultToken)
@@ -116,18 +169,87 @@ func TestAuthUsecase_Login_Failure_InvalidPassword(t *testing.T) {
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("wrongpassword"), bcrypt.DefaultCost)
 	user := entity.User{ID: 1, Username: username, Password: string(hashedPassword), Role: "user"}
 
+	mockAuthRepo.On("GetUsernameLoginFailures", username, mock.AnythingOfType("time.Time")).Return(entity.LoginFailureStats{}, nil)
+	mockAuthRepo.On("GetIPLoginFailures", "10.0.0.1", mock.AnythingOfType("time.Time")).Return(entity.LoginFailureStats{}, nil)
 	mockAuthRepo.On("GetUserByUsername", username).Return(user, nil)
+	mockAuthRepo.On("RecordLoginFailure", username, "10.0.0.1", mock.AnythingOfType("time.Time")).Return(nil)
 
-	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, logger)
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
 
-	resultToken, err := authUsecase.Login(username, password)
+	resultToken, err := authUsecase.Login(username, password, entity.DeviceInfo{IPAddress: "10.0.0.1"})
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
 	assert.Equal(t, "", resultToken)
 
 	mockAuthRepo.AssertExpectations(t)
 }
 
+func TestAuthUsecase_Login_Backoff(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	// После трех неудач следующая попытка разрешена через 4 секунды после последней
+	mockAuthRepo.On("GetUsernameLoginFailures", "testuser", mock.AnythingOfType("time.Time")).
+		Return(entity.LoginFailureStats{Failures: 3, LastFailure: time.Now().UTC()}, nil)
+	mockAuthRepo.On("GetIPLoginFailures", "10.0.0.1", mock.AnythingOfType("time.Time")).Return(entity.LoginFailureStats{}, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	_, err := authUsecase.Login("testuser", "password", entity.DeviceInfo{IPAddress: "10.0.0.1"})
+
+	var throttled *ThrottledError
+	assert.ErrorAs(t, err, &throttled)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.EqualError(t, err, "invalid credentials")
+	assert.InDelta(t, 4*time.Second, throttled.RetryAfter, float64(time.Second))
+
+	mockAuthRepo.AssertNotCalled(t, "GetUserByUsername", mock.Anything)
+	mockAuthRepo.AssertNotCalled(t, "RecordLoginFailure", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Login_IPLockout(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetUsernameLoginFailures", "testuser", mock.AnythingOfType("time.Time")).Return(entity.LoginFailureStats{}, nil)
+	mockAuthRepo.On("GetIPLoginFailures", "10.0.0.1", mock.AnythingOfType("time.Time")).
+		Return(entity.LoginFailureStats{Failures: 3, LastFailure: time.Now().UTC().Add(-time.Minute)}, nil)
+
+	throttle := LoginThrottleConfig{MaxIPFailures: 3, LockoutDuration: 10 * time.Minute}
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, throttle, nil, logger)
+
+	_, err := authUsecase.Login("testuser", "password", entity.DeviceInfo{IPAddress: "10.0.0.1"})
+
+	var throttled *ThrottledError
+	assert.ErrorAs(t, err, &throttled)
+	assert.InDelta(t, 9*time.Minute, throttled.RetryAfter, float64(time.Second))
+}
+
+func TestAuthUsecase_UnlockUser(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetUserByID", 1).Return(entity.User{ID: 1, Username: "testuser"}, nil)
+	mockAuthRepo.On("ClearLoginFailures", "testuser").Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.UnlockUser(1)
+
+	assert.NoError(t, err)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
 func TestAuthUsecase_GetUserRole_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
@@ -140,7 +262,7 @@ func TestAuthUsecase_GetUserRole_Success(t *testing.T) {
 
 	mockAuthRepo.On("GetUserByUsername", username).Return(user, nil)
 
-	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, logger)
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
 
 	role, err := authUsecase.GetUserRole(username)
 
@@ -161,7 +283,7 @@ func TestAuthUsecase_GetUserRole_Failure(t *testing.T) {
 
 	mockAuthRepo.On("GetUserByUsername", username).Return(entity.User{}, errors.New("user not found"))
 
-	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, logger)
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
 
 	role, err := authUsecase.GetUserRole(username)
 
@@ -170,3 +292,541 @@ func TestAuthUsecase_GetUserRole_Failure(t *testing.T) {
 
 	mockAuthRepo.AssertExpectations(t)
 }
+
+func TestAuthUsecase_Refresh_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	refreshToken := "refresh-token"
+	stored := entity.RefreshToken{ID: 5, UserID: 1, TokenHash: hashToken(refreshToken), FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+	user := entity.User{ID: 1, Username: "testuser", Role: "user"}
+
+	mockAuthRepo.On("GetRefreshTokenByHash", hashToken(refreshToken)).Return(stored, nil)
+	mockAuthRepo.On("RevokeRefreshToken", stored.ID).Return(true, nil)
+	mockAuthRepo.On("GetUserByID", user.ID).Return(user, nil)
+	mockAuthRepo.On("SaveToken", user.ID, mock.Anything, mock.Anything).Return(nil)
+	mockAuthRepo.On("SaveRefreshToken", mock.MatchedBy(func(token entity.RefreshToken) bool {
+		return token.UserID == user.ID && token.FamilyID == stored.FamilyID && token.TokenHash != stored.TokenHash
+	})).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	accessToken, newRefreshToken, err := authUsecase.Refresh(refreshToken, entity.DeviceInfo{})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, accessToken)
+	assert.NotEmpty(t, newRefreshToken)
+	assert.NotEqual(t, refreshToken, newRefreshToken)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Refresh_ReuseRevokesFamily(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	refreshToken := "refresh-token"
+	revokedAt := time.Now().Add(-time.Minute)
+	stored := entity.RefreshToken{ID: 5, UserID: 1, TokenHash: hashToken(refreshToken), FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+
+	mockAuthRepo.On("GetRefreshTokenByHash", hashToken(refreshToken)).Return(stored, nil)
+	mockAuthRepo.On("RevokeRefreshTokenFamily", stored.FamilyID).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	accessToken, newRefreshToken, err := authUsecase.Refresh(refreshToken, entity.DeviceInfo{})
+
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	assert.Empty(t, accessToken)
+	assert.Empty(t, newRefreshToken)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Refresh_Expired(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	refreshToken := "refresh-token"
+	stored := entity.RefreshToken{ID: 5, UserID: 1, TokenHash: hashToken(refreshToken), FamilyID: "family", ExpiresAt: time.Now().Add(-time.Hour)}
+
+	mockAuthRepo.On("GetRefreshTokenByHash", hashToken(refreshToken)).Return(stored, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	_, _, err := authUsecase.Refresh(refreshToken, entity.DeviceInfo{})
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_ValidateToken_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	token, _ := jwtUtil.GenerateToken(1, "user")
+	expiresAt := time.Now().Add(time.Hour)
+	stored := entity.Token{ID: 3, UserID: 1, Token: token, ExpiresAt: &expiresAt, Username: "testuser", Role: "user"}
+
+	mockAuthRepo.On("GetToken", token).Return(stored, nil)
+	mockAuthRepo.On("GetUserPermissions", 1).Return([]string{"comment.create", "post.create"}, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	result, err := authUsecase.ValidateToken(token)
+
+	assert.NoError(t, err)
+	stored.Permissions = []string{"comment.create", "post.create"}
+	assert.Equal(t, stored, result)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_ValidateToken_Revoked(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	token, _ := jwtUtil.GenerateToken(1, "user")
+	revokedAt := time.Now()
+	stored := entity.Token{ID: 3, UserID: 1, Token: token, RevokedAt: &revokedAt}
+
+	mockAuthRepo.On("GetToken", token).Return(stored, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	_, err := authUsecase.ValidateToken(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Logout_RevokesRefreshFamily(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	token, _ := jwtUtil.GenerateToken(1, "user")
+	refreshToken := "refresh-token"
+
+	mockAuthRepo.On("GetToken", token).Return(entity.Token{ID: 3, UserID: 1, Token: token}, nil)
+	mockAuthRepo.On("RevokeToken", token).Return(nil)
+	mockAuthRepo.On("GetRefreshTokenByHash", hashToken(refreshToken)).Return(entity.RefreshToken{ID: 5, UserID: 1, FamilyID: "family"}, nil)
+	mockAuthRepo.On("RevokeRefreshTokenFamily", "family").Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.Logout(token, refreshToken)
+
+	assert.NoError(t, err)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_LogoutAll_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	token, _ := jwtUtil.GenerateToken(1, "user")
+
+	mockAuthRepo.On("GetToken", token).Return(entity.Token{ID: 3, UserID: 1, Token: token}, nil)
+	mockAuthRepo.On("RevokeAllUserTokens", 1).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.LogoutAll(token)
+
+	assert.NoError(t, err)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Authorize_Forbidden(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	token, _ := jwtUtil.GenerateToken(1, "user")
+
+	mockAuthRepo.On("GetToken", token).Return(entity.Token{ID: 3, UserID: 1, Token: token}, nil)
+	mockAuthRepo.On("GetUserPermissions", 1).Return([]string{"comment.create", "post.create"}, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	_, err := authUsecase.Authorize(token, "role.manage")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_GrantRole_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetUserByID", 2).Return(entity.User{ID: 2, Username: "testuser", Role: "user"}, nil)
+	mockAuthRepo.On("GetRoleByName", "moderator").Return(entity.Role{ID: 2, Name: "moderator"}, nil)
+	mockAuthRepo.On("AssignRole", 2, 2).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.GrantRole(2, "moderator")
+
+	assert.NoError(t, err)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_GrantRole_UnknownRole(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetUserByID", 2).Return(entity.User{ID: 2, Username: "testuser", Role: "user"}, nil)
+	mockAuthRepo.On("GetRoleByName", "superuser").Return(entity.Role{}, sql.ErrNoRows)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.GrantRole(2, "superuser")
+
+	assert.ErrorIs(t, err, ErrRoleNotFound)
+
+	mockAuthRepo.AssertNotCalled(t, "AssignRole", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_BootstrapAdmin_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetUserByUsername", "root").Return(entity.User{ID: 1, Username: "root", Role: "user"}, nil)
+	mockAuthRepo.On("GetUserByID", 1).Return(entity.User{ID: 1, Username: "root", Role: "user"}, nil)
+	mockAuthRepo.On("GetRoleByName", "admin").Return(entity.Role{ID: 3, Name: "admin"}, nil)
+	mockAuthRepo.On("AssignRole", 1, 3).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.BootstrapAdmin("root")
+
+	assert.NoError(t, err)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_BootstrapAdmin_UnknownUser(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetUserByUsername", "ghost").Return(entity.User{}, sql.ErrNoRows)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.BootstrapAdmin("ghost")
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	mockAuthRepo.AssertNotCalled(t, "AssignRole", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_RevokeRole_OwnAdminRole(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.RevokeRole(1, 1, "admin")
+
+	assert.ErrorIs(t, err, ErrOwnAdminRole)
+
+	mockAuthRepo.AssertNotCalled(t, "RemoveRole", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_UpdateProfile_Partial(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	current := entity.Profile{ID: 1, Username: "testuser", Bio: "old bio", Signature: "old signature"}
+	displayName := "  Test User  "
+	expected := current
+	expected.DisplayName = "Test User"
+
+	mockAuthRepo.On("GetProfile", 1).Return(current, nil)
+	mockAuthRepo.On("UpdateProfile", expected).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	profile, err := authUsecase.UpdateProfile(1, entity.UpdateProfileRequest{DisplayName: &displayName})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, profile)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_UpdateProfile_InvalidAvatarURL(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	avatarURL := "javascript:alert(1)"
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	_, err := authUsecase.UpdateProfile(1, entity.UpdateProfileRequest{AvatarURL: &avatarURL})
+
+	assert.ErrorIs(t, err, ErrInvalidProfile)
+
+	mockAuthRepo.AssertNotCalled(t, "UpdateProfile", mock.Anything)
+}
+
+func TestAuthUsecase_UpdateProfile_BioTooLong(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	bio := strings.Repeat("я", maxBioLength+1)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	_, err := authUsecase.UpdateProfile(1, entity.UpdateProfileRequest{Bio: &bio})
+
+	assert.ErrorIs(t, err, ErrInvalidProfile)
+
+	mockAuthRepo.AssertNotCalled(t, "GetProfile", mock.Anything)
+}
+
+func TestAuthUsecase_GetProfile_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetProfile", 42).Return(entity.Profile{}, sql.ErrNoRows)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	_, err := authUsecase.GetProfile(42)
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_ChangePassword_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("oldpass"), bcrypt.MinCost)
+	mockAuthRepo.On("GetUserByID", 1).Return(entity.User{ID: 1, Username: "testuser", Password: string(hashed)}, nil)
+	mockAuthRepo.On("UpdatePassword", 1, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("newpass1")) == nil
+	})).Return(nil)
+	mockAuthRepo.On("RevokeAllUserTokens", 1).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.ChangePassword(1, "oldpass", "newpass1")
+
+	assert.NoError(t, err)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_ChangePassword_WrongOldPassword(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("oldpass"), bcrypt.MinCost)
+	mockAuthRepo.On("GetUserByID", 1).Return(entity.User{ID: 1, Username: "testuser", Password: string(hashed)}, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.ChangePassword(1, "wrong", "newpass1")
+
+	assert.ErrorIs(t, err, ErrInvalidPassword)
+
+	mockAuthRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	mockAuthRepo.AssertNotCalled(t, "RevokeAllUserTokens", mock.Anything)
+}
+
+func TestAuthUsecase_RequestPasswordReset_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	mockNotifier := new(mocks.Notifier)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	user := entity.User{ID: 1, Username: "testuser"}
+	var savedHash, sentToken string
+	mockAuthRepo.On("GetUserByUsername", "testuser").Return(user, nil)
+	mockAuthRepo.On("SavePasswordResetToken", mock.MatchedBy(func(token entity.PasswordResetToken) bool {
+		return token.UserID == 1 && token.ExpiresAt.After(time.Now())
+	})).Run(func(args mock.Arguments) {
+		savedHash = args.Get(0).(entity.PasswordResetToken).TokenHash
+	}).Return(nil)
+	mockNotifier.On("SendPasswordReset", user, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { sentToken = args.String(1) }).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, mockNotifier, logger)
+
+	err := authUsecase.RequestPasswordReset("testuser")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sentToken)
+	assert.Equal(t, hashToken(sentToken), savedHash)
+
+	mockAuthRepo.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestAuthUsecase_RequestPasswordReset_UnknownUser(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	mockNotifier := new(mocks.Notifier)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetUserByUsername", "ghost").Return(entity.User{}, sql.ErrNoRows)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, mockNotifier, logger)
+
+	err := authUsecase.RequestPasswordReset("ghost")
+
+	assert.NoError(t, err)
+
+	mockNotifier.AssertNotCalled(t, "SendPasswordReset", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_RequestPasswordReset_NotifierDisabled(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetUserByUsername", "testuser").Return(entity.User{ID: 1, Username: "testuser"}, nil)
+	mockAuthRepo.On("SavePasswordResetToken", mock.Anything).Return(nil)
+
+	// Без notifier доставка отключена, токен никуда не пишется
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.RequestPasswordReset("testuser")
+
+	assert.ErrorIs(t, err, ErrPasswordResetUnavailable)
+}
+
+func TestAuthUsecase_ConfirmPasswordReset_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetPasswordResetTokenByHash", hashToken("reset-token")).
+		Return(entity.PasswordResetToken{ID: 5, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockAuthRepo.On("GetUserByID", 1).Return(entity.User{ID: 1, Username: "testuser"}, nil)
+	mockAuthRepo.On("UsePasswordResetToken", 5).Return(true, nil)
+	mockAuthRepo.On("UpdatePassword", 1, mock.AnythingOfType("string")).Return(nil)
+	mockAuthRepo.On("RevokeAllUserTokens", 1).Return(nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.ConfirmPasswordReset("reset-token", "newpass1")
+
+	assert.NoError(t, err)
+
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_ConfirmPasswordReset_Expired(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetPasswordResetTokenByHash", hashToken("reset-token")).
+		Return(entity.PasswordResetToken{ID: 5, UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.ConfirmPasswordReset("reset-token", "newpass1")
+
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+
+	mockAuthRepo.AssertNotCalled(t, "UsePasswordResetToken", mock.Anything)
+	mockAuthRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_ConfirmPasswordReset_AlreadyUsed(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockAuthRepo := new(mocks.AuthRepository)
+	jwtUtil := EnglsJwt.NewJWTUtil("secret")
+
+	mockAuthRepo.On("GetPasswordResetTokenByHash", hashToken("reset-token")).
+		Return(entity.PasswordResetToken{ID: 5, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockAuthRepo.On("GetUserByID", 1).Return(entity.User{ID: 1, Username: "testuser"}, nil)
+	mockAuthRepo.On("UsePasswordResetToken", 5).Return(false, nil)
+
+	authUsecase := NewAuthUsecase(mockAuthRepo, jwtUtil, TokenConfig{}, validation.PasswordPolicy{}, LoginThrottleConfig{}, nil, logger)
+
+	err := authUsecase.ConfirmPasswordReset("reset-token", "newpass1")
+
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+
+	mockAuthRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Engls/forum-project2/auth_service/internal/entity"
+)
+
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 32
+
+	DefaultMinPasswordLength = 8
+	// bcrypt учитывает только первые 72 байта пароля
+	MaxPasswordBytes = 72
+)
+
+// Error - ошибка валидации с описанием проблем по каждому полю
+type Error struct {
+	Fields []entity.FieldError
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+func (e *Error) Add(field, message string) {
+	e.Fields = append(e.Fields, entity.FieldError{Field: field, Message: message})
+}
+
+// Err возвращает nil, если ошибок не накопилось
+func (e *Error) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateUsername проверяет длину и набор символов: латиница, цифры, '_', '.', '-',
+// первый символ - буква или цифра
+func ValidateUsername(username string, errs *Error) {
+	if username == "" {
+		errs.Add("username", "is required")
+		return
+	}
+	if n := utf8.RuneCountInString(username); n < MinUsernameLength || n > MaxUsernameLength {
+		errs.Add("username", fmt.Sprintf("must be between %d and %d characters", MinUsernameLength, MaxUsernameLength))
+	}
+	for i, r := range username {
+		if r > unicode.MaxASCII || !(isAlnum(r) || (i > 0 && strings.ContainsRune("_.-", r))) {
+			errs.Add("username", "may contain only latin letters, digits, '_', '.' and '-' and must start with a letter or digit")
+			return
+		}
+	}
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// PasswordPolicy - требования к паролю. Blocklist хранит распространенные пароли в нижнем регистре.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	Blocklist     map[string]struct{}
+}
+
+// ValidatePassword проверяет пароль по политике и записывает нарушения в поле field.
+// username нужен, чтобы запретить пароль, совпадающий с логином.
+func (p PasswordPolicy) ValidatePassword(field, password, username string, errs *Error) {
+	if password == "" {
+		errs.Add(field, "is required")
+		return
+	}
+	minLength := p.MinLength
+	if minLength <= 0 {
+		minLength = DefaultMinPasswordLength
+	}
+	if utf8.RuneCountInString(password) < minLength {
+		errs.Add(field, fmt.Sprintf("must be at least %d characters", minLength))
+	}
+	if len(password) > MaxPasswordBytes {
+		errs.Add(field, fmt.Sprintf("must be at most %d bytes", MaxPasswordBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		errs.Add(field, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		errs.Add(field, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		errs.Add(field, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		errs.Add(field, "must contain a special character")
+	}
+
+	lower := strings.ToLower(password)
+	if username != "" && lower == strings.ToLower(username) {
+		errs.Add(field, "must not match the username")
+	}
+	if _, blocked := p.Blocklist[lower]; blocked {
+		errs.Add(field, "is too common")
+	}
+}
+
+// LoadBlocklist читает список распространенных паролей: по одному в строке, '#' - комментарий
+func LoadBlocklist(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	blocklist := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		blocklist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return blocklist, nil
+}
@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		valid    bool
+	}{
+		{"user123", true},
+		{"john.doe-1_x", true},
+		{"ab", false},
+		{"", false},
+		{"_leading", false},
+		{"with space", false},
+		{"иван", false},
+		{"tab\tname", false},
+		{"a123456789012345678901234567890123", false},
+	}
+	for _, tt := range tests {
+		errs := &Error{}
+		ValidateUsername(tt.username, errs)
+		assert.Equal(t, tt.valid, errs.Err() == nil, tt.username)
+	}
+}
+
+func TestPasswordPolicy_ValidatePassword(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:     10,
+		RequireUpper:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		Blocklist:     map[string]struct{}{"qwerty123!": {}},
+	}
+
+	errs := &Error{}
+	policy.ValidatePassword("password", "Str0ng!Passw0rd", "user", errs)
+	assert.NoError(t, errs.Err())
+
+	errs = &Error{}
+	policy.ValidatePassword("password", "short", "user", errs)
+	assert.Len(t, errs.Fields, 4)
+
+	errs = &Error{}
+	policy.ValidatePassword("password", "QWERTY123!", "user", errs)
+	assert.Equal(t, "is too common", errs.Fields[len(errs.Fields)-1].Message)
+
+	errs = &Error{}
+	policy.ValidatePassword("newPassword", "Username1!", "username1!", errs)
+	assert.Equal(t, "newPassword", errs.Fields[0].Field)
+	assert.Equal(t, "must not match the username", errs.Fields[0].Message)
+}
+
+func TestLoadBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# comment\nPassword\n\n123456\n"), 0600))
+
+	blocklist, err := LoadBlocklist(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"password": {}, "123456": {}}, blocklist)
+}
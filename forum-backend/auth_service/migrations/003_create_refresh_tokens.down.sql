@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+DROP TABLE IF EXISTS refresh_tokens;
+
+ALTER TABLE tokens DROP COLUMN revoked_at;
+ALTER TABLE tokens DROP COLUMN expires_at;
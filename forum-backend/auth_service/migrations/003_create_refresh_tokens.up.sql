@@ -0,0 +1,17 @@
+ALTER TABLE tokens ADD COLUMN expires_at DATETIME;
+ALTER TABLE tokens ADD COLUMN revoked_at DATETIME;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+                                              id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                              user_id INTEGER NOT NULL,
+                                              token_hash VARCHAR(64) NOT NULL UNIQUE,
+                                              family_id VARCHAR(32) NOT NULL,
+                                              user_agent TEXT,
+                                              ip_address VARCHAR(64),
+                                              expires_at DATETIME NOT NULL,
+                                              revoked_at DATETIME,
+                                              created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+                                              FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS roles (
+                                     id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                     name VARCHAR(64) NOT NULL UNIQUE,
+    description TEXT,
+    priority INTEGER NOT NULL DEFAULT 0
+    );
+
+CREATE TABLE IF NOT EXISTS permissions (
+                                           id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                           name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT
+    );
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+                                                role_id INTEGER NOT NULL,
+                                                permission_id INTEGER NOT NULL,
+                                                PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+    );
+
+CREATE TABLE IF NOT EXISTS user_roles (
+                                          user_id INTEGER NOT NULL,
+                                          role_id INTEGER NOT NULL,
+                                          created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+                                          PRIMARY KEY (user_id, role_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+    );
+
+INSERT INTO roles (name, description, priority) VALUES
+    ('user', 'Обычный пользователь', 10),
+    ('moderator', 'Модератор', 50),
+    ('admin', 'Администратор', 100);
+
+INSERT INTO permissions (name, description) VALUES
+    ('post.create', 'Создание постов'),
+    ('post.update.any', 'Редактирование любых постов'),
+    ('post.delete.any', 'Удаление любых постов'),
+    ('comment.create', 'Создание комментариев'),
+    ('comment.moderate', 'Модерация любых комментариев'),
+    ('role.manage', 'Назначение и отзыв ролей');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+WHERE (r.name = 'user' AND p.name IN ('post.create', 'comment.create'))
+   OR (r.name = 'moderator' AND p.name IN ('post.create', 'comment.create', 'post.delete.any', 'comment.moderate'))
+   OR r.name = 'admin';
+
+-- users.role раньше задавал сам клиент при регистрации, поэтому admin и moderator оттуда не переносятся:
+-- все существующие пользователи получают роль 'user'. Первого администратора назначает INITIAL_ADMIN
+UPDATE users SET role = 'user';
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u JOIN roles r ON r.name = 'user';
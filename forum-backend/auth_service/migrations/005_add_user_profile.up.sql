@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN display_name VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN bio TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN avatar_url VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN signature VARCHAR(200) NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+                                                     id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                                     user_id INTEGER NOT NULL,
+                                                     token_hash VARCHAR(64) NOT NULL UNIQUE,
+                                                     expires_at DATETIME NOT NULL,
+                                                     used_at DATETIME,
+                                                     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+                                                     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
@@ -0,0 +1,3 @@
+-- Имена пользователей уникальны без учета регистра.
+-- Если в базе уже есть имена, отличающиеся только регистром, их нужно переименовать до применения миграции.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_nocase ON users(username COLLATE NOCASE);
@@ -0,0 +1,6 @@
+DELETE FROM role_permissions WHERE permission_id IN (SELECT id FROM permissions WHERE name = 'user.unlock');
+DELETE FROM permissions WHERE name = 'user.unlock';
+
+DROP INDEX IF EXISTS idx_login_failures_ip;
+DROP INDEX IF EXISTS idx_login_failures_username;
+DROP TABLE IF EXISTS login_failures;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS login_failures (
+                                              id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                              username VARCHAR(255) NOT NULL COLLATE NOCASE,
+                                              ip_address VARCHAR(64) NOT NULL DEFAULT '',
+                                              created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_failures_username ON login_failures(username, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_failures_ip ON login_failures(ip_address, created_at);
+
+INSERT INTO permissions (name, description) VALUES ('user.unlock', 'Снятие блокировки входа');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+WHERE r.name IN ('moderator', 'admin') AND p.name = 'user.unlock';
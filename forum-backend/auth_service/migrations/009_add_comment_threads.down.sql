@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_comments_parent;
+DROP INDEX IF EXISTS idx_comments_post_parent;
+
+ALTER TABLE comments DROP COLUMN depth;
+ALTER TABLE comments DROP COLUMN parent_id;
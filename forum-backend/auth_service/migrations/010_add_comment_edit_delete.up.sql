@@ -0,0 +1,2 @@
+ALTER TABLE comments ADD COLUMN edited_at DATETIME;
+ALTER TABLE comments ADD COLUMN deleted_at DATETIME;
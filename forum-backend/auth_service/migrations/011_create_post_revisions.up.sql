@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+                                              id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                              post_id INTEGER NOT NULL,
+                                              revision INTEGER NOT NULL,
+                                              title TEXT,
+                                              content TEXT,
+                                              editor_id INTEGER,
+                                              created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                              UNIQUE (post_id, revision),
+                                              FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+                                              FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO permissions (name, description) VALUES ('post.history', 'Просмотр истории правок постов');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+WHERE r.name IN ('moderator', 'admin') AND p.name = 'post.history';
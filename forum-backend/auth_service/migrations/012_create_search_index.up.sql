@@ -0,0 +1,58 @@
+-- Полнотекстовый поиск (FTS5). Индексы хранят только токены, тексты берутся из posts и comments.
+-- go-sqlite3 собирает FTS5 только с тегом sqlite_fts5: go build -tags sqlite_fts5
+CREATE VIRTUAL TABLE IF NOT EXISTS posts_fts USING fts5(
+    title,
+    content,
+    content = 'posts',
+    content_rowid = 'id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS comments_fts USING fts5(
+    content,
+    content = 'comments',
+    content_rowid = 'id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_insert
+    AFTER INSERT ON posts
+BEGIN
+    INSERT INTO posts_fts (rowid, title, content) VALUES (new.id, new.title, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_delete
+    AFTER DELETE ON posts
+BEGIN
+    INSERT INTO posts_fts (posts_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS posts_fts_update
+    AFTER UPDATE OF title, content ON posts
+BEGIN
+    INSERT INTO posts_fts (posts_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content);
+    INSERT INTO posts_fts (rowid, title, content) VALUES (new.id, new.title, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_insert
+    AFTER INSERT ON comments
+BEGIN
+    INSERT INTO comments_fts (rowid, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_fts_delete
+    AFTER DELETE ON comments
+BEGIN
+    INSERT INTO comments_fts (comments_fts, rowid, content) VALUES ('delete', old.id, old.content);
+END;
+
+-- мягкое удаление очищает content, так что удаленный комментарий пропадает из индекса этим же триггером
+CREATE TRIGGER IF NOT EXISTS comments_fts_update
+    AFTER UPDATE OF content ON comments
+BEGIN
+    INSERT INTO comments_fts (comments_fts, rowid, content) VALUES ('delete', old.id, old.content);
+    INSERT INTO comments_fts (rowid, content) VALUES (new.id, new.content);
+END;
+
+INSERT INTO posts_fts (posts_fts) VALUES ('rebuild');
+INSERT INTO comments_fts (comments_fts) VALUES ('rebuild');
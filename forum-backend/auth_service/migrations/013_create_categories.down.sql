@@ -0,0 +1,7 @@
+DELETE FROM role_permissions WHERE permission_id IN (SELECT id FROM permissions WHERE name = 'category.manage');
+DELETE FROM permissions WHERE name = 'category.manage';
+
+DROP INDEX IF EXISTS idx_posts_category;
+ALTER TABLE posts DROP COLUMN category_id;
+
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS categories (
+                                          id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                          slug TEXT NOT NULL UNIQUE,
+                                          title TEXT NOT NULL,
+                                          description TEXT NOT NULL DEFAULT '',
+                                          sort_order INTEGER NOT NULL DEFAULT 0,
+                                          created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE posts ADD COLUMN category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_category ON posts(category_id, created_at);
+
+INSERT INTO permissions (name, description) VALUES ('category.manage', 'Управление категориями');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+WHERE r.name = 'admin' AND p.name = 'category.manage';
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS tags (
+                                    id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS post_tags (
+                                         post_id INTEGER NOT NULL,
+                                         tag_id INTEGER NOT NULL,
+                                         PRIMARY KEY (post_id, tag_id),
+                                         FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+                                         FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_tags_tag ON post_tags(tag_id, post_id);
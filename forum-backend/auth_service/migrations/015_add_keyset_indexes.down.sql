@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_chat_messages_timestamp;
+DROP INDEX IF EXISTS idx_comments_post_created;
+DROP INDEX IF EXISTS idx_posts_created;
@@ -0,0 +1,4 @@
+-- Индексы для курсорной навигации по (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_posts_created ON posts(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_created ON comments(post_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_timestamp ON chat_messages(timestamp, id);
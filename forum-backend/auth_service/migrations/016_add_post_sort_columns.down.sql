@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_posts_comment_count;
+DROP INDEX IF EXISTS idx_posts_activity;
+DROP INDEX IF EXISTS idx_posts_score;
+DROP INDEX IF EXISTS idx_posts_author;
+
+DROP TRIGGER IF EXISTS comments_counters_ad;
+DROP TRIGGER IF EXISTS comments_counters_ai;
+
+ALTER TABLE posts DROP COLUMN last_comment_at;
+ALTER TABLE posts DROP COLUMN comment_count;
+ALTER TABLE posts DROP COLUMN score;
@@ -0,0 +1,32 @@
+-- Денормализованные счетчики для сортировок ленты: score - рейтинг поста (sort=top),
+-- comment_count и last_comment_at поддерживаются триггерами на comments
+ALTER TABLE posts ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN comment_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN last_comment_at DATETIME;
+
+UPDATE posts SET
+    comment_count = (SELECT COUNT(*) FROM comments c WHERE c.post_id = posts.id),
+    last_comment_at = (SELECT c.created_at FROM comments c WHERE c.post_id = posts.id
+                       ORDER BY c.created_at DESC LIMIT 1);
+
+CREATE TRIGGER IF NOT EXISTS comments_counters_ai
+    AFTER INSERT ON comments
+BEGIN
+    UPDATE posts SET comment_count = comment_count + 1, last_comment_at = new.created_at
+    WHERE id = new.post_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_counters_ad
+    AFTER DELETE ON comments
+BEGIN
+    UPDATE posts SET comment_count = comment_count - 1,
+        last_comment_at = (SELECT c.created_at FROM comments c WHERE c.post_id = old.post_id
+                           ORDER BY c.created_at DESC LIMIT 1)
+    WHERE id = old.post_id;
+END;
+
+CREATE INDEX IF NOT EXISTS idx_posts_author ON posts(author_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_posts_score ON posts(score, id);
+-- sort=active: у поста без комментариев активность - время создания
+CREATE INDEX IF NOT EXISTS idx_posts_activity ON posts(COALESCE(last_comment_at, created_at), id);
+CREATE INDEX IF NOT EXISTS idx_posts_comment_count ON posts(comment_count, id);
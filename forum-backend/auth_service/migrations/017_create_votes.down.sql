@@ -0,0 +1,10 @@
+DELETE FROM role_permissions WHERE permission_id IN (SELECT id FROM permissions WHERE name = 'vote');
+DELETE FROM permissions WHERE name = 'vote';
+
+DROP TRIGGER IF EXISTS comments_votes_ad;
+DROP TRIGGER IF EXISTS posts_votes_ad;
+
+ALTER TABLE comments DROP COLUMN score;
+
+DROP INDEX IF EXISTS idx_votes_target;
+DROP TABLE IF EXISTS votes;
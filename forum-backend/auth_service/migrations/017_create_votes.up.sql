@@ -0,0 +1,34 @@
+-- Голоса +1/-1: один голос пользователя на пост или комментарий. Сумма голосов
+-- хранится в posts.score и comments.score и меняется в той же транзакции, что и голос
+CREATE TABLE IF NOT EXISTS votes (
+                                     user_id INTEGER NOT NULL,
+                                     target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment')),
+                                     target_id INTEGER NOT NULL,
+                                     value INTEGER NOT NULL CHECK (value IN (-1, 1)),
+                                     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                     UNIQUE (user_id, target_type, target_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_votes_target ON votes(target_type, target_id);
+
+ALTER TABLE comments ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+
+-- у votes нет внешних ключей на цель, поэтому голоса удаленных постов и комментариев чистят триггеры
+CREATE TRIGGER IF NOT EXISTS posts_votes_ad
+    AFTER DELETE ON posts
+BEGIN
+    DELETE FROM votes WHERE target_type = 'post' AND target_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_votes_ad
+    AFTER DELETE ON comments
+BEGIN
+    DELETE FROM votes WHERE target_type = 'comment' AND target_id = old.id;
+END;
+
+INSERT INTO permissions (name, description) VALUES ('vote', 'Голосование за посты и комментарии');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+WHERE r.name IN ('user', 'moderator', 'admin') AND p.name = 'vote';
@@ -0,0 +1,9 @@
+DELETE FROM role_permissions WHERE permission_id IN (SELECT id FROM permissions WHERE name = 'react');
+DELETE FROM permissions WHERE name = 'react';
+
+DROP TRIGGER IF EXISTS chat_messages_reactions_ad;
+DROP TRIGGER IF EXISTS comments_reactions_ad;
+DROP TRIGGER IF EXISTS posts_reactions_ad;
+
+DROP INDEX IF EXISTS idx_reactions_target;
+DROP TABLE IF EXISTS reactions;
@@ -0,0 +1,37 @@
+-- Реакции эмодзи на посты, комментарии и сообщения чата. Набор допустимых эмодзи
+-- задается в конфиге forum_service (REACTION_EMOJI), в базе он не проверяется
+CREATE TABLE IF NOT EXISTS reactions (
+                                         id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                         user_id INTEGER NOT NULL,
+                                         target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment', 'chat_message')),
+                                         target_id INTEGER NOT NULL,
+                                         emoji TEXT NOT NULL,
+                                         created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                         UNIQUE (user_id, target_type, target_id, emoji)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reactions_target ON reactions(target_type, target_id, emoji);
+
+CREATE TRIGGER IF NOT EXISTS posts_reactions_ad
+    AFTER DELETE ON posts
+BEGIN
+    DELETE FROM reactions WHERE target_type = 'post' AND target_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_reactions_ad
+    AFTER DELETE ON comments
+BEGIN
+    DELETE FROM reactions WHERE target_type = 'comment' AND target_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS chat_messages_reactions_ad
+    AFTER DELETE ON chat_messages
+BEGIN
+    DELETE FROM reactions WHERE target_type = 'chat_message' AND target_id = old.id;
+END;
+
+INSERT INTO permissions (name, description) VALUES ('react', 'Реакции на посты, комментарии и сообщения чата');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+WHERE r.name IN ('user', 'moderator', 'admin') AND p.name = 'react';
@@ -0,0 +1,16 @@
+DROP TRIGGER IF EXISTS chat_rooms_ad;
+
+DROP INDEX IF EXISTS idx_chat_messages_room;
+DELETE FROM chat_messages WHERE room_id <> 1;
+ALTER TABLE chat_messages DROP COLUMN room_id;
+
+DROP INDEX IF EXISTS idx_chat_room_members_user;
+DROP TABLE IF EXISTS chat_room_members;
+DROP TABLE IF EXISTS chat_rooms;
+
+CREATE TRIGGER IF NOT EXISTS cleanup_old_messages
+    AFTER INSERT ON chat_messages
+BEGIN
+    DELETE FROM chat_messages
+    WHERE timestamp < datetime('now', '-10 minutes');
+END;
@@ -0,0 +1,38 @@
+-- Комнаты чата. Публичные комнаты открыты всем, в приватную попадают только
+-- по приглашению владельца. owner_id = 0 у общей комнаты, ее нельзя удалить
+CREATE TABLE IF NOT EXISTS chat_rooms (
+                                          id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                          name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+                                          is_private INTEGER NOT NULL DEFAULT 0,
+                                          owner_id INTEGER NOT NULL DEFAULT 0,
+                                          created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS chat_room_members (
+                                                 room_id INTEGER NOT NULL,
+                                                 user_id INTEGER NOT NULL,
+                                                 role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'member')),
+                                                 joined_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                                 PRIMARY KEY (room_id, user_id),
+                                                 FOREIGN KEY (room_id) REFERENCES chat_rooms(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_room_members_user ON chat_room_members(user_id);
+
+-- Триггер из 002 удалял при каждой вставке сообщения старше 10 минут, а из-за сравнения строк -
+-- все сообщения до текущего дня. Теперь история комнат хранится целиком
+DROP TRIGGER IF EXISTS cleanup_old_messages;
+
+-- Общая комната, в нее попадают все сообщения, написанные до появления комнат
+INSERT INTO chat_rooms (id, name) VALUES (1, 'general');
+
+ALTER TABLE chat_messages ADD COLUMN room_id INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_room ON chat_messages(room_id, timestamp, id);
+
+CREATE TRIGGER IF NOT EXISTS chat_rooms_ad
+    AFTER DELETE ON chat_rooms
+BEGIN
+    DELETE FROM chat_room_members WHERE room_id = old.id;
+    DELETE FROM chat_messages WHERE room_id = old.id;
+END;
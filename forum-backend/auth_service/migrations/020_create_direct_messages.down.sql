@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_direct_messages_unread;
+DROP INDEX IF EXISTS idx_direct_messages_recipient;
+DROP INDEX IF EXISTS idx_direct_messages_sender;
+DROP TABLE IF EXISTS direct_messages;
@@ -0,0 +1,15 @@
+-- Личные сообщения. read_at заполняется, когда получатель прочитал сообщение,
+-- NULL - непрочитанное
+CREATE TABLE IF NOT EXISTS direct_messages (
+                                               id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                               sender_id INTEGER NOT NULL,
+                                               recipient_id INTEGER NOT NULL,
+                                               content TEXT NOT NULL,
+                                               created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                               read_at DATETIME,
+                                               CHECK (sender_id <> recipient_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_direct_messages_sender ON direct_messages(sender_id, recipient_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_direct_messages_recipient ON direct_messages(recipient_id, sender_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_direct_messages_unread ON direct_messages(recipient_id, read_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_direct_messages_client;
+ALTER TABLE direct_messages DROP COLUMN client_id;
+
+DROP INDEX IF EXISTS idx_chat_messages_client;
+ALTER TABLE chat_messages DROP COLUMN client_id;
@@ -0,0 +1,7 @@
+-- client_id - id сообщения, который сгенерировал клиент. Повторная отправка с тем же
+-- client_id не создает второе сообщение, а возвращает уже сохраненное. NULL - клиент id не прислал
+ALTER TABLE chat_messages ADD COLUMN client_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_messages_client ON chat_messages(user_id, client_id);
+
+ALTER TABLE direct_messages ADD COLUMN client_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_direct_messages_client ON direct_messages(sender_id, client_id);
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+	time "time"
+
 	entity "github.com/Engls/forum-project2/auth_service/internal/entity"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,6 +15,334 @@ type AuthRepository struct {
 	mock.Mock
 }
 
+// AssignRole provides a mock function with given fields: userID, roleID
+func (_m *AuthRepository) AssignRole(userID int, roleID int) error {
+	ret := _m.Called(userID, roleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, roleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClearLoginFailures provides a mock function with given fields: username
+func (_m *AuthRepository) ClearLoginFailures(username string) error {
+	ret := _m.Called(username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearLoginFailures")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredTokens provides a mock function with given fields: now
+func (_m *AuthRepository) DeleteExpiredTokens(now time.Time) error {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteLoginFailuresBefore provides a mock function with given fields: before
+func (_m *AuthRepository) DeleteLoginFailuresBefore(before time.Time) error {
+	ret := _m.Called(before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginFailuresBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetIPLoginFailures provides a mock function with given fields: ipAddress, since
+func (_m *AuthRepository) GetIPLoginFailures(ipAddress string, since time.Time) (entity.LoginFailureStats, error) {
+	ret := _m.Called(ipAddress, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIPLoginFailures")
+	}
+
+	var r0 entity.LoginFailureStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (entity.LoginFailureStats, error)); ok {
+		return rf(ipAddress, since)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) entity.LoginFailureStats); ok {
+		r0 = rf(ipAddress, since)
+	} else {
+		r0 = ret.Get(0).(entity.LoginFailureStats)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(ipAddress, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPasswordResetTokenByHash provides a mock function with given fields: tokenHash
+func (_m *AuthRepository) GetPasswordResetTokenByHash(tokenHash string) (entity.PasswordResetToken, error) {
+	ret := _m.Called(tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPasswordResetTokenByHash")
+	}
+
+	var r0 entity.PasswordResetToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.PasswordResetToken, error)); ok {
+		return rf(tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.PasswordResetToken); ok {
+		r0 = rf(tokenHash)
+	} else {
+		r0 = ret.Get(0).(entity.PasswordResetToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfile provides a mock function with given fields: userID
+func (_m *AuthRepository) GetProfile(userID int) (entity.Profile, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 entity.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (entity.Profile, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) entity.Profile); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(entity.Profile)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfilesByIDs provides a mock function with given fields: userIDs
+func (_m *AuthRepository) GetProfilesByIDs(userIDs []int) ([]entity.Profile, error) {
+	ret := _m.Called(userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfilesByIDs")
+	}
+
+	var r0 []entity.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int) ([]entity.Profile, error)); ok {
+		return rf(userIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]int) []entity.Profile); ok {
+		r0 = rf(userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Profile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int) error); ok {
+		r1 = rf(userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefreshTokenByHash provides a mock function with given fields: tokenHash
+func (_m *AuthRepository) GetRefreshTokenByHash(tokenHash string) (entity.RefreshToken, error) {
+	ret := _m.Called(tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefreshTokenByHash")
+	}
+
+	var r0 entity.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.RefreshToken, error)); ok {
+		return rf(tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.RefreshToken); ok {
+		r0 = rf(tokenHash)
+	} else {
+		r0 = ret.Get(0).(entity.RefreshToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoleByName provides a mock function with given fields: name
+func (_m *AuthRepository) GetRoleByName(name string) (entity.Role, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoleByName")
+	}
+
+	var r0 entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.Role, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.Role); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(entity.Role)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoles provides a mock function with no fields
+func (_m *AuthRepository) GetRoles() ([]entity.Role, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoles")
+	}
+
+	var r0 []entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.Role, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.Role); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetToken provides a mock function with given fields: token
+func (_m *AuthRepository) GetToken(token string) (entity.Token, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetToken")
+	}
+
+	var r0 entity.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.Token, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.Token); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(entity.Token)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: userID
+func (_m *AuthRepository) GetUserByID(userID int) (entity.User, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (entity.User, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) entity.User); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByUsername provides a mock function with given fields: username
 func (_m *AuthRepository) GetUserByUsername(username string) (entity.User, error) {
 	ret := _m.Called(username)
@@ -40,6 +371,170 @@ func (_m *AuthRepository) GetUserByUsername(username string) (entity.User, error
 	return r0, r1
 }
 
+// GetUserPermissions provides a mock function with given fields: userID
+func (_m *AuthRepository) GetUserPermissions(userID int) ([]string, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserPermissions")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]string, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRoles provides a mock function with given fields: userID
+func (_m *AuthRepository) GetUserRoles(userID int) ([]string, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRoles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]string, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsernameByID provides a mock function with given fields: ctx, userID
+func (_m *AuthRepository) GetUsernameByID(ctx context.Context, userID int) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsernameByID")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsernameLoginFailures provides a mock function with given fields: username, since
+func (_m *AuthRepository) GetUsernameLoginFailures(username string, since time.Time) (entity.LoginFailureStats, error) {
+	ret := _m.Called(username, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsernameLoginFailures")
+	}
+
+	var r0 entity.LoginFailureStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (entity.LoginFailureStats, error)); ok {
+		return rf(username, since)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) entity.LoginFailureStats); ok {
+		r0 = rf(username, since)
+	} else {
+		r0 = ret.Get(0).(entity.LoginFailureStats)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(username, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsernamesByIDs provides a mock function with given fields: userIDs
+func (_m *AuthRepository) GetUsernamesByIDs(userIDs []int) (map[int]string, error) {
+	ret := _m.Called(userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsernamesByIDs")
+	}
+
+	var r0 map[int]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int) (map[int]string, error)); ok {
+		return rf(userIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]int) map[int]string); ok {
+		r0 = rf(userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int) error); ok {
+		r1 = rf(userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordLoginFailure provides a mock function with given fields: username, ipAddress, at
+func (_m *AuthRepository) RecordLoginFailure(username string, ipAddress string, at time.Time) error {
+	ret := _m.Called(username, ipAddress, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) error); ok {
+		r0 = rf(username, ipAddress, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Register provides a mock function with given fields: user
 func (_m *AuthRepository) Register(user entity.User) error {
 	ret := _m.Called(user)
@@ -58,17 +553,189 @@ func (_m *AuthRepository) Register(user entity.User) error {
 	return r0
 }
 
-// SaveToken provides a mock function with given fields: userID, token
-func (_m *AuthRepository) SaveToken(userID int, token string) error {
-	ret := _m.Called(userID, token)
+// RemoveRole provides a mock function with given fields: userID, roleID
+func (_m *AuthRepository) RemoveRole(userID int, roleID int) error {
+	ret := _m.Called(userID, roleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, roleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAllUserTokens provides a mock function with given fields: userID
+func (_m *AuthRepository) RevokeAllUserTokens(userID int) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshToken provides a mock function with given fields: id
+func (_m *AuthRepository) RevokeRefreshToken(id int) (bool, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshToken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (bool, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) bool); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRefreshTokenFamily provides a mock function with given fields: familyID
+func (_m *AuthRepository) RevokeRefreshTokenFamily(familyID string) error {
+	ret := _m.Called(familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokenFamily")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeToken provides a mock function with given fields: token
+func (_m *AuthRepository) RevokeToken(token string) error {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SavePasswordResetToken provides a mock function with given fields: token
+func (_m *AuthRepository) SavePasswordResetToken(token entity.PasswordResetToken) error {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavePasswordResetToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.PasswordResetToken) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveRefreshToken provides a mock function with given fields: token
+func (_m *AuthRepository) SaveRefreshToken(token entity.RefreshToken) error {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.RefreshToken) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveToken provides a mock function with given fields: userID, token, expiresAt
+func (_m *AuthRepository) SaveToken(userID int, token string, expiresAt time.Time) error {
+	ret := _m.Called(userID, token, expiresAt)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveToken")
 	}
 
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, time.Time) error); ok {
+		r0 = rf(userID, token, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: userID, passwordHash
+func (_m *AuthRepository) UpdatePassword(userID int, passwordHash string) error {
+	ret := _m.Called(userID, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(int, string) error); ok {
-		r0 = rf(userID, token)
+		r0 = rf(userID, passwordHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProfile provides a mock function with given fields: profile
+func (_m *AuthRepository) UpdateProfile(profile entity.Profile) error {
+	ret := _m.Called(profile)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Profile) error); ok {
+		r0 = rf(profile)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -76,6 +743,62 @@ func (_m *AuthRepository) SaveToken(userID int, token string) error {
 	return r0
 }
 
+// UsePasswordResetToken provides a mock function with given fields: id
+func (_m *AuthRepository) UsePasswordResetToken(id int) (bool, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UsePasswordResetToken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (bool, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) bool); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UsernameExists provides a mock function with given fields: username
+func (_m *AuthRepository) UsernameExists(username string) (bool, error) {
+	ret := _m.Called(username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UsernameExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewAuthRepository creates a new instance of AuthRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthRepository(t interface {
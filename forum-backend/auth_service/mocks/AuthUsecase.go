@@ -2,13 +2,144 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	entity "github.com/Engls/forum-project2/auth_service/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // AuthUsecase is an autogenerated mock type for the AuthUsecase type
 type AuthUsecase struct {
 	mock.Mock
 }
 
+// Authorize provides a mock function with given fields: token, permission
+func (_m *AuthUsecase) Authorize(token string, permission string) (entity.Token, error) {
+	ret := _m.Called(token, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 entity.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (entity.Token, error)); ok {
+		return rf(token, permission)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) entity.Token); ok {
+		r0 = rf(token, permission)
+	} else {
+		r0 = ret.Get(0).(entity.Token)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(token, permission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BootstrapAdmin provides a mock function with given fields: username
+func (_m *AuthUsecase) BootstrapAdmin(username string) error {
+	ret := _m.Called(username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BootstrapAdmin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangePassword provides a mock function with given fields: userID, oldPassword, newPassword
+func (_m *AuthUsecase) ChangePassword(userID int, oldPassword string, newPassword string) error {
+	ret := _m.Called(userID, oldPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, string) error); ok {
+		r0 = rf(userID, oldPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmPasswordReset provides a mock function with given fields: token, newPassword
+func (_m *AuthUsecase) ConfirmPasswordReset(token string, newPassword string) error {
+	ret := _m.Called(token, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmPasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(token, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredTokens provides a mock function with no fields
+func (_m *AuthUsecase) DeleteExpiredTokens() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProfile provides a mock function with given fields: userID
+func (_m *AuthUsecase) GetProfile(userID int) (entity.Profile, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 entity.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (entity.Profile, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) entity.Profile); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(entity.Profile)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserRole provides a mock function with given fields: username
 func (_m *AuthUsecase) GetUserRole(username string) (string, error) {
 	ret := _m.Called(username)
@@ -37,9 +168,115 @@ func (_m *AuthUsecase) GetUserRole(username string) (string, error) {
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: username, password
-func (_m *AuthUsecase) Login(username string, password string) (string, error) {
-	ret := _m.Called(username, password)
+// GetUserRoles provides a mock function with given fields: userID
+func (_m *AuthUsecase) GetUserRoles(userID int) ([]string, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRoles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]string, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantRole provides a mock function with given fields: userID, role
+func (_m *AuthUsecase) GrantRole(userID int, role string) error {
+	ret := _m.Called(userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IssueRefreshToken provides a mock function with given fields: userID, device
+func (_m *AuthUsecase) IssueRefreshToken(userID int, device entity.DeviceInfo) (string, error) {
+	ret := _m.Called(userID, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueRefreshToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, entity.DeviceInfo) (string, error)); ok {
+		return rf(userID, device)
+	}
+	if rf, ok := ret.Get(0).(func(int, entity.DeviceInfo) string); ok {
+		r0 = rf(userID, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, entity.DeviceInfo) error); ok {
+		r1 = rf(userID, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRoles provides a mock function with no fields
+func (_m *AuthUsecase) ListRoles() ([]entity.Role, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRoles")
+	}
+
+	var r0 []entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.Role, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.Role); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: username, password, device
+func (_m *AuthUsecase) Login(username string, password string, device entity.DeviceInfo) (string, error) {
+	ret := _m.Called(username, password, device)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
@@ -47,17 +284,17 @@ func (_m *AuthUsecase) Login(username string, password string) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
-		return rf(username, password)
+	if rf, ok := ret.Get(0).(func(string, string, entity.DeviceInfo) (string, error)); ok {
+		return rf(username, password, device)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = rf(username, password)
+	if rf, ok := ret.Get(0).(func(string, string, entity.DeviceInfo) string); ok {
+		r0 = rf(username, password, device)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(username, password)
+	if rf, ok := ret.Get(1).(func(string, string, entity.DeviceInfo) error); ok {
+		r1 = rf(username, password, device)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -65,17 +302,88 @@ func (_m *AuthUsecase) Login(username string, password string) (string, error) {
 	return r0, r1
 }
 
-// Register provides a mock function with given fields: username, password, role
-func (_m *AuthUsecase) Register(username string, password string, role string) error {
-	ret := _m.Called(username, password, role)
+// Logout provides a mock function with given fields: token, refreshToken
+func (_m *AuthUsecase) Logout(token string, refreshToken string) error {
+	ret := _m.Called(token, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(token, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LogoutAll provides a mock function with given fields: token
+func (_m *AuthUsecase) LogoutAll(token string) error {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogoutAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Refresh provides a mock function with given fields: refreshToken, device
+func (_m *AuthUsecase) Refresh(refreshToken string, device entity.DeviceInfo) (string, string, error) {
+	ret := _m.Called(refreshToken, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, entity.DeviceInfo) (string, string, error)); ok {
+		return rf(refreshToken, device)
+	}
+	if rf, ok := ret.Get(0).(func(string, entity.DeviceInfo) string); ok {
+		r0 = rf(refreshToken, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, entity.DeviceInfo) string); ok {
+		r1 = rf(refreshToken, device)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, entity.DeviceInfo) error); ok {
+		r2 = rf(refreshToken, device)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Register provides a mock function with given fields: username, password
+func (_m *AuthUsecase) Register(username string, password string) error {
+	ret := _m.Called(username, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Register")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(username, password, role)
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(username, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -83,6 +391,116 @@ func (_m *AuthUsecase) Register(username string, password string, role string) e
 	return r0
 }
 
+// RequestPasswordReset provides a mock function with given fields: username
+func (_m *AuthUsecase) RequestPasswordReset(username string) error {
+	ret := _m.Called(username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRole provides a mock function with given fields: actorID, userID, role
+func (_m *AuthUsecase) RevokeRole(actorID int, userID int, role string) error {
+	ret := _m.Called(actorID, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, string) error); ok {
+		r0 = rf(actorID, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnlockUser provides a mock function with given fields: userID
+func (_m *AuthUsecase) UnlockUser(userID int) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProfile provides a mock function with given fields: userID, req
+func (_m *AuthUsecase) UpdateProfile(userID int, req entity.UpdateProfileRequest) (entity.Profile, error) {
+	ret := _m.Called(userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 entity.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, entity.UpdateProfileRequest) (entity.Profile, error)); ok {
+		return rf(userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(int, entity.UpdateProfileRequest) entity.Profile); ok {
+		r0 = rf(userID, req)
+	} else {
+		r0 = ret.Get(0).(entity.Profile)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, entity.UpdateProfileRequest) error); ok {
+		r1 = rf(userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateToken provides a mock function with given fields: token
+func (_m *AuthUsecase) ValidateToken(token string) (entity.Token, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateToken")
+	}
+
+	var r0 entity.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.Token, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.Token); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(entity.Token)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewAuthUsecase creates a new instance of AuthUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthUsecase(t interface {
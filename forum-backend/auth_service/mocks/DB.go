@@ -3,9 +3,10 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
-
+	context "context"
 	sql "database/sql"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // DB is an autogenerated mock type for the DB type
@@ -67,6 +68,50 @@ func (_m *DB) Get(dest interface{}, query string, args ...interface{}) error {
 	return r0
 }
 
+// QueryRowContext provides a mock function with given fields: ctx, query, args
+func (_m *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryRowContext")
+	}
+
+	var r0 *sql.Row
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Row); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Row)
+		}
+	}
+
+	return r0
+}
+
+// Select provides a mock function with given fields: dest, query, args
+func (_m *DB) Select(dest interface{}, query string, args ...interface{}) error {
+	var _ca []interface{}
+	_ca = append(_ca, dest, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Select")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(interface{}, string, ...interface{}) error); ok {
+		r0 = rf(dest, query, args...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewDB creates a new instance of DB. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDB(t interface {
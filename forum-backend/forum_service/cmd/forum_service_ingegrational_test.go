@@ -2,27 +2,74 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/Engls/EnglsJwt"
+	"github.com/Engls/forum-project2/forum_service/internal/authz"
 	"github.com/Engls/forum-project2/forum_service/internal/controllers/chat"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/grpc"
 	http2 "github.com/Engls/forum-project2/forum_service/internal/controllers/http"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/repository"
 	"github.com/Engls/forum-project2/forum_service/internal/usecase"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
 
+// stubUserService заменяет gRPC-клиент auth_service в интеграционном тесте
+type stubUserService struct {
+	jwtUtil *EnglsJwt.JWTUtil
+}
+
+func (s *stubUserService) ValidateToken(ctx context.Context, token string) (entity.Principal, error) {
+	userID, err := s.jwtUtil.GetUserIDFromToken(token)
+	if err != nil {
+		return entity.Principal{}, fmt.Errorf("%w: %v", grpc.ErrInvalidToken, err)
+	}
+	role, err := s.jwtUtil.GetRoleFromToken(token)
+	if err != nil {
+		return entity.Principal{}, fmt.Errorf("%w: %v", grpc.ErrInvalidToken, err)
+	}
+	permissions := []string{authz.PostCreate, authz.CommentCreate, authz.Vote, authz.React}
+	if role == authz.RoleAdmin {
+		permissions = append(permissions, authz.CategoryManage)
+	}
+	return entity.Principal{UserID: userID, Role: role, Permissions: permissions}, nil
+}
+
+func (s *stubUserService) GetUsernames(ctx context.Context, userIDs []int) (map[int]string, error) {
+	usernames := make(map[int]string, len(userIDs))
+	for _, id := range userIDs {
+		usernames[id] = "testuser"
+	}
+	return usernames, nil
+}
+
+func (s *stubUserService) GetAuthorCards(ctx context.Context, userIDs []int) (map[int]entity.AuthorCard, error) {
+	cards := make(map[int]entity.AuthorCard, len(userIDs))
+	for _, id := range userIDs {
+		cards[id] = entity.AuthorCard{UserID: id, Username: "testuser", Role: authz.RoleUser}
+	}
+	return cards, nil
+}
+
 func setupTestDB(t *testing.T) *sqlx.DB {
 
 	tmpfile, err := os.CreateTemp("", "testdb-*.db")
@@ -43,11 +90,23 @@ func setupTestDB(t *testing.T) *sqlx.DB {
 			password TEXT NOT NULL,
 			role TEXT NOT NULL
 		);
+		CREATE TABLE IF NOT EXISTS categories (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			slug TEXT NOT NULL UNIQUE,
+			title TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
 		CREATE TABLE IF NOT EXISTS posts (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			author_id INTEGER,
 			title TEXT,
 			content TEXT,
+			category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+			score INTEGER NOT NULL DEFAULT 0,
+			comment_count INTEGER NOT NULL DEFAULT 0,
+			last_comment_at DATETIME,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (author_id) REFERENCES users(id)
@@ -57,18 +116,120 @@ func setupTestDB(t *testing.T) *sqlx.DB {
 			author_id INTEGER,
 			post_id INTEGER,
 			content TEXT,
+			edited_at DATETIME,
+			deleted_at DATETIME,
+			parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+			depth INTEGER NOT NULL DEFAULT 0,
+			score INTEGER NOT NULL DEFAULT 0,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
 			FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
 		);
+		CREATE TRIGGER IF NOT EXISTS comments_counters_ai AFTER INSERT ON comments BEGIN
+			UPDATE posts SET comment_count = comment_count + 1, last_comment_at = new.created_at WHERE id = new.post_id;
+		END;
+		CREATE TRIGGER IF NOT EXISTS comments_counters_ad AFTER DELETE ON comments BEGIN
+			UPDATE posts SET comment_count = comment_count - 1,
+				last_comment_at = (SELECT c.created_at FROM comments c WHERE c.post_id = old.post_id ORDER BY c.created_at DESC LIMIT 1)
+			WHERE id = old.post_id;
+		END;
+		CREATE TABLE IF NOT EXISTS votes (
+			user_id INTEGER NOT NULL,
+			target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment')),
+			target_id INTEGER NOT NULL,
+			value INTEGER NOT NULL CHECK (value IN (-1, 1)),
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (user_id, target_type, target_id)
+		);
+		CREATE TRIGGER IF NOT EXISTS posts_votes_ad AFTER DELETE ON posts BEGIN
+			DELETE FROM votes WHERE target_type = 'post' AND target_id = old.id;
+		END;
+		CREATE TRIGGER IF NOT EXISTS comments_votes_ad AFTER DELETE ON comments BEGIN
+			DELETE FROM votes WHERE target_type = 'comment' AND target_id = old.id;
+		END;
+		CREATE TABLE IF NOT EXISTS reactions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment', 'chat_message')),
+			target_id INTEGER NOT NULL,
+			emoji TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (user_id, target_type, target_id, emoji)
+		);
+		CREATE TRIGGER IF NOT EXISTS posts_reactions_ad AFTER DELETE ON posts BEGIN
+			DELETE FROM reactions WHERE target_type = 'post' AND target_id = old.id;
+		END;
+		CREATE TRIGGER IF NOT EXISTS comments_reactions_ad AFTER DELETE ON comments BEGIN
+			DELETE FROM reactions WHERE target_type = 'comment' AND target_id = old.id;
+		END;
+		CREATE TABLE IF NOT EXISTS tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE
+		);
+		CREATE TABLE IF NOT EXISTS post_tags (
+			post_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY (post_id, tag_id),
+			FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+			FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+		);
+		CREATE TABLE IF NOT EXISTS post_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			post_id INTEGER NOT NULL,
+			revision INTEGER NOT NULL,
+			title TEXT,
+			content TEXT,
+			editor_id INTEGER,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (post_id, revision),
+			FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+		);
+		CREATE TABLE IF NOT EXISTS chat_rooms (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			is_private INTEGER NOT NULL DEFAULT 0,
+			owner_id INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS chat_room_members (
+			room_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'member')),
+			joined_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (room_id, user_id),
+			FOREIGN KEY (room_id) REFERENCES chat_rooms(id) ON DELETE CASCADE
+		);
+		INSERT INTO chat_rooms (id, name) VALUES (1, 'general');
 		CREATE TABLE IF NOT EXISTS chat_messages (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			room_id INTEGER NOT NULL DEFAULT 1,
 			user_id INTEGER NOT NULL,
 			username TEXT NOT NULL,
 			content TEXT NOT NULL,
 			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			client_id TEXT,
 			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_messages_client ON chat_messages(user_id, client_id);
+		CREATE TRIGGER IF NOT EXISTS chat_messages_reactions_ad AFTER DELETE ON chat_messages BEGIN
+			DELETE FROM reactions WHERE target_type = 'chat_message' AND target_id = old.id;
+		END;
+		CREATE TRIGGER IF NOT EXISTS chat_rooms_ad AFTER DELETE ON chat_rooms BEGIN
+			DELETE FROM chat_room_members WHERE room_id = old.id;
+			DELETE FROM chat_messages WHERE room_id = old.id;
+		END;
+		CREATE TABLE IF NOT EXISTS direct_messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			sender_id INTEGER NOT NULL,
+			recipient_id INTEGER NOT NULL,
+			content TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			read_at DATETIME,
+			client_id TEXT,
+			CHECK (sender_id <> recipient_id)
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_direct_messages_client ON direct_messages(sender_id, client_id);
 		CREATE TABLE IF NOT EXISTS tokens (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			user_id INTEGER NOT NULL,
@@ -93,15 +254,32 @@ func TestForumService_Integration(t *testing.T) {
 	postRepo := repository.NewPostRepository(db, logger)
 	commentRepo := repository.NewCommentsRepository(db, logger)
 	chatRepo := repository.NewChatRepository(db, logger)
-	postUsecase := usecase.NewPostUsecase(postRepo, logger)
-	commentUsecase := usecase.NewCommentsUsecases(commentRepo, logger)
+	chatRoomRepo := repository.NewChatRoomRepository(db, logger)
+	directMessageRepo := repository.NewDirectMessageRepository(db, logger)
+	categoryRepo := repository.NewCategoryRepository(db, logger)
+	voteRepo := repository.NewVoteRepository(db, logger)
+	reactionRepo := repository.NewReactionRepository(db, logger)
+	postUsecase := usecase.NewPostUsecase(postRepo, categoryRepo, logger)
+	commentUsecase := usecase.NewCommentsUsecases(commentRepo, 5, logger)
+	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo, logger)
+	voteUsecase := usecase.NewVoteUsecase(voteRepo, logger)
+	reactionUsecase := usecase.NewReactionUsecase(reactionRepo, []string{"👍", "❤️", "🎉"}, logger)
 	hub := chat.NewHub()
+	// Хаб запускается в первом тесте с WebSocket, до него тесты читают hub.Broadcast сами
+	startHub := sync.OnceFunc(func() { go hub.Run() })
 	chatUsecase := usecase.NewChatUsecase(chatRepo, logger)
+	chatRoomUsecase := usecase.NewChatRoomUsecase(chatRoomRepo, logger)
 	jwtUtil := EnglsJwt.NewJWTUtil("secret")
 
-	postHandler := http2.NewPostHandler(postUsecase, postRepo, jwtUtil, logger)
-	commentHandler := http2.NewCommentHandler(commentUsecase, jwtUtil, logger)
-	chatHandler := http2.NewChatHandler(hub, chatUsecase, jwtUtil, logger)
+	userService := &stubUserService{jwtUtil: jwtUtil}
+	directMessageUsecase := usecase.NewDirectMessageUsecase(directMessageRepo, userService, logger)
+	authMiddleware := middleware.NewAuthMiddleware(userService, logger)
+
+	postHandler := http2.NewPostHandler(postUsecase, postRepo, commentUsecase, voteUsecase, reactionUsecase, logger, userService, userService)
+	commentHandler := http2.NewCommentHandler(commentUsecase, voteUsecase, reactionUsecase, logger, userService)
+	chatHandler := http2.NewChatHandler(hub, chatUsecase, chatRoomUsecase, directMessageUsecase, reactionUsecase, userService, userService, logger)
+	directMessageHandler := http2.NewDirectMessageHandler(hub, directMessageUsecase, logger, userService)
+	categoryHandler := http2.NewCategoryHandler(categoryUsecase, postUsecase, logger, userService)
 
 	router := gin.Default()
 	router.Use(cors.New(cors.Config{
@@ -114,11 +292,50 @@ func TestForumService_Integration(t *testing.T) {
 	}))
 
 	router.GET("/ws", chatHandler.ServeWS)
-	router.POST("/posts", postHandler.CreatePost)
-	router.GET("/posts", postHandler.GetPosts)
-	router.DELETE("/posts/:id", postHandler.DeletePost)
-	router.POST("/posts/:id/comments", commentHandler.CreateComment)
-	router.GET("/posts/:id/comments", commentHandler.GetCommentsByPostID)
+
+	public := router.Group("/", authMiddleware.OptionalAuth())
+	public.GET("/posts", postHandler.GetPosts)
+	public.GET("/posts/:post_id", postHandler.GetPost)
+	public.GET("/posts/:post_id/comments", commentHandler.GetComments)
+	public.GET("/comments/:id/replies", commentHandler.GetReplies)
+	public.GET("/tags", postHandler.GetTags)
+	public.GET("/categories", categoryHandler.GetCategories)
+	public.GET("/categories/:slug/posts", categoryHandler.GetCategoryPosts)
+	public.GET("/chat/messages", chatHandler.GetHistory)
+	public.GET("/chat/online", chatHandler.GetOnline)
+	public.GET("/chat/rooms", chatHandler.ListRooms)
+	public.GET("/chat/rooms/:id", chatHandler.GetRoom)
+	public.GET("/chat/rooms/:id/messages", chatHandler.GetRoomHistory)
+	public.GET("/chat/rooms/:id/members", chatHandler.ListRoomMembers)
+
+	protected := router.Group("/", authMiddleware.RequireAuth())
+	protected.POST("/posts", middleware.RequirePermission(authz.PostCreate), postHandler.CreatePost)
+	protected.DELETE("/posts/:id", postHandler.DeletePost)
+	protected.PUT("/posts/:id", postHandler.UpdatePost)
+	protected.GET("/posts/:post_id/revisions", postHandler.GetPostRevisions)
+	protected.GET("/posts/:post_id/revisions/diff", postHandler.DiffPostRevisions)
+	protected.GET("/posts/:post_id/revisions/:rev", postHandler.GetPostRevision)
+	protected.PUT("/posts/:id/vote", middleware.RequirePermission(authz.Vote), postHandler.VotePost)
+	protected.PUT("/posts/:id/reactions/:emoji", middleware.RequirePermission(authz.React), postHandler.AddReaction)
+	protected.DELETE("/posts/:id/reactions/:emoji", middleware.RequirePermission(authz.React), postHandler.RemoveReaction)
+	protected.POST("/posts/:id/comments", middleware.RequirePermission(authz.CommentCreate), commentHandler.CreateComment)
+	protected.PUT("/comments/:id", commentHandler.UpdateComment)
+	protected.DELETE("/comments/:id", commentHandler.DeleteComment)
+	protected.PUT("/comments/:id/vote", middleware.RequirePermission(authz.Vote), commentHandler.VoteComment)
+	protected.PUT("/comments/:id/reactions/:emoji", middleware.RequirePermission(authz.React), commentHandler.AddReaction)
+	protected.DELETE("/comments/:id/reactions/:emoji", middleware.RequirePermission(authz.React), commentHandler.RemoveReaction)
+	protected.POST("/chat/rooms", chatHandler.CreateRoom)
+	protected.DELETE("/chat/rooms/:id", chatHandler.DeleteRoom)
+	protected.POST("/chat/rooms/:id/members", chatHandler.AddRoomMember)
+	protected.DELETE("/chat/rooms/:id/members/:user_id", chatHandler.RemoveRoomMember)
+	protected.PUT("/chat/messages/:id/reactions/:emoji", middleware.RequirePermission(authz.React), chatHandler.AddReaction)
+	protected.DELETE("/chat/messages/:id/reactions/:emoji", middleware.RequirePermission(authz.React), chatHandler.RemoveReaction)
+	protected.GET("/dm/conversations", directMessageHandler.GetConversations)
+	protected.GET("/dm/:userID/messages", directMessageHandler.GetMessages)
+	protected.POST("/dm/:userID/read", directMessageHandler.MarkRead)
+	protected.POST("/categories", middleware.RequirePermission(authz.CategoryManage), categoryHandler.CreateCategory)
+	protected.PUT("/categories/:id", middleware.RequirePermission(authz.CategoryManage), categoryHandler.UpdateCategory)
+	protected.DELETE("/categories/:id", middleware.RequirePermission(authz.CategoryManage), categoryHandler.DeleteCategory)
 
 	token, err := jwtUtil.GenerateToken(1, "user")
 	if err != nil {
@@ -148,6 +365,18 @@ func TestForumService_Integration(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "This is a test post")
 	})
 
+	t.Run("CreatePostUnauthorized", func(t *testing.T) {
+		reqBodyBytes, _ := json.Marshal(entity.Post{Title: "Anonymous", Content: "No token"})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/posts", bytes.NewBuffer(reqBodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"error":"Authorization header required"}`, w.Body.String())
+	})
+
 	t.Run("GetPosts", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, "/posts", nil)
@@ -158,6 +387,33 @@ func TestForumService_Integration(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Test Post")
 	})
 
+	t.Run("GetPost", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/posts/1", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Post         map[string]interface{} `json:"post"`
+			CommentCount int                    `json:"comment_count"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Test Post", response.Post["title"])
+		assert.Equal(t, "testuser", response.Post["username"])
+		if assert.IsType(t, map[string]interface{}{}, response.Post["author"]) {
+			author := response.Post["author"].(map[string]interface{})
+			assert.Equal(t, "testuser", author["username"])
+			assert.Equal(t, authz.RoleUser, author["role"])
+		}
+		assert.NotEmpty(t, response.Post["created_at"])
+		assert.Equal(t, 0, response.CommentCount)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/posts/999", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
 	t.Run("CreateComment", func(t *testing.T) {
 		reqBody := entity.Comment{
 			PostId:   1,
@@ -185,4 +441,830 @@ func TestForumService_Integration(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "This is a test comment")
 	})
+
+	t.Run("ThreadedReplies", func(t *testing.T) {
+		reply := func(parentID int, content string) *httptest.ResponseRecorder {
+			reqBodyBytes, _ := json.Marshal(entity.Comment{ParentId: &parentID, Content: content})
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/posts/1/comments", bytes.NewBuffer(reqBodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		w := reply(1, "First reply")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		w = reply(2, "Nested reply")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"depth":2`)
+		w = reply(100, "Orphan reply")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/posts/1/comments?tree=true&depth=1", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var tree struct {
+			Comments   []entity.CommentNode `json:"comments"`
+			Pagination struct {
+				Total int `json:"total"`
+			} `json:"pagination"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+		assert.Equal(t, 1, tree.Pagination.Total)
+		if assert.Len(t, tree.Comments, 1) {
+			root := tree.Comments[0]
+			assert.Equal(t, 1, root.ReplyCount)
+			if assert.Len(t, root.Replies, 1) {
+				assert.Equal(t, "First reply", root.Replies[0].Content)
+				assert.Equal(t, 1, root.Replies[0].ReplyCount)
+				assert.Empty(t, root.Replies[0].Replies)
+			}
+		}
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/comments/2/replies", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Nested reply")
+		assert.Contains(t, w.Body.String(), `"username":"testuser"`)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/comments/100/replies", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("EditAndDeleteComment", func(t *testing.T) {
+		send := func(method, path, bearer string, body interface{}) *httptest.ResponseRecorder {
+			var reqBody bytes.Buffer
+			if body != nil {
+				_ = json.NewEncoder(&reqBody).Encode(body)
+			}
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, path, &reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+bearer)
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		otherToken, err := jwtUtil.GenerateToken(2, "user")
+		assert.NoError(t, err)
+
+		w := send(http.MethodPut, "/comments/2", otherToken, entity.UpdateCommentRequest{Content: "Hijacked"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = send(http.MethodDelete, "/comments/2", otherToken, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = send(http.MethodPut, "/comments/2", token, entity.UpdateCommentRequest{Content: "Edited reply"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Edited reply")
+		assert.Contains(t, w.Body.String(), `"edited_at"`)
+
+		w = send(http.MethodDelete, "/comments/2", token, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = send(http.MethodPut, "/comments/2", token, entity.UpdateCommentRequest{Content: "Too late"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/comments/1/replies", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var replies struct {
+			Replies []entity.CommentNode `json:"replies"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &replies))
+		if assert.Len(t, replies.Replies, 1) {
+			assert.Equal(t, entity.DeletedCommentContent, replies.Replies[0].Content)
+			assert.NotNil(t, replies.Replies[0].DeletedAt)
+			assert.Equal(t, 1, replies.Replies[0].ReplyCount)
+		}
+	})
+
+	t.Run("PostRevisions", func(t *testing.T) {
+		update := func(content string) {
+			reqBodyBytes, _ := json.Marshal(entity.Post{Title: "Test Post", Content: content})
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, "/posts/1", bytes.NewBuffer(reqBodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		get := func(path, bearer string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Authorization", "Bearer "+bearer)
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		update("This is a test post\nwith a second line")
+		update("This is an edited post\nwith a second line")
+
+		w := get("/posts/1/revisions", token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var revisions []entity.PostRevision
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+		if assert.Len(t, revisions, 3) {
+			assert.Equal(t, "This is a test post", revisions[0].Content)
+			assert.Equal(t, 3, revisions[2].Revision)
+			assert.Equal(t, 1, revisions[2].EditorId)
+		}
+
+		w = get("/posts/1/revisions/2", token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "with a second line")
+
+		w = get("/posts/1/revisions/diff?from=2", token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result entity.RevisionDiff
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, 3, result.To)
+		assert.Equal(t, []entity.DiffLine{
+			{Op: entity.DiffDelete, Text: "This is a test post"},
+			{Op: entity.DiffInsert, Text: "This is an edited post"},
+			{Op: entity.DiffEqual, Text: "with a second line"},
+		}, result.Content)
+
+		otherToken, err := jwtUtil.GenerateToken(2, "user")
+		assert.NoError(t, err)
+		w = get("/posts/1/revisions", otherToken)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	send := func(method, path, bearer string, body interface{}) *httptest.ResponseRecorder {
+		var reader *bytes.Buffer
+		if body != nil {
+			reqBodyBytes, _ := json.Marshal(body)
+			reader = bytes.NewBuffer(reqBodyBytes)
+		} else {
+			reader = &bytes.Buffer{}
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Categories", func(t *testing.T) {
+		adminToken, err := jwtUtil.GenerateToken(3, authz.RoleAdmin)
+		assert.NoError(t, err)
+
+		w := send(http.MethodPost, "/categories", token, entity.CategoryRequest{Slug: "golang", Title: "Go"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = send(http.MethodPost, "/categories", adminToken, entity.CategoryRequest{Slug: "Not a slug", Title: "Go"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = send(http.MethodPost, "/categories", adminToken, entity.CategoryRequest{Slug: "golang", Title: "Go", SortOrder: 2})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var golang entity.Category
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &golang))
+
+		w = send(http.MethodPost, "/categories", adminToken, entity.CategoryRequest{Slug: "news", Title: "News", SortOrder: 1})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = send(http.MethodPost, "/categories", adminToken, entity.CategoryRequest{Slug: "golang", Title: "Duplicate"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = send(http.MethodPost, "/posts", token, map[string]interface{}{"title": "Lost", "content": "No such category", "category_id": 999})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = send(http.MethodPost, "/posts", token, entity.Post{Title: "Generics", Content: "Type parameters", CategoryId: &golang.ID})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = send(http.MethodGet, "/categories", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var categories []entity.Category
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &categories))
+		if assert.Len(t, categories, 2) {
+			assert.Equal(t, "news", categories[0].Slug)
+			assert.Equal(t, 0, categories[0].PostCount)
+			assert.Nil(t, categories[0].LastActivityAt)
+			assert.Equal(t, "golang", categories[1].Slug)
+			assert.Equal(t, 1, categories[1].PostCount)
+			assert.NotNil(t, categories[1].LastActivityAt)
+		}
+
+		w = send(http.MethodGet, "/categories/golang/posts", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Generics")
+		assert.NotContains(t, w.Body.String(), "Test Post")
+
+		w = send(http.MethodGet, "/categories/missing/posts", "", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = send(http.MethodPut, "/categories/"+strconv.Itoa(golang.ID), adminToken, entity.CategoryRequest{Slug: "go", Title: "Go"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"post_count":1`)
+
+		w = send(http.MethodDelete, "/categories/"+strconv.Itoa(golang.ID), adminToken, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = send(http.MethodGet, "/categories/go/posts", "", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		w := send(http.MethodPost, "/posts", token, entity.Post{Title: "Streaming", Content: "Bidirectional", Tags: []string{" Go ", "GRPC"}})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var streaming entity.Post
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &streaming))
+		assert.Equal(t, []string{"go", "grpc"}, streaming.Tags)
+
+		w = send(http.MethodPost, "/posts", token, entity.Post{Title: "Channels", Content: "Select", Tags: []string{"go"}})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = send(http.MethodPost, "/posts", token, entity.Post{Title: "Too long", Content: "Tag", Tags: []string{strings.Repeat("x", 40)}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = send(http.MethodGet, "/posts?tag=go&tag=grpc", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":2`)
+
+		w = send(http.MethodGet, "/posts?tag=go&tag=grpc&tag_mode=and", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":1`)
+		assert.Contains(t, w.Body.String(), "Streaming")
+		assert.NotContains(t, w.Body.String(), "Channels")
+
+		w = send(http.MethodPut, "/posts/"+strconv.Itoa(streaming.ID), token, entity.Post{Title: "Streaming", Content: "Bidirectional", Tags: []string{"rpc"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = send(http.MethodGet, "/tags", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var tags []entity.TagCount
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+		assert.Equal(t, []entity.TagCount{{Name: "go", Count: 1}, {Name: "rpc", Count: 1}}, tags)
+	})
+
+	t.Run("CursorPagination", func(t *testing.T) {
+		type postsPage struct {
+			Posts      []map[string]interface{} `json:"posts"`
+			Total      int                      `json:"total"`
+			NextCursor *string                  `json:"next_cursor"`
+		}
+		getPosts := func(path string) postsPage {
+			w := send(http.MethodGet, path, "", nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+			var page postsPage
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			return page
+		}
+
+		first := getPosts("/posts?cursor=&limit=2")
+		assert.Len(t, first.Posts, 2)
+		if !assert.NotNil(t, first.NextCursor) {
+			return
+		}
+
+		// Новый пост посреди прокрутки не сдвигает следующие страницы
+		w := send(http.MethodPost, "/posts", token, entity.Post{Title: "Fresh", Content: "Arrived mid-scroll"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		seen := map[float64]bool{}
+		for _, post := range first.Posts {
+			seen[post["id"].(float64)] = true
+		}
+		cursor := *first.NextCursor
+		for {
+			page := getPosts("/posts?limit=2&cursor=" + cursor)
+			for _, post := range page.Posts {
+				assert.False(t, seen[post["id"].(float64)], "duplicate post %v", post["id"])
+				assert.NotEqual(t, "Fresh", post["title"])
+				seen[post["id"].(float64)] = true
+			}
+			if page.NextCursor == nil {
+				assert.Equal(t, first.Total+1, page.Total)
+				break
+			}
+			cursor = *page.NextCursor
+		}
+		assert.Len(t, seen, first.Total)
+
+		w = send(http.MethodGet, "/posts?cursor=garbage", "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// page/limit по-прежнему работают и отдают курсор для продолжения
+		paged := getPosts("/posts?page=1&limit=1")
+		assert.Len(t, paged.Posts, 1)
+		assert.NotNil(t, paged.NextCursor)
+
+		for i := 0; i < 3; i++ {
+			w = send(http.MethodPost, "/posts/1/comments", token, map[string]string{"content": "Cursor comment " + strconv.Itoa(i)})
+			assert.Equal(t, http.StatusCreated, w.Code)
+		}
+		var comments struct {
+			Comments   []map[string]interface{} `json:"comments"`
+			Pagination struct {
+				NextCursor *string `json:"next_cursor"`
+			} `json:"pagination"`
+		}
+		w = send(http.MethodGet, "/posts/1/comments?cursor=&limit=2", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &comments))
+		assert.Len(t, comments.Comments, 2)
+		if assert.NotNil(t, comments.Pagination.NextCursor) {
+			firstIDs := []interface{}{comments.Comments[0]["id"], comments.Comments[1]["id"]}
+			w = send(http.MethodGet, "/posts/1/comments?limit=2&cursor="+*comments.Pagination.NextCursor, "", nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &comments))
+			assert.NotEmpty(t, comments.Comments)
+			assert.NotContains(t, firstIDs, comments.Comments[0]["id"])
+		}
+
+		for i := 0; i < 3; i++ {
+			_, _, err := chatUsecase.HandleMessage(context.Background(), entity.DefaultChatRoomID, 1, "testuser", "message "+strconv.Itoa(i), "")
+			assert.NoError(t, err)
+		}
+		var history struct {
+			Messages   []entity.ChatMessage `json:"messages"`
+			NextCursor *string              `json:"next_cursor"`
+		}
+		w = send(http.MethodGet, "/chat/messages?limit=2", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+		if assert.Len(t, history.Messages, 2) && assert.NotNil(t, history.NextCursor) {
+			assert.Equal(t, "message 1", history.Messages[0].Content)
+			assert.Equal(t, "message 2", history.Messages[1].Content)
+
+			w = send(http.MethodGet, "/chat/messages?limit=2&cursor="+*history.NextCursor, "", nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+			if assert.Len(t, history.Messages, 1) {
+				assert.Equal(t, "message 0", history.Messages[0].Content)
+			}
+			assert.Nil(t, history.NextCursor)
+		}
+	})
+
+	t.Run("SortAndFilters", func(t *testing.T) {
+		type postsPage struct {
+			Posts      []map[string]interface{} `json:"posts"`
+			Total      int                      `json:"total"`
+			NextCursor *string                  `json:"next_cursor"`
+		}
+		getPosts := func(path string) postsPage {
+			w := send(http.MethodGet, path, "", nil)
+			assert.Equal(t, http.StatusOK, w.Code, path)
+			var page postsPage
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			return page
+		}
+
+		w := send(http.MethodPost, "/posts", token, entity.Post{Title: "Quiet", Content: "Nobody answers"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var quiet entity.Post
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &quiet))
+
+		w = send(http.MethodPost, "/posts", token, entity.Post{Title: "Lively", Content: "Everybody answers"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var lively entity.Post
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &lively))
+		w = send(http.MethodPost, "/posts/"+strconv.Itoa(lively.ID)+"/comments", token, map[string]string{"content": "Latest reply"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		newest := getPosts("/posts?sort=new&limit=1")
+		assert.Equal(t, float64(lively.ID), newest.Posts[0]["id"])
+		oldest := getPosts("/posts?sort=old&limit=1")
+		assert.Equal(t, float64(1), oldest.Posts[0]["id"])
+		assert.NotNil(t, oldest.NextCursor)
+
+		active := getPosts("/posts?sort=active&limit=1")
+		assert.Equal(t, float64(lively.ID), active.Posts[0]["id"])
+		assert.NotNil(t, active.Posts[0]["last_comment_at"])
+		assert.Nil(t, active.NextCursor)
+
+		commented := getPosts("/posts?sort=commented")
+		assert.Equal(t, float64(1), commented.Posts[0]["id"])
+		last := commented.Posts[len(commented.Posts)-1]
+		assert.Equal(t, float64(0), last["comment_count"])
+
+		byAuthor := getPosts("/posts?author_id=999")
+		assert.Equal(t, 0, byAuthor.Total)
+		assert.Empty(t, byAuthor.Posts)
+
+		future := getPosts("/posts?since=" + time.Now().AddDate(0, 0, 2).Format("2006-01-02"))
+		assert.Equal(t, 0, future.Total)
+		today := getPosts("/posts?since=" + time.Now().UTC().Format("2006-01-02") + "&until=" + time.Now().UTC().Format("2006-01-02"))
+		assert.Equal(t, newest.Total, today.Total)
+
+		// Пост без комментариев, созданный после последнего комментария, активнее обсуждавшихся раньше
+		w = send(http.MethodPost, "/posts", token, entity.Post{Title: "Fresh", Content: "Just posted"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var fresh entity.Post
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &fresh))
+		active = getPosts("/posts?sort=active&limit=2")
+		if assert.Len(t, active.Posts, 2) {
+			assert.Equal(t, float64(fresh.ID), active.Posts[0]["id"])
+			assert.Equal(t, float64(lively.ID), active.Posts[1]["id"])
+		}
+
+		w = send(http.MethodGet, "/posts?sort=random", "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = send(http.MethodGet, "/posts?sort=top&cursor=", "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = send(http.MethodGet, "/posts?since=2024-05-02&until=2024-05-01", "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Votes", func(t *testing.T) {
+		otherToken, err := jwtUtil.GenerateToken(2, "user")
+		assert.NoError(t, err)
+
+		w := send(http.MethodPost, "/posts", token, entity.Post{Title: "Vote me", Content: "Please"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var post entity.Post
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &post))
+		postPath := "/posts/" + strconv.Itoa(post.ID)
+
+		w = send(http.MethodPost, postPath+"/comments", token, map[string]string{"content": "Vote me too"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var comment entity.Comment
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &comment))
+
+		vote := func(path, bearer string, value int) entity.VoteResult {
+			w := send(http.MethodPut, path, bearer, map[string]int{"value": value})
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var result entity.VoteResult
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			return result
+		}
+
+		assert.Equal(t, 1, vote(postPath+"/vote", token, 1).Score)
+		assert.Equal(t, 2, vote(postPath+"/vote", otherToken, 1).Score)
+		// повторный голос не накручивает рейтинг, смена голоса меняет его на 2
+		assert.Equal(t, 2, vote(postPath+"/vote", otherToken, 1).Score)
+		assert.Equal(t, 0, vote(postPath+"/vote", otherToken, -1).Score)
+		assert.Equal(t, -1, vote("/comments/"+strconv.Itoa(comment.ID)+"/vote", otherToken, -1).Score)
+
+		var count int
+		assert.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM votes WHERE target_type = 'post' AND target_id = ?`, post.ID))
+		assert.Equal(t, 2, count)
+
+		w = send(http.MethodGet, "/posts?sort=top&limit=50", otherToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var page struct {
+			Posts []map[string]interface{} `json:"posts"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		for _, item := range page.Posts {
+			if item["id"] == float64(post.ID) {
+				assert.Equal(t, float64(0), item["score"])
+				assert.Equal(t, float64(-1), item["my_vote"])
+			} else {
+				assert.Equal(t, float64(0), item["my_vote"])
+			}
+		}
+
+		w = send(http.MethodGet, postPath+"/comments", otherToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"my_vote":-1`)
+		assert.Contains(t, w.Body.String(), `"score":-1`)
+
+		w = send(http.MethodGet, postPath+"/comments?tree=true", otherToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"my_vote":-1`)
+
+		w = send(http.MethodGet, postPath+"/comments", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "my_vote")
+
+		assert.Equal(t, 1, vote(postPath+"/vote", otherToken, 0).Score)
+
+		w = send(http.MethodPut, postPath+"/vote", otherToken, map[string]int{"value": 2})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = send(http.MethodPut, "/posts/9999/vote", otherToken, map[string]int{"value": 1})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w = send(http.MethodPut, postPath+"/vote", "", map[string]int{"value": 1})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Reactions", func(t *testing.T) {
+		otherToken, err := jwtUtil.GenerateToken(2, "user")
+		assert.NoError(t, err)
+
+		w := send(http.MethodPost, "/posts", token, entity.Post{Title: "React to me", Content: "Please"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var post entity.Post
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &post))
+		reactionsPath := "/posts/" + strconv.Itoa(post.ID) + "/reactions/"
+
+		react := func(method, path, bearer string) entity.ReactionResult {
+			w := send(method, path, bearer, nil)
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var result entity.ReactionResult
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			return result
+		}
+
+		react(http.MethodPut, reactionsPath+url.PathEscape("👍"), token)
+		// повторная реакция не считается дважды, "❤" без U+FE0F - та же реакция, что "❤️"
+		react(http.MethodPut, reactionsPath+url.PathEscape("👍"), token)
+		react(http.MethodPut, reactionsPath+url.PathEscape("❤"), token)
+		result := react(http.MethodPut, reactionsPath+url.PathEscape("👍"), otherToken)
+		assert.Equal(t, []entity.ReactionCount{
+			{Emoji: "👍", Count: 2, ReactedByMe: true},
+			{Emoji: "❤️", Count: 1},
+		}, result.Reactions)
+
+		w = send(http.MethodGet, "/posts/"+strconv.Itoa(post.ID), "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"reactions":[{"emoji":"👍","count":2,"reacted_by_me":false},{"emoji":"❤️","count":1,"reacted_by_me":false}]`)
+
+		result = react(http.MethodDelete, reactionsPath+url.PathEscape("❤️"), token)
+		assert.Equal(t, []entity.ReactionCount{{Emoji: "👍", Count: 2, ReactedByMe: true}}, result.Reactions)
+
+		w = send(http.MethodPut, reactionsPath+url.PathEscape("🦄"), token, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = send(http.MethodPut, "/posts/9999/reactions/"+url.PathEscape("👍"), token, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w = send(http.MethodPut, reactionsPath+url.PathEscape("👍"), "", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		_, err = db.Exec(`INSERT INTO chat_messages (user_id, username, content) VALUES (1, 'user', 'hello')`)
+		assert.NoError(t, err)
+		var messageID int
+		assert.NoError(t, db.Get(&messageID, `SELECT MAX(id) FROM chat_messages`))
+		result = react(http.MethodPut, "/chat/messages/"+strconv.Itoa(messageID)+"/reactions/"+url.PathEscape("🎉"), otherToken)
+		assert.Equal(t, []entity.ReactionCount{{Emoji: "🎉", Count: 1, ReactedByMe: true}}, result.Reactions)
+		assert.Contains(t, string((<-hub.Broadcast).Data), `"type":"reaction"`)
+
+		w = send(http.MethodGet, "/chat/messages", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"reactions":[{"emoji":"🎉","count":1,"reacted_by_me":false}]`)
+
+		w = send(http.MethodDelete, "/posts/"+strconv.Itoa(post.ID), token, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		var count int
+		assert.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM reactions WHERE target_type = 'post' AND target_id = ?`, post.ID))
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("ChatRooms", func(t *testing.T) {
+		otherToken, err := jwtUtil.GenerateToken(2, "other")
+		assert.NoError(t, err)
+
+		w := send(http.MethodPost, "/chat/rooms", token, entity.ChatRoomRequest{Name: "team", IsPrivate: true})
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var room entity.ChatRoom
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &room))
+		roomPath := "/chat/rooms/" + strconv.Itoa(room.ID)
+
+		w = send(http.MethodPost, "/chat/rooms", otherToken, entity.ChatRoomRequest{Name: "TEAM"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		roomNames := func(bearer string) []string {
+			w := send(http.MethodGet, "/chat/rooms", bearer, nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+			var rooms []entity.ChatRoom
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rooms))
+			names := make([]string, len(rooms))
+			for i, room := range rooms {
+				names[i] = room.Name
+			}
+			return names
+		}
+		assert.Equal(t, []string{"general", "team"}, roomNames(token))
+		assert.Equal(t, []string{"general"}, roomNames(otherToken))
+		assert.Equal(t, []string{"general"}, roomNames(""))
+
+		w = send(http.MethodGet, roomPath+"/messages", otherToken, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = send(http.MethodPost, roomPath+"/members", otherToken, entity.ChatRoomMemberRequest{UserID: 2})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// Сообщения в комнату не видны подписчикам других комнат
+		startHub()
+		server := httptest.NewServer(router)
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+		_, resp, err := websocket.DefaultDialer.Dial(wsURL+"?token="+otherToken+"&room="+strconv.Itoa(room.ID), nil)
+		assert.Error(t, err)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		}
+
+		owner, _, err := websocket.DefaultDialer.Dial(wsURL+"?token="+token+"&room=1&room="+strconv.Itoa(room.ID), nil)
+		assert.NoError(t, err)
+		defer owner.Close()
+		other, _, err := websocket.DefaultDialer.Dial(wsURL+"?token="+otherToken, nil)
+		assert.NoError(t, err)
+		defer other.Close()
+		time.Sleep(100 * time.Millisecond)
+
+		assert.NoError(t, owner.WriteJSON(map[string]interface{}{"type": "message", "room_id": room.ID, "content": "secret"}))
+		assert.NoError(t, owner.WriteJSON(map[string]interface{}{"type": "message", "room_id": 1, "content": "hello everyone"}))
+
+		// Подписчик общей комнаты сначала получает ее историю, затем "hello everyone", но не "secret"
+		assert.NoError(t, other.SetReadDeadline(time.Now().Add(2*time.Second)))
+		for {
+			var frame map[string]interface{}
+			if !assert.NoError(t, other.ReadJSON(&frame)) {
+				break
+			}
+			assert.NotEqual(t, "secret", frame["content"])
+			if frame["content"] == "hello everyone" {
+				assert.Equal(t, float64(1), frame["room_id"])
+				break
+			}
+		}
+
+		w = send(http.MethodGet, roomPath+"/messages", token, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"secret"`)
+		w = send(http.MethodGet, "/chat/messages", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), `"secret"`)
+
+		w = send(http.MethodPost, roomPath+"/members", token, entity.ChatRoomMemberRequest{UserID: 2})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, []string{"general", "team"}, roomNames(otherToken))
+		w = send(http.MethodGet, roomPath+"/messages", otherToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = send(http.MethodGet, roomPath+"/members", otherToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var members []entity.ChatRoomMember
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &members))
+		assert.Len(t, members, 2)
+
+		w = send(http.MethodDelete, roomPath+"/members/2", otherToken, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = send(http.MethodGet, roomPath+"/messages", otherToken, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = send(http.MethodDelete, "/chat/rooms/1", token, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = send(http.MethodDelete, roomPath, token, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = send(http.MethodGet, roomPath, token, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var count int
+		assert.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM chat_messages WHERE room_id = ?`, room.ID))
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("DirectMessages", func(t *testing.T) {
+		otherToken, err := jwtUtil.GenerateToken(2, "user")
+		assert.NoError(t, err)
+
+		startHub()
+		server := httptest.NewServer(router)
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+		// У получателя открыты две вкладки, сообщение приходит в обе
+		sender, _, err := websocket.DefaultDialer.Dial(wsURL+"?token="+token, nil)
+		assert.NoError(t, err)
+		defer sender.Close()
+		var tabs []*websocket.Conn
+		for i := 0; i < 2; i++ {
+			tab, _, err := websocket.DefaultDialer.Dial(wsURL+"?token="+otherToken, nil)
+			assert.NoError(t, err)
+			defer tab.Close()
+			tabs = append(tabs, tab)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		// Оба пользователя в сети, у получателя два соединения
+		w := send(http.MethodGet, "/chat/online", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var online []entity.OnlineUser
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &online))
+		if assert.Len(t, online, 2) {
+			assert.Equal(t, 1, online[0].UserID)
+			assert.Equal(t, 2, online[1].UserID)
+			assert.Equal(t, 2, online[1].Connections)
+		}
+
+		assert.NoError(t, sender.WriteJSON(map[string]interface{}{"v": 1, "type": "dm", "client_id": "dm-1", "to": 2, "content": "psst"}))
+		for _, tab := range tabs {
+			assert.NoError(t, tab.SetReadDeadline(time.Now().Add(2*time.Second)))
+			for {
+				var frame map[string]interface{}
+				if !assert.NoError(t, tab.ReadJSON(&frame)) {
+					break
+				}
+				if frame["type"] == "dm" {
+					assert.Equal(t, "psst", frame["content"])
+					assert.Equal(t, float64(1), frame["sender_id"])
+					assert.Equal(t, "testuser", frame["username"])
+					break
+				}
+			}
+		}
+		// Отправитель получает подтверждение с id сообщения, повторная отправка не создает копию
+		var ack map[string]interface{}
+		assert.NoError(t, sender.SetReadDeadline(time.Now().Add(2*time.Second)))
+		for ack["type"] != "ack" {
+			ack = nil
+			if !assert.NoError(t, sender.ReadJSON(&ack)) {
+				break
+			}
+		}
+		assert.Equal(t, "dm-1", ack["client_id"])
+		assert.Equal(t, false, ack["duplicate"])
+		assert.NoError(t, sender.WriteJSON(map[string]interface{}{"v": 1, "type": "dm", "client_id": "dm-1", "to": 2, "content": "psst"}))
+		for {
+			var frame map[string]interface{}
+			if !assert.NoError(t, sender.ReadJSON(&frame)) {
+				break
+			}
+			if frame["type"] == "ack" {
+				assert.Equal(t, ack["id"], frame["id"])
+				assert.Equal(t, true, frame["duplicate"])
+				break
+			}
+		}
+
+		// Кадры неизвестного типа отклоняются кадром error
+		assert.NoError(t, sender.WriteJSON(map[string]interface{}{"type": "poke", "to": 2}))
+		for {
+			var frame map[string]interface{}
+			if !assert.NoError(t, sender.ReadJSON(&frame)) {
+				break
+			}
+			if frame["type"] == "error" {
+				assert.Equal(t, entity.ChatErrUnknownType, frame["code"])
+				break
+			}
+		}
+
+		assert.NoError(t, sender.WriteJSON(map[string]interface{}{"v": 1, "type": "dm", "client_id": "dm-2", "to": 2, "content": "are you there?"}))
+		time.Sleep(100 * time.Millisecond)
+
+		type conversations struct {
+			Conversations []entity.Conversation `json:"conversations"`
+			UnreadTotal   int                   `json:"unread_total"`
+		}
+		w = send(http.MethodGet, "/dm/conversations", otherToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var inbox conversations
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &inbox))
+		if assert.Len(t, inbox.Conversations, 1) {
+			assert.Equal(t, 1, inbox.Conversations[0].UserID)
+			assert.Equal(t, "testuser", inbox.Conversations[0].Username)
+			assert.Equal(t, "are you there?", inbox.Conversations[0].LastMessage.Content)
+			assert.Equal(t, 2, inbox.Conversations[0].UnreadCount)
+		}
+		assert.Equal(t, 2, inbox.UnreadTotal)
+
+		w = send(http.MethodGet, "/dm/1/messages?limit=1", otherToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var page struct {
+			Messages   []entity.DirectMessage `json:"messages"`
+			NextCursor *string                `json:"next_cursor"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		if assert.Len(t, page.Messages, 1) && assert.NotNil(t, page.NextCursor) {
+			assert.Equal(t, "are you there?", page.Messages[0].Content)
+			w = send(http.MethodGet, "/dm/1/messages?limit=1&cursor="+*page.NextCursor, otherToken, nil)
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			assert.Equal(t, "psst", page.Messages[0].Content)
+			assert.Nil(t, page.NextCursor)
+		}
+
+		// Переписку не видят посторонние
+		strangerToken, err := jwtUtil.GenerateToken(3, "user")
+		assert.NoError(t, err)
+		w = send(http.MethodGet, "/dm/1/messages", strangerToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "psst")
+
+		w = send(http.MethodPost, "/dm/1/read", otherToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"marked": 2}`, w.Body.String())
+
+		// Отправитель узнает, что его сообщения прочитаны
+		assert.NoError(t, sender.SetReadDeadline(time.Now().Add(2*time.Second)))
+		for {
+			var frame map[string]interface{}
+			if !assert.NoError(t, sender.ReadJSON(&frame)) {
+				break
+			}
+			if frame["type"] == "dm_read" {
+				assert.Equal(t, float64(2), frame["user_id"])
+				break
+			}
+		}
+
+		w = send(http.MethodGet, "/dm/conversations", otherToken, nil)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &inbox))
+		assert.Equal(t, 0, inbox.UnreadTotal)
+		w = send(http.MethodGet, "/dm/2/messages", otherToken, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = send(http.MethodGet, "/dm/conversations", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
 }
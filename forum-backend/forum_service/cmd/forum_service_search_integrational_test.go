@@ -0,0 +1,133 @@
+//go:build sqlite_fts5
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	http2 "github.com/Engls/forum-project2/forum_service/internal/controllers/http"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// Поиск требует FTS5: go test -tags sqlite_fts5 ./cmd/
+func TestForumService_Search_Integration(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	migration, err := os.ReadFile("../../auth_service/migrations/012_create_search_index.up.sql")
+	if err != nil {
+		t.Fatalf("Failed to read search migration: %s", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("Failed to create search index: %s", err)
+	}
+
+	ctx := context.Background()
+	postRepo := repository.NewPostRepository(db, logger)
+	commentRepo := repository.NewCommentsRepository(db, logger)
+
+	goPost, err := postRepo.CreatePost(ctx, entity.Post{AuthorId: 1, Title: "Goroutines <explained>", Content: "Channels and goroutines in depth"})
+	assert.NoError(t, err)
+	rustPost, err := postRepo.CreatePost(ctx, entity.Post{AuthorId: 2, Title: "Rust ownership", Content: "Borrowing rules"})
+	assert.NoError(t, err)
+	comment, err := commentRepo.CreateComment(ctx, entity.Comment{PostId: rustPost.ID, AuthorId: 2, Content: "Goroutines are lighter than threads"})
+	assert.NoError(t, err)
+	deleted, err := commentRepo.CreateComment(ctx, entity.Comment{PostId: rustPost.ID, AuthorId: 1, Content: "goroutines again"})
+	assert.NoError(t, err)
+	assert.NoError(t, commentRepo.SoftDeleteComment(ctx, deleted.ID, time.Now().UTC()))
+
+	searchHandler := http2.NewSearchHandler(usecase.NewSearchUsecase(repository.NewSearchRepository(db, logger), logger), logger, &stubUserService{})
+	router := gin.Default()
+	router.GET("/search", searchHandler.Search)
+
+	search := func(url string) (int, []entity.SearchResult, int) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		router.ServeHTTP(w, req)
+		var response struct {
+			Results    []entity.SearchResult `json:"results"`
+			Pagination struct {
+				Total int `json:"total"`
+			} `json:"pagination"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response.Results, response.Pagination.Total
+	}
+
+	t.Run("RankingAndHighlight", func(t *testing.T) {
+		code, results, total := search("/search?q=gorout")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 2, total)
+		if assert.Len(t, results, 2) {
+			// совпадение в заголовке весит больше
+			assert.Equal(t, entity.SearchTypePost, results[0].Type)
+			assert.Equal(t, goPost.ID, results[0].PostId)
+			assert.Equal(t, "<mark>Goroutines</mark> &lt;explained&gt;", results[0].Title)
+			assert.Equal(t, "testuser", results[0].Username)
+
+			assert.Equal(t, entity.SearchTypeComment, results[1].Type)
+			assert.Equal(t, comment.ID, *results[1].CommentId)
+			assert.Equal(t, "Rust ownership", results[1].Title)
+			assert.Contains(t, results[1].Snippet, "<mark>Goroutines</mark>")
+		}
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		_, results, total := search("/search?q=goroutines&author_id=2")
+		assert.Equal(t, 1, total)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, entity.SearchTypeComment, results[0].Type)
+		}
+
+		_, _, total = search("/search?q=goroutines&until=2000-01-01")
+		assert.Equal(t, 0, total)
+
+		_, _, total = search("/search?q=goroutines&since=2000-01-01&limit=1")
+		assert.Equal(t, 2, total)
+
+		// Граница since включительна и для времени с точностью до секунды
+		stored, err := postRepo.GetPostByID(ctx, goPost.ID)
+		assert.NoError(t, err)
+		since := stored.CreatedAt.UTC().Format(time.RFC3339)
+		_, _, total = search("/search?q=goroutines&since=" + url.QueryEscape(since))
+		assert.Equal(t, 2, total)
+	})
+
+	t.Run("IndexFollowsUpdates", func(t *testing.T) {
+		_, err := postRepo.UpdatePost(ctx, entity.Post{ID: goPost.ID, Title: "Concurrency", Content: "Channels only"}, 1)
+		assert.NoError(t, err)
+
+		_, results, _ := search("/search?q=goroutines")
+		assert.Len(t, results, 1)
+
+		_, results, _ = search("/search?q=concurrency")
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("OperatorsAreLiteral", func(t *testing.T) {
+		code, results, _ := search(`/search?q=rust%20OR%20"borrowing`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, results)
+
+		code, _, _ = search("/search?q=*%20-%20!!!")
+		assert.Equal(t, http.StatusOK, code)
+
+		code, _, _ = search("/search?q=%20")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
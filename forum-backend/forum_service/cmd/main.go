@@ -3,10 +3,12 @@ package main
 import (
 	utils "github.com/Engls/EnglsJwt"
 	_ "github.com/Engls/forum-project2/forum_service/docs"
+	"github.com/Engls/forum-project2/forum_service/internal/authz"
 	"github.com/Engls/forum-project2/forum_service/internal/config"
 	"github.com/Engls/forum-project2/forum_service/internal/controllers/chat"
 	"github.com/Engls/forum-project2/forum_service/internal/controllers/grpc"
 	"github.com/Engls/forum-project2/forum_service/internal/controllers/http"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
 	"github.com/Engls/forum-project2/forum_service/internal/repository"
 	"github.com/Engls/forum-project2/forum_service/internal/usecase"
 	"github.com/gin-contrib/cors"
@@ -47,6 +49,9 @@ func main() {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
 	defer db.Close()
+	if err := repository.CheckFTS5(db); err != nil {
+		logger.Warn("Full-text search is unavailable, /search will fail", zap.Error(err))
+	}
 
 	userClient, err := grpc.NewUserClient("localhost:50052") // Или localhost для тестов
 	if err != nil {
@@ -57,19 +62,39 @@ func main() {
 	postRepo := repository.NewPostRepository(db, logger)
 	commentRepo := repository.NewCommentsRepository(db, logger)
 	chatRepo := repository.NewChatRepository(db, logger)
-	postUsecase := usecase.NewPostUsecase(postRepo, logger)
-	commentUsecase := usecase.NewCommentsUsecases(commentRepo, logger)
+	chatRoomRepo := repository.NewChatRoomRepository(db, logger)
+	directMessageRepo := repository.NewDirectMessageRepository(db, logger)
+	searchRepo := repository.NewSearchRepository(db, logger)
+	categoryRepo := repository.NewCategoryRepository(db, logger)
+	voteRepo := repository.NewVoteRepository(db, logger)
+	reactionRepo := repository.NewReactionRepository(db, logger)
+	postUsecase := usecase.NewPostUsecase(postRepo, categoryRepo, logger)
+	commentUsecase := usecase.NewCommentsUsecases(commentRepo, cfg.MaxCommentDepth, logger)
+	searchUsecase := usecase.NewSearchUsecase(searchRepo, logger)
+	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo, logger)
+	voteUsecase := usecase.NewVoteUsecase(voteRepo, logger)
+	reactionUsecase := usecase.NewReactionUsecase(reactionRepo, cfg.ReactionEmoji, logger)
 	hub := chat.NewHub()
 	chatUsecase := usecase.NewChatUsecase(chatRepo, logger)
-	jwtUtil := utils.NewJWTUtil(cfg.JWTSecret)
+	chatRoomUsecase := usecase.NewChatRoomUsecase(chatRoomRepo, logger)
+	directMessageUsecase := usecase.NewDirectMessageUsecase(directMessageRepo, userClient, logger)
+
+	authMiddleware := middleware.NewAuthMiddleware(userClient, logger)
 
-	postHandler := http.NewPostHandler(postUsecase, postRepo, jwtUtil, logger, userClient)
-	commentHandler := http.NewCommentHandler(commentUsecase, jwtUtil, logger, userClient)
-	chatHandler := http.NewChatHandler(hub, chatUsecase, jwtUtil, logger)
+	usernames := grpc.NewUsernameCache(userClient, cfg.UsernameCacheTTL)
+	authorCards := grpc.NewAuthorCardCache(userClient, cfg.UsernameCacheTTL)
+	postHandler := http.NewPostHandler(postUsecase, postRepo, commentUsecase, voteUsecase, reactionUsecase, logger, usernames, authorCards)
+	commentHandler := http.NewCommentHandler(commentUsecase, voteUsecase, reactionUsecase, logger, usernames)
+	searchHandler := http.NewSearchHandler(searchUsecase, logger, usernames)
+	categoryHandler := http.NewCategoryHandler(categoryUsecase, postUsecase, logger, usernames)
+	chatHandler := http.NewChatHandler(hub, chatUsecase, chatRoomUsecase, directMessageUsecase, reactionUsecase, userClient, usernames, logger)
+	directMessageHandler := http.NewDirectMessageHandler(hub, directMessageUsecase, logger, usernames)
 
 	go hub.Run()
 
-	router := gin.Default()
+	// Вместо gin.Default: журнал запросов без токена из ?token= у /ws
+	router := gin.New()
+	router.Use(middleware.AccessLogger(), gin.Recovery())
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"PUT", "PATCH", "POST", "GET", "DELETE"},
@@ -80,12 +105,51 @@ func main() {
 	}))
 
 	router.GET("/ws", chatHandler.ServeWS)
-	router.POST("/posts", postHandler.CreatePost)
-	router.GET("/posts", postHandler.GetPosts)
-	router.DELETE("/posts/:id", postHandler.DeletePost)
-	router.POST("/posts/:id/comments", commentHandler.CreateComment)
-	router.GET("/posts/:post_id/comments", commentHandler.GetComments)
-	router.PUT("/posts/:id", postHandler.UpdatePost)
+
+	public := router.Group("/", authMiddleware.OptionalAuth())
+	public.GET("/posts", postHandler.GetPosts)
+	public.GET("/posts/:post_id", postHandler.GetPost)
+	public.GET("/posts/:post_id/comments", commentHandler.GetComments)
+	public.GET("/comments/:id/replies", commentHandler.GetReplies)
+	public.GET("/tags", postHandler.GetTags)
+	public.GET("/search", searchHandler.Search)
+	public.GET("/categories", categoryHandler.GetCategories)
+	public.GET("/categories/:slug/posts", categoryHandler.GetCategoryPosts)
+	public.GET("/chat/messages", chatHandler.GetHistory)
+	public.GET("/chat/online", chatHandler.GetOnline)
+	public.GET("/chat/rooms", chatHandler.ListRooms)
+	public.GET("/chat/rooms/:id", chatHandler.GetRoom)
+	public.GET("/chat/rooms/:id/messages", chatHandler.GetRoomHistory)
+	public.GET("/chat/rooms/:id/members", chatHandler.ListRoomMembers)
+
+	protected := router.Group("/", authMiddleware.RequireAuth())
+	protected.POST("/posts", middleware.RequirePermission(authz.PostCreate), postHandler.CreatePost)
+	protected.DELETE("/posts/:id", postHandler.DeletePost)
+	protected.GET("/posts/:post_id/revisions", postHandler.GetPostRevisions)
+	protected.GET("/posts/:post_id/revisions/diff", postHandler.DiffPostRevisions)
+	protected.GET("/posts/:post_id/revisions/:rev", postHandler.GetPostRevision)
+	protected.PUT("/posts/:id", postHandler.UpdatePost)
+	protected.PUT("/posts/:id/vote", middleware.RequirePermission(authz.Vote), postHandler.VotePost)
+	protected.PUT("/posts/:id/reactions/:emoji", middleware.RequirePermission(authz.React), postHandler.AddReaction)
+	protected.DELETE("/posts/:id/reactions/:emoji", middleware.RequirePermission(authz.React), postHandler.RemoveReaction)
+	protected.POST("/posts/:id/comments", middleware.RequirePermission(authz.CommentCreate), commentHandler.CreateComment)
+	protected.PUT("/comments/:id", commentHandler.UpdateComment)
+	protected.DELETE("/comments/:id", commentHandler.DeleteComment)
+	protected.PUT("/comments/:id/vote", middleware.RequirePermission(authz.Vote), commentHandler.VoteComment)
+	protected.PUT("/comments/:id/reactions/:emoji", middleware.RequirePermission(authz.React), commentHandler.AddReaction)
+	protected.DELETE("/comments/:id/reactions/:emoji", middleware.RequirePermission(authz.React), commentHandler.RemoveReaction)
+	protected.POST("/chat/rooms", chatHandler.CreateRoom)
+	protected.DELETE("/chat/rooms/:id", chatHandler.DeleteRoom)
+	protected.POST("/chat/rooms/:id/members", chatHandler.AddRoomMember)
+	protected.DELETE("/chat/rooms/:id/members/:user_id", chatHandler.RemoveRoomMember)
+	protected.PUT("/chat/messages/:id/reactions/:emoji", middleware.RequirePermission(authz.React), chatHandler.AddReaction)
+	protected.DELETE("/chat/messages/:id/reactions/:emoji", middleware.RequirePermission(authz.React), chatHandler.RemoveReaction)
+	protected.GET("/dm/conversations", directMessageHandler.GetConversations)
+	protected.GET("/dm/:userID/messages", directMessageHandler.GetMessages)
+	protected.POST("/dm/:userID/read", directMessageHandler.MarkRead)
+	protected.POST("/categories", middleware.RequirePermission(authz.CategoryManage), categoryHandler.CreateCategory)
+	protected.PUT("/categories/:id", middleware.RequirePermission(authz.CategoryManage), categoryHandler.UpdateCategory)
+	protected.DELETE("/categories/:id", middleware.RequirePermission(authz.CategoryManage), categoryHandler.DeleteCategory)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,6 +14,12 @@ type Config struct {
 	DBPath         string
 	MigrationsPath string
 	JWTSecret      string
+	// UsernameCacheTTL - время жизни кэша имен пользователей
+	UsernameCacheTTL time.Duration
+	// MaxCommentDepth - максимальная глубина вложенности ответов на комментарии
+	MaxCommentDepth int
+	// ReactionEmoji - эмодзи, которыми можно реагировать на посты, комментарии и сообщения чата
+	ReactionEmoji []string
 }
 
 func LoadConfig() (Config, error) {
@@ -21,10 +30,13 @@ func LoadConfig() (Config, error) {
 	}
 
 	cfg := Config{
-		Port:           getEnv("AUTH_SERVICE_PORT", ":8081"),
-		DBPath:         getEnv("DB_PATH", "../../db/forum.db"),
-		MigrationsPath: getEnv("AUTH_SERVICE_MIGRATIONS_PATH", "C:\\forum-project\\forum-backend\\auth_service\\migrations"),
-		JWTSecret:      getEnv("JWT_SECRET", "your-secret-key"),
+		Port:             getEnv("AUTH_SERVICE_PORT", ":8081"),
+		DBPath:           getEnv("DB_PATH", "../../db/forum.db"),
+		MigrationsPath:   getEnv("AUTH_SERVICE_MIGRATIONS_PATH", "C:\\forum-project\\forum-backend\\auth_service\\migrations"),
+		JWTSecret:        getEnv("JWT_SECRET", "your-secret-key"),
+		UsernameCacheTTL: getDurationEnv("USERNAME_CACHE_TTL", time.Minute),
+		MaxCommentDepth:  getIntEnv("MAX_COMMENT_DEPTH", 5),
+		ReactionEmoji:    getListEnv("REACTION_EMOJI", []string{"👍", "👎", "❤️", "😂", "🎉", "😮", "😢"}),
 	}
 	return cfg, nil
 }
@@ -36,3 +48,33 @@ func getEnv(key string, defaultValue string) string {
 	}
 	return value
 }
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getListEnv читает список через запятую, пустые элементы пропускаются
+func getListEnv(key string, defaultValue []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
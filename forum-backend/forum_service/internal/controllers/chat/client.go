@@ -1,34 +1,136 @@
 package chat
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/usecase"
 	"github.com/goccy/go-json"
 	"github.com/gorilla/websocket"
 	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+var (
+	// ErrReadOnly - гость пытается писать в чат
+	ErrReadOnly = errors.New("guests can only read the chat")
+	// errAuthFailed закрывает соединение: токен из кадра auth не прошел проверку
+	errAuthFailed = errors.New("websocket authentication failed")
+)
+
+const (
+	// SendBufferSize - размер канала Send клиента. Если канал переполнен, хаб отключает клиента
+	SendBufferSize = 256
+	// MaxRooms - на сколько комнат может подписаться одно соединение
+	MaxRooms = 10
+	// maxFrameSize - наибольший размер входящего кадра. С запасом вмещает сообщение
+	// из usecase.MaxChatMessageLength символов в конверте. Кадр больше разрывает соединение
+	maxFrameSize = 16 << 10
+)
+
+// Authenticator определяет пользователя по токену и возвращает его id и имя
+type Authenticator func(ctx context.Context, token string) (userID int, username string, err error)
+
+// RoomAccessChecker проверяет, что пользователь может читать комнату и писать в нее
+type RoomAccessChecker func(ctx context.Context, roomID, userID int) error
+
 type Client struct {
-	Hub             *Hub
-	Conn            *websocket.Conn
-	Send            chan []byte
-	UserID          int
-	Username        string
-	IsAuthenticated bool
-	ChatUC          usecase.ChatUsecase
+	Hub  *Hub
+	Conn *websocket.Conn
+	Send chan []byte
+	// UserID и Username берутся из токена, UserID = 0 у гостя, он может только читать.
+	// После регистрации в хабе читаются через ID и Identity: гость может
+	// аутентифицироваться первым кадром {"type":"auth","token":"..."}
+	UserID   int
+	Username string
+	ChatUC   usecase.ChatUsecase
+	DirectUC usecase.DirectMessageUsecase
+	// Authenticate проверяет токен из кадра auth
+	Authenticate Authenticator
+	// CheckRoomAccess проверяет доступ к комнатам из кадра auth уже от имени пользователя
+	CheckRoomAccess RoomAccessChecker
+	identityMu      sync.RWMutex
+	// Rooms - комнаты, на которые подписан клиент. После регистрации в хабе
+	// читается и меняется только через InRoom, RoomIDs и leaveRoom
+	Rooms   map[int]bool
+	roomsMu sync.RWMutex
+	// joinedOnAuth - комнаты из кадра auth, хаб отправляет их историю при повторной регистрации
+	joinedOnAuth []int
+	// framesRead считает входящие кадры, кадр auth принимается только первым
+	framesRead int
+	// closed - хаб отключил клиента и закрыл Send, повторно клиент не регистрируется
+	closed atomic.Bool
+	// lastTyping - когда клиент последний раз сообщил о наборе в комнату, только для ReadPump
+	lastTyping map[int]time.Time
+}
+
+// typingInterval - не чаще одного кадра typing в комнату за интервал от клиента
+const typingInterval = 2 * time.Second
+
+// Closed сообщает, что хаб отключил клиента и закрыл его канал Send
+func (c *Client) Closed() bool {
+	return c.closed.Load()
+}
+
+// ID возвращает id пользователя клиента, 0 - гость
+func (c *Client) ID() int {
+	c.identityMu.RLock()
+	defer c.identityMu.RUnlock()
+	return c.UserID
+}
+
+// Identity возвращает id и имя пользователя клиента
+func (c *Client) Identity() (int, string) {
+	c.identityMu.RLock()
+	defer c.identityMu.RUnlock()
+	return c.UserID, c.Username
+}
+
+func (c *Client) setIdentity(userID int, username string) {
+	c.identityMu.Lock()
+	defer c.identityMu.Unlock()
+	c.UserID, c.Username = userID, username
+}
+
+// InRoom сообщает, подписан ли клиент на комнату
+func (c *Client) InRoom(roomID int) bool {
+	c.roomsMu.RLock()
+	defer c.roomsMu.RUnlock()
+	return c.Rooms[roomID]
+}
+
+// RoomIDs возвращает комнаты клиента по возрастанию id
+func (c *Client) RoomIDs() []int {
+	c.roomsMu.RLock()
+	defer c.roomsMu.RUnlock()
+	ids := make([]int, 0, len(c.Rooms))
+	for id := range c.Rooms {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (c *Client) leaveRoom(roomID int) {
+	c.roomsMu.Lock()
+	defer c.roomsMu.Unlock()
+	delete(c.Rooms, roomID)
 }
 
 func (c *Client) ReadPump() {
-	log.Printf("[CLIENT %d] Starting read pump", c.UserID)
+	log.Printf("[CLIENT %d] Starting read pump", c.ID())
 	defer func() {
-		log.Printf("[CLIENT %d] Closing read pump", c.UserID)
+		log.Printf("[CLIENT %d] Closing read pump", c.ID())
 		c.Hub.Unregister <- c
 		c.Conn.Close()
 	}()
 
-	c.Conn.SetReadLimit(512)
+	c.Conn.SetReadLimit(maxFrameSize)
 	c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	c.Conn.SetPongHandler(func(string) error {
 		c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
@@ -39,70 +141,357 @@ func (c *Client) ReadPump() {
 		_, rawMessage, err := c.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("[CLIENT %d] Read error: %v", c.UserID, err)
+				log.Printf("[CLIENT %d] Read error: %v", c.ID(), err)
 			}
 			break
 		}
-		log.Printf("[CLIENT %d] Received raw message: %s", c.UserID, string(rawMessage))
+		if c.Closed() {
+			log.Printf("[CLIENT %d] Disconnected by hub, stopping read pump", c.ID())
+			break
+		}
+		log.Printf("[CLIENT %d] Received raw message: %s", c.ID(), redactFrame(rawMessage))
 
 		if err2 := c.handleIncomingMessage(rawMessage); err2 != nil {
-			log.Printf("[CLIENT %d] Message handling error: %v", c.UserID, err2)
+			log.Printf("[CLIENT %d] Message handling error: %v", c.ID(), err2)
+			if errors.Is(err2, errAuthFailed) {
+				break
+			}
 		}
 	}
 }
 
+// redactFrame возвращает кадр для журнала: токен кадра auth не пишется
+func redactFrame(rawMessage []byte) string {
+	var frame entity.ChatFrame
+	if err := json.Unmarshal(rawMessage, &frame); err != nil {
+		if bytes.Contains(rawMessage, []byte("token")) {
+			return "[unparsable frame with token REDACTED]"
+		}
+		return string(rawMessage)
+	}
+	if frame.Token == "" {
+		return string(rawMessage)
+	}
+	return fmt.Sprintf(`{"type":%q,"token":"REDACTED"}`, frame.Type)
+}
+
+// frameError - ошибка кадра, о которой клиент узнает кадром error с кодом code
+type frameError struct {
+	code    string
+	message string
+}
+
+func (e *frameError) Error() string {
+	return e.message
+}
+
+func newFrameError(code, format string, args ...interface{}) error {
+	return &frameError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// handleIncomingMessage разбирает кадр entity.ChatFrame и передает его обработчику типа.
+// Ошибку обработки клиент получает кадром error
 func (c *Client) handleIncomingMessage(rawMessage []byte) error {
 	if len(rawMessage) == 0 {
-		log.Printf("[CLIENT %d] Empty message received", c.UserID)
+		log.Printf("[CLIENT %d] Empty message received", c.ID())
 		return nil
 	}
+	c.framesRead++
+
+	var frame entity.ChatFrame
+	err := json.Unmarshal(rawMessage, &frame)
+	switch {
+	case err != nil:
+		err = newFrameError(entity.ChatErrBadFrame, "frame is not a valid JSON object: %v", err)
+	case frame.V != 0 && frame.V != entity.ChatProtocolVersion:
+		err = newFrameError(entity.ChatErrUnsupportedVersion, "unsupported protocol version %d, server speaks %d", frame.V, entity.ChatProtocolVersion)
+	default:
+		err = c.handleFrame(frame)
+	}
+
+	if err != nil && !errors.Is(err, errAuthFailed) {
+		c.replyError(frame.ClientID, err)
+	}
+	return err
+}
+
+func (c *Client) handleFrame(frame entity.ChatFrame) error {
+	switch frame.Type {
+	case entity.FrameMessage:
+		return c.handleChatMessage(frame)
+	case entity.FrameDirectMessage:
+		return c.handleDirectMessage(frame)
+	case entity.FrameTyping:
+		return c.handleTyping(frame)
+	case entity.FrameHistoryRequest:
+		return c.handleHistoryRequest(frame)
+	case entity.FrameAuth:
+		return c.handleAuth(frame)
+	case entity.FramePresence, entity.FrameAck, entity.FrameError, entity.FrameHistory:
+		return newFrameError(entity.ChatErrUnknownType, "frame type %q is sent only by the server", frame.Type)
+	case "":
+		return newFrameError(entity.ChatErrBadFrame, "frame type is required")
+	default:
+		return newFrameError(entity.ChatErrUnknownType, "unknown frame type %q", frame.Type)
+	}
+}
+
+// replyError отправляет клиенту кадр error. Неизвестные ошибки не раскрываются
+func (c *Client) replyError(clientID string, err error) {
+	event := entity.ChatError{V: entity.ChatProtocolVersion, Type: entity.FrameError, ClientID: clientID, Error: err.Error()}
+	var fe *frameError
+	switch {
+	case errors.As(err, &fe):
+		event.Code = fe.code
+	case errors.Is(err, ErrReadOnly):
+		event.Code = entity.ChatErrReadOnly
+	case errors.Is(err, usecase.ErrChatMessageTooLong), errors.Is(err, usecase.ErrDirectMessageTooLong):
+		event.Code = entity.ChatErrBadFrame
+	case errors.Is(err, usecase.ErrEmptyChatMessage), errors.Is(err, usecase.ErrInvalidClientID),
+		errors.Is(err, usecase.ErrInvalidRecipient), errors.Is(err, usecase.ErrEmptyDirectMessage),
+		errors.Is(err, entity.ErrInvalidCursor), errors.Is(err, usecase.ErrChatRoomNotFound),
+		errors.Is(err, usecase.ErrChatRoomForbidden):
+		event.Code = entity.ChatErrInvalid
+	default:
+		event.Code, event.Error = entity.ChatErrInternal, "internal server error"
+	}
+	if err := c.Hub.SendTo(c, event); err != nil {
+		log.Printf("[CLIENT %d] Failed to send error frame: %v", c.ID(), err)
+	}
+}
+
+// ack подтверждает отправителю, что сообщение сохранено
+func (c *Client) ack(clientID string, id int, timestamp time.Time, created bool) error {
+	return c.Hub.SendTo(c, entity.ChatAck{
+		V:         entity.ChatProtocolVersion,
+		Type:      entity.FrameAck,
+		ClientID:  clientID,
+		ID:        id,
+		Timestamp: timestamp,
+		Duplicate: !created,
+	})
+}
 
-	var msg entity.ChatMessage
-	if err := json.Unmarshal(rawMessage, &msg); err != nil {
-		log.Printf("[CLIENT %d] Failed to unmarshal message, creating default: %v", c.UserID, err)
-		msg = entity.ChatMessage{
-			UserID:    c.UserID,
-			Username:  c.Username,
-			Content:   string(rawMessage),
-			Timestamp: time.Now(),
+// frameRoom возвращает комнату кадра: без room_id - единственная комната клиента
+func (c *Client) frameRoom(frame entity.ChatFrame) (int, error) {
+	roomID := frame.RoomID
+	if roomID == 0 {
+		if rooms := c.RoomIDs(); len(rooms) == 1 {
+			roomID = rooms[0]
 		}
 	}
+	if !c.InRoom(roomID) {
+		return 0, newFrameError(entity.ChatErrNotSubscribed, "client is not subscribed to room %d", roomID)
+	}
+	return roomID, nil
+}
 
-	if msg.Content == "" {
-		log.Printf("[CLIENT %d] Empty content in message", c.UserID)
+// handleChatMessage сохраняет сообщение в комнату, подтверждает его отправителю и рассылает
+// подписчикам комнаты. Повторный кадр с тем же client_id только подтверждается
+func (c *Client) handleChatMessage(frame entity.ChatFrame) error {
+	userID, username := c.Identity()
+	if userID == 0 {
+		return ErrReadOnly
+	}
+	roomID, err := c.frameRoom(frame)
+	if err != nil {
+		return err
+	}
+
+	// Автор сообщения - всегда пользователь соединения
+	log.Printf("[CLIENT %d] Saving message to DB: %s", userID, frame.Content)
+	msg, created, err := c.ChatUC.HandleMessage(context.Background(), roomID, userID, username, frame.Content, frame.ClientID)
+	if err != nil {
+		return err
+	}
+	if err := c.ack(frame.ClientID, msg.ID, msg.Timestamp, created); err != nil {
+		return err
+	}
+	if !created {
+		log.Printf("[CLIENT %d] Duplicate message %s acknowledged", userID, frame.ClientID)
 		return nil
 	}
-	log.Println(c.IsAuthenticated)
-	if c.IsAuthenticated {
-		log.Printf("[CLIENT %d] Saving message to DB: %s", c.UserID, msg.Content)
-		if err := c.ChatUC.HandleMessage(context.Background(), msg.UserID, msg.Username, msg.Content); err != nil {
-			log.Printf("[CLIENT %d] DB save error: %v", c.UserID, err)
+
+	log.Printf("[CLIENT %d] Broadcasting message %d to room %d", userID, msg.ID, roomID)
+	return c.Hub.Publish(roomID, entity.ChatMessageEvent{V: entity.ChatProtocolVersion, Type: entity.FrameMessage, ChatMessage: *msg})
+}
+
+// handleAuth аутентифицирует гостя токеном из первого кадра. При неверном токене
+// соединение закрывается
+func (c *Client) handleAuth(frame entity.ChatFrame) error {
+	if c.framesRead > 1 || c.ID() != 0 || c.Authenticate == nil {
+		return newFrameError(entity.ChatErrBadFrame, "auth frame is accepted only as the first message of a guest connection")
+	}
+
+	userID, username, err := c.Authenticate(context.Background(), frame.Token)
+	if err != nil {
+		closeMessage := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "invalid token")
+		c.Conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+		return fmt.Errorf("%w: %v", errAuthFailed, err)
+	}
+	// Закрытые комнаты гость не может указать при подключении, поэтому они приходят в кадре auth
+	// и проверяются уже для пользователя. Если хотя бы одна недоступна, соединение остается гостевым
+	rooms, err := c.authRooms(frame.Rooms, userID)
+	if err != nil {
+		return err
+	}
+
+	c.setIdentity(userID, username)
+	c.joinRooms(rooms)
+	log.Printf("[CLIENT %d] Authenticated by auth frame as %s", userID, username)
+	// Повторная регистрация учитывает пользователя в присутствии
+	c.Hub.Register <- c
+	return c.Hub.SendTo(c, map[string]interface{}{
+		"v":        entity.ChatProtocolVersion,
+		"type":     entity.FrameAuth,
+		"user_id":  userID,
+		"username": username,
+	})
+}
+
+// authRooms возвращает комнаты из кадра auth, на которые клиент еще не подписан, проверив доступ к ним
+func (c *Client) authRooms(roomIDs []int, userID int) ([]int, error) {
+	var rooms []int
+	seen := make(map[int]bool, len(roomIDs))
+	for _, roomID := range roomIDs {
+		if !seen[roomID] && !c.InRoom(roomID) {
+			seen[roomID] = true
+			rooms = append(rooms, roomID)
+		}
+	}
+	if len(rooms) == 0 {
+		return nil, nil
+	}
+	if len(c.RoomIDs())+len(rooms) > MaxRooms {
+		return nil, newFrameError(entity.ChatErrBadFrame, "at most %d rooms per connection", MaxRooms)
+	}
+	if c.CheckRoomAccess == nil {
+		return nil, newFrameError(entity.ChatErrBadFrame, "rooms are not accepted in the auth frame")
+	}
+	for _, roomID := range rooms {
+		if err := c.CheckRoomAccess(context.Background(), roomID, userID); err != nil {
+			return nil, fmt.Errorf("room %d: %w", roomID, err)
 		}
 	}
+	return rooms, nil
+}
+
+// joinRooms подписывает клиента на комнаты из кадра auth
+func (c *Client) joinRooms(roomIDs []int) {
+	c.roomsMu.Lock()
+	defer c.roomsMu.Unlock()
+	for _, roomID := range roomIDs {
+		c.Rooms[roomID] = true
+	}
+	c.joinedOnAuth = roomIDs
+}
+
+// takeJoinedOnAuth возвращает комнаты из кадра auth один раз
+func (c *Client) takeJoinedOnAuth() []int {
+	c.roomsMu.Lock()
+	defer c.roomsMu.Unlock()
+	rooms := c.joinedOnAuth
+	c.joinedOnAuth = nil
+	return rooms
+}
+
+// handleDirectMessage сохраняет личное сообщение, подтверждает его отправителю и доставляет
+// всем клиентам получателя и отправителя. Повторный кадр с тем же client_id только подтверждается
+func (c *Client) handleDirectMessage(frame entity.ChatFrame) error {
+	userID, username := c.Identity()
+	if userID == 0 {
+		return ErrReadOnly
+	}
 
-	outMsg := map[string]interface{}{
-		"userID":    msg.UserID,
-		"username":  msg.Username,
-		"content":   msg.Content,
-		"timestamp": time.Now().Format(time.RFC3339),
+	msg, created, err := c.DirectUC.SendMessage(context.Background(), userID, frame.To, frame.Content, frame.ClientID)
+	if err != nil {
+		return err
+	}
+	if err := c.ack(frame.ClientID, msg.ID, msg.CreatedAt, created); err != nil {
+		return err
+	}
+	if !created {
+		return nil
+	}
+
+	log.Printf("[CLIENT %d] Sending direct message %d to user %d", userID, msg.ID, msg.RecipientID)
+	event := entity.DirectMessageEvent{V: entity.ChatProtocolVersion, Type: entity.FrameDirectMessage, DirectMessage: *msg, Username: username}
+	return c.Hub.PublishToUsers(event, msg.RecipientID, msg.SenderID)
+}
+
+// handleTyping рассылает подписчикам комнаты, что пользователь набирает сообщение.
+// Кадры чаще typingInterval отбрасываются без ответа: клиент шлет их на каждое нажатие
+func (c *Client) handleTyping(frame entity.ChatFrame) error {
+	userID, username := c.Identity()
+	if userID == 0 {
+		return ErrReadOnly
+	}
+	roomID, err := c.frameRoom(frame)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.Sub(c.lastTyping[roomID]) < typingInterval {
+		return nil
+	}
+	if c.lastTyping == nil {
+		c.lastTyping = make(map[int]time.Time)
 	}
+	c.lastTyping[roomID] = now
+
+	return c.Hub.Publish(roomID, entity.ChatTypingEvent{
+		V:        entity.ChatProtocolVersion,
+		Type:     entity.FrameTyping,
+		RoomID:   roomID,
+		UserID:   userID,
+		Username: username,
+	})
+}
 
-	jsonMsg, err := json.Marshal(outMsg)
+// handleHistoryRequest отправляет клиенту страницу истории комнаты, на которую он подписан
+func (c *Client) handleHistoryRequest(frame entity.ChatFrame) error {
+	roomID, err := c.frameRoom(frame)
 	if err != nil {
-		log.Printf("[CLIENT %d] Marshal error: %v", c.UserID, err)
 		return err
 	}
+	var before *entity.Cursor
+	if frame.Before != "" {
+		if before, err = entity.DecodeCursor(frame.Before); err != nil {
+			return err
+		}
+	}
+	limit := frame.Limit
+	if limit < 1 {
+		limit = 50
+	}
+	if limit > usecase.MaxChatHistoryLimit {
+		limit = usecase.MaxChatHistoryLimit
+	}
 
-	log.Printf("[CLIENT %d] Broadcasting message: %s", c.UserID, string(jsonMsg))
-	c.Hub.Broadcast <- jsonMsg
-	return nil
+	messages, err := c.ChatUC.GetHistory(context.Background(), roomID, before, limit+1)
+	if err != nil {
+		return err
+	}
+
+	// Сообщения идут от старых к новым, лишнее самое старое означает, что история продолжается
+	event := entity.ChatHistoryEvent{V: entity.ChatProtocolVersion, Type: entity.FrameHistory, RoomID: roomID, Messages: messages}
+	if len(messages) > limit {
+		event.Messages = messages[len(messages)-limit:]
+		next := entity.CursorAfter(event.Messages[0].Timestamp, event.Messages[0].ID).Encode()
+		event.NextCursor = &next
+	}
+	if event.Messages == nil {
+		event.Messages = []entity.ChatMessage{}
+	}
+	return c.Hub.SendTo(c, event)
 }
 
 func (c *Client) WritePump() {
-	log.Printf("[CLIENT %d] Starting write pump", c.UserID)
+	log.Printf("[CLIENT %d] Starting write pump", c.ID())
 	ticker := time.NewTicker(50 * time.Second)
 	defer func() {
-		log.Printf("[CLIENT %d] Closing write pump", c.UserID)
+		log.Printf("[CLIENT %d] Closing write pump", c.ID())
 		ticker.Stop()
 		c.Conn.Close()
 	}()
@@ -112,35 +501,35 @@ func (c *Client) WritePump() {
 		case message, ok := <-c.Send:
 			c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if !ok {
-				log.Printf("[CLIENT %d] Send channel closed, sending close message", c.UserID)
+				log.Printf("[CLIENT %d] Send channel closed, sending close message", c.ID())
 				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
 
-			log.Printf("[CLIENT %d] Preparing to write message: %s", c.UserID, string(message))
+			log.Printf("[CLIENT %d] Preparing to write message: %s", c.ID(), string(message))
 			w, err := c.Conn.NextWriter(websocket.TextMessage)
 			if err != nil {
-				log.Printf("[CLIENT %d] NextWriter error: %v", c.UserID, err)
+				log.Printf("[CLIENT %d] NextWriter error: %v", c.ID(), err)
 				return
 			}
 			if _, err := w.Write(message); err != nil {
-				log.Printf("[CLIENT %d] Write error: %v", c.UserID, err)
+				log.Printf("[CLIENT %d] Write error: %v", c.ID(), err)
 				return
 			}
 
 			if err := w.Close(); err != nil {
-				log.Printf("[CLIENT %d] Writer close error: %v", c.UserID, err)
+				log.Printf("[CLIENT %d] Writer close error: %v", c.ID(), err)
 				return
 			}
-			log.Printf("[CLIENT %d] Message successfully sent", c.UserID)
+			log.Printf("[CLIENT %d] Message successfully sent", c.ID())
 
 		case <-ticker.C:
 			c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				log.Printf("[CLIENT %d] Ping error: %v", c.UserID, err)
+				log.Printf("[CLIENT %d] Ping error: %v", c.ID(), err)
 				return
 			}
-			log.Printf("[CLIENT %d] Ping sent", c.UserID)
+			log.Printf("[CLIENT %d] Ping sent", c.ID())
 		}
 	}
 }
@@ -0,0 +1,118 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRedactFrame(t *testing.T) {
+	assert.Equal(t, `{"type":"auth","token":"REDACTED"}`, redactFrame([]byte(`{"type":"auth","token":"secret.jwt"}`)))
+	assert.Equal(t, `{"type":"message","content":"hi"}`, redactFrame([]byte(`{"type":"message","content":"hi"}`)))
+	assert.Equal(t, `not json`, redactFrame([]byte(`not json`)))
+	assert.NotContains(t, redactFrame([]byte(`{"type":"auth","token":"secret.jwt"`)), "secret.jwt")
+}
+
+func TestClient_TypingRateLimit(t *testing.T) {
+
+	// Хаб не запущен: рассылки остаются в буфере hub.Broadcast
+	hub := NewHub()
+	client := newTestClient(hub, new(mocks.ChatUsecase), 1, "alice", 10, 1, 2)
+
+	typing := func(roomID int) {
+		data, _ := json.Marshal(entity.ChatFrame{Type: entity.FrameTyping, RoomID: roomID})
+		assert.NoError(t, client.handleIncomingMessage(data))
+	}
+
+	// Повторные кадры в ту же комнату в пределах typingInterval отбрасываются
+	typing(1)
+	typing(1)
+	typing(1)
+	assert.Len(t, hub.Broadcast, 1)
+
+	// Ограничение действует для каждой комнаты отдельно
+	typing(2)
+	assert.Len(t, hub.Broadcast, 2)
+
+	// После typingInterval кадр снова рассылается
+	client.lastTyping[1] = time.Now().Add(-typingInterval)
+	typing(1)
+	assert.Len(t, hub.Broadcast, 3)
+
+	var event entity.ChatTypingEvent
+	for _, roomID := range []int{1, 2, 1} {
+		message := <-hub.Broadcast
+		assert.Equal(t, roomID, message.RoomID)
+		assert.NoError(t, json.Unmarshal(message.Data, &event))
+		assert.Equal(t, entity.ChatTypingEvent{V: entity.ChatProtocolVersion, Type: entity.FrameTyping, RoomID: roomID, UserID: 1, Username: "alice"}, event)
+	}
+}
+
+func TestClient_TypingGuest(t *testing.T) {
+
+	hub := NewHub()
+	guest := newTestClient(hub, new(mocks.ChatUsecase), 0, "", 10, 1)
+
+	err := guest.handleIncomingMessage([]byte(`{"type":"typing","room_id":1}`))
+
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.Empty(t, hub.Broadcast)
+	reply := <-hub.Reply
+	assert.Contains(t, string(reply.Data), entity.ChatErrReadOnly)
+}
+
+func TestClient_AuthFrame(t *testing.T) {
+
+	hub := NewHub()
+	go hub.Run()
+
+	guest := newTestClient(hub, new(mocks.ChatUsecase), 0, "", 10)
+	guest.Authenticate = func(ctx context.Context, token string) (int, string, error) {
+		assert.Equal(t, "valid.jwt", token)
+		return 3, "carol", nil
+	}
+	hub.Register <- guest
+
+	// Первый кадр auth делает гостя пользователем и учитывает его в присутствии
+	assert.NoError(t, guest.handleIncomingMessage([]byte(`{"type":"auth","token":"valid.jwt"}`)))
+	assert.JSONEq(t, `{"v":1,"type":"auth","user_id":3,"username":"carol"}`, string(receive(t, guest)))
+	userID, username := guest.Identity()
+	assert.Equal(t, 3, userID)
+	assert.Equal(t, "carol", username)
+	assert.Equal(t, []entity.OnlineUser{{UserID: 3, Username: "carol", Connections: 1, Since: hub.Online()[0].Since}}, hub.Online())
+
+	// Повторный кадр auth отклоняется, пользователь соединения не меняется
+	assert.Error(t, guest.handleIncomingMessage([]byte(`{"type":"auth","token":"other.jwt"}`)))
+	assert.Contains(t, string(receive(t, guest)), entity.ChatErrBadFrame)
+	userID, _ = guest.Identity()
+	assert.Equal(t, 3, userID)
+	assert.Len(t, hub.Online(), 1)
+}
+
+func TestClient_MessageTooLong(t *testing.T) {
+
+	hub := NewHub()
+	chatUC := new(mocks.ChatUsecase)
+	client := newTestClient(hub, chatUC, 1, "alice", 10, 1)
+
+	// Сообщение длиннее лимита в символах помещается в кадр, но отклоняется как bad_frame
+	content := strings.Repeat("я", usecase.MaxChatMessageLength+1)
+	chatUC.On("HandleMessage", mock.Anything, 1, 1, "alice", content, "c-1").Return(nil, false, usecase.ErrChatMessageTooLong)
+	data, _ := json.Marshal(entity.ChatFrame{Type: entity.FrameMessage, RoomID: 1, Content: content, ClientID: "c-1"})
+	assert.Less(t, len(data), maxFrameSize)
+
+	assert.ErrorIs(t, client.handleIncomingMessage(data), usecase.ErrChatMessageTooLong)
+	reply := <-hub.Reply
+	assert.Contains(t, string(reply.Data), entity.ChatErrBadFrame)
+	assert.Contains(t, string(reply.Data), `"client_id":"c-1"`)
+	assert.Empty(t, hub.Broadcast)
+	chatUC.AssertExpectations(t)
+}
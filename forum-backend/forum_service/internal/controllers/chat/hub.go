@@ -4,21 +4,67 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
 )
 
+// RoomMessage - кадр для клиентов, подписанных на комнату RoomID
+type RoomMessage struct {
+	RoomID int
+	Data   []byte
+}
+
+// RoomLeave отписывает от комнаты клиентов пользователя UserID, UserID = 0 - всех клиентов
+type RoomLeave struct {
+	RoomID int
+	UserID int
+}
+
+// UserMessage - кадр для всех клиентов пользователей UserIDs
+type UserMessage struct {
+	UserIDs []int
+	Data    []byte
+}
+
+// ClientMessage - кадр для одного клиента
+type ClientMessage struct {
+	Client *Client
+	Data   []byte
+}
+
+// presence - открытые соединения пользователя
+type presence struct {
+	username string
+	clients  map[*Client]bool
+	since    time.Time
+}
+
 type Hub struct {
 	Clients    map[*Client]bool
-	Broadcast  chan []byte
+	Broadcast  chan RoomMessage
 	Register   chan *Client
 	Unregister chan *Client
+	Leave      chan RoomLeave
+	Direct     chan UserMessage
+	Reply      chan ClientMessage
+	// online меняется только в Run, Online читает его под presenceMu
+	online     map[int]*presence
+	presenceMu sync.RWMutex
 }
 
 func NewHub() *Hub {
 	return &Hub{
 		Clients:    make(map[*Client]bool),
-		Broadcast:  make(chan []byte, 100), // Буферизованный канал
+		Broadcast:  make(chan RoomMessage, 100), // Буферизованный канал
 		Register:   make(chan *Client),
 		Unregister: make(chan *Client),
+		Leave:      make(chan RoomLeave, 100),
+		Direct:     make(chan UserMessage, 100),
+		Reply:      make(chan ClientMessage, 100),
+		online:     make(map[int]*presence),
 	}
 }
 
@@ -27,56 +73,256 @@ func (h *Hub) Run() {
 	for {
 		select {
 		case client := <-h.Register:
-			log.Printf("[HUB] Registering new client: UserID=%d, Username=%s", client.UserID, client.Username)
-			h.Clients[client] = true
-
-			messages, err := client.ChatUC.GetRecentMessages(context.Background(), 50)
-			if err != nil {
-				log.Printf("[HUB] Error getting messages: %v", err)
+			userID, username := client.Identity()
+			if client.Closed() {
+				// Хаб уже отключил клиента, его канал Send закрыт
+				log.Printf("[HUB] Ignoring registration of closed client: UserID=%d", userID)
 				continue
 			}
-			log.Printf("[HUB] Sending %d historical messages to client %d", len(messages), client.UserID)
-
-			for _, msg := range messages {
-				jsonMsg, err := json.Marshal(msg)
-				if err != nil {
-					log.Printf("[HUB] Error marshaling message: %v", err)
-					continue
+			if h.Clients[client] {
+				// Повторная регистрация после кадра auth: гость стал пользователем
+				// и, возможно, подписался на закрытые комнаты
+				log.Printf("[HUB] Client authenticated: UserID=%d, Username=%s", userID, username)
+				h.goOnline(client)
+				limit := historyLimit(cap(client.Send), len(client.RoomIDs()))
+				for _, roomID := range client.takeJoinedOnAuth() {
+					if !h.sendHistory(client, roomID, limit) {
+						break
+					}
 				}
-				select {
-				case client.Send <- jsonMsg:
-					log.Printf("[HUB] Historical message sent to %d", client.UserID)
-				default:
-					log.Printf("[HUB] Client %d send channel blocked, closing", client.UserID)
-					close(client.Send)
-					delete(h.Clients, client)
+				continue
+			}
+			log.Printf("[HUB] Registering new client: UserID=%d, Username=%s", userID, username)
+			h.Clients[client] = true
+			h.goOnline(client)
+
+			rooms := client.RoomIDs()
+			limit := historyLimit(cap(client.Send), len(rooms))
+			for _, roomID := range rooms {
+				if !h.sendHistory(client, roomID, limit) {
+					break
 				}
 			}
 
 		case client := <-h.Unregister:
-			log.Printf("[HUB] Unregistering client: UserID=%d", client.UserID)
+			log.Printf("[HUB] Unregistering client: UserID=%d", client.ID())
 			if _, ok := h.Clients[client]; ok {
-				delete(h.Clients, client)
-				close(client.Send)
+				h.disconnect(client)
+			}
+
+		case leave := <-h.Leave:
+			log.Printf("[HUB] Leaving room %d: UserID=%d", leave.RoomID, leave.UserID)
+			for client := range h.Clients {
+				if leave.UserID == 0 || client.ID() == leave.UserID {
+					client.leaveRoom(leave.RoomID)
+				}
 			}
 
 		case message := <-h.Broadcast:
-			log.Printf("[HUB] Broadcasting message to %d clients: %s", len(h.Clients), string(message))
-			if len(message) == 0 {
+			log.Printf("[HUB] Broadcasting message to room %d: %s", message.RoomID, string(message.Data))
+			if len(message.Data) == 0 {
 				log.Println("[HUB] Warning: empty message received")
 				continue
 			}
 
 			for client := range h.Clients {
-				select {
-				case client.Send <- message:
-					log.Printf("[HUB] Message sent to client %d", client.UserID)
-				default:
-					log.Printf("[HUB] Client %d channel blocked, disconnecting", client.UserID)
-					close(client.Send)
-					delete(h.Clients, client)
+				if client.InRoom(message.RoomID) {
+					h.deliver(client, message.Data)
+				}
+			}
+
+		case message := <-h.Direct:
+			log.Printf("[HUB] Sending direct message to users %v", message.UserIDs)
+			for client := range h.Clients {
+				clientUserID := client.ID()
+				for _, userID := range message.UserIDs {
+					if clientUserID != 0 && clientUserID == userID {
+						h.deliver(client, message.Data)
+						break
+					}
 				}
 			}
+
+		case message := <-h.Reply:
+			// Клиент мог отключиться, пока кадр ждал в очереди
+			if _, ok := h.Clients[message.Client]; ok {
+				h.deliver(message.Client, message.Data)
+			}
 		}
 	}
 }
+
+// deliver кладет кадр в канал клиента. Если канал переполнен, клиент отключается
+func (h *Hub) deliver(client *Client, data []byte) {
+	select {
+	case client.Send <- data:
+		log.Printf("[HUB] Message sent to client %d", client.ID())
+	default:
+		log.Printf("[HUB] Client %d channel blocked, disconnecting", client.ID())
+		h.disconnect(client)
+	}
+}
+
+// disconnect удаляет клиента из хаба и закрывает его канал
+func (h *Hub) disconnect(client *Client) {
+	delete(h.Clients, client)
+	client.closed.Store(true)
+	close(client.Send)
+	h.goOffline(client)
+}
+
+// goOnline учитывает соединение пользователя. О первом соединении узнают все клиенты
+func (h *Hub) goOnline(client *Client) {
+	userID, username := client.Identity()
+	if userID == 0 {
+		return
+	}
+
+	h.presenceMu.Lock()
+	user, ok := h.online[userID]
+	if !ok {
+		user = &presence{username: username, clients: make(map[*Client]bool), since: time.Now()}
+		h.online[userID] = user
+	}
+	user.clients[client] = true
+	h.presenceMu.Unlock()
+
+	if !ok {
+		h.broadcastPresence(client, userID, username, entity.PresenceOnline)
+	}
+}
+
+// goOffline убирает соединение пользователя. О закрытии последнего соединения узнают все клиенты
+func (h *Hub) goOffline(client *Client) {
+	userID, username := client.Identity()
+
+	h.presenceMu.Lock()
+	user, ok := h.online[userID]
+	if ok {
+		delete(user.clients, client)
+		ok = len(user.clients) == 0
+		if ok {
+			delete(h.online, userID)
+		}
+	}
+	h.presenceMu.Unlock()
+
+	if ok {
+		h.broadcastPresence(client, userID, username, entity.PresenceOffline)
+	}
+}
+
+// broadcastPresence рассылает кадр presence всем клиентам, кроме соединения source, которое его вызвало:
+// подключившийся клиент узнает, кто в сети, из GET /chat/online
+func (h *Hub) broadcastPresence(source *Client, userID int, username, status string) {
+	log.Printf("[HUB] User %d is %s", userID, status)
+	data, err := json.Marshal(entity.ChatPresenceEvent{
+		V:        entity.ChatProtocolVersion,
+		Type:     entity.FramePresence,
+		UserID:   userID,
+		Username: username,
+		Status:   status,
+	})
+	if err != nil {
+		log.Printf("[HUB] Error marshaling presence: %v", err)
+		return
+	}
+	for client := range h.Clients {
+		if client != source {
+			h.deliver(client, data)
+		}
+	}
+}
+
+// Online возвращает пользователей, у которых открыто хотя бы одно соединение, по возрастанию id
+func (h *Hub) Online() []entity.OnlineUser {
+	h.presenceMu.RLock()
+	defer h.presenceMu.RUnlock()
+
+	users := make([]entity.OnlineUser, 0, len(h.online))
+	for userID, user := range h.online {
+		users = append(users, entity.OnlineUser{
+			UserID:      userID,
+			Username:    user.username,
+			Connections: len(user.clients),
+			Since:       user.since,
+		})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].UserID < users[j].UserID })
+	return users
+}
+
+// historyOnConnect - сколько последних сообщений комнаты получает клиент при подключении
+const historyOnConnect = 50
+
+// historyLimit делит между комнатами половину канала клиента, чтобы история при подключении
+// не переполнила канал. Более ранние сообщения клиент запрашивает кадром history_request
+func historyLimit(buffer, rooms int) int {
+	if rooms == 0 {
+		return historyOnConnect
+	}
+	return max(1, min(historyOnConnect, buffer/2/rooms))
+}
+
+// sendHistory отправляет клиенту последние limit сообщений комнаты. Если канал клиента
+// переполнен, клиент отключается и возвращается false
+func (h *Hub) sendHistory(client *Client, roomID, limit int) bool {
+	messages, err := client.ChatUC.GetRecentMessages(context.Background(), roomID, limit)
+	if err != nil {
+		log.Printf("[HUB] Error getting messages: %v", err)
+		return true
+	}
+	log.Printf("[HUB] Sending %d historical messages of room %d to client %d", len(messages), roomID, client.ID())
+
+	for _, msg := range messages {
+		jsonMsg, err := json.Marshal(entity.ChatMessageEvent{V: entity.ChatProtocolVersion, Type: entity.FrameMessage, ChatMessage: msg})
+		if err != nil {
+			log.Printf("[HUB] Error marshaling message: %v", err)
+			continue
+		}
+		select {
+		case client.Send <- jsonMsg:
+			log.Printf("[HUB] Historical message sent to %d", client.ID())
+		default:
+			log.Printf("[HUB] Client %d send channel blocked, closing", client.ID())
+			h.disconnect(client)
+			return false
+		}
+	}
+	return true
+}
+
+// Publish рассылает событие клиентам, подписанным на комнату roomID
+func (h *Hub) Publish(roomID int, event interface{}) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	h.Broadcast <- RoomMessage{RoomID: roomID, Data: message}
+	return nil
+}
+
+// PublishToUsers доставляет событие всем клиентам пользователей userIDs
+func (h *Hub) PublishToUsers(event interface{}, userIDs ...int) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	h.Direct <- UserMessage{UserIDs: userIDs, Data: message}
+	return nil
+}
+
+// SendTo отправляет событие одному клиенту
+func (h *Hub) SendTo(client *Client, event interface{}) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	h.Reply <- ClientMessage{Client: client, Data: message}
+	return nil
+}
+
+// LeaveRoom отписывает от комнаты клиентов пользователя userID, userID = 0 - всех клиентов
+func (h *Hub) LeaveRoom(roomID, userID int) {
+	h.Leave <- RoomLeave{RoomID: roomID, UserID: userID}
+}
@@ -0,0 +1,249 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestClient создает клиента без соединения, подписанного на комнаты rooms
+func newTestClient(hub *Hub, chatUC *mocks.ChatUsecase, userID int, username string, buffer int, rooms ...int) *Client {
+	client := &Client{
+		Hub:      hub,
+		Send:     make(chan []byte, buffer),
+		UserID:   userID,
+		Username: username,
+		ChatUC:   chatUC,
+		Rooms:    make(map[int]bool),
+	}
+	for _, roomID := range rooms {
+		client.Rooms[roomID] = true
+	}
+	return client
+}
+
+// drain читает кадры клиента, пока хаб не закроет канал, и возвращает их число
+func drain(t *testing.T, client *Client) int {
+	count := 0
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-client.Send:
+			if !ok {
+				return count
+			}
+			count++
+		case <-timeout:
+			t.Fatal("client was not disconnected")
+			return count
+		}
+	}
+}
+
+// receive ждет следующий кадр клиента
+func receive(t *testing.T, client *Client) []byte {
+	select {
+	case data, ok := <-client.Send:
+		assert.True(t, ok, "client channel is closed")
+		return data
+	case <-time.After(2 * time.Second):
+		t.Fatal("no frame received")
+		return nil
+	}
+}
+
+// receivePresence ждет следующий кадр клиента и разбирает его как presence
+func receivePresence(t *testing.T, client *Client) entity.ChatPresenceEvent {
+	var event entity.ChatPresenceEvent
+	assert.NoError(t, json.Unmarshal(receive(t, client), &event))
+	assert.Equal(t, entity.FramePresence, event.Type)
+	return event
+}
+
+// flush ждет, пока хаб обработает все отправленное до него: кадр-метка приходит
+// клиенту следующим, только если до нее хаб ничего клиенту не отправил
+func flush(t *testing.T, hub *Hub, client *Client) {
+	hub.Reply <- ClientMessage{Client: client, Data: []byte(`"marker"`)}
+	assert.Equal(t, `"marker"`, string(receive(t, client)))
+}
+
+// connections возвращает число соединений пользователя по Online, 0 - не в сети
+func connections(hub *Hub, userID int) int {
+	for _, user := range hub.Online() {
+		if user.UserID == userID {
+			return user.Connections
+		}
+	}
+	return 0
+}
+
+func TestHub_PresenceCountsTabs(t *testing.T) {
+
+	chatUC := new(mocks.ChatUsecase)
+
+	hub := NewHub()
+	go hub.Run()
+
+	observer := newTestClient(hub, chatUC, 2, "bob", 10)
+	hub.Register <- observer
+	flush(t, hub, observer)
+
+	// Первая вкладка: остальные узнают, что пользователь в сети
+	firstTab := newTestClient(hub, chatUC, 1, "alice", 10)
+	hub.Register <- firstTab
+	assert.Equal(t, entity.ChatPresenceEvent{V: entity.ChatProtocolVersion, Type: entity.FramePresence, UserID: 1, Username: "alice", Status: entity.PresenceOnline}, receivePresence(t, observer))
+	flush(t, hub, firstTab)
+
+	// Вторая вкладка и гость не меняют присутствие
+	secondTab := newTestClient(hub, chatUC, 1, "alice", 10)
+	hub.Register <- secondTab
+	guest := newTestClient(hub, chatUC, 0, "", 10)
+	hub.Register <- guest
+	flush(t, hub, observer)
+	flush(t, hub, firstTab)
+	assert.Equal(t, 2, connections(hub, 1))
+	assert.Len(t, hub.Online(), 2)
+
+	// Закрытие одной вкладки не уводит пользователя из сети
+	hub.Unregister <- firstTab
+	drain(t, firstTab)
+	flush(t, hub, observer)
+	assert.Equal(t, 1, connections(hub, 1))
+
+	// Закрытие последней вкладки: остальные узнают, что пользователь ушел
+	hub.Unregister <- secondTab
+	drain(t, secondTab)
+	assert.Equal(t, entity.PresenceOffline, receivePresence(t, observer).Status)
+	assert.Equal(t, entity.PresenceOffline, receivePresence(t, guest).Status)
+	assert.Equal(t, 0, connections(hub, 1))
+
+	// Повторное отключение того же клиента ничего не рассылает
+	hub.Unregister <- secondTab
+	flush(t, hub, observer)
+	chatUC.AssertNotCalled(t, "GetRecentMessages", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHub_Reconnect(t *testing.T) {
+
+	chatUC := new(mocks.ChatUsecase)
+
+	hub := NewHub()
+	go hub.Run()
+
+	observer := newTestClient(hub, chatUC, 2, "bob", 10)
+	hub.Register <- observer
+	flush(t, hub, observer)
+
+	client := newTestClient(hub, chatUC, 1, "alice", 10)
+	hub.Register <- client
+	assert.Equal(t, entity.PresenceOnline, receivePresence(t, observer).Status)
+	since := hub.Online()[0].Since
+
+	// Новое соединение после разрыва - новый клиент: пользователь снова в сети с новым since
+	hub.Unregister <- client
+	drain(t, client)
+	assert.Equal(t, entity.PresenceOffline, receivePresence(t, observer).Status)
+
+	time.Sleep(time.Millisecond)
+	reconnected := newTestClient(hub, chatUC, 1, "alice", 10)
+	hub.Register <- reconnected
+	assert.Equal(t, entity.PresenceOnline, receivePresence(t, observer).Status)
+	assert.Equal(t, 1, connections(hub, 1))
+	assert.True(t, hub.Online()[0].Since.After(since))
+}
+
+func TestHub_AuthReRegisterCountsPresenceOnce(t *testing.T) {
+
+	chatUC := new(mocks.ChatUsecase)
+	chatUC.On("GetRecentMessages", mock.Anything, 1, mock.Anything).Return([]entity.ChatMessage{{ID: 1, Content: "first"}}, nil)
+
+	hub := NewHub()
+	go hub.Run()
+
+	observer := newTestClient(hub, chatUC, 2, "bob", 10)
+	hub.Register <- observer
+	flush(t, hub, observer)
+
+	// Гость в сети не считается
+	guest := newTestClient(hub, chatUC, 0, "", 10, 1)
+	hub.Register <- guest
+	assert.Contains(t, string(receive(t, guest)), `"first"`)
+	flush(t, hub, observer)
+	assert.Equal(t, 0, connections(hub, 3))
+
+	// Кадр auth регистрирует клиента повторно: пользователь в сети, история не отправляется заново
+	guest.setIdentity(3, "carol")
+	hub.Register <- guest
+	assert.Equal(t, entity.ChatPresenceEvent{V: entity.ChatProtocolVersion, Type: entity.FramePresence, UserID: 3, Username: "carol", Status: entity.PresenceOnline}, receivePresence(t, observer))
+	flush(t, hub, guest)
+	assert.Equal(t, 1, connections(hub, 3))
+	chatUC.AssertNumberOfCalls(t, "GetRecentMessages", 1)
+
+	hub.Unregister <- guest
+	drain(t, guest)
+	assert.Equal(t, entity.PresenceOffline, receivePresence(t, observer).Status)
+	assert.Equal(t, 0, connections(hub, 3))
+}
+
+func TestHub_RegisterIgnoresDisconnectedClient(t *testing.T) {
+
+	chatUC := new(mocks.ChatUsecase)
+	history := []entity.ChatMessage{{ID: 1, Content: "first"}, {ID: 2, Content: "second"}}
+	chatUC.On("GetRecentMessages", mock.Anything, mock.Anything, mock.Anything).Return(history, nil)
+
+	hub := NewHub()
+	go hub.Run()
+
+	// История двух комнат не помещается в канал, хаб отключает клиента
+	guest := newTestClient(hub, chatUC, 0, "", 1, 1, 2)
+	hub.Register <- guest
+	drain(t, guest)
+	assert.True(t, guest.Closed())
+
+	// Кадр auth после отключения снова регистрирует клиента, затем ReadPump его отключает.
+	// Хаб не должен писать в закрытый канал и закрывать его второй раз
+	guest.setIdentity(1, "alice")
+	hub.Register <- guest
+	hub.Unregister <- guest
+
+	// Хаб продолжает работать, отключенный клиент не считается в сети
+	client := newTestClient(hub, chatUC, 2, "bob", 10, 1)
+	hub.Register <- client
+	assert.Contains(t, string(receive(t, client)), `"first"`)
+	assert.Equal(t, []entity.OnlineUser{{UserID: 2, Username: "bob", Connections: 1, Since: hub.Online()[0].Since}}, hub.Online())
+}
+
+func TestHub_HistoryFitsSendBuffer(t *testing.T) {
+
+	chatUC := new(mocks.ChatUsecase)
+	limit := historyLimit(SendBufferSize, MaxRooms)
+	messages := make([]entity.ChatMessage, limit)
+	chatUC.On("GetRecentMessages", mock.Anything, mock.Anything, limit).Return(messages, nil)
+
+	hub := NewHub()
+	go hub.Run()
+
+	// История всех комнат занимает не больше половины канала, клиент остается подключенным
+	rooms := make([]int, MaxRooms)
+	for i := range rooms {
+		rooms[i] = i + 1
+	}
+	client := newTestClient(hub, chatUC, 1, "alice", SendBufferSize, rooms...)
+	hub.Register <- client
+	assert.Eventually(t, func() bool { return len(client.Send) == MaxRooms*limit }, 2*time.Second, 10*time.Millisecond)
+	assert.LessOrEqual(t, len(client.Send), SendBufferSize/2)
+	assert.False(t, client.Closed())
+	chatUC.AssertNumberOfCalls(t, "GetRecentMessages", MaxRooms)
+}
+
+func TestHistoryLimit(t *testing.T) {
+	assert.Equal(t, historyOnConnect, historyLimit(SendBufferSize, 1))
+	assert.Equal(t, 21, historyLimit(SendBufferSize, 6))
+	assert.Equal(t, 12, historyLimit(SendBufferSize, 10))
+	assert.Equal(t, 1, historyLimit(1, 2))
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+)
+
+// AuthorCardFetcher загружает карточки авторов пачкой
+type AuthorCardFetcher interface {
+	GetAuthorCards(ctx context.Context, userIDs []int) (map[int]entity.AuthorCard, error)
+}
+
+type cachedAuthorCard struct {
+	card      entity.AuthorCard
+	found     bool
+	expiresAt time.Time
+}
+
+// AuthorCardCache - короткоживущий кэш карточек авторов поверх auth_service, по образцу UsernameCache.
+// Отсутствующие пользователи тоже кэшируются, чтобы не запрашивать их повторно.
+type AuthorCardCache struct {
+	fetcher AuthorCardFetcher
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[int]cachedAuthorCard
+}
+
+func NewAuthorCardCache(fetcher AuthorCardFetcher, ttl time.Duration) *AuthorCardCache {
+	return &AuthorCardCache{
+		fetcher: fetcher,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[int]cachedAuthorCard),
+	}
+}
+
+// GetAuthorCards возвращает карточки известных пользователей, запрашивая в auth_service только отсутствующие в кэше
+func (c *AuthorCardCache) GetAuthorCards(ctx context.Context, userIDs []int) (map[int]entity.AuthorCard, error) {
+	cards := make(map[int]entity.AuthorCard, len(userIDs))
+	seen := make(map[int]bool, len(userIDs))
+	var missing []int
+
+	now := c.now()
+	c.mu.Lock()
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		entry, ok := c.entries[id]
+		if ok && now.Before(entry.expiresAt) {
+			if entry.found {
+				cards[id] = entry.card
+			}
+			continue
+		}
+		missing = append(missing, id)
+	}
+	c.mu.Unlock()
+
+	if len(missing) == 0 {
+		return cards, nil
+	}
+
+	fetched, err := c.fetcher.GetAuthorCards(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := c.now().Add(c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries)+len(missing) > maxCachedUsernames {
+		c.purgeExpired(now)
+	}
+	for _, id := range missing {
+		card, found := fetched[id]
+		if found {
+			cards[id] = card
+		}
+		c.entries[id] = cachedAuthorCard{card: card, found: found, expiresAt: expiresAt}
+	}
+	return cards, nil
+}
+
+func (c *AuthorCardCache) purgeExpired(now time.Time) {
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	if len(c.entries) >= maxCachedUsernames {
+		c.entries = make(map[int]cachedAuthorCard)
+	}
+}
@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCardFetcher struct {
+	calls [][]int
+	cards map[int]entity.AuthorCard
+	err   error
+}
+
+func (f *fakeCardFetcher) GetAuthorCards(ctx context.Context, userIDs []int) (map[int]entity.AuthorCard, error) {
+	f.calls = append(f.calls, userIDs)
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make(map[int]entity.AuthorCard)
+	for _, id := range userIDs {
+		if card, ok := f.cards[id]; ok {
+			result[id] = card
+		}
+	}
+	return result, nil
+}
+
+func TestAuthorCardCache_FetchesOnlyMisses(t *testing.T) {
+	alice := entity.AuthorCard{UserID: 1, Username: "alice"}
+	bob := entity.AuthorCard{UserID: 2, Username: "bob"}
+	fetcher := &fakeCardFetcher{cards: map[int]entity.AuthorCard{1: alice, 2: bob}}
+	cache := NewAuthorCardCache(fetcher, time.Minute)
+
+	cards, err := cache.GetAuthorCards(context.Background(), []int{1, 1, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]entity.AuthorCard{1: alice}, cards)
+
+	cards, err = cache.GetAuthorCards(context.Background(), []int{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]entity.AuthorCard{1: alice, 2: bob}, cards)
+
+	assert.Equal(t, [][]int{{1, 3}, {2}}, fetcher.calls)
+}
+
+func TestAuthorCardCache_Expiry(t *testing.T) {
+	fetcher := &fakeCardFetcher{cards: map[int]entity.AuthorCard{1: {UserID: 1, PostCount: 1}}}
+	cache := NewAuthorCardCache(fetcher, time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.GetAuthorCards(context.Background(), []int{1})
+	assert.NoError(t, err)
+
+	fetcher.cards[1] = entity.AuthorCard{UserID: 1, PostCount: 2}
+	now = now.Add(2 * time.Minute)
+
+	cards, err := cache.GetAuthorCards(context.Background(), []int{1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cards[1].PostCount)
+	assert.Len(t, fetcher.calls, 2)
+}
+
+func TestAuthorCardCache_FetchError(t *testing.T) {
+	fetcher := &fakeCardFetcher{err: errors.New("unavailable")}
+	cache := NewAuthorCardCache(fetcher, time.Minute)
+
+	_, err := cache.GetAuthorCards(context.Background(), []int{1})
+	assert.EqualError(t, err, "unavailable")
+	assert.Empty(t, cache.entries)
+}
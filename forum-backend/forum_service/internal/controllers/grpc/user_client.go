@@ -2,13 +2,18 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/proto"
 	"log"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+var ErrInvalidToken = errors.New("invalid or revoked token")
+
 type UserClient struct {
 	conn   *grpc.ClientConn
 	client user.UserServiceClient
@@ -35,6 +40,88 @@ func (c *UserClient) GetUsername(ctx context.Context, userID int) (string, error
 	return resp.Username, nil
 }
 
+// maxBatchUsers - сколько ID auth_service принимает в одном запросе GetUsers
+const maxBatchUsers = 500
+
+// GetUsernames получает имена пользователей пачками по maxBatchUsers. Неизвестные ID в результат не попадают.
+func (c *UserClient) GetUsernames(ctx context.Context, userIDs []int) (map[int]string, error) {
+	usernames := make(map[int]string, len(userIDs))
+	for start := 0; start < len(userIDs); start += maxBatchUsers {
+		end := min(start+maxBatchUsers, len(userIDs))
+		ids := make([]int32, 0, end-start)
+		for _, id := range userIDs[start:end] {
+			ids = append(ids, int32(id))
+		}
+		resp, err := c.client.GetUsers(ctx, &user.GetUsersRequest{UserIds: ids})
+		if err != nil {
+			log.Printf("Failed to get usernames: %v", err)
+			return nil, err
+		}
+		for _, u := range resp.Users {
+			usernames[int(u.UserId)] = u.Username
+		}
+	}
+	return usernames, nil
+}
+
+// ValidateToken проверяет токен в auth_service, включая то, что он не был отозван
+func (c *UserClient) ValidateToken(ctx context.Context, token string) (entity.Principal, error) {
+	resp, err := c.client.ValidateToken(ctx, &user.ValidateTokenRequest{Token: token})
+	if err != nil {
+		log.Printf("Failed to validate token: %v", err)
+		return entity.Principal{}, err
+	}
+	if !resp.Valid {
+		return entity.Principal{}, ErrInvalidToken
+	}
+	return entity.Principal{
+		UserID:      int(resp.UserId),
+		Role:        resp.Role,
+		Username:    resp.Username,
+		TokenID:     int(resp.TokenId),
+		Permissions: resp.Permissions,
+	}, nil
+}
+
+// GetAuthorCards получает карточки авторов пачками по maxBatchUsers. Неизвестные ID в результат не попадают
+func (c *UserClient) GetAuthorCards(ctx context.Context, userIDs []int) (map[int]entity.AuthorCard, error) {
+	cards := make(map[int]entity.AuthorCard, len(userIDs))
+	for start := 0; start < len(userIDs); start += maxBatchUsers {
+		end := min(start+maxBatchUsers, len(userIDs))
+		ids := make([]int32, 0, end-start)
+		for _, id := range userIDs[start:end] {
+			ids = append(ids, int32(id))
+		}
+		resp, err := c.client.GetUserProfiles(ctx, &user.GetUsersRequest{UserIds: ids})
+		if err != nil {
+			log.Printf("Failed to get author cards: %v", err)
+			return nil, err
+		}
+		for _, profile := range resp.Profiles {
+			cards[int(profile.UserId)] = authorCard(profile)
+		}
+	}
+	return cards, nil
+}
+
+func authorCard(profile *user.UserProfileResponse) entity.AuthorCard {
+	joinedAt, err := time.Parse(time.RFC3339, profile.JoinedAt)
+	if err != nil {
+		log.Printf("Failed to parse join date %q: %v", profile.JoinedAt, err)
+	}
+	return entity.AuthorCard{
+		UserID:       int(profile.UserId),
+		Username:     profile.Username,
+		Role:         profile.Role,
+		DisplayName:  profile.DisplayName,
+		AvatarURL:    profile.AvatarUrl,
+		Signature:    profile.Signature,
+		PostCount:    int(profile.PostCount),
+		CommentCount: int(profile.CommentCount),
+		JoinedAt:     joinedAt,
+	}
+}
+
 func (c *UserClient) Close() error {
 	return c.conn.Close()
 }
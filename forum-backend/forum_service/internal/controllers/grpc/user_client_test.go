@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	user "github.com/Engls/forum-project2/forum_service/internal/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// fakeUserServiceClient отвечает на GetUsers как auth_service, отклоняя слишком большие пачки
+type fakeUserServiceClient struct {
+	user.UserServiceClient
+	batches []int
+	err     error
+}
+
+func (f *fakeUserServiceClient) GetUsers(ctx context.Context, in *user.GetUsersRequest, opts ...grpc.CallOption) (*user.GetUsersResponse, error) {
+	f.batches = append(f.batches, len(in.UserIds))
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(in.UserIds) > maxBatchUsers {
+		return nil, errors.New("too many user ids")
+	}
+	resp := &user.GetUsersResponse{}
+	for _, id := range in.UserIds {
+		if id%2 == 0 {
+			resp.Users = append(resp.Users, &user.UserInfo{UserId: id, Username: "user"})
+		}
+	}
+	return resp, nil
+}
+
+func TestUserClient_GetUsernames_SplitsIntoBatches(t *testing.T) {
+	fake := &fakeUserServiceClient{}
+	client := &UserClient{client: fake}
+
+	userIDs := make([]int, 2*maxBatchUsers+1)
+	for i := range userIDs {
+		userIDs[i] = i + 1
+	}
+	usernames, err := client.GetUsernames(context.Background(), userIDs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{maxBatchUsers, maxBatchUsers, 1}, fake.batches)
+	assert.Len(t, usernames, maxBatchUsers)
+	assert.Equal(t, "user", usernames[2*maxBatchUsers])
+}
+
+func TestUserClient_GetUsernames_Error(t *testing.T) {
+	fake := &fakeUserServiceClient{err: errors.New("unavailable")}
+	client := &UserClient{client: fake}
+
+	usernames, err := client.GetUsernames(context.Background(), []int{1, 2})
+
+	assert.Error(t, err)
+	assert.Nil(t, usernames)
+
+	usernames, err = client.GetUsernames(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, usernames)
+	assert.Equal(t, []int{2}, fake.batches)
+}
+
+func (f *fakeUserServiceClient) GetUserProfiles(ctx context.Context, in *user.GetUsersRequest, opts ...grpc.CallOption) (*user.GetUserProfilesResponse, error) {
+	f.batches = append(f.batches, len(in.UserIds))
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(in.UserIds) > maxBatchUsers {
+		return nil, errors.New("too many user ids")
+	}
+	resp := &user.GetUserProfilesResponse{}
+	for _, id := range in.UserIds {
+		if id%2 == 0 {
+			resp.Profiles = append(resp.Profiles, &user.UserProfileResponse{
+				UserId:    id,
+				Username:  "user",
+				Role:      "moderator",
+				PostCount: 3,
+				JoinedAt:  "2024-01-02T03:04:05Z",
+			})
+		}
+	}
+	return resp, nil
+}
+
+func TestUserClient_GetAuthorCards_SplitsIntoBatches(t *testing.T) {
+	fake := &fakeUserServiceClient{}
+	client := &UserClient{client: fake}
+
+	userIDs := make([]int, maxBatchUsers+1)
+	for i := range userIDs {
+		userIDs[i] = i + 1
+	}
+	cards, err := client.GetAuthorCards(context.Background(), userIDs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{maxBatchUsers, 1}, fake.batches)
+	assert.Len(t, cards, (maxBatchUsers+1)/2)
+	card := cards[2]
+	assert.Equal(t, 2, card.UserID)
+	assert.Equal(t, "moderator", card.Role)
+	assert.Equal(t, 3, card.PostCount)
+	assert.True(t, card.JoinedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+}
+
+func TestUserClient_GetAuthorCards_Error(t *testing.T) {
+	fake := &fakeUserServiceClient{err: errors.New("unavailable")}
+	client := &UserClient{client: fake}
+
+	cards, err := client.GetAuthorCards(context.Background(), []int{1, 2})
+
+	assert.Error(t, err)
+	assert.Nil(t, cards)
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxCachedUsernames - при превышении из кэша вычищаются устаревшие записи
+const maxCachedUsernames = 10000
+
+// UsernameFetcher загружает имена пользователей пачкой
+type UsernameFetcher interface {
+	GetUsernames(ctx context.Context, userIDs []int) (map[int]string, error)
+}
+
+type cachedUsername struct {
+	username  string
+	expiresAt time.Time
+}
+
+// UsernameCache - короткоживущий кэш ID -> имя пользователя поверх auth_service.
+// Отсутствующие пользователи кэшируются с пустым именем, чтобы не запрашивать их повторно.
+type UsernameCache struct {
+	fetcher UsernameFetcher
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[int]cachedUsername
+}
+
+func NewUsernameCache(fetcher UsernameFetcher, ttl time.Duration) *UsernameCache {
+	return &UsernameCache{
+		fetcher: fetcher,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[int]cachedUsername),
+	}
+}
+
+// GetUsernames возвращает имена для всех ID, запрашивая в auth_service только отсутствующие в кэше
+func (c *UsernameCache) GetUsernames(ctx context.Context, userIDs []int) (map[int]string, error) {
+	usernames := make(map[int]string, len(userIDs))
+	var missing []int
+
+	now := c.now()
+	c.mu.Lock()
+	for _, id := range userIDs {
+		if _, seen := usernames[id]; seen {
+			continue
+		}
+		entry, ok := c.entries[id]
+		if ok && now.Before(entry.expiresAt) {
+			usernames[id] = entry.username
+			continue
+		}
+		usernames[id] = ""
+		missing = append(missing, id)
+	}
+	c.mu.Unlock()
+
+	if len(missing) == 0 {
+		return usernames, nil
+	}
+
+	fetched, err := c.fetcher.GetUsernames(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := c.now().Add(c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries)+len(missing) > maxCachedUsernames {
+		c.purgeExpired(now)
+	}
+	for _, id := range missing {
+		usernames[id] = fetched[id]
+		c.entries[id] = cachedUsername{username: fetched[id], expiresAt: expiresAt}
+	}
+	return usernames, nil
+}
+
+// GetUsername возвращает имя одного пользователя через кэш
+func (c *UsernameCache) GetUsername(ctx context.Context, userID int) (string, error) {
+	usernames, err := c.GetUsernames(ctx, []int{userID})
+	if err != nil {
+		return "", err
+	}
+	return usernames[userID], nil
+}
+
+func (c *UsernameCache) purgeExpired(now time.Time) {
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	if len(c.entries) >= maxCachedUsernames {
+		c.entries = make(map[int]cachedUsername)
+	}
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFetcher struct {
+	calls     [][]int
+	usernames map[int]string
+	err       error
+}
+
+func (f *fakeFetcher) GetUsernames(ctx context.Context, userIDs []int) (map[int]string, error) {
+	f.calls = append(f.calls, userIDs)
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make(map[int]string)
+	for _, id := range userIDs {
+		if name, ok := f.usernames[id]; ok {
+			result[id] = name
+		}
+	}
+	return result, nil
+}
+
+func TestUsernameCache_FetchesOnlyMisses(t *testing.T) {
+	fetcher := &fakeFetcher{usernames: map[int]string{1: "alice", 2: "bob"}}
+	cache := NewUsernameCache(fetcher, time.Minute)
+
+	usernames, err := cache.GetUsernames(context.Background(), []int{1, 1, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "alice", 3: ""}, usernames)
+
+	usernames, err = cache.GetUsernames(context.Background(), []int{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "alice", 2: "bob", 3: ""}, usernames)
+
+	assert.Equal(t, [][]int{{1, 3}, {2}}, fetcher.calls)
+}
+
+func TestUsernameCache_Expiry(t *testing.T) {
+	fetcher := &fakeFetcher{usernames: map[int]string{1: "alice"}}
+	cache := NewUsernameCache(fetcher, time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.GetUsername(context.Background(), 1)
+	assert.NoError(t, err)
+
+	fetcher.usernames[1] = "alice2"
+	now = now.Add(2 * time.Minute)
+
+	username, err := cache.GetUsername(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice2", username)
+	assert.Len(t, fetcher.calls, 2)
+}
+
+func TestUsernameCache_FetchError(t *testing.T) {
+	fetcher := &fakeFetcher{err: errors.New("unavailable")}
+	cache := NewUsernameCache(fetcher, time.Minute)
+
+	_, err := cache.GetUsernames(context.Background(), []int{1})
+	assert.EqualError(t, err, "unavailable")
+	assert.Empty(t, cache.entries)
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"errors"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type CategoryHandler struct {
+	categoryUsecase usecase.CategoryUsecase
+	postUsecase     usecase.PostUsecase
+	logger          *zap.Logger
+	userClient      UserService
+}
+
+func NewCategoryHandler(categoryUsecase usecase.CategoryUsecase, postUsecase usecase.PostUsecase, logger *zap.Logger, userClient UserService) *CategoryHandler {
+	return &CategoryHandler{categoryUsecase: categoryUsecase, postUsecase: postUsecase, logger: logger, userClient: userClient}
+}
+
+// GetCategories godoc
+// @Summary Список категорий
+// @Description Все категории в порядке sort_order с числом постов и временем последней активности (пост или комментарий)
+// @Tags Категории
+// @Produce json
+// @Success 200 {array} entity.Category
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /categories [get]
+func (h *CategoryHandler) GetCategories(c *gin.Context) {
+	categories, err := h.categoryUsecase.GetCategories(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get categories", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// GetCategoryPosts godoc
+// @Summary Посты категории
+// @Description Возвращает категорию и страницу ее постов, новые первыми
+// @Tags Категории
+// @Produce json
+// @Param slug path string true "Slug категории"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{} "category, posts and pagination info"
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /categories/{slug}/posts [get]
+func (h *CategoryHandler) GetCategoryPosts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	category, err := h.categoryUsecase.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to get category", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	posts, err := h.postUsecase.GetPostsByCategory(c.Request.Context(), category.ID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorIDs := make([]int, len(posts))
+	for i, post := range posts {
+		authorIDs[i] = post.AuthorId
+	}
+	usernames := lookupUsernames(c.Request.Context(), h.userClient, h.logger, authorIDs)
+
+	c.JSON(http.StatusOK, gin.H{
+		"category": category,
+		"posts":    postListItems(posts, usernames, nil, nil),
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": category.PostCount,
+		},
+	})
+}
+
+// CreateCategory godoc
+// @Summary Создать категорию
+// @Description Создает категорию (требуется право category.manage). Slug - строчные латинские буквы, цифры и дефисы
+// @Tags Категории
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category body entity.CategoryRequest true "Данные категории"
+// @Success 201 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req entity.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categoryUsecase.CreateCategory(c.Request.Context(), req)
+	if err != nil {
+		h.respondCategoryError(c, err)
+		return
+	}
+
+	h.logger.Info("Category created successfully", zap.Int("categoryID", category.ID))
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory godoc
+// @Summary Изменить категорию
+// @Description Меняет slug, название, описание и порядок категории (требуется право category.manage)
+// @Tags Категории
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID категории"
+// @Param category body entity.CategoryRequest true "Данные категории"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req entity.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categoryUsecase.UpdateCategory(c.Request.Context(), id, req)
+	if err != nil {
+		h.respondCategoryError(c, err)
+		return
+	}
+
+	h.logger.Info("Category updated successfully", zap.Int("categoryID", id))
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory godoc
+// @Summary Удалить категорию
+// @Description Удаляет категорию, ее посты остаются без категории (требуется право category.manage)
+// @Tags Категории
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID категории"
+// @Success 204 "No Content"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	if err := h.categoryUsecase.DeleteCategory(c.Request.Context(), id); err != nil {
+		h.respondCategoryError(c, err)
+		return
+	}
+
+	h.logger.Info("Category deleted successfully", zap.Int("categoryID", id))
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CategoryHandler) respondCategoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSlug), errors.Is(err, usecase.ErrEmptyCategoryName):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCategoryNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCategorySlugTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Category operation failed", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
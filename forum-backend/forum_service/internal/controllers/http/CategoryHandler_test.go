@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestCategoryHandler_GetCategoryPosts_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCategoryUsecase := new(mocks.CategoryUsecase)
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockUserService := new(mocks.UserService)
+
+	categoryHandler := NewCategoryHandler(mockCategoryUsecase, mockPostUsecase, logger, mockUserService)
+
+	category := &entity.Category{ID: 2, Slug: "golang", Title: "Go", PostCount: 11}
+	posts := []entity.Post{{ID: 5, AuthorId: 1, Title: "Generics", CategoryId: &category.ID}}
+
+	mockCategoryUsecase.On("GetCategoryBySlug", mock.Anything, "golang").Return(category, nil)
+	mockPostUsecase.On("GetPostsByCategory", mock.Anything, 2, 10, 10).Return(posts, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "gopher"}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/categories/golang/posts?page=2", nil)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "golang"}}
+
+	categoryHandler.GetCategoryPosts(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Category   entity.Category          `json:"category"`
+		Posts      []map[string]interface{} `json:"posts"`
+		Pagination map[string]int           `json:"pagination"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "golang", response.Category.Slug)
+	if assert.Len(t, response.Posts, 1) {
+		assert.Equal(t, "gopher", response.Posts[0]["username"])
+		assert.Equal(t, float64(2), response.Posts[0]["category_id"])
+	}
+	assert.Equal(t, map[string]int{"page": 2, "limit": 10, "total": 11}, response.Pagination)
+
+	mockCategoryUsecase.AssertExpectations(t)
+	mockPostUsecase.AssertExpectations(t)
+}
+
+func TestCategoryHandler_GetCategoryPosts_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCategoryUsecase := new(mocks.CategoryUsecase)
+
+	categoryHandler := NewCategoryHandler(mockCategoryUsecase, new(mocks.PostUsecase), logger, new(mocks.UserService))
+
+	mockCategoryUsecase.On("GetCategoryBySlug", mock.Anything, "missing").Return(nil, usecase.ErrCategoryNotFound)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/categories/missing/posts", nil)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "missing"}}
+
+	categoryHandler.GetCategoryPosts(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCategoryHandler_CreateCategory_Errors(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	cases := map[error]int{
+		usecase.ErrInvalidSlug:       http.StatusBadRequest,
+		usecase.ErrCategorySlugTaken: http.StatusConflict,
+	}
+	for usecaseErr, status := range cases {
+		mockCategoryUsecase := new(mocks.CategoryUsecase)
+		categoryHandler := NewCategoryHandler(mockCategoryUsecase, new(mocks.PostUsecase), logger, new(mocks.UserService))
+
+		req := entity.CategoryRequest{Slug: "golang", Title: "Go"}
+		mockCategoryUsecase.On("CreateCategory", mock.Anything, req).Return(nil, usecaseErr)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/categories", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		categoryHandler.CreateCategory(c)
+
+		assert.Equal(t, status, w.Code, usecaseErr.Error())
+	}
+}
+
+func TestCategoryHandler_DeleteCategory_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCategoryUsecase := new(mocks.CategoryUsecase)
+
+	categoryHandler := NewCategoryHandler(mockCategoryUsecase, new(mocks.PostUsecase), logger, new(mocks.UserService))
+
+	mockCategoryUsecase.On("DeleteCategory", mock.Anything, 3).Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/categories/3", nil)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+	categoryHandler.DeleteCategory(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+	mockCategoryUsecase.AssertExpectations(t)
+}
@@ -1,11 +1,16 @@
 package http
 
 import (
-	utils "github.com/Engls/EnglsJwt"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/Engls/forum-project2/forum_service/internal/controllers/chat"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -19,63 +24,116 @@ var upgrader = websocket.Upgrader{
 }
 
 type ChatHandler struct {
-	hub     *chat.Hub
-	chatUC  usecase.ChatUsecase
-	jwtUtil *utils.JWTUtil
-	logger  *zap.Logger
+	hub        *chat.Hub
+	chatUC     usecase.ChatUsecase
+	roomUC     usecase.ChatRoomUsecase
+	dmUC       usecase.DirectMessageUsecase
+	reactionUC usecase.ReactionUsecase
+	validator  middleware.TokenValidator
+	userClient UserService
+	logger     *zap.Logger
 }
 
-func NewChatHandler(hub *chat.Hub, chatUC usecase.ChatUsecase, jwtUtil *utils.JWTUtil, logger *zap.Logger) *ChatHandler {
+var errUnknownChatUser = errors.New("token owner not found")
+
+func NewChatHandler(
+	hub *chat.Hub,
+	chatUC usecase.ChatUsecase,
+	roomUC usecase.ChatRoomUsecase,
+	dmUC usecase.DirectMessageUsecase,
+	reactionUC usecase.ReactionUsecase,
+	validator middleware.TokenValidator,
+	userClient UserService,
+	logger *zap.Logger,
+) *ChatHandler {
 	return &ChatHandler{
-		hub:     hub,
-		chatUC:  chatUC,
-		jwtUtil: jwtUtil,
-		logger:  logger,
+		hub:        hub,
+		chatUC:     chatUC,
+		roomUC:     roomUC,
+		dmUC:       dmUC,
+		reactionUC: reactionUC,
+		validator:  validator,
+		userClient: userClient,
+		logger:     logger,
 	}
 }
 
 // ServeWS godoc
 // @Summary Установить WebSocket соединение для чата
-// @Description Обновляет HTTP соединение до WebSocket для обмена сообщениями в реальном времени
+// @Description Обновляет HTTP соединение до WebSocket для обмена сообщениями в реальном времени.
+// @Description Пользователь определяется только по токену из заголовка Authorization или параметра token, имя берется из auth_service.
+// @Description Без токена соединение открывается в режиме гостя: гость только читает чат, но может аутентифицироваться первым кадром {"type":"auth","token":"..."}.
+// @Description Гостю при подключении доступны только публичные комнаты, закрытые перечисляются в кадре auth: {"type":"auth","token":"...","rooms":[3]}. Если хотя бы одна из них недоступна, соединение остается гостевым.
+// @Description Одно соединение подписывается на несколько комнат, не больше 10: ?room=1&room=2 или ?room=1,2, без room - общая комната.
+// @Description При подключении клиент получает последние сообщения каждой комнаты: до 50, при многих комнатах меньше, остальное - через history_request.
+// @Description Кадры в обе стороны - JSON объекты с версией протокола v и типом type, формат входящего кадра - entity.ChatFrame.
+// @Description Клиент отправляет кадры message, dm, typing, history_request и auth. Кадр без v считается кадром текущей версии, кадр неизвестного типа или не JSON отклоняется.
+// @Description Сообщение в комнату - {"v":1,"type":"message","client_id":"...","room_id":1,"content":"..."}, room_id нужен, если клиент подписан на несколько комнат.
+// @Description Личное сообщение - {"v":1,"type":"dm","client_id":"...","to":2,"content":"..."}, его получают все клиенты получателя и отправителя кадром entity.DirectMessageEvent.
+// @Description Сохраненное сообщение подтверждается отправителю кадром entity.ChatAck с id и временем сервера. Повторный кадр с тем же client_id не создает копию, а подтверждается с duplicate = true.
+// @Description Ошибка обработки кадра приходит кадром entity.ChatError с кодом: bad_frame, unsupported_version, unknown_type, read_only, not_subscribed, invalid, internal.
+// @Description Сообщения комнат приходят кадрами entity.ChatMessageEvent, ответ на history_request - entity.ChatHistoryEvent, typing - entity.ChatTypingEvent.
+// @Description Кадры typing не сохраняются, от клиента в комнату рассылается не больше одного за 2 секунды, остальные отбрасываются.
+// @Description Когда пользователь открывает первое соединение или закрывает последнее, остальные клиенты получают кадр entity.ChatPresenceEvent
 // @Tags Чат
 // @Accept json
 // @Produce json
-// @Param token query string true "JWT токен авторизации"
-// @Param userID query int true "ID пользователя"
-// @Param username query string false "Имя пользователя"
-// @Param auth query bool true "Флаг аутентификации"
+// @Param token query string false "JWT токен авторизации, без него - режим гостя"
+// @Param room query []int false "ID комнат" collectionFormat(multi)
 // @Success 101 "Switching Protocols" {object} nil
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
-// @Router /ws/chat [get]
+// @Router /ws [get]
 func (h *ChatHandler) ServeWS(c *gin.Context) {
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
-	if err != nil {
-		h.logger.Error("WebSocket upgrade error", zap.Error(err))
-		return
+	// Токен проверяется до upgrade, чтобы с неверным токеном соединение не открывалось вовсе
+	var userID int
+	var username string
+	if token := wsToken(c); token != "" {
+		var err error
+		userID, username, err = h.authenticate(c.Request.Context(), token)
+		if err != nil {
+			h.logger.Warn("WebSocket authentication failed", zap.Error(err))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or revoked token"})
+			return
+		}
 	}
 
-	realUserID, err := h.jwtUtil.GetUserIDFromToken(c.Query("token"))
+	roomIDs, err := roomsQuery(c)
 	if err != nil {
-		h.logger.Error("Failed to get user ID from token", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rooms := make(map[int]bool, len(roomIDs))
+	for _, roomID := range roomIDs {
+		// Гостю доступны только публичные комнаты, закрытые он указывает в кадре auth
+		if err := h.roomUC.CheckAccess(c.Request.Context(), roomID, userID); err != nil {
+			h.respondChatRoomError(c, err)
+			return
+		}
+		rooms[roomID] = true
 	}
-	userID, err := strconv.Atoi(c.Query("userID"))
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		h.logger.Error("Failed to convert user ID to integer", zap.Error(err))
+		h.logger.Error("WebSocket upgrade error", zap.Error(err))
+		return
 	}
-	h.logger.Info("Real userID", zap.Int("realUserID", realUserID))
-	username := c.Query("username")
-	isAuthenticated := c.Query("auth") == "true"
+	h.logger.Info("WebSocket connected", zap.Int("userID", userID), zap.Bool("guest", userID == 0))
 
 	client := &chat.Client{
 		Hub:             h.hub,
 		Conn:            conn,
-		Send:            make(chan []byte, 256),
+		Send:            make(chan []byte, chat.SendBufferSize),
 		UserID:          userID,
 		Username:        username,
-		IsAuthenticated: isAuthenticated,
 		ChatUC:          h.chatUC,
+		DirectUC:        h.dmUC,
+		Authenticate:    h.authenticate,
+		CheckRoomAccess: h.roomUC.CheckAccess,
+		Rooms:           rooms,
 	}
 
 	h.hub.Register <- client
@@ -83,3 +141,495 @@ func (h *ChatHandler) ServeWS(c *gin.Context) {
 	go client.WritePump()
 	go client.ReadPump()
 }
+
+// authenticate проверяет токен в auth_service и возвращает id и имя его владельца
+func (h *ChatHandler) authenticate(ctx context.Context, token string) (int, string, error) {
+	principal, err := h.validator.ValidateToken(ctx, token)
+	if err != nil {
+		return 0, "", err
+	}
+	usernames, err := h.userClient.GetUsernames(ctx, []int{principal.UserID})
+	if err != nil {
+		return 0, "", err
+	}
+	username, ok := usernames[principal.UserID]
+	if !ok {
+		return 0, "", errUnknownChatUser
+	}
+	return principal.UserID, username, nil
+}
+
+// wsToken берет токен из заголовка Authorization, а если его нет - из параметра token:
+// браузерный WebSocket не умеет отправлять заголовки
+func wsToken(c *gin.Context) string {
+	if token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "); token != "" {
+		return token
+	}
+	return c.Query("token")
+}
+
+// GetHistory godoc
+// @Summary История общей комнаты чата
+// @Description Страница сообщений общей комнаты с реакциями в хронологическом порядке. next_cursor указывает на более ранние сообщения, null - история закончилась
+// @Tags Чат
+// @Produce json
+// @Param cursor query string false "next_cursor из предыдущего ответа, пусто - последние сообщения"
+// @Param limit query int false "Сообщений на странице" default(50)
+// @Success 200 {object} map[string]interface{} "messages and next_cursor"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/messages [get]
+func (h *ChatHandler) GetHistory(c *gin.Context) {
+	h.roomHistory(c, entity.DefaultChatRoomID)
+}
+
+// GetOnline godoc
+// @Summary Пользователи в сети
+// @Description Пользователи, у которых открыто хотя бы одно соединение с чатом, по возрастанию id. Изменения приходят по WebSocket кадрами entity.ChatPresenceEvent
+// @Tags Чат
+// @Produce json
+// @Success 200 {array} entity.OnlineUser
+// @Router /chat/online [get]
+func (h *ChatHandler) GetOnline(c *gin.Context) {
+	c.JSON(http.StatusOK, h.hub.Online())
+}
+
+// GetRoomHistory godoc
+// @Summary История комнаты чата
+// @Description Страница сообщений комнаты с реакциями в хронологическом порядке. Историю приватной комнаты видят только ее участники
+// @Tags Чат
+// @Produce json
+// @Param id path int true "ID комнаты"
+// @Param cursor query string false "next_cursor из предыдущего ответа, пусто - последние сообщения"
+// @Param limit query int false "Сообщений на странице" default(50)
+// @Success 200 {object} map[string]interface{} "messages and next_cursor"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/rooms/{id}/messages [get]
+func (h *ChatHandler) GetRoomHistory(c *gin.Context) {
+	roomID, ok := h.roomParam(c)
+	if !ok {
+		return
+	}
+	if err := h.roomUC.CheckAccess(c.Request.Context(), roomID, currentUserID(c)); err != nil {
+		h.respondChatRoomError(c, err)
+		return
+	}
+	h.roomHistory(c, roomID)
+}
+
+func (h *ChatHandler) roomHistory(c *gin.Context, roomID int) {
+	before, _, err := cursorQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	if limit > usecase.MaxChatHistoryLimit {
+		limit = usecase.MaxChatHistoryLimit
+	}
+
+	messages, err := h.chatUC.GetHistory(c.Request.Context(), roomID, before, limit+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Сообщения идут от старых к новым, лишнее самое старое означает, что история продолжается
+	var next *string
+	if len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+		next = nextCursor(messages[0].Timestamp, messages[0].ID)
+	}
+	if messages == nil {
+		messages = []entity.ChatMessage{}
+	}
+
+	messageIDs := make([]int, len(messages))
+	for i, message := range messages {
+		messageIDs[i] = message.ID
+	}
+	reactions := lookupReactions(c, h.reactionUC, h.logger, entity.ReactionTargetChatMessage, messageIDs)
+	for i := range messages {
+		messages[i].Reactions = reactions[messages[i].ID]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages":    messages,
+		"next_cursor": next,
+	})
+}
+
+// AddReaction godoc
+// @Summary Поставить реакцию на сообщение чата
+// @Description Ставит реакцию эмодзи из разрешенного набора и рассылает клиентам комнаты кадр entity.ReactionEvent (требуется право react)
+// @Tags Чат
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сообщения"
+// @Param emoji path string true "Эмодзи"
+// @Success 200 {object} entity.ReactionResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/messages/{id}/reactions/{emoji} [put]
+func (h *ChatHandler) AddReaction(c *gin.Context) {
+	roomID, ok := h.messageRoom(c)
+	if !ok {
+		return
+	}
+	if result, userID, ok := setReaction(c, h.reactionUC, h.logger, entity.ReactionTargetChatMessage, true); ok {
+		h.publishReaction(roomID, result, userID, entity.ReactionAdded)
+	}
+}
+
+// RemoveReaction godoc
+// @Summary Снять реакцию с сообщения чата
+// @Description Снимает реакцию текущего пользователя и рассылает клиентам комнаты кадр entity.ReactionEvent (требуется право react)
+// @Tags Чат
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сообщения"
+// @Param emoji path string true "Эмодзи"
+// @Success 200 {object} entity.ReactionResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/messages/{id}/reactions/{emoji} [delete]
+func (h *ChatHandler) RemoveReaction(c *gin.Context) {
+	roomID, ok := h.messageRoom(c)
+	if !ok {
+		return
+	}
+	if result, userID, ok := setReaction(c, h.reactionUC, h.logger, entity.ReactionTargetChatMessage, false); ok {
+		h.publishReaction(roomID, result, userID, entity.ReactionRemoved)
+	}
+}
+
+// messageRoom возвращает комнату сообщения :id, если у текущего пользователя есть к ней доступ.
+// При ошибке ответ уже записан
+func (h *ChatHandler) messageRoom(c *gin.Context) (int, bool) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid chat_message ID"})
+		return 0, false
+	}
+	message, err := h.chatUC.GetMessage(c.Request.Context(), messageID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrChatMessageNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return 0, false
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chat message"})
+		return 0, false
+	}
+	if err := h.roomUC.CheckAccess(c.Request.Context(), message.RoomID, currentUserID(c)); err != nil {
+		h.respondChatRoomError(c, err)
+		return 0, false
+	}
+	return message.RoomID, true
+}
+
+// publishReaction рассылает клиентам комнаты новое число реакций эмодзи на сообщение
+func (h *ChatHandler) publishReaction(roomID int, result *entity.ReactionResult, userID int, action string) {
+	event := entity.ReactionEvent{
+		V:         entity.ChatProtocolVersion,
+		Type:      entity.FrameReaction,
+		MessageID: result.TargetID,
+		UserID:    userID,
+		Emoji:     result.Emoji,
+		Action:    action,
+	}
+	for _, count := range result.Reactions {
+		if count.Emoji == result.Emoji {
+			event.Count = count.Count
+		}
+	}
+	if err := h.hub.Publish(roomID, event); err != nil {
+		h.logger.Error("Failed to publish reaction", zap.Int("messageID", result.TargetID), zap.Error(err))
+	}
+}
+
+// ListRooms godoc
+// @Summary Список комнат чата
+// @Description Публичные комнаты и приватные комнаты, где текущий пользователь участник
+// @Tags Чат
+// @Produce json
+// @Success 200 {array} entity.ChatRoom
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/rooms [get]
+func (h *ChatHandler) ListRooms(c *gin.Context) {
+	rooms, err := h.roomUC.ListRooms(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		h.logger.Error("Failed to list chat rooms", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rooms)
+}
+
+// CreateRoom godoc
+// @Summary Создать комнату чата
+// @Description Создает публичную или приватную комнату, текущий пользователь становится ее владельцем
+// @Tags Чат
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param room body entity.ChatRoomRequest true "Данные комнаты"
+// @Success 201 {object} entity.ChatRoom
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/rooms [post]
+func (h *ChatHandler) CreateRoom(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return
+	}
+
+	var req entity.ChatRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	room, err := h.roomUC.CreateRoom(c.Request.Context(), principal.UserID, req)
+	if err != nil {
+		h.respondChatRoomError(c, err)
+		return
+	}
+
+	h.logger.Info("Chat room created successfully", zap.Int("roomID", room.ID))
+	c.JSON(http.StatusCreated, room)
+}
+
+// GetRoom godoc
+// @Summary Комната чата
+// @Description Приватную комнату видят только ее участники
+// @Tags Чат
+// @Produce json
+// @Param id path int true "ID комнаты"
+// @Success 200 {object} entity.ChatRoom
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/rooms/{id} [get]
+func (h *ChatHandler) GetRoom(c *gin.Context) {
+	roomID, ok := h.roomParam(c)
+	if !ok {
+		return
+	}
+	room, err := h.roomUC.GetRoom(c.Request.Context(), roomID, currentUserID(c))
+	if err != nil {
+		h.respondChatRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, room)
+}
+
+// DeleteRoom godoc
+// @Summary Удалить комнату чата
+// @Description Удаляет комнату вместе с историей, это может только владелец. Общую комнату удалить нельзя
+// @Tags Чат
+// @Security BearerAuth
+// @Param id path int true "ID комнаты"
+// @Success 204 "No Content"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/rooms/{id} [delete]
+func (h *ChatHandler) DeleteRoom(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return
+	}
+	roomID, ok := h.roomParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.roomUC.DeleteRoom(c.Request.Context(), roomID, principal.UserID); err != nil {
+		h.respondChatRoomError(c, err)
+		return
+	}
+	h.hub.LeaveRoom(roomID, 0)
+
+	h.logger.Info("Chat room deleted successfully", zap.Int("roomID", roomID))
+	c.Status(http.StatusNoContent)
+}
+
+// ListRoomMembers godoc
+// @Summary Участники комнаты чата
+// @Tags Чат
+// @Produce json
+// @Param id path int true "ID комнаты"
+// @Success 200 {array} entity.ChatRoomMember
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/rooms/{id}/members [get]
+func (h *ChatHandler) ListRoomMembers(c *gin.Context) {
+	roomID, ok := h.roomParam(c)
+	if !ok {
+		return
+	}
+	members, err := h.roomUC.ListMembers(c.Request.Context(), roomID, currentUserID(c))
+	if err != nil {
+		h.respondChatRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// AddRoomMember godoc
+// @Summary Добавить участника в комнату чата
+// @Description Владелец приглашает пользователя, остальные могут только сами вступить в публичную комнату
+// @Tags Чат
+// @Accept json
+// @Security BearerAuth
+// @Param id path int true "ID комнаты"
+// @Param member body entity.ChatRoomMemberRequest true "Пользователь"
+// @Success 204 "No Content"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/rooms/{id}/members [post]
+func (h *ChatHandler) AddRoomMember(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return
+	}
+	roomID, ok := h.roomParam(c)
+	if !ok {
+		return
+	}
+
+	var req entity.ChatRoomMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.roomUC.AddMember(c.Request.Context(), roomID, principal.UserID, req.UserID); err != nil {
+		h.respondChatRoomError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveRoomMember godoc
+// @Summary Удалить участника из комнаты чата
+// @Description Владелец удаляет любого участника, остальные могут только выйти сами. Клиенты удаленного участника отписываются от комнаты
+// @Tags Чат
+// @Security BearerAuth
+// @Param id path int true "ID комнаты"
+// @Param user_id path int true "ID пользователя"
+// @Success 204 "No Content"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /chat/rooms/{id}/members/{user_id} [delete]
+func (h *ChatHandler) RemoveRoomMember(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return
+	}
+	roomID, ok := h.roomParam(c)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.roomUC.RemoveMember(c.Request.Context(), roomID, principal.UserID, userID); err != nil {
+		h.respondChatRoomError(c, err)
+		return
+	}
+	h.hub.LeaveRoom(roomID, userID)
+	c.Status(http.StatusNoContent)
+}
+
+// roomParam разбирает ID комнаты из :id, при ошибке ответ уже записан
+func (h *ChatHandler) roomParam(c *gin.Context) (int, bool) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return 0, false
+	}
+	return roomID, true
+}
+
+// roomsQuery разбирает комнаты из ?room=1&room=2 или ?room=1,2 без повторов. Без параметра - общая комната
+func roomsQuery(c *gin.Context) ([]int, error) {
+	var roomIDs []int
+	seen := make(map[int]bool)
+	for _, value := range c.QueryArray("room") {
+		for _, part := range strings.Split(value, ",") {
+			roomID, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || roomID < 1 {
+				return nil, errors.New("invalid room ID: " + part)
+			}
+			if seen[roomID] {
+				continue
+			}
+			seen[roomID] = true
+			roomIDs = append(roomIDs, roomID)
+		}
+	}
+	if len(roomIDs) > chat.MaxRooms {
+		return nil, fmt.Errorf("too many rooms: at most %d per connection", chat.MaxRooms)
+	}
+	if len(roomIDs) == 0 {
+		roomIDs = []int{entity.DefaultChatRoomID}
+	}
+	return roomIDs, nil
+}
+
+func (h *ChatHandler) respondChatRoomError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrChatRoomNotFound), errors.Is(err, usecase.ErrNotChatRoomMember):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrChatRoomForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidChatRoomName):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrChatRoomNameTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Chat room request failed", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Chat room request failed"})
+	}
+}
+
+// currentUserID - ID текущего пользователя, 0 для анонимного запроса
+func currentUserID(c *gin.Context) int {
+	if principal, ok := middleware.GetPrincipal(c); ok {
+		return principal.UserID
+	}
+	return 0
+}
@@ -1,92 +1,648 @@
 package http
 
 import (
-	utils "github.com/Engls/EnglsJwt"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Engls/forum-project2/forum_service/internal/controllers/chat"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
 	"github.com/Engls/forum-project2/forum_service/mocks"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 )
 
-func TestChatHandler_ServeWS_Success(t *testing.T) {
+// chatUsers - проверка токенов для тестов ServeWS: "alice-token" принадлежит пользователю 1 alice
+func chatUsers() (*mocks.TokenValidator, *mocks.UserService) {
+	validator := new(mocks.TokenValidator)
+	validator.On("ValidateToken", mock.Anything, "alice-token").Return(entity.Principal{UserID: 1, Role: "user"}, nil).Maybe()
+	validator.On("ValidateToken", mock.Anything, mock.Anything).Return(entity.Principal{}, errors.New("invalid token")).Maybe()
+	userService := new(mocks.UserService)
+	userService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "alice"}, nil).Maybe()
+	return validator, userService
+}
 
+// chatServer запускает хаб и сервер с ServeWS и GET /chat/online и возвращает адрес сервера
+func chatServer(t *testing.T, chatUsecase *mocks.ChatUsecase) string {
 	logger, _ := zap.NewProduction()
 
-	mockChatUsecase := new(mocks.ChatUsecase)
-	jwtUtil := utils.NewJWTUtil("secret")
+	validator, userService := chatUsers()
 	hub := chat.NewHub()
+	go hub.Run()
 
-	chatHandler := NewChatHandler(hub, mockChatUsecase, jwtUtil, logger)
-
-	token, err := jwtUtil.GenerateToken(1, "user")
-	assert.NoError(t, err)
+	chatHandler := NewChatHandler(hub, chatUsecase, publicRooms(), new(mocks.DirectMessageUsecase), new(mocks.ReactionUsecase), validator, userService, logger)
 
 	router := gin.Default()
-	router.GET("/ws/chat", chatHandler.ServeWS)
+	router.GET("/ws", chatHandler.ServeWS)
+	router.GET("/chat/online", chatHandler.GetOnline)
 
 	server := httptest.NewServer(router)
-	defer server.Close()
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// dialChat запускает хаб и сервер с ServeWS и открывает соединение с параметрами query
+func dialChat(t *testing.T, chatUsecase *mocks.ChatUsecase, query string) (*websocket.Conn, *http.Response, error) {
+	return websocket.DefaultDialer.Dial("ws"+chatServer(t, chatUsecase)[4:]+"/ws"+query, nil)
+}
+
+// readFrame читает следующий кадр соединения
+func readFrame(t *testing.T, ws *websocket.Conn) map[string]interface{} {
+	var frame map[string]interface{}
+	assert.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	assert.NoError(t, ws.ReadJSON(&frame))
+	return frame
+}
+
+func TestChatHandler_ServeWS_IdentityFromToken(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+	stored := &entity.ChatMessage{ID: 7, RoomID: entity.DefaultChatRoomID, UserID: 1, Username: "alice", Content: "hi", ClientID: "c-1",
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	mockChatUsecase.On("HandleMessage", mock.Anything, entity.DefaultChatRoomID, 1, "alice", "hi", "c-1").Return(stored, true, nil)
 
-	url := "ws" + server.URL[4:] + "/ws/chat?token=" + token + "&userID=1&username=user&auth=true"
-	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
-	assert.NoError(t, err)
+	// userID и username из URL и кадра не влияют на автора сообщения
+	ws, _, err := dialChat(t, mockChatUsecase, "?token=alice-token&userID=2&username=mallory&auth=true")
+	if !assert.NoError(t, err) {
+		return
+	}
 	defer ws.Close()
 
-	assert.NotNil(t, ws)
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"v": 1, "type": "message", "client_id": "c-1", "userID": 2, "username": "mallory", "content": "hi"}))
+
+	assert.Equal(t, map[string]interface{}{"v": float64(1), "type": "ack", "client_id": "c-1", "id": float64(7),
+		"timestamp": "2025-01-02T03:04:05Z", "duplicate": false}, readFrame(t, ws))
+
+	frame := readFrame(t, ws)
+	assert.Equal(t, "message", frame["type"])
+	assert.Equal(t, float64(1), frame["userID"])
+	assert.Equal(t, "alice", frame["username"])
+	assert.Equal(t, "c-1", frame["client_id"])
+
+	mockChatUsecase.AssertExpectations(t)
+}
+
+func TestChatHandler_ServeWS_DuplicateIsOnlyAcknowledged(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+	stored := &entity.ChatMessage{ID: 7, RoomID: entity.DefaultChatRoomID, UserID: 1, Username: "alice", Content: "hi", ClientID: "c-1"}
+	mockChatUsecase.On("HandleMessage", mock.Anything, entity.DefaultChatRoomID, 1, "alice", "hi", "c-1").Return(stored, false, nil)
+
+	ws, _, err := dialChat(t, mockChatUsecase, "?token=alice-token")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "message", "client_id": "c-1", "content": "hi"}))
+	frame := readFrame(t, ws)
+	assert.Equal(t, "ack", frame["type"])
+	assert.Equal(t, float64(7), frame["id"])
+	assert.Equal(t, true, frame["duplicate"])
+
+	// Повторное сообщение не рассылается
+	assert.NoError(t, ws.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+	assert.Error(t, ws.ReadJSON(&frame))
+}
+
+func TestChatHandler_ServeWS_ErrorFrames(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+	mockChatUsecase.On("HandleMessage", mock.Anything, entity.DefaultChatRoomID, 1, "alice", " ", "c-2").Return(nil, false, usecase.ErrEmptyChatMessage)
+	mockChatUsecase.On("HandleMessage", mock.Anything, entity.DefaultChatRoomID, 1, "alice", "hi", "c-3").Return(nil, false, errors.New("disk I/O error"))
+
+	ws, _, err := dialChat(t, mockChatUsecase, "?token=alice-token")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	tests := []struct {
+		frame string
+		code  string
+	}{
+		{`plain text`, entity.ChatErrBadFrame},
+		{`{"content":"no type"}`, entity.ChatErrBadFrame},
+		{`{"v":2,"type":"message","content":"hi"}`, entity.ChatErrUnsupportedVersion},
+		{`{"type":"shout","content":"hi"}`, entity.ChatErrUnknownType},
+		{`{"type":"ack","client_id":"c-1"}`, entity.ChatErrUnknownType},
+		{`{"type":"message","room_id":99,"content":"hi"}`, entity.ChatErrNotSubscribed},
+		{`{"type":"message","client_id":"c-2","content":" "}`, entity.ChatErrInvalid},
+		{`{"type":"message","client_id":"c-3","content":"hi"}`, entity.ChatErrInternal},
+	}
+	for _, tt := range tests {
+		assert.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(tt.frame)))
+		frame := readFrame(t, ws)
+		assert.Equal(t, "error", frame["type"], tt.frame)
+		assert.Equal(t, tt.code, frame["code"], tt.frame)
+	}
+
+	// Внутренние ошибки не раскрываются клиенту
+	assert.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(`{"type":"message","client_id":"c-3","content":"hi"}`)))
+	frame := readFrame(t, ws)
+	assert.Equal(t, "c-3", frame["client_id"])
+	assert.Equal(t, "internal server error", frame["error"])
+}
+
+func TestChatHandler_ServeWS_HistoryRequest(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	messages := []entity.ChatMessage{{ID: 1, Content: "first", Timestamp: createdAt}, {ID: 2, Content: "second", Timestamp: createdAt}, {ID: 3, Content: "third", Timestamp: createdAt}}
+	mockChatUsecase.On("GetHistory", mock.Anything, entity.DefaultChatRoomID, (*entity.Cursor)(nil), 3).Return(messages, nil)
+
+	// Историю может запросить и гость
+	ws, _, err := dialChat(t, mockChatUsecase, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "history_request", "limit": 2}))
+	frame := readFrame(t, ws)
+	assert.Equal(t, "history", frame["type"])
+	assert.Equal(t, float64(entity.DefaultChatRoomID), frame["room_id"])
+	assert.Len(t, frame["messages"], 2)
+	assert.Equal(t, entity.CursorAfter(createdAt, 2).Encode(), frame["next_cursor"])
+
+	mockChatUsecase.AssertExpectations(t)
+}
+
+func TestChatHandler_ServeWS_Typing(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+
+	ws, _, err := dialChat(t, mockChatUsecase, "?token=alice-token")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "typing"}))
+	assert.Equal(t, map[string]interface{}{"v": float64(1), "type": "typing", "room_id": float64(entity.DefaultChatRoomID),
+		"user_id": float64(1), "username": "alice"}, readFrame(t, ws))
+
+	// Частые кадры typing отбрасываются
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "typing"}))
+	var frame map[string]interface{}
+	assert.NoError(t, ws.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+	assert.Error(t, ws.ReadJSON(&frame))
+
+	mockChatUsecase.AssertNotCalled(t, "HandleMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChatHandler_ServeWS_Presence(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+
+	serverURL := chatServer(t, mockChatUsecase)
+	wsURL := "ws" + serverURL[4:] + "/ws"
+	online := func() []entity.OnlineUser {
+		resp, err := http.Get(serverURL + "/chat/online")
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		defer resp.Body.Close()
+		var users []entity.OnlineUser
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&users))
+		return users
+	}
+
+	observer, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer observer.Close()
+
+	// Гость не появляется в сети, о первой вкладке пользователя узнают остальные
+	first, _, err := websocket.DefaultDialer.Dial(wsURL+"?token=alice-token", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{"v": float64(1), "type": "presence", "user_id": float64(1),
+		"username": "alice", "status": "online"}, readFrame(t, observer))
+
+	second, _, err := websocket.DefaultDialer.Dial(wsURL+"?token=alice-token", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Eventually(t, func() bool {
+		users := online()
+		return len(users) == 1 && users[0].Connections == 2
+	}, 2*time.Second, 20*time.Millisecond)
+	if users := online(); assert.Len(t, users, 1) {
+		assert.Equal(t, 1, users[0].UserID)
+		assert.Equal(t, "alice", users[0].Username)
+	}
+
+	// Закрытие одной из вкладок не меняет присутствие
+	first.Close()
+	assert.Eventually(t, func() bool {
+		users := online()
+		return len(users) == 1 && users[0].Connections == 1
+	}, 2*time.Second, 20*time.Millisecond)
+
+	second.Close()
+	assert.Equal(t, map[string]interface{}{"v": float64(1), "type": "presence", "user_id": float64(1),
+		"username": "alice", "status": "offline"}, readFrame(t, observer))
+	assert.Empty(t, online())
+}
+
+func TestChatHandler_ServeWS_AuthFrameGoesOnline(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+
+	wsURL := "ws" + chatServer(t, mockChatUsecase)[4:] + "/ws"
+	observer, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer observer.Close()
+	guest, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer guest.Close()
+
+	assert.NoError(t, guest.WriteJSON(map[string]interface{}{"type": "auth", "token": "alice-token"}))
+	assert.Equal(t, "auth", readFrame(t, guest)["type"])
+
+	frame := readFrame(t, observer)
+	assert.Equal(t, "presence", frame["type"])
+	assert.Equal(t, "online", frame["status"])
+	assert.Equal(t, float64(1), frame["user_id"])
 }
 
 func TestChatHandler_ServeWS_InvalidToken(t *testing.T) {
 
+	_, resp, err := dialChat(t, new(mocks.ChatUsecase), "?token=invalid_token")
+
+	assert.Error(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+}
+
+func TestChatHandler_ServeWS_GuestIsReadOnly(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).
+		Return([]entity.ChatMessage{{ID: 1, UserID: 1, Username: "alice", Content: "welcome"}}, nil)
+
+	ws, _, err := dialChat(t, mockChatUsecase, "?userID=1&username=alice&auth=true")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	// Гость получает историю, но его сообщения не сохраняются и не рассылаются
+	frame := readFrame(t, ws)
+	assert.Equal(t, "message", frame["type"])
+	assert.Equal(t, "welcome", frame["content"])
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "message", "client_id": "c-1", "content": "spoofed"}))
+	frame = readFrame(t, ws)
+	assert.Equal(t, "error", frame["type"])
+	assert.Equal(t, entity.ChatErrReadOnly, frame["code"])
+	assert.Equal(t, "c-1", frame["client_id"])
+
+	mockChatUsecase.AssertNotCalled(t, "HandleMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChatHandler_ServeWS_AuthFrame(t *testing.T) {
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+	stored := &entity.ChatMessage{ID: 7, RoomID: entity.DefaultChatRoomID, UserID: 1, Username: "alice", Content: "hi"}
+	mockChatUsecase.On("HandleMessage", mock.Anything, entity.DefaultChatRoomID, 1, "alice", "hi", "").Return(stored, true, nil)
+
+	ws, _, err := dialChat(t, mockChatUsecase, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "auth", "token": "alice-token"}))
+	assert.Equal(t, map[string]interface{}{"v": float64(1), "type": "auth", "user_id": float64(1), "username": "alice"}, readFrame(t, ws))
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "message", "content": "hi"}))
+	assert.Equal(t, "ack", readFrame(t, ws)["type"])
+	assert.Equal(t, "alice", readFrame(t, ws)["username"])
+
+	mockChatUsecase.AssertExpectations(t)
+}
+
+func TestChatHandler_ServeWS_AuthFramePrivateRooms(t *testing.T) {
+
 	logger, _ := zap.NewProduction()
 
 	mockChatUsecase := new(mocks.ChatUsecase)
-	jwtUtil := utils.NewJWTUtil("secret")
-	hub := chat.NewHub()
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+	history := []entity.ChatMessage{{ID: 3, RoomID: 5, UserID: 2, Username: "bob", Content: "secret"}}
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, 5, 50).Return(history, nil).Once()
+	stored := &entity.ChatMessage{ID: 8, RoomID: 5, UserID: 1, Username: "alice", Content: "hi"}
+	mockChatUsecase.On("HandleMessage", mock.Anything, 5, 1, "alice", "hi", "").Return(stored, true, nil)
 
-	chatHandler := NewChatHandler(hub, mockChatUsecase, jwtUtil, logger)
+	// Гость видит только общую комнату, закрытая комната 5 доступна alice, комната 6 - нет
+	mockRoomUsecase := new(mocks.ChatRoomUsecase)
+	mockRoomUsecase.On("CheckAccess", mock.Anything, entity.DefaultChatRoomID, 0).Return(nil)
+	mockRoomUsecase.On("CheckAccess", mock.Anything, 5, 1).Return(nil).Once()
+	mockRoomUsecase.On("CheckAccess", mock.Anything, 6, 1).Return(usecase.ErrChatRoomForbidden).Once()
 
+	validator, userService := chatUsers()
+	hub := chat.NewHub()
+	go hub.Run()
+	chatHandler := NewChatHandler(hub, mockChatUsecase, mockRoomUsecase, new(mocks.DirectMessageUsecase), new(mocks.ReactionUsecase), validator, userService, logger)
 	router := gin.Default()
-	router.GET("/ws/chat", chatHandler.ServeWS)
-
+	router.GET("/ws", chatHandler.ServeWS)
 	server := httptest.NewServer(router)
 	defer server.Close()
+	wsURL := "ws" + server.URL[4:] + "/ws"
+
+	ws, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	// Доступ к комнате 5 проверяется уже для alice, после подписки приходит ее история
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "auth", "token": "alice-token", "rooms": []int{5}}))
+	assert.Equal(t, "secret", readFrame(t, ws)["content"])
+	assert.Equal(t, "auth", readFrame(t, ws)["type"])
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "message", "room_id": 5, "content": "hi"}))
+	assert.Equal(t, "ack", readFrame(t, ws)["type"])
+	assert.Equal(t, "hi", readFrame(t, ws)["content"])
+
+	// Недоступная комната отклоняет кадр auth, соединение остается гостевым
+	denied, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer denied.Close()
+
+	assert.NoError(t, denied.WriteJSON(map[string]interface{}{"type": "auth", "token": "alice-token", "rooms": []int{6}}))
+	frame := readFrame(t, denied)
+	assert.Equal(t, "error", frame["type"])
+	assert.Equal(t, entity.ChatErrInvalid, frame["code"])
+
+	assert.NoError(t, denied.WriteJSON(map[string]interface{}{"type": "message", "room_id": 6, "content": "hi"}))
+	assert.Equal(t, entity.ChatErrReadOnly, readFrame(t, denied)["code"])
+
+	mockChatUsecase.AssertExpectations(t)
+	mockRoomUsecase.AssertExpectations(t)
+}
+
+func TestChatHandler_ServeWS_AuthFrameInvalidToken(t *testing.T) {
 
-	url := "ws" + server.URL[4:] + "/ws/chat?token=invalid_token&userID=1&username=user&auth=true"
-	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
-	assert.NoError(t, err)
+	mockChatUsecase := new(mocks.ChatUsecase)
+	mockChatUsecase.On("GetRecentMessages", mock.Anything, entity.DefaultChatRoomID, 50).Return([]entity.ChatMessage{}, nil)
+
+	ws, _, err := dialChat(t, mockChatUsecase, "")
+	if !assert.NoError(t, err) {
+		return
+	}
 	defer ws.Close()
 
-	assert.NotNil(t, ws)
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "auth", "token": "invalid_token"}))
+	assert.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, _, err = ws.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "unexpected error: %v", err)
+}
+
+func TestChatHandler_GetHistory_LastPage(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockChatUsecase := new(mocks.ChatUsecase)
+
+	mockReactionUsecase := new(mocks.ReactionUsecase)
+
+	chatHandler := NewChatHandler(chat.NewHub(), mockChatUsecase, publicRooms(), new(mocks.DirectMessageUsecase), mockReactionUsecase, new(mocks.TokenValidator), new(mocks.UserService), logger)
+
+	messages := []entity.ChatMessage{{ID: 1, Content: "first"}, {ID: 2, Content: "second"}}
+	mockChatUsecase.On("GetHistory", mock.Anything, entity.DefaultChatRoomID, (*entity.Cursor)(nil), 51).Return(messages, nil)
+	mockReactionUsecase.On("GetReactions", mock.Anything, 0, entity.ReactionTargetChatMessage, []int{1, 2}).
+		Return(map[int][]entity.ReactionCount{2: {{Emoji: "😂", Count: 3}}}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/chat/messages", nil)
+
+	chatHandler.GetHistory(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"next_cursor":null`)
+	assert.Contains(t, w.Body.String(), `"second"`)
+	assert.Contains(t, w.Body.String(), `"reactions":[{"emoji":"😂","count":3,"reacted_by_me":false}]`)
+
+	mockChatUsecase.AssertExpectations(t)
+	mockReactionUsecase.AssertExpectations(t)
 }
 
-func TestChatHandler_ServeWS_InvalidUserID(t *testing.T) {
+func TestChatHandler_AddReaction_Broadcasts(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockChatUsecase := new(mocks.ChatUsecase)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockRoomUsecase := new(mocks.ChatRoomUsecase)
+	mockReactionUsecase := new(mocks.ReactionUsecase)
 	hub := chat.NewHub()
 
-	chatHandler := NewChatHandler(hub, mockChatUsecase, jwtUtil, logger)
+	chatHandler := NewChatHandler(hub, mockChatUsecase, mockRoomUsecase, new(mocks.DirectMessageUsecase), mockReactionUsecase, new(mocks.TokenValidator), new(mocks.UserService), logger)
 
-	token, err := jwtUtil.GenerateToken(1, "user")
-	assert.NoError(t, err)
+	mockChatUsecase.On("GetMessage", mock.Anything, 5).Return(&entity.ChatMessage{ID: 5, RoomID: 3}, nil)
+	mockRoomUsecase.On("CheckAccess", mock.Anything, 3, 7).Return(nil)
 
-	router := gin.Default()
-	router.GET("/ws/chat", chatHandler.ServeWS)
+	reaction := entity.Reaction{UserID: 7, TargetType: entity.ReactionTargetChatMessage, TargetID: 5, Emoji: "❤"}
+	mockReactionUsecase.On("AddReaction", mock.Anything, reaction).Return(&entity.ReactionResult{
+		TargetType: entity.ReactionTargetChatMessage,
+		TargetID:   5,
+		Emoji:      "❤️",
+		Reactions:  []entity.ReactionCount{{Emoji: "👍", Count: 1}, {Emoji: "❤️", Count: 2, ReactedByMe: true}},
+	}, nil)
 
-	server := httptest.NewServer(router)
-	defer server.Close()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PUT", "/chat/messages/5/reactions/❤", nil)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}, gin.Param{Key: "emoji", Value: "❤"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
 
-	url := "ws" + server.URL[4:] + "/ws/chat?token=" + token + "&userID=invalid&username=user&auth=true"
-	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
-	assert.NoError(t, err)
-	defer ws.Close()
+	chatHandler.AddReaction(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	message := <-hub.Broadcast
+	assert.Equal(t, 3, message.RoomID)
+	var event entity.ReactionEvent
+	assert.NoError(t, json.Unmarshal(message.Data, &event))
+	assert.Equal(t, entity.ReactionEvent{
+		V: 1, Type: "reaction", MessageID: 5, UserID: 7, Emoji: "❤️", Action: entity.ReactionAdded, Count: 2,
+	}, event)
+
+	mockReactionUsecase.AssertExpectations(t)
+}
+
+// publicRooms - ChatRoomUsecase для тестов, где все комнаты публичные
+func publicRooms() *mocks.ChatRoomUsecase {
+	roomUsecase := new(mocks.ChatRoomUsecase)
+	roomUsecase.On("CheckAccess", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return roomUsecase
+}
+
+func TestChatHandler_ServeWS_PrivateRoomForbidden(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomUsecase := new(mocks.ChatRoomUsecase)
+	mockValidator := new(mocks.TokenValidator)
+	mockUserService := new(mocks.UserService)
+
+	chatHandler := NewChatHandler(chat.NewHub(), new(mocks.ChatUsecase), mockRoomUsecase, new(mocks.DirectMessageUsecase), new(mocks.ReactionUsecase), mockValidator, mockUserService, logger)
+
+	token := "bob-token"
+	mockValidator.On("ValidateToken", mock.Anything, token).Return(entity.Principal{UserID: 2, Role: "user"}, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{2}).Return(map[int]string{2: "bob"}, nil)
+
+	mockRoomUsecase.On("CheckAccess", mock.Anything, 1, 2).Return(nil)
+	mockRoomUsecase.On("CheckAccess", mock.Anything, 5, 2).Return(usecase.ErrChatRoomForbidden)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/ws?token="+token+"&room=1,5", nil)
+
+	chatHandler.ServeWS(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRoomUsecase.AssertExpectations(t)
+}
+
+func TestChatHandler_ServeWS_InvalidRoom(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	chatHandler := NewChatHandler(chat.NewHub(), new(mocks.ChatUsecase), new(mocks.ChatRoomUsecase), new(mocks.DirectMessageUsecase), new(mocks.ReactionUsecase), new(mocks.TokenValidator), new(mocks.UserService), logger)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/ws?room=abc", nil)
+
+	chatHandler.ServeWS(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRoomsQuery(t *testing.T) {
+
+	tests := []struct {
+		query   string
+		rooms   []int
+		wantErr bool
+	}{
+		{"", []int{entity.DefaultChatRoomID}, false},
+		{"?room=3&room=1,3", []int{3, 1}, false},
+		{"?room=1,1,1,1,1,1,1,1,1,1,1,1", []int{1}, false},
+		{"?room=1,2,3,4,5,6,7,8,9,10", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, false},
+		{"?room=1,2,3,4,5,6,7,8,9,10,11", nil, true},
+		{"?room=0", nil, true},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/ws"+tt.query, nil)
+
+		rooms, err := roomsQuery(c)
+		if tt.wantErr {
+			assert.Error(t, err, tt.query)
+			continue
+		}
+		assert.NoError(t, err, tt.query)
+		assert.Equal(t, tt.rooms, rooms, tt.query)
+	}
+}
+
+func TestChatHandler_GetRoomHistory_Forbidden(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomUsecase := new(mocks.ChatRoomUsecase)
+
+	chatHandler := NewChatHandler(chat.NewHub(), new(mocks.ChatUsecase), mockRoomUsecase, new(mocks.DirectMessageUsecase), new(mocks.ReactionUsecase), new(mocks.TokenValidator), new(mocks.UserService), logger)
+
+	mockRoomUsecase.On("CheckAccess", mock.Anything, 5, 0).Return(usecase.ErrChatRoomForbidden)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/chat/rooms/5/messages", nil)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+
+	chatHandler.GetRoomHistory(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRoomUsecase.AssertExpectations(t)
+}
+
+func TestChatHandler_CreateRoom_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomUsecase := new(mocks.ChatRoomUsecase)
+
+	chatHandler := NewChatHandler(chat.NewHub(), new(mocks.ChatUsecase), mockRoomUsecase, new(mocks.DirectMessageUsecase), new(mocks.ReactionUsecase), new(mocks.TokenValidator), new(mocks.UserService), logger)
+
+	mockRoomUsecase.On("CreateRoom", mock.Anything, 7, entity.ChatRoomRequest{Name: "team", IsPrivate: true}).
+		Return(&entity.ChatRoom{ID: 5, Name: "team", IsPrivate: true, OwnerID: 7, MemberCount: 1}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/chat/rooms", bytes.NewBufferString(`{"name": "team", "is_private": true}`))
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	chatHandler.CreateRoom(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var room entity.ChatRoom
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &room))
+	assert.Equal(t, 5, room.ID)
+	assert.True(t, room.IsPrivate)
+
+	mockRoomUsecase.AssertExpectations(t)
+}
+
+func TestChatHandler_RemoveRoomMember_LeavesHubRoom(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomUsecase := new(mocks.ChatRoomUsecase)
+	hub := chat.NewHub()
+
+	chatHandler := NewChatHandler(hub, new(mocks.ChatUsecase), mockRoomUsecase, new(mocks.DirectMessageUsecase), new(mocks.ReactionUsecase), new(mocks.TokenValidator), new(mocks.UserService), logger)
+
+	mockRoomUsecase.On("RemoveMember", mock.Anything, 5, 7, 8).Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("DELETE", "/chat/rooms/5/members/8", nil)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}, gin.Param{Key: "user_id", Value: "8"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	chatHandler.RemoveRoomMember(c)
 
-	assert.NotNil(t, ws)
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+	assert.Equal(t, chat.RoomLeave{RoomID: 5, UserID: 8}, <-hub.Leave)
+	mockRoomUsecase.AssertExpectations(t)
 }
@@ -1,8 +1,9 @@
 package http
 
 import (
-	utils "github.com/Engls/EnglsJwt"
-	"github.com/Engls/forum-project2/forum_service/internal/controllers/grpc"
+	"errors"
+	"github.com/Engls/forum-project2/forum_service/internal/authz"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/usecase"
 	"github.com/gin-gonic/gin"
@@ -13,19 +14,32 @@ import (
 )
 
 type CommentHandler struct {
-	commentUsecase usecase.CommentsUsecases
-	jwtUtil        *utils.JWTUtil
-	logger         *zap.Logger
-	userClient     *grpc.UserClient
+	commentUsecase  usecase.CommentsUsecases
+	voteUsecase     usecase.VoteUsecase
+	reactionUsecase usecase.ReactionUsecase
+	logger          *zap.Logger
+	userClient      UserService
 }
 
-func NewCommentHandler(commentUsecase usecase.CommentsUsecases, jwtUtil *utils.JWTUtil, logger *zap.Logger, userClient *grpc.UserClient) *CommentHandler {
-	return &CommentHandler{commentUsecase: commentUsecase, jwtUtil: jwtUtil, logger: logger, userClient: userClient}
+func NewCommentHandler(
+	commentUsecase usecase.CommentsUsecases,
+	voteUsecase usecase.VoteUsecase,
+	reactionUsecase usecase.ReactionUsecase,
+	logger *zap.Logger,
+	userClient UserService,
+) *CommentHandler {
+	return &CommentHandler{
+		commentUsecase:  commentUsecase,
+		voteUsecase:     voteUsecase,
+		reactionUsecase: reactionUsecase,
+		logger:          logger,
+		userClient:      userClient,
+	}
 }
 
 // CreateComment godoc
 // @Summary Создать новый комментарий
-// @Description Создает новый комментарий к указанному посту
+// @Description Создает новый комментарий к указанному посту. Если задан parent_id, комментарий становится ответом
 // @Tags Комментарии
 // @Accept json
 // @Produce json
@@ -53,32 +67,23 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		return
 	}
 
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		h.logger.Error("Authorization header required")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-		return
-	}
-
-	tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-	if tokenString == authHeader {
-		h.logger.Error("Invalid Authorization header format")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format"})
-		return
-	}
-
-	userID, err := h.jwtUtil.GetUserIDFromToken(tokenString)
-	if err != nil {
-		h.logger.Error("Invalid token or user ID", zap.Error(err))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token or user ID"})
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		h.logger.Error("Principal not found in context")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
 		return
 	}
+	userID := principal.UserID
 
 	comment.PostId = postID
 	comment.AuthorId = userID
 
 	createdComment, err := h.commentUsecase.CreateComment(c.Request.Context(), comment)
 	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidParent) || errors.Is(err, usecase.ErrMaxDepthExceeded) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to create comment", zap.Error(err))
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -90,14 +95,18 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 
 // GetComments returns paginated comments for a post
 // @Summary Получить комментарии
-// @Description Получить комментарии
+// @Description Получить комментарии с рейтингом и реакциями, для аутентифицированного пользователя - с его голосом (my_vote). В режиме tree страница состоит из комментариев верхнего уровня с ответами на depth уровней вглубь, остальные ответы подгружаются через /comments/{id}/replies
 // @Tags Комментарии
 // @Accept json
 // @Produce json
 // @Param post_id path int true "Post ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param tree query bool false "Вернуть комментарии верхнего уровня деревом" default(false)
+// @Param depth query int false "Сколько уровней ответов загрузить в режиме дерева" default(0)
+// @Param cursor query string false "Курсор: pagination.next_cursor из предыдущего ответа, пусто - первая страница. Заменяет page"
 // @Success 200 {object} map[string]interface{} "comments and pagination info"
+// @Failure 400 {object} entity.ErrorResponse
 // @Router /posts/{post_id}/comments [get]
 func (h *CommentHandler) GetComments(c *gin.Context) {
 	postID, err := strconv.Atoi(c.Param("post_id"))
@@ -106,10 +115,31 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 		return
 	}
 
+	after, cursorMode, err := cursorQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tree, _ := strconv.ParseBool(c.DefaultQuery("tree", "false"))
+
+	if cursorMode {
+		if tree {
+			h.getCommentTreeAfter(c, postID, after)
+		} else {
+			h.getCommentsAfter(c, postID, after)
+		}
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	offset := (page - 1) * limit
 
+	if tree {
+		h.getCommentTree(c, postID, page, limit, offset)
+		return
+	}
+
 	comments, err := h.commentUsecase.GetComments(c.Request.Context(), postID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -121,27 +151,213 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	commentsWithUsernames := make([]map[string]interface{}, len(comments))
+	items := h.commentItems(c, comments)
+
+	var next *string
+	if len(comments) > 0 && offset+len(comments) < total {
+		last := comments[len(comments)-1]
+		next = nextCursor(last.CreatedAt, last.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": items,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"next_cursor": next,
+		},
+	})
+}
+
+// getCommentsAfter отдает плоскую страницу комментариев после курсора
+func (h *CommentHandler) getCommentsAfter(c *gin.Context, postID int, after *entity.Cursor) {
+	limit := cursorLimit(c)
+
+	comments, err := h.commentUsecase.GetCommentsAfter(c.Request.Context(), postID, after, limit+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	total, err := h.commentUsecase.GetTotalCommentsCount(c.Request.Context(), postID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var next *string
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[limit-1]
+		next = nextCursor(last.CreatedAt, last.ID)
+	}
+
+	items := h.commentItems(c, comments)
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": items,
+		"pagination": gin.H{
+			"limit":       limit,
+			"total":       total,
+			"next_cursor": next,
+		},
+	})
+}
+
+// getCommentTreeAfter отдает страницу комментариев верхнего уровня после курсора с ответами
+func (h *CommentHandler) getCommentTreeAfter(c *gin.Context, postID int, after *entity.Cursor) {
+	limit := cursorLimit(c)
+	depth, _ := strconv.Atoi(c.DefaultQuery("depth", "0"))
+
+	comments, err := h.commentUsecase.GetCommentTreeAfter(c.Request.Context(), postID, after, limit+1, depth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	total, err := h.commentUsecase.GetTopLevelCommentsCount(c.Request.Context(), postID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var next *string
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[limit-1]
+		next = nextCursor(last.CreatedAt, last.ID)
+	}
+	h.fillNodes(c, comments)
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"limit":       limit,
+			"total":       total,
+			"next_cursor": next,
+		},
+	})
+}
+
+// commentItems - плоский список комментариев с именами авторов, реакциями и голосами текущего пользователя
+func (h *CommentHandler) commentItems(c *gin.Context, comments []entity.Comment) []map[string]interface{} {
+	authorIDs := make([]int, len(comments))
+	commentIDs := make([]int, len(comments))
+	for i, comment := range comments {
+		authorIDs[i] = comment.AuthorId
+		commentIDs[i] = comment.ID
+	}
+	usernames := lookupUsernames(c.Request.Context(), h.userClient, h.logger, authorIDs)
+	myVotes := lookupMyVotes(c, h.voteUsecase, h.logger, entity.VoteTargetComment, commentIDs)
+	reactions := lookupReactions(c, h.reactionUsecase, h.logger, entity.ReactionTargetComment, commentIDs)
+	return commentListItems(comments, usernames, myVotes, reactions)
+}
+
+// commentListItems - плоский список комментариев с именами авторов и реакциями. my_vote добавляется,
+// только если myVotes не nil
+func commentListItems(comments []entity.Comment, usernames map[int]string, myVotes map[int]int, reactions map[int][]entity.ReactionCount) []map[string]interface{} {
+	items := make([]map[string]interface{}, len(comments))
 	for i, comment := range comments {
-		username, err := h.userClient.GetUsername(c.Request.Context(), comment.AuthorId)
-		if err != nil {
-			h.logger.Warn("Failed to get username",
-				zap.Int("userID", comment.AuthorId),
-				zap.Error(err))
-			username = "" // Используем пустое имя, если не удалось получить
+		items[i] = map[string]interface{}{
+			"id":         comment.ID,
+			"author_id":  comment.AuthorId,
+			"post_id":    comment.PostId,
+			"parent_id":  comment.ParentId,
+			"depth":      comment.Depth,
+			"content":    comment.Content,
+			"created_at": comment.CreatedAt,
+			"edited_at":  comment.EditedAt,
+			"deleted":    comment.IsDeleted(),
+			"score":      comment.Score,
+			"reactions":  reactionsOf(reactions, comment.ID),
+			"username":   usernames[comment.AuthorId],
+		}
+		if myVotes != nil {
+			items[i]["my_vote"] = myVotes[comment.ID]
 		}
+	}
+	return items
+}
+
+func (h *CommentHandler) getCommentTree(c *gin.Context, postID, page, limit, offset int) {
+	depth, _ := strconv.Atoi(c.DefaultQuery("depth", "0"))
 
-		commentsWithUsernames[i] = map[string]interface{}{
-			"id":        comment.ID,
-			"author_id": comment.AuthorId,
-			"post_id":   comment.PostId,
-			"content":   comment.Content,
-			"username":  username, // Добавляем имя пользователя
+	comments, err := h.commentUsecase.GetCommentTree(c.Request.Context(), postID, limit, offset, depth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	total, err := h.commentUsecase.GetTopLevelCommentsCount(c.Request.Context(), postID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.fillNodes(c, comments)
+
+	var next *string
+	if len(comments) > 0 && offset+len(comments) < total {
+		last := comments[len(comments)-1]
+		next = nextCursor(last.CreatedAt, last.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"next_cursor": next,
+		},
+	})
+}
+
+// GetReplies godoc
+// @Summary Получить ответы на комментарий
+// @Description Возвращает страницу прямых ответов на комментарий с вложенными ответами на depth уровней вглубь
+// @Tags Комментарии
+// @Produce json
+// @Param id path int true "ID комментария"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param depth query int false "Сколько уровней вложенных ответов загрузить" default(0)
+// @Success 200 {object} map[string]interface{} "replies and pagination info"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /comments/{id}/replies [get]
+func (h *CommentHandler) GetReplies(c *gin.Context) {
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	depth, _ := strconv.Atoi(c.DefaultQuery("depth", "0"))
+	offset := (page - 1) * limit
+
+	replies, err := h.commentUsecase.GetReplies(c.Request.Context(), commentID, limit, offset, depth)
+	if err != nil {
+		if errors.Is(err, usecase.ErrCommentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
 		}
+		h.logger.Error("Failed to get replies", zap.Error(err), zap.Int("commentID", commentID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	total, err := h.commentUsecase.GetRepliesCount(c.Request.Context(), commentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	h.fillNodes(c, replies)
 
 	c.JSON(http.StatusOK, gin.H{
-		"comments": commentsWithUsernames,
+		"comment_id": commentID,
+		"replies":    replies,
 		"pagination": gin.H{
 			"page":  page,
 			"limit": limit,
@@ -149,3 +365,218 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 		},
 	})
 }
+
+// VoteComment godoc
+// @Summary Проголосовать за комментарий
+// @Description Ставит голос 1 или -1, повторный запрос меняет голос, value=0 снимает его. Возвращает новый рейтинг комментария (требуется право vote)
+// @Tags Комментарии
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID комментария"
+// @Param vote body entity.VoteRequest true "Голос"
+// @Success 200 {object} entity.VoteResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /comments/{id}/vote [put]
+func (h *CommentHandler) VoteComment(c *gin.Context) {
+	castVote(c, h.voteUsecase, h.logger, entity.VoteTargetComment)
+}
+
+// AddReaction godoc
+// @Summary Поставить реакцию на комментарий
+// @Description Ставит реакцию эмодзи из разрешенного набора, повторный запрос ничего не меняет. Возвращает реакции на комментарий (требуется право react)
+// @Tags Комментарии
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID комментария"
+// @Param emoji path string true "Эмодзи"
+// @Success 200 {object} entity.ReactionResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /comments/{id}/reactions/{emoji} [put]
+func (h *CommentHandler) AddReaction(c *gin.Context) {
+	setReaction(c, h.reactionUsecase, h.logger, entity.ReactionTargetComment, true)
+}
+
+// RemoveReaction godoc
+// @Summary Снять реакцию с комментария
+// @Description Снимает реакцию текущего пользователя. Возвращает реакции на комментарий (требуется право react)
+// @Tags Комментарии
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID комментария"
+// @Param emoji path string true "Эмодзи"
+// @Success 200 {object} entity.ReactionResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /comments/{id}/reactions/{emoji} [delete]
+func (h *CommentHandler) RemoveReaction(c *gin.Context) {
+	setReaction(c, h.reactionUsecase, h.logger, entity.ReactionTargetComment, false)
+}
+
+// UpdateComment godoc
+// @Summary Редактировать комментарий
+// @Description Меняет текст комментария и проставляет edited_at (доступно автору или пользователю с правом comment.moderate)
+// @Tags Комментарии
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID комментария"
+// @Param comment body entity.UpdateCommentRequest true "Новый текст"
+// @Success 200 {object} entity.Comment
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /comments/{id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	var req entity.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Content is required"})
+		return
+	}
+
+	comment, ok := h.authorizeCommentChange(c, "update")
+	if !ok {
+		return
+	}
+	if comment.IsDeleted() {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": usecase.ErrCommentDeleted.Error()})
+		return
+	}
+
+	updated, err := h.commentUsecase.UpdateComment(c.Request.Context(), comment.ID, content)
+	if err != nil {
+		if errors.Is(err, usecase.ErrCommentNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to update comment", zap.Int("commentID", comment.ID), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	h.logger.Info("Comment updated successfully", zap.Int("commentID", comment.ID))
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteComment godoc
+// @Summary Удалить комментарий
+// @Description Мягко удаляет комментарий: в ветке остается заглушка "[deleted]", ответы сохраняются (доступно автору или пользователю с правом comment.moderate)
+// @Tags Комментарии
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID комментария"
+// @Success 204 "No Content"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	comment, ok := h.authorizeCommentChange(c, "delete")
+	if !ok {
+		return
+	}
+	if comment.IsDeleted() {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	err := h.commentUsecase.DeleteComment(c.Request.Context(), comment.ID)
+	if err != nil && !errors.Is(err, usecase.ErrCommentNotFound) {
+		h.logger.Error("Failed to delete comment", zap.Int("commentID", comment.ID), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	h.logger.Info("Comment deleted successfully", zap.Int("commentID", comment.ID))
+	c.Status(http.StatusNoContent)
+}
+
+// authorizeCommentChange загружает комментарий из пути и проверяет, что менять его может
+// автор или пользователь с правом comment.moderate. При отказе ответ уже записан
+func (h *CommentHandler) authorizeCommentChange(c *gin.Context, action string) (*entity.Comment, bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		h.logger.Warn("Principal not found in context")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return nil, false
+	}
+
+	commentIDStr := c.Param("id")
+	commentID, err := strconv.Atoi(commentIDStr)
+	if err != nil {
+		h.logger.Warn("Invalid comment ID", zap.String("commentID", commentIDStr), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return nil, false
+	}
+
+	comment, err := h.commentUsecase.GetCommentByID(c.Request.Context(), commentID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrCommentNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		h.logger.Error("Failed to get comment", zap.Int("commentID", commentID), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get comment"})
+		return nil, false
+	}
+
+	if comment.AuthorId != principal.UserID && !authz.Can(principal.Permissions, authz.CommentModerate) {
+		h.logger.Warn("Unauthorized attempt to "+action+" comment",
+			zap.Int("userID", principal.UserID),
+			zap.Int("commentAuthorID", comment.AuthorId),
+			zap.Int("commentID", commentID))
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not authorized to " + action + " this comment"})
+		return nil, false
+	}
+	return comment, true
+}
+
+// fillNodes проставляет имена авторов, реакции и голоса текущего пользователя во всем дереве,
+// по одному запросу на имена, реакции и голоса
+func (h *CommentHandler) fillNodes(c *gin.Context, nodes []*entity.CommentNode) {
+	var authorIDs, commentIDs []int
+	walkCommentNodes(nodes, func(node *entity.CommentNode) {
+		authorIDs = append(authorIDs, node.AuthorId)
+		commentIDs = append(commentIDs, node.ID)
+	})
+	usernames := lookupUsernames(c.Request.Context(), h.userClient, h.logger, authorIDs)
+	myVotes := lookupMyVotes(c, h.voteUsecase, h.logger, entity.VoteTargetComment, commentIDs)
+	reactions := lookupReactions(c, h.reactionUsecase, h.logger, entity.ReactionTargetComment, commentIDs)
+	walkCommentNodes(nodes, func(node *entity.CommentNode) {
+		node.Username = usernames[node.AuthorId]
+		node.Reactions = reactionsOf(reactions, node.ID)
+		if myVotes != nil {
+			vote := myVotes[node.ID]
+			node.MyVote = &vote
+		}
+	})
+}
+
+func walkCommentNodes(nodes []*entity.CommentNode, fn func(node *entity.CommentNode)) {
+	for _, node := range nodes {
+		fn(node)
+		walkCommentNodes(node.Replies, fn)
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	utils "github.com/Engls/EnglsJwt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/Engls/forum-project2/forum_service/internal/authz"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
 	"github.com/Engls/forum-project2/forum_service/mocks"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -22,36 +25,34 @@ func TestCommentHandler_CreateComment_Success(t *testing.T) {
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
-
-	token, err := jwtUtil.GenerateToken(1, "user")
-	assert.NoError(t, err)
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
 
 	comment := entity.Comment{
 		Content: "This is a test comment",
 	}
 	commentJSON, _ := json.Marshal(comment)
 
-	mockCommentUsecase.On("CreateComment", mock.Anything, mock.Anything).Return(comment, nil)
+	expected := entity.Comment{PostId: 1, AuthorId: 1, Content: "This is a test comment"}
+	mockCommentUsecase.On("CreateComment", mock.Anything, expected).Return(expected, nil)
 
 	req, _ := http.NewRequest("POST", "/posts/1/comments", bytes.NewBuffer(commentJSON))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
 	commentHandler.CreateComment(c)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
 	var responseComment entity.Comment
-	err = json.Unmarshal(w.Body.Bytes(), &responseComment)
+	err := json.Unmarshal(w.Body.Bytes(), &responseComment)
 	assert.NoError(t, err)
-	assert.Equal(t, comment, responseComment)
+	assert.Equal(t, expected, responseComment)
 
 	mockCommentUsecase.AssertExpectations(t)
 }
@@ -61,26 +62,20 @@ func TestCommentHandler_CreateComment_InvalidPostID(t *testing.T) {
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
-
-	token, err := jwtUtil.GenerateToken(1, "user")
-	assert.NoError(t, err)
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
 
-	comment := entity.Comment{
-		Content: "This is a test comment",
-	}
-	commentJSON, _ := json.Marshal(comment)
+	commentJSON, _ := json.Marshal(entity.Comment{Content: "This is a test comment"})
 
 	req, _ := http.NewRequest("POST", "/posts/invalid/comments", bytes.NewBuffer(commentJSON))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{gin.Param{Key: "id", Value: "invalid"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
 	commentHandler.CreateComment(c)
 
@@ -88,19 +83,16 @@ func TestCommentHandler_CreateComment_InvalidPostID(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Invalid post ID")
 }
 
-func TestCommentHandler_CreateComment_MissingAuthorizationHeader(t *testing.T) {
+func TestCommentHandler_CreateComment_NoPrincipal(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
 
-	comment := entity.Comment{
-		Content: "This is a test comment",
-	}
-	commentJSON, _ := json.Marshal(comment)
+	commentJSON, _ := json.Marshal(entity.Comment{Content: "This is a test comment"})
 
 	req, _ := http.NewRequest("POST", "/posts/1/comments", bytes.NewBuffer(commentJSON))
 	req.Header.Set("Content-Type", "application/json")
@@ -113,184 +105,495 @@ func TestCommentHandler_CreateComment_MissingAuthorizationHeader(t *testing.T) {
 	commentHandler.CreateComment(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Authorization header required")
+	assert.Contains(t, w.Body.String(), "Authorization required")
 }
 
-func TestCommentHandler_CreateComment_InvalidAuthorizationHeaderFormat(t *testing.T) {
+func TestCommentHandler_CreateComment_FailedToCreateComment(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
 
-	comment := entity.Comment{
-		Content: "This is a test comment",
-	}
-	commentJSON, _ := json.Marshal(comment)
+	commentJSON, _ := json.Marshal(entity.Comment{Content: "This is a test comment"})
+
+	mockCommentUsecase.On("CreateComment", mock.Anything, mock.Anything).Return(entity.Comment{}, errors.New("failed to create comment"))
 
 	req, _ := http.NewRequest("POST", "/posts/1/comments", bytes.NewBuffer(commentJSON))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "InvalidFormat")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
 	commentHandler.CreateComment(c)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid Authorization header format")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "failed to create comment")
+
+	mockCommentUsecase.AssertExpectations(t)
 }
 
-func TestCommentHandler_CreateComment_InvalidToken(t *testing.T) {
+func TestCommentHandler_GetComments_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
 
-	comment := entity.Comment{
-		Content: "This is a test comment",
+	comments := []entity.Comment{
+		{ID: 1, PostId: 1, AuthorId: 1, Content: "Comment 1"},
+		{ID: 2, PostId: 1, AuthorId: 1, Content: "Comment 2"},
 	}
-	commentJSON, _ := json.Marshal(comment)
+
+	mockCommentUsecase.On("GetComments", mock.Anything, 1, 10, 0).Return(comments, nil)
+	mockCommentUsecase.On("GetTotalCommentsCount", mock.Anything, 1).Return(2, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "alice"}, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/posts/1/comments", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+
+	commentHandler.GetComments(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Comments []map[string]interface{} `json:"comments"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Comments, 2)
+	assert.Equal(t, "alice", response.Comments[0]["username"])
+	assert.Equal(t, "alice", response.Comments[1]["username"])
+
+	mockCommentUsecase.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
+}
+
+func TestCommentHandler_GetComments_InvalidPostID(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
+
+	req, _ := http.NewRequest("GET", "/posts/invalid/comments", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "invalid"}}
+
+	commentHandler.GetComments(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid post ID")
+}
+
+func TestCommentHandler_GetComments_FailedToGetComments(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
+
+	mockCommentUsecase.On("GetComments", mock.Anything, 1, 10, 0).Return(nil, errors.New("failed to get comments"))
+
+	req, _ := http.NewRequest("GET", "/posts/1/comments", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+
+	commentHandler.GetComments(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "failed to get comments")
+
+	mockCommentUsecase.AssertExpectations(t)
+}
+
+func TestCommentHandler_CreateComment_MaxDepthExceeded(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
+
+	parentID := 7
+	commentJSON, _ := json.Marshal(entity.Comment{ParentId: &parentID, Content: "Too deep"})
+
+	mockCommentUsecase.On("CreateComment", mock.Anything, mock.Anything).Return(entity.Comment{}, usecase.ErrMaxDepthExceeded)
 
 	req, _ := http.NewRequest("POST", "/posts/1/comments", bytes.NewBuffer(commentJSON))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer invalid.jwt.token")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
 	commentHandler.CreateComment(c)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid token or user ID")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), usecase.ErrMaxDepthExceeded.Error())
+
+	mockCommentUsecase.AssertExpectations(t)
 }
 
-func TestCommentHandler_CreateComment_FailedToCreateComment(t *testing.T) {
+func TestCommentHandler_GetComments_Tree(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
+
+	parentID := 1
+	tree := []*entity.CommentNode{
+		{
+			Comment:    entity.Comment{ID: 1, PostId: 1, AuthorId: 1, Content: "Root"},
+			ReplyCount: 1,
+			Replies: []*entity.CommentNode{
+				{
+					Comment: entity.Comment{ID: 2, PostId: 1, AuthorId: 2, ParentId: &parentID, Depth: 1, Content: "Reply"},
+					Replies: []*entity.CommentNode{},
+				},
+			},
+		},
+	}
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
+	mockCommentUsecase.On("GetCommentTree", mock.Anything, 1, 10, 0, 2).Return(tree, nil)
+	mockCommentUsecase.On("GetTopLevelCommentsCount", mock.Anything, 1).Return(1, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1, 2}).Return(map[int]string{1: "alice", 2: "bob"}, nil).Once()
 
-	token, err := jwtUtil.GenerateToken(1, "user")
+	req, _ := http.NewRequest("GET", "/posts/1/comments?tree=true&depth=2", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+
+	commentHandler.GetComments(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Comments []entity.CommentNode `json:"comments"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
+	assert.Len(t, response.Comments, 1)
+	assert.Equal(t, "alice", response.Comments[0].Username)
+	assert.Equal(t, 1, response.Comments[0].ReplyCount)
+	assert.Equal(t, "bob", response.Comments[0].Replies[0].Username)
 
-	comment := entity.Comment{
-		Content: "This is a test comment",
+	mockCommentUsecase.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
+}
+
+func TestCommentHandler_GetReplies_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
+
+	parentID := 1
+	replies := []*entity.CommentNode{
+		{Comment: entity.Comment{ID: 2, PostId: 1, AuthorId: 2, ParentId: &parentID, Depth: 1, Content: "Reply"}, Replies: []*entity.CommentNode{}},
 	}
-	commentJSON, _ := json.Marshal(comment)
 
-	mockCommentUsecase.On("CreateComment", mock.Anything, mock.Anything).Return(entity.Comment{}, errors.New("failed to create comment"))
+	mockCommentUsecase.On("GetReplies", mock.Anything, 1, 10, 0, 0).Return(replies, nil)
+	mockCommentUsecase.On("GetRepliesCount", mock.Anything, 1).Return(1, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{2}).Return(map[int]string{2: "bob"}, nil).Once()
 
-	req, _ := http.NewRequest("POST", "/posts/1/comments", bytes.NewBuffer(commentJSON))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	req, _ := http.NewRequest("GET", "/comments/1/replies", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
 
-	commentHandler.CreateComment(c)
+	commentHandler.GetReplies(c)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "failed to create comment")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"username":"bob"`)
+	assert.Contains(t, w.Body.String(), `"total":1`)
 
 	mockCommentUsecase.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
 }
 
-func TestCommentHandler_GetCommentsByPostID_Success(t *testing.T) {
+func TestCommentHandler_GetReplies_NotFound(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
 
-	comments := []entity.Comment{
-		{ID: 1, PostId: 1, Content: "Comment 1"},
-		{ID: 2, PostId: 1, Content: "Comment 2"},
-	}
+	mockCommentUsecase.On("GetReplies", mock.Anything, 42, 10, 0, 0).Return(nil, usecase.ErrCommentNotFound)
 
-	mockCommentUsecase.On("GetCommentByPostID", mock.Anything, 1).Return(comments, nil)
+	req, _ := http.NewRequest("GET", "/comments/42/replies", nil)
 
-	req, _ := http.NewRequest("GET", "/posts/1/comments", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "42"}}
+
+	commentHandler.GetReplies(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "comment not found")
+
+	mockCommentUsecase.AssertExpectations(t)
+}
+
+func TestCommentHandler_UpdateComment_Author(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
+
+	editedAt := time.Now()
+	existing := &entity.Comment{ID: 3, PostId: 1, AuthorId: 1, Content: "Typo"}
+	updated := &entity.Comment{ID: 3, PostId: 1, AuthorId: 1, Content: "Fixed", EditedAt: &editedAt}
+
+	mockCommentUsecase.On("GetCommentByID", mock.Anything, 3).Return(existing, nil)
+	mockCommentUsecase.On("UpdateComment", mock.Anything, 3, "Fixed").Return(updated, nil)
+
+	body, _ := json.Marshal(entity.UpdateCommentRequest{Content: "  Fixed "})
+	req, _ := http.NewRequest("PUT", "/comments/3", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
-	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
-	commentHandler.GetCommentsByPostID(c)
+	commentHandler.UpdateComment(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var responseComments []entity.Comment
-	err := json.Unmarshal(w.Body.Bytes(), &responseComments)
-	assert.NoError(t, err)
-	assert.Equal(t, comments, responseComments)
+	assert.Contains(t, w.Body.String(), `"content":"Fixed"`)
+	assert.Contains(t, w.Body.String(), `"edited_at"`)
 
 	mockCommentUsecase.AssertExpectations(t)
 }
 
-func TestCommentHandler_GetCommentsByPostID_InvalidPostID(t *testing.T) {
+func TestCommentHandler_UpdateComment_Forbidden(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
 
-	req, _ := http.NewRequest("GET", "/posts/invalid/comments", nil)
+	mockCommentUsecase.On("GetCommentByID", mock.Anything, 3).Return(&entity.Comment{ID: 3, AuthorId: 2}, nil)
+
+	body, _ := json.Marshal(entity.UpdateCommentRequest{Content: "Not mine"})
+	req, _ := http.NewRequest("PUT", "/comments/3", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
-	c.Params = gin.Params{gin.Param{Key: "id", Value: "invalid"}}
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
-	commentHandler.GetCommentsByPostID(c)
+	commentHandler.UpdateComment(c)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid post ID")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockCommentUsecase.AssertNotCalled(t, "UpdateComment", mock.Anything, mock.Anything, mock.Anything)
+	mockCommentUsecase.AssertExpectations(t)
 }
 
-func TestCommentHandler_GetCommentsByPostID_FailedToGetComments(t *testing.T) {
+func TestCommentHandler_UpdateComment_Deleted(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentUsecase := new(mocks.CommentsUsecases)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	commentHandler := NewCommentHandler(mockCommentUsecase, jwtUtil, logger)
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
 
-	mockCommentUsecase.On("GetCommentByPostID", mock.Anything, 1).Return(nil, errors.New("failed to get comments"))
+	deletedAt := time.Now()
+	mockCommentUsecase.On("GetCommentByID", mock.Anything, 3).Return(&entity.Comment{ID: 3, AuthorId: 1, DeletedAt: &deletedAt}, nil)
 
-	req, _ := http.NewRequest("GET", "/posts/1/comments", nil)
+	body, _ := json.Marshal(entity.UpdateCommentRequest{Content: "Too late"})
+	req, _ := http.NewRequest("PUT", "/comments/3", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
-	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
-	commentHandler.GetCommentsByPostID(c)
+	commentHandler.UpdateComment(c)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "failed to get comments")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockCommentUsecase.AssertExpectations(t)
+}
+
+func TestCommentHandler_DeleteComment_Moderator(t *testing.T) {
 
+	logger, _ := zap.NewProduction()
+
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
+
+	mockCommentUsecase.On("GetCommentByID", mock.Anything, 3).Return(&entity.Comment{ID: 3, AuthorId: 2}, nil)
+	mockCommentUsecase.On("DeleteComment", mock.Anything, 3).Return(nil)
+
+	req, _ := http.NewRequest("DELETE", "/comments/3", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "moderator", Permissions: []string{authz.CommentModerate}})
+
+	commentHandler.DeleteComment(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
 	mockCommentUsecase.AssertExpectations(t)
 }
+
+func TestCommentHandler_DeleteComment_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService)
+
+	mockCommentUsecase.On("GetCommentByID", mock.Anything, 3).Return(nil, usecase.ErrCommentNotFound)
+
+	req, _ := http.NewRequest("DELETE", "/comments/3", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
+
+	commentHandler.DeleteComment(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockCommentUsecase.AssertExpectations(t)
+}
+
+func TestCommentHandler_GetComments_TreeWithMyVotes(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockVoteUsecase := new(mocks.VoteUsecase)
+	mockUserService := new(mocks.UserService)
+
+	commentHandler := NewCommentHandler(mockCommentUsecase, mockVoteUsecase, emptyReactions(), logger, mockUserService)
+
+	parentID := 1
+	tree := []*entity.CommentNode{
+		{
+			Comment:    entity.Comment{ID: 1, PostId: 1, AuthorId: 1, Content: "Root", Score: 2},
+			ReplyCount: 1,
+			Replies: []*entity.CommentNode{
+				{
+					Comment: entity.Comment{ID: 2, PostId: 1, AuthorId: 1, ParentId: &parentID, Depth: 1, Content: "Reply"},
+					Replies: []*entity.CommentNode{},
+				},
+			},
+		},
+	}
+
+	mockCommentUsecase.On("GetCommentTree", mock.Anything, 1, 10, 0, 1).Return(tree, nil)
+	mockCommentUsecase.On("GetTopLevelCommentsCount", mock.Anything, 1).Return(1, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "alice"}, nil)
+	mockVoteUsecase.On("GetUserVotes", mock.Anything, 7, entity.VoteTargetComment, []int{1, 2}).Return(map[int]int{2: -1}, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/posts/1/comments?tree=true&depth=1", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	commentHandler.GetComments(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Comments []entity.CommentNode `json:"comments"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Comments, 1) {
+		root := response.Comments[0]
+		assert.Equal(t, 2, root.Score)
+		if assert.NotNil(t, root.MyVote) {
+			assert.Equal(t, 0, *root.MyVote)
+		}
+		if assert.NotNil(t, root.Replies[0].MyVote) {
+			assert.Equal(t, -1, *root.Replies[0].MyVote)
+		}
+	}
+
+	mockVoteUsecase.AssertExpectations(t)
+}
+
+func TestCommentHandler_VoteComment_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockVoteUsecase := new(mocks.VoteUsecase)
+
+	commentHandler := NewCommentHandler(new(mocks.CommentsUsecases), mockVoteUsecase, emptyReactions(), logger, new(mocks.UserService))
+
+	vote := entity.Vote{UserID: 7, TargetType: entity.VoteTargetComment, TargetID: 3, Value: 1}
+	mockVoteUsecase.On("Vote", mock.Anything, vote).
+		Return(&entity.VoteResult{TargetType: entity.VoteTargetComment, TargetID: 3, Score: 1, MyVote: 1}, nil)
+
+	req, _ := http.NewRequest("PUT", "/comments/3/vote", bytes.NewBufferString(`{"value": 1}`))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	commentHandler.VoteComment(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"score":1`)
+
+	mockVoteUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/chat"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DirectMessageHandler struct {
+	hub        *chat.Hub
+	dmUC       usecase.DirectMessageUsecase
+	logger     *zap.Logger
+	userClient UserService
+}
+
+func NewDirectMessageHandler(hub *chat.Hub, dmUC usecase.DirectMessageUsecase, logger *zap.Logger, userClient UserService) *DirectMessageHandler {
+	return &DirectMessageHandler{hub: hub, dmUC: dmUC, logger: logger, userClient: userClient}
+}
+
+// GetConversations godoc
+// @Summary Список личных переписок
+// @Description Переписки текущего пользователя, начиная с последней, с последним сообщением и числом непрочитанных. unread_total - непрочитанные во всех переписках
+// @Tags Личные сообщения
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "conversations and unread_total"
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /dm/conversations [get]
+func (h *DirectMessageHandler) GetConversations(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return
+	}
+
+	conversations, err := h.dmUC.GetConversations(c.Request.Context(), principal.UserID)
+	if err != nil {
+		h.logger.Error("Failed to get conversations", zap.Error(err), zap.Int("userID", principal.UserID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	peerIDs := make([]int, len(conversations))
+	for i, conversation := range conversations {
+		peerIDs[i] = conversation.UserID
+	}
+	usernames := lookupUsernames(c.Request.Context(), h.userClient, h.logger, peerIDs)
+
+	unreadTotal := 0
+	for i := range conversations {
+		conversations[i].Username = usernames[conversations[i].UserID]
+		unreadTotal += conversations[i].UnreadCount
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conversations": conversations,
+		"unread_total":  unreadTotal,
+	})
+}
+
+// GetMessages godoc
+// @Summary Личная переписка с пользователем
+// @Description Страница сообщений между текущим пользователем и userID в хронологическом порядке. next_cursor указывает на более ранние сообщения, null - переписка закончилась
+// @Tags Личные сообщения
+// @Produce json
+// @Security BearerAuth
+// @Param userID path int true "ID собеседника"
+// @Param cursor query string false "next_cursor из предыдущего ответа, пусто - последние сообщения"
+// @Param limit query int false "Сообщений на странице" default(50)
+// @Success 200 {object} map[string]interface{} "messages and next_cursor"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /dm/{userID}/messages [get]
+func (h *DirectMessageHandler) GetMessages(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return
+	}
+	peerID, ok := h.peerParam(c)
+	if !ok {
+		return
+	}
+	before, _, err := cursorQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	if limit > usecase.MaxChatHistoryLimit {
+		limit = usecase.MaxChatHistoryLimit
+	}
+
+	messages, err := h.dmUC.GetMessages(c.Request.Context(), principal.UserID, peerID, before, limit+1)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	// Сообщения идут от старых к новым, лишнее самое старое означает, что переписка продолжается
+	var next *string
+	if len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+		next = nextCursor(messages[0].CreatedAt, messages[0].ID)
+	}
+	if messages == nil {
+		messages = []entity.DirectMessage{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages":    messages,
+		"next_cursor": next,
+	})
+}
+
+// MarkRead godoc
+// @Summary Отметить переписку прочитанной
+// @Description Отмечает прочитанными сообщения от userID к текущему пользователю. Клиенты userID получают кадр entity.DirectReadEvent
+// @Tags Личные сообщения
+// @Produce json
+// @Security BearerAuth
+// @Param userID path int true "ID собеседника"
+// @Success 200 {object} map[string]interface{} "marked - число отмеченных сообщений"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /dm/{userID}/read [post]
+func (h *DirectMessageHandler) MarkRead(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return
+	}
+	peerID, ok := h.peerParam(c)
+	if !ok {
+		return
+	}
+
+	marked, err := h.dmUC.MarkRead(c.Request.Context(), principal.UserID, peerID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	if marked > 0 {
+		event := entity.DirectReadEvent{V: entity.ChatProtocolVersion, Type: entity.FrameDirectRead, UserID: principal.UserID, Count: marked}
+		if err := h.hub.PublishToUsers(event, peerID); err != nil {
+			h.logger.Error("Failed to publish read event", zap.Int("userID", principal.UserID), zap.Error(err))
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// peerParam разбирает ID собеседника из :userID, при ошибке ответ уже записан
+func (h *DirectMessageHandler) peerParam(c *gin.Context) (int, bool) {
+	peerID, err := strconv.Atoi(c.Param("userID"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+	return peerID, true
+}
+
+func (h *DirectMessageHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrInvalidRecipient) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.logger.Error("Direct message request failed", zap.Error(err))
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Direct message request failed"})
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/chat"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestDirectMessageHandler_GetConversations(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDMUsecase := new(mocks.DirectMessageUsecase)
+	mockUserService := new(mocks.UserService)
+
+	dmHandler := NewDirectMessageHandler(chat.NewHub(), mockDMUsecase, logger, mockUserService)
+
+	mockDMUsecase.On("GetConversations", mock.Anything, 7).Return([]entity.Conversation{
+		{UserID: 3, LastMessage: entity.DirectMessage{ID: 9, SenderID: 3, RecipientID: 7, Content: "hi"}, UnreadCount: 2},
+		{UserID: 4, LastMessage: entity.DirectMessage{ID: 5, SenderID: 7, RecipientID: 4, Content: "bye"}},
+	}, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{3, 4}).Return(map[int]string{3: "alice", 4: "bob"}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/dm/conversations", nil)
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	dmHandler.GetConversations(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Conversations []entity.Conversation `json:"conversations"`
+		UnreadTotal   int                   `json:"unread_total"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.UnreadTotal)
+	if assert.Len(t, response.Conversations, 2) {
+		assert.Equal(t, "alice", response.Conversations[0].Username)
+		assert.Equal(t, "bob", response.Conversations[1].Username)
+	}
+
+	mockDMUsecase.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
+}
+
+func TestDirectMessageHandler_GetMessages_InvalidPeer(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDMUsecase := new(mocks.DirectMessageUsecase)
+
+	dmHandler := NewDirectMessageHandler(chat.NewHub(), mockDMUsecase, logger, new(mocks.UserService))
+
+	mockDMUsecase.On("GetMessages", mock.Anything, 7, 7, (*entity.Cursor)(nil), 51).Return(nil, usecase.ErrInvalidRecipient)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/dm/7/messages", nil)
+	c.Params = gin.Params{gin.Param{Key: "userID", Value: "7"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	dmHandler.GetMessages(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockDMUsecase.AssertExpectations(t)
+}
+
+func TestDirectMessageHandler_MarkRead_NotifiesSender(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDMUsecase := new(mocks.DirectMessageUsecase)
+	hub := chat.NewHub()
+
+	dmHandler := NewDirectMessageHandler(hub, mockDMUsecase, logger, new(mocks.UserService))
+
+	mockDMUsecase.On("MarkRead", mock.Anything, 7, 3).Return(2, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/dm/3/read", nil)
+	c.Params = gin.Params{gin.Param{Key: "userID", Value: "3"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	dmHandler.MarkRead(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"marked": 2}`, w.Body.String())
+
+	message := <-hub.Direct
+	assert.Equal(t, []int{3}, message.UserIDs)
+	assert.JSONEq(t, `{"v": 1, "type": "dm_read", "user_id": 7, "count": 2}`, string(message.Data))
+
+	mockDMUsecase.AssertExpectations(t)
+}
@@ -1,8 +1,11 @@
 package http
 
 import (
-	utils "github.com/Engls/EnglsJwt"
-	"github.com/Engls/forum-project2/forum_service/internal/controllers/grpc"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Engls/forum-project2/forum_service/internal/authz"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/repository"
 	"github.com/Engls/forum-project2/forum_service/internal/usecase"
@@ -10,36 +13,106 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
-	"strings"
 )
 
+// UserService - данные пользователей из auth_service
+type UserService interface {
+	GetUsernames(ctx context.Context, userIDs []int) (map[int]string, error)
+}
+
+// lookupUsernames получает имена авторов одним запросом. При ошибке имена остаются пустыми.
+func lookupUsernames(ctx context.Context, userClient UserService, logger *zap.Logger, authorIDs []int) map[int]string {
+	seen := make(map[int]bool, len(authorIDs))
+	uniqueIDs := make([]int, 0, len(authorIDs))
+	for _, id := range authorIDs {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+	if len(uniqueIDs) == 0 {
+		return map[int]string{}
+	}
+	usernames, err := userClient.GetUsernames(ctx, uniqueIDs)
+	if err != nil {
+		logger.Warn("Failed to get usernames", zap.Ints("userIDs", uniqueIDs), zap.Error(err))
+		return map[int]string{}
+	}
+	return usernames
+}
+
+// AuthorService - карточки авторов из auth_service
+type AuthorService interface {
+	GetAuthorCards(ctx context.Context, userIDs []int) (map[int]entity.AuthorCard, error)
+}
+
+// lookupAuthorCards получает карточки авторов одним запросом. Без сервиса или при ошибке карточек нет.
+func lookupAuthorCards(ctx context.Context, authorService AuthorService, logger *zap.Logger, authorIDs []int) map[int]entity.AuthorCard {
+	if authorService == nil || len(authorIDs) == 0 {
+		return map[int]entity.AuthorCard{}
+	}
+	seen := make(map[int]bool, len(authorIDs))
+	uniqueIDs := make([]int, 0, len(authorIDs))
+	for _, id := range authorIDs {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+	cards, err := authorService.GetAuthorCards(ctx, uniqueIDs)
+	if err != nil {
+		logger.Warn("Failed to get author cards", zap.Ints("userIDs", uniqueIDs), zap.Error(err))
+		return map[int]entity.AuthorCard{}
+	}
+	return cards
+}
+
+// attachAuthorCards добавляет карточку автора (author) к элементам списка, для которых она нашлась
+func attachAuthorCards(items []map[string]interface{}, cards map[int]entity.AuthorCard) {
+	for _, item := range items {
+		authorID, _ := item["author_id"].(int)
+		if card, ok := cards[authorID]; ok {
+			item["author"] = card
+		}
+	}
+}
+
 type PostHandler struct {
-	postUsecase usecase.PostUsecase
-	postRepo    repository.PostRepository
-	jwtUtil     *utils.JWTUtil
-	logger      *zap.Logger
-	userClient  *grpc.UserClient
+	postUsecase     usecase.PostUsecase
+	postRepo        repository.PostRepository
+	commentUsecase  usecase.CommentsUsecases
+	voteUsecase     usecase.VoteUsecase
+	reactionUsecase usecase.ReactionUsecase
+	logger          *zap.Logger
+	userClient      UserService
+	authorCards     AuthorService
 }
 
 func NewPostHandler(
 	postUsecase usecase.PostUsecase,
 	postRepo repository.PostRepository,
-	jwtUtil *utils.JWTUtil,
+	commentUsecase usecase.CommentsUsecases,
+	voteUsecase usecase.VoteUsecase,
+	reactionUsecase usecase.ReactionUsecase,
 	logger *zap.Logger,
-	userClient *grpc.UserClient,
+	userClient UserService,
+	authorCards AuthorService,
 ) *PostHandler {
 	return &PostHandler{
-		postUsecase: postUsecase,
-		postRepo:    postRepo,
-		jwtUtil:     jwtUtil,
-		logger:      logger,
-		userClient:  userClient,
+		postUsecase:     postUsecase,
+		postRepo:        postRepo,
+		commentUsecase:  commentUsecase,
+		voteUsecase:     voteUsecase,
+		reactionUsecase: reactionUsecase,
+		logger:          logger,
+		userClient:      userClient,
+		authorCards:     authorCards,
 	}
 }
 
 // CreatePost godoc
 // @Summary Создать новый пост
-// @Description Создает новый пост в системе
+// @Description Создает новый пост в системе. Если указан category_id, категория должна существовать
 // @Tags Посты
 // @Accept json
 // @Produce json
@@ -51,26 +124,13 @@ func NewPostHandler(
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /posts [post]
 func (h *PostHandler) CreatePost(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		h.logger.Warn("Authorization header required")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-		return
-	}
-
-	tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-	if tokenString == authHeader {
-		h.logger.Warn("Invalid Authorization header format", zap.String("header", authHeader))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format"})
-		return
-	}
-
-	userID, err := h.postRepo.GetUserIDByToken(c.Request.Context(), tokenString)
-	if err != nil {
-		h.logger.Warn("Invalid token", zap.Error(err))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		h.logger.Warn("Principal not found in context")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
 		return
 	}
+	userID := principal.UserID
 
 	var post entity.Post
 	if err := c.BindJSON(&post); err != nil {
@@ -84,6 +144,10 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 	h.logger.Info("Creating post", zap.Any("post", post))
 	createdPost, err := h.postUsecase.CreatePost(c.Request.Context(), post)
 	if err != nil {
+		if errors.Is(err, usecase.ErrCategoryNotFound) || errors.Is(err, usecase.ErrInvalidTag) || errors.Is(err, usecase.ErrTooManyTags) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to create post", zap.Error(err))
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -95,64 +159,351 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 // GetPosts returns paginated list of posts with usernames
 // @Summary Получить посты
-// @Description Получить посты с юзернеймами
+// @Description Получить посты с юзернеймами, карточками авторов (author), рейтингом и реакциями, для аутентифицированного пользователя - с его голосом (my_vote). Параметр tag можно повторять: tag_mode=or - посты с любым из тегов, tag_mode=and - со всеми. Курсор работает только с sort=new и sort=old
 // @Tags Посты
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Success 200 {object} map[string]interface{} "posts with usernames and total count"
+// @Param sort query string false "Порядок: new, old, top (рейтинг), active (последний комментарий или создание), commented (число комментариев)" Enums(new, old, top, active, commented) default(new)
+// @Param author_id query int false "Только посты этого автора"
+// @Param since query string false "Созданы не раньше (YYYY-MM-DD или RFC3339)"
+// @Param until query string false "Созданы раньше (RFC3339) или в этот день (YYYY-MM-DD)"
+// @Param tag query []string false "Фильтр по тегу" collectionFormat(multi)
+// @Param tag_mode query string false "Совпадение тегов: or, and" Enums(or, and) default(or)
+// @Param cursor query string false "Курсор: next_cursor из предыдущего ответа, пусто - первая страница. Заменяет page"
+// @Success 200 {object} map[string]interface{} "posts with usernames, total count and next_cursor"
+// @Failure 400 {object} entity.ErrorResponse
 // @Router /posts [get]
 func (h *PostHandler) GetPosts(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset := (page - 1) * limit
+	filter, err := postFilterQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	after, cursorMode, err := cursorQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if cursorMode && !filter.SortedByTime() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": usecase.ErrCursorNotSupported.Error()})
+		return
+	}
+
+	var page, limit, offset, fetch int
+	if cursorMode {
+		// Запрашиваем на один пост больше, чтобы понять, есть ли следующая страница
+		filter.After = after
+		limit = cursorLimit(c)
+		fetch = limit + 1
+	} else {
+		page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ = strconv.Atoi(c.DefaultQuery("limit", "10"))
+		offset = (page - 1) * limit
+		fetch = limit
+	}
 
-	// Получаем посты с пагинацией
-	posts, err := h.postUsecase.GetPosts(c.Request.Context(), limit, offset)
+	// Получаем посты с пагинацией и общее количество постов
+	posts, err := h.postUsecase.ListPosts(c.Request.Context(), filter, fetch, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondPostListError(c, err)
+		return
+	}
+	total, err := h.postUsecase.CountPosts(c.Request.Context(), filter)
+	if err != nil {
+		h.respondPostListError(c, err)
+		return
+	}
+	h.respondPostList(c, filter, posts, total, page, limit, offset, cursorMode)
+}
+
+func (h *PostHandler) respondPostListError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrInvalidSort) || errors.Is(err, usecase.ErrCursorNotSupported) ||
+		errors.Is(err, usecase.ErrInvalidDateRange) || errors.Is(err, usecase.ErrInvalidTag) || errors.Is(err, usecase.ErrTooManyTags) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func (h *PostHandler) respondPostList(c *gin.Context, filter entity.PostFilter, posts []entity.Post, total, page, limit, offset int, cursorMode bool) {
+	// next_cursor позволяет перейти с page на курсоры с любой страницы ленты по времени
+	hasMore := offset+len(posts) < total
+	if cursorMode {
+		hasMore = len(posts) > limit
+		if hasMore {
+			posts = posts[:limit]
+		}
+	}
+	var next *string
+	if filter.SortedByTime() && hasMore && len(posts) > 0 {
+		last := posts[len(posts)-1]
+		next = nextCursor(last.CreatedAt, last.ID)
+	}
+
+	// Добавляем имена пользователей, голоса и реакции к постам одним запросом на страницу
+	authorIDs := make([]int, len(posts))
+	postIDs := make([]int, len(posts))
+	for i, post := range posts {
+		authorIDs[i] = post.AuthorId
+		postIDs[i] = post.ID
+	}
+	usernames := lookupUsernames(c.Request.Context(), h.userClient, h.logger, authorIDs)
+	myVotes := lookupMyVotes(c, h.voteUsecase, h.logger, entity.VoteTargetPost, postIDs)
+	reactions := lookupReactions(c, h.reactionUsecase, h.logger, entity.ReactionTargetPost, postIDs)
+	items := postListItems(posts, usernames, myVotes, reactions)
+	attachAuthorCards(items, lookupAuthorCards(c.Request.Context(), h.authorCards, h.logger, authorIDs))
+
+	response := gin.H{
+		"posts":       items,
+		"total":       total,
+		"limit":       limit,
+		"next_cursor": next,
+	}
+	if !cursorMode {
+		response["page"] = page
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+var errInvalidTagMode = errors.New("tag_mode must be 'or' or 'and'")
+
+// postFilterQuery читает фильтры ленты из query. Сортировка и теги проверяются в usecase
+func postFilterQuery(c *gin.Context) (entity.PostFilter, error) {
+	filter := entity.PostFilter{Sort: c.Query("sort")}
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		filter.Tags = tags
+	}
+
+	switch c.DefaultQuery("tag_mode", "or") {
+	case "or":
+	case "and":
+		filter.MatchAllTags = true
+	default:
+		return filter, errInvalidTagMode
+	}
 
-	// Получаем общее количество постов
-	total, err := h.postUsecase.GetTotalPostsCount(c.Request.Context())
+	if authorID := c.Query("author_id"); authorID != "" {
+		id, err := strconv.Atoi(authorID)
+		if err != nil || id <= 0 {
+			return filter, errors.New("Invalid author ID")
+		}
+		filter.AuthorID = id
+	}
+
+	var err error
+	if filter.Since, err = parseDateQuery(c.Query("since"), false); err != nil {
+		return filter, errors.New("Invalid since date")
+	}
+	if filter.Until, err = parseDateQuery(c.Query("until"), true); err != nil {
+		return filter, errors.New("Invalid until date")
+	}
+	return filter, nil
+}
+
+// GetTags godoc
+// @Summary Список тегов
+// @Description Используемые теги с числом постов, самые популярные первыми
+// @Tags Посты
+// @Produce json
+// @Param limit query int false "Наибольшее число тегов" default(100)
+// @Success 200 {array} entity.TagCount
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /tags [get]
+func (h *PostHandler) GetTags(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 {
+		limit = 100
+	}
+
+	tags, err := h.postUsecase.GetTags(c.Request.Context(), limit)
 	if err != nil {
+		h.logger.Error("Failed to get tags", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, tags)
+}
 
-	// Добавляем имена пользователей к постам
-	postsWithUsernames := make([]map[string]interface{}, len(posts))
+// postListItems - посты для списка с именами авторов. my_vote и reactions добавляются,
+// только если myVotes и reactions не nil
+func postListItems(posts []entity.Post, usernames map[int]string, myVotes map[int]int, reactions map[int][]entity.ReactionCount) []map[string]interface{} {
+	items := make([]map[string]interface{}, len(posts))
 	for i, post := range posts {
-		username, err := h.userClient.GetUsername(c.Request.Context(), post.AuthorId)
-		if err != nil {
-			h.logger.Warn("Failed to get username",
-				zap.Int("userID", post.AuthorId),
-				zap.Error(err))
-			username = "" // Используем пустое имя, если не удалось получить
+		items[i] = map[string]interface{}{
+			"id":              post.ID,
+			"title":           post.Title,
+			"content":         post.Content,
+			"author_id":       post.AuthorId,
+			"username":        usernames[post.AuthorId],
+			"category_id":     post.CategoryId,
+			"tags":            post.Tags,
+			"score":           post.Score,
+			"comment_count":   post.CommentCount,
+			"last_comment_at": post.LastCommentAt,
+			"created_at":      post.CreatedAt,
+			"updated_at":      post.UpdatedAt,
 		}
-
-		postsWithUsernames[i] = map[string]interface{}{
-			"id":        post.ID,
-			"title":     post.Title,
-			"content":   post.Content,
-			"author_id": post.AuthorId,
-			"username":  username, // Добавляем имя пользователя
+		if myVotes != nil {
+			items[i]["my_vote"] = myVotes[post.ID]
 		}
+		if reactions != nil {
+			items[i]["reactions"] = reactionsOf(reactions, post.ID)
+		}
+	}
+	return items
+}
+
+// GetPost godoc
+// @Summary Получить пост
+// @Description Возвращает пост с именем и карточкой автора (author), рейтингом, реакциями, числом комментариев и первой страницей комментариев. Для аутентифицированного пользователя добавляется его голос (my_vote)
+// @Tags Посты
+// @Produce json
+// @Param post_id path int true "ID поста"
+// @Param comments_limit query int false "Комментариев на первой странице (не больше 100)" default(10)
+// @Success 200 {object} map[string]interface{} "post, comment_count and first page of comments"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /posts/{post_id} [get]
+func (h *PostHandler) GetPost(c *gin.Context) {
+	postIDStr := c.Param("post_id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Warn("Invalid post ID", zap.String("postID", postIDStr), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+	limit := queryLimit(c, "comments_limit")
+
+	post, err := h.postUsecase.GetPostByID(c.Request.Context(), postID)
+	if err != nil {
+		h.respondPostLookupError(c, postID, err)
+		return
 	}
 
+	commentCount, err := h.commentUsecase.GetTotalCommentsCount(c.Request.Context(), postID)
+	if err != nil {
+		h.logger.Error("Failed to count comments", zap.Int("postID", postID), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get comments"})
+		return
+	}
+	comments, err := h.commentUsecase.GetComments(c.Request.Context(), postID, limit, 0)
+	if err != nil {
+		h.logger.Error("Failed to get comments", zap.Int("postID", postID), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get comments"})
+		return
+	}
+
+	authorIDs := []int{post.AuthorId}
+	commentIDs := make([]int, len(comments))
+	for i, comment := range comments {
+		authorIDs = append(authorIDs, comment.AuthorId)
+		commentIDs[i] = comment.ID
+	}
+	usernames := lookupUsernames(c.Request.Context(), h.userClient, h.logger, authorIDs)
+	authorCards := lookupAuthorCards(c.Request.Context(), h.authorCards, h.logger, authorIDs)
+
+	postReactions := lookupReactions(c, h.reactionUsecase, h.logger, entity.ReactionTargetPost, []int{post.ID})
+	postItem := gin.H{
+		"id":          post.ID,
+		"title":       post.Title,
+		"content":     post.Content,
+		"author_id":   post.AuthorId,
+		"username":    usernames[post.AuthorId],
+		"category_id": post.CategoryId,
+		"tags":        post.Tags,
+		"score":       post.Score,
+		"reactions":   reactionsOf(postReactions, post.ID),
+		"created_at":  post.CreatedAt,
+		"updated_at":  post.UpdatedAt,
+	}
+	if card, ok := authorCards[post.AuthorId]; ok {
+		postItem["author"] = card
+	}
+	if myVotes := lookupMyVotes(c, h.voteUsecase, h.logger, entity.VoteTargetPost, []int{post.ID}); myVotes != nil {
+		postItem["my_vote"] = myVotes[post.ID]
+	}
+	commentVotes := lookupMyVotes(c, h.voteUsecase, h.logger, entity.VoteTargetComment, commentIDs)
+	commentReactions := lookupReactions(c, h.reactionUsecase, h.logger, entity.ReactionTargetComment, commentIDs)
+	commentItems := commentListItems(comments, usernames, commentVotes, commentReactions)
+	attachAuthorCards(commentItems, authorCards)
+
 	c.JSON(http.StatusOK, gin.H{
-		"posts": postsWithUsernames,
-		"total": total,
-		"page":  page,
-		"limit": limit,
+		"post":          postItem,
+		"comment_count": commentCount,
+		"comments":      commentItems,
+		"comments_pagination": gin.H{
+			"page":  1,
+			"limit": limit,
+			"total": commentCount,
+		},
 	})
 }
 
+// VotePost godoc
+// @Summary Проголосовать за пост
+// @Description Ставит голос 1 или -1, повторный запрос меняет голос, value=0 снимает его. Возвращает новый рейтинг поста (требуется право vote)
+// @Tags Посты
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID поста"
+// @Param vote body entity.VoteRequest true "Голос"
+// @Success 200 {object} entity.VoteResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /posts/{id}/vote [put]
+func (h *PostHandler) VotePost(c *gin.Context) {
+	castVote(c, h.voteUsecase, h.logger, entity.VoteTargetPost)
+}
+
+// AddReaction godoc
+// @Summary Поставить реакцию на пост
+// @Description Ставит реакцию эмодзи из разрешенного набора, повторный запрос ничего не меняет. Возвращает реакции на пост (требуется право react)
+// @Tags Посты
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID поста"
+// @Param emoji path string true "Эмодзи"
+// @Success 200 {object} entity.ReactionResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /posts/{id}/reactions/{emoji} [put]
+func (h *PostHandler) AddReaction(c *gin.Context) {
+	setReaction(c, h.reactionUsecase, h.logger, entity.ReactionTargetPost, true)
+}
+
+// RemoveReaction godoc
+// @Summary Снять реакцию с поста
+// @Description Снимает реакцию текущего пользователя. Возвращает реакции на пост (требуется право react)
+// @Tags Посты
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID поста"
+// @Param emoji path string true "Эмодзи"
+// @Success 200 {object} entity.ReactionResult
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /posts/{id}/reactions/{emoji} [delete]
+func (h *PostHandler) RemoveReaction(c *gin.Context) {
+	setReaction(c, h.reactionUsecase, h.logger, entity.ReactionTargetPost, false)
+}
+
 // DeletePost godoc
 // @Summary Удалить пост
-// @Description Удаляет пост по ID (доступно автору или администратору)
+// @Description Удаляет пост по ID (доступно автору или пользователю с правом post.delete.any)
 // @Tags Посты
 // @Accept json
 // @Produce json
@@ -166,19 +517,13 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /posts/{id} [delete]
 func (h *PostHandler) DeletePost(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		h.logger.Warn("Authorization header required")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-		return
-	}
-
-	tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-	if tokenString == authHeader {
-		h.logger.Warn("Invalid Authorization header format", zap.String("header", authHeader))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format"})
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		h.logger.Warn("Principal not found in context")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
 		return
 	}
+	userID := principal.UserID
 
 	postIDStr := c.Param("id")
 	postID, err := strconv.Atoi(postIDStr)
@@ -188,25 +533,10 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	userID, err := h.jwtUtil.GetUserIDFromToken(tokenString)
-	if err != nil {
-		h.logger.Warn("Invalid token or user ID", zap.Error(err))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token or user ID"})
-		return
-	}
-
-	userRole, err := h.jwtUtil.GetRoleFromToken(tokenString)
-	if err != nil {
-		h.logger.Warn("Invalid token or user role", zap.Error(err))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token or user role"})
-		return
-	}
-
-	if userRole != "admin" {
+	if !authz.Can(principal.Permissions, authz.PostDeleteAny) {
 		post, err := h.postRepo.GetPostByID(c.Request.Context(), postID)
 		if err != nil {
-			h.logger.Error("Failed to get post", zap.Int("postID", postID), zap.Error(err))
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get post"})
+			h.respondPostLookupError(c, postID, err)
 			return
 		}
 
@@ -234,32 +564,27 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 
 // UpdatePost updates an existing po
 // @Summary Редактировать пост
-// @Description Редактировать пост(если ты админ или владелец поста)
+// @Description Редактировать пост (доступно автору или пользователю с правом post.update.any)
 // @Tags Посты
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Post ID"
-// @Param Authorization header string true "Bearer token"
 // @Param post body entity.Post true "Post data"
 // @Success 200 {object} entity.Post
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
 // @Failure 403 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /posts/{id} [put]
 func (h *PostHandler) UpdatePost(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		h.logger.Warn("Authorization header required")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-		return
-	}
-
-	tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-	if tokenString == authHeader {
-		h.logger.Warn("Invalid Authorization header format", zap.String("header", authHeader))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format"})
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		h.logger.Warn("Principal not found in context")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
 		return
 	}
+	userID := principal.UserID
 
 	postIDStr := c.Param("id")
 	postID, err := strconv.Atoi(postIDStr)
@@ -269,54 +594,206 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	userID, err := h.jwtUtil.GetUserIDFromToken(tokenString)
-	if err != nil {
-		h.logger.Warn("Invalid token or user ID", zap.Error(err))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token or user ID"})
+	var newpost entity.Post
+	if err := c.BindJSON(&newpost); err != nil {
+		h.logger.Error("Failed to bind JSON", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	userRole, err := h.jwtUtil.GetRoleFromToken(tokenString)
+	post, err := h.postRepo.GetPostByID(c.Request.Context(), postID)
 	if err != nil {
-		h.logger.Warn("Invalid token or user role", zap.Error(err))
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token or user role"})
+		h.respondPostLookupError(c, postID, err)
 		return
 	}
-	var newpost entity.Post
-	if err := c.BindJSON(&newpost); err != nil {
-		h.logger.Error("Failed to bind JSON", zap.Error(err))
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+
+	if post.AuthorId != userID && !authz.Can(principal.Permissions, authz.PostUpdateAny) {
+		h.logger.Warn("Unauthorized attempt to update post",
+			zap.Int("userID", userID),
+			zap.Int("postAuthorID", post.AuthorId),
+			zap.Int("postID", postID))
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not authorized to update this post"})
 		return
 	}
-	var updatedpost entity.Post
-	if userRole != "admin" {
-		post, err := h.postRepo.GetPostByID(c.Request.Context(), postID)
-		post.Title = newpost.Title
-		post.Content = newpost.Content
-		if err != nil {
-			h.logger.Error("Failed to get post", zap.Int("postID", postID), zap.Error(err))
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get post"})
-			return
-		}
 
-		if post.AuthorId != userID {
-			h.logger.Warn("Unauthorized attempt to update post",
-				zap.Int("userID", userID),
-				zap.Int("postAuthorID", post.AuthorId),
-				zap.Int("postID", postID))
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not authorized to delete this post"})
+	post.Title = newpost.Title
+	post.Content = newpost.Content
+	// без поля tags в запросе теги поста не меняются
+	if newpost.Tags != nil {
+		post.Tags = newpost.Tags
+	}
+	h.logger.Info("Updating post", zap.Int("postID", postID))
+	updatedpost, err := h.postUsecase.UpdatePost(c.Request.Context(), *post, userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrPostNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
-		h.logger.Info("Deleting post", zap.Int("postID", postID))
-		updatedpost2, err := h.postUsecase.UpdatePost(c.Request.Context(), *post)
-		updatedpost = *updatedpost2
-		if err != nil {
-			h.logger.Error("Failed to delete post", zap.Int("postID", postID), zap.Error(err))
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post"})
+		if errors.Is(err, usecase.ErrInvalidTag) || errors.Is(err, usecase.ErrTooManyTags) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		h.logger.Error("Failed to update post", zap.Int("postID", postID), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
 	}
 
-	h.logger.Info("Post deleted successfully", zap.Int("postID", postID))
+	h.logger.Info("Post updated successfully", zap.Int("postID", postID))
 	c.JSON(http.StatusOK, updatedpost)
 }
+
+// GetPostRevisions godoc
+// @Summary История правок поста
+// @Description Возвращает все версии поста начиная с исходной (доступно автору или пользователю с правом post.history). У поста без правок история пустая
+// @Tags Посты
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path int true "ID поста"
+// @Success 200 {array} entity.PostRevision
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /posts/{post_id}/revisions [get]
+func (h *PostHandler) GetPostRevisions(c *gin.Context) {
+	postID, ok := h.authorizeHistory(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.postUsecase.GetPostRevisions(c.Request.Context(), postID)
+	if err != nil {
+		h.logger.Error("Failed to get post revisions", zap.Int("postID", postID), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get post revisions"})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetPostRevision godoc
+// @Summary Версия поста
+// @Description Возвращает одну версию поста по ее номеру (доступно автору или пользователю с правом post.history)
+// @Tags Посты
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path int true "ID поста"
+// @Param rev path int true "Номер версии"
+// @Success 200 {object} entity.PostRevision
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /posts/{post_id}/revisions/{rev} [get]
+func (h *PostHandler) GetPostRevision(c *gin.Context) {
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid revision"})
+		return
+	}
+	postID, ok := h.authorizeHistory(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.postUsecase.GetPostRevision(c.Request.Context(), postID, rev)
+	if err != nil {
+		h.respondRevisionError(c, postID, err)
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
+
+// DiffPostRevisions godoc
+// @Summary Сравнить версии поста
+// @Description Построчный дифф заголовка и текста между версиями from и to (по умолчанию to - последняя версия). Доступно автору или пользователю с правом post.history
+// @Tags Посты
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path int true "ID поста"
+// @Param from query int true "Исходная версия"
+// @Param to query int false "Конечная версия"
+// @Success 200 {object} entity.RevisionDiff
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /posts/{post_id}/revisions/diff [get]
+func (h *PostHandler) DiffPostRevisions(c *gin.Context) {
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid from revision"})
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid to revision"})
+		return
+	}
+	postID, ok := h.authorizeHistory(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.postUsecase.DiffPostRevisions(c.Request.Context(), postID, from, to)
+	if err != nil {
+		h.respondRevisionError(c, postID, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// authorizeHistory проверяет, что историю поста смотрит автор или пользователь
+// с правом post.history. При отказе ответ уже записан
+func (h *PostHandler) authorizeHistory(c *gin.Context) (int, bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		h.logger.Warn("Principal not found in context")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return 0, false
+	}
+
+	postIDStr := c.Param("post_id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Warn("Invalid post ID", zap.String("postID", postIDStr), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return 0, false
+	}
+
+	post, err := h.postRepo.GetPostByID(c.Request.Context(), postID)
+	if err != nil {
+		h.respondPostLookupError(c, postID, err)
+		return 0, false
+	}
+
+	if post.AuthorId != principal.UserID && !authz.Can(principal.Permissions, authz.PostHistory) {
+		h.logger.Warn("Unauthorized attempt to view post history",
+			zap.Int("userID", principal.UserID),
+			zap.Int("postAuthorID", post.AuthorId),
+			zap.Int("postID", postID))
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view this post history"})
+		return 0, false
+	}
+	return postID, true
+}
+
+// respondPostLookupError отвечает 404 для несуществующего поста и 500 для остальных ошибок
+func (h *PostHandler) respondPostLookupError(c *gin.Context, postID int, err error) {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, usecase.ErrPostNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": usecase.ErrPostNotFound.Error()})
+		return
+	}
+	h.logger.Error("Failed to get post", zap.Int("postID", postID), zap.Error(err))
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get post"})
+}
+
+func (h *PostHandler) respondRevisionError(c *gin.Context, postID int, err error) {
+	if errors.Is(err, usecase.ErrRevisionNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	h.logger.Error("Failed to get post revision", zap.Int("postID", postID), zap.Error(err))
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get post revision"})
+}
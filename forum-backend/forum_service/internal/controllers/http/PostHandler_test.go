@@ -2,14 +2,18 @@ package http
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"errors"
-	utils "github.com/Engls/EnglsJwt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/Engls/forum-project2/forum_service/internal/authz"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
 	"github.com/Engls/forum-project2/forum_service/mocks"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -23,9 +27,9 @@ func TestPostHandler_CreatePost_Success(t *testing.T) {
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
 	post := &entity.Post{
 		Title:   "Test Post",
@@ -33,41 +37,35 @@ func TestPostHandler_CreatePost_Success(t *testing.T) {
 	}
 	postJSON, _ := json.Marshal(post)
 
-	mockPostRepo.On("GetUserIDByToken", mock.Anything, "valid.jwt.token").Return(1, nil)
-	mockPostUsecase.On("CreatePost", mock.Anything, mock.Anything).Return(post, nil)
+	mockPostUsecase.On("CreatePost", mock.Anything, entity.Post{Title: "Test Post", Content: "This is a test post", AuthorId: 1}).Return(post, nil)
 
 	req, _ := http.NewRequest("POST", "/posts", bytes.NewBuffer(postJSON))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer valid.jwt.token")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
 	postHandler.CreatePost(c)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
 	assert.Contains(t, w.Body.String(), "Test Post")
 
-	mockPostRepo.AssertExpectations(t)
 	mockPostUsecase.AssertExpectations(t)
 }
 
-func TestPostHandler_CreatePost_MissingAuthorizationHeader(t *testing.T) {
+func TestPostHandler_CreatePost_NoPrincipal(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	post := entity.Post{
-		Title:   "Test Post",
-		Content: "This is a test post",
-	}
-	postJSON, _ := json.Marshal(post)
+	postJSON, _ := json.Marshal(entity.Post{Title: "Test Post", Content: "This is a test post"})
 
 	req, _ := http.NewRequest("POST", "/posts", bytes.NewBuffer(postJSON))
 	req.Header.Set("Content-Type", "application/json")
@@ -79,128 +77,131 @@ func TestPostHandler_CreatePost_MissingAuthorizationHeader(t *testing.T) {
 	postHandler.CreatePost(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Authorization header required")
+	assert.Contains(t, w.Body.String(), "Authorization required")
 }
 
-func TestPostHandler_CreatePost_InvalidAuthorizationHeaderFormat(t *testing.T) {
+func TestPostHandler_CreatePost_FailedToCreatePost(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	post := entity.Post{
-		Title:   "Test Post",
-		Content: "This is a test post",
-	}
-	postJSON, _ := json.Marshal(post)
+	postJSON, _ := json.Marshal(entity.Post{Title: "Test Post", Content: "This is a test post"})
+
+	mockPostUsecase.On("CreatePost", mock.Anything, mock.Anything).Return(nil, errors.New("failed to create post"))
 
 	req, _ := http.NewRequest("POST", "/posts", bytes.NewBuffer(postJSON))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "InvalidFormat")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
 	postHandler.CreatePost(c)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid Authorization header format")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "failed to create post")
+
+	mockPostUsecase.AssertExpectations(t)
 }
 
-func TestPostHandler_CreatePost_InvalidToken(t *testing.T) {
+func TestPostHandler_GetPosts_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	post := entity.Post{
-		Title:   "Test Post",
-		Content: "This is a test post",
+	posts := []entity.Post{
+		{ID: 1, Title: "Post 1", Content: "Content 1", AuthorId: 1},
+		{ID: 2, Title: "Post 2", Content: "Content 2", AuthorId: 2},
 	}
-	postJSON, _ := json.Marshal(post)
 
-	mockPostRepo.On("GetUserIDByToken", mock.Anything, "invalid.jwt.token\t").Return(0, errors.New("invalid token"))
+	mockPostUsecase.On("ListPosts", mock.Anything, entity.PostFilter{}, 10, 0).Return(posts, nil)
+	mockPostUsecase.On("CountPosts", mock.Anything, entity.PostFilter{}).Return(2, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1, 2}).Return(map[int]string{1: "alice"}, nil).Once()
 
-	req, _ := http.NewRequest("POST", "/posts", bytes.NewBuffer(postJSON))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer invalid.jwt.token	")
+	req, _ := http.NewRequest("GET", "/posts", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 
-	postHandler.CreatePost(c)
+	postHandler.GetPosts(c)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid token")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Posts []map[string]interface{} `json:"posts"`
+		Total int                      `json:"total"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.Total)
+	assert.Len(t, response.Posts, 2)
+	assert.Equal(t, "alice", response.Posts[0]["username"])
+	assert.Equal(t, "", response.Posts[1]["username"])
 
-	mockPostRepo.AssertExpectations(t)
+	mockPostUsecase.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
 }
 
-func TestPostHandler_CreatePost_FailedToCreatePost(t *testing.T) {
+func TestPostHandler_GetPosts_UsernamesUnavailable(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	post := &entity.Post{
-		Title:   "Test Post",
-		Content: "This is a test post",
-	}
-	postJSON, _ := json.Marshal(post)
+	posts := []entity.Post{{ID: 1, Title: "Post 1", Content: "Content 1", AuthorId: 1}}
 
-	mockPostRepo.On("GetUserIDByToken", mock.Anything, "valid.jwt.token").Return(1, nil)
-	mockPostUsecase.On("CreatePost", mock.Anything, mock.Anything).Return(post, errors.New("failed to create post"))
+	mockPostUsecase.On("ListPosts", mock.Anything, entity.PostFilter{}, 10, 0).Return(posts, nil)
+	mockPostUsecase.On("CountPosts", mock.Anything, entity.PostFilter{}).Return(1, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(nil, errors.New("unavailable"))
 
-	req, _ := http.NewRequest("POST", "/posts", bytes.NewBuffer(postJSON))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer valid.jwt.token")
+	req, _ := http.NewRequest("GET", "/posts", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 
-	postHandler.CreatePost(c)
+	postHandler.GetPosts(c)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "failed to create post")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Posts []map[string]interface{} `json:"posts"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Posts, 1)
+	assert.Equal(t, "", response.Posts[0]["username"])
 
-	mockPostRepo.AssertExpectations(t)
-	mockPostUsecase.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
 }
 
-func TestPostHandler_GetPosts_Success(t *testing.T) {
+func TestPostHandler_GetPosts_FailedToGetPosts(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	posts := []entity.Post{
-		{ID: 1, Title: "Post 1", Content: "Content 1"},
-		{ID: 2, Title: "Post 2", Content: "Content 2"},
-	}
-
-	mockPostRepo.On("GetPosts", mock.Anything).Return(posts, nil)
+	mockPostUsecase.On("ListPosts", mock.Anything, entity.PostFilter{}, 10, 0).Return(nil, errors.New("failed to get posts"))
 
 	req, _ := http.NewRequest("GET", "/posts", nil)
-	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -208,152 +209,938 @@ func TestPostHandler_GetPosts_Success(t *testing.T) {
 
 	postHandler.GetPosts(c)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-	var responsePosts []entity.Post
-	err := json.Unmarshal(w.Body.Bytes(), &responsePosts)
-	assert.NoError(t, err)
-	assert.Equal(t, posts, responsePosts)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "failed to get posts")
 
-	mockPostRepo.AssertExpectations(t)
+	mockPostUsecase.AssertExpectations(t)
 }
 
-func TestPostHandler_GetPosts_FailedToGetPosts(t *testing.T) {
+func TestPostHandler_DeletePost_NoPrincipal(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockPostRepo := new(mocks.PostRepository)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+	postHandler.DeletePost(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Authorization required")
+}
+
+func TestPostHandler_DeletePost_Success_Owner(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	mockPostRepo.On("GetPosts", mock.Anything).Return(nil, errors.New("failed to get posts"))
+	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 1}, nil)
+	mockPostUsecase.On("DeletePost", mock.Anything, 1).Return(nil)
 
-	req, _ := http.NewRequest("GET", "/posts", nil)
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
-	postHandler.GetPosts(c)
+	postHandler.DeletePost(c)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "failed to get posts")
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
 
 	mockPostRepo.AssertExpectations(t)
+	mockPostUsecase.AssertExpectations(t)
 }
 
-func TestPostHandler_DeletePost_MissingAuthorizationHeader(t *testing.T) {
+func TestPostHandler_DeletePost_Success_Admin(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	mockPostUsecase.On("DeletePost", mock.Anything, 1).Return(nil)
 
 	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
-	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 2, Role: "admin", Permissions: []string{"post.delete.any"}})
 
 	postHandler.DeletePost(c)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Authorization header required")
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+
+	mockPostRepo.AssertNotCalled(t, "GetPostByID", mock.Anything, mock.Anything)
+	mockPostUsecase.AssertExpectations(t)
 }
 
-func TestPostHandler_DeletePost_InvalidAuthorizationHeaderFormat(t *testing.T) {
+func TestPostHandler_DeletePost_Forbidden(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 2}, nil)
 
 	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "InvalidFormat")
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
 	postHandler.DeletePost(c)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid Authorization header format")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	mockPostRepo.AssertExpectations(t)
+	mockPostUsecase.AssertNotCalled(t, "DeletePost", mock.Anything, mock.Anything)
 }
 
-func TestPostHandler_DeletePost_Success_Owner(t *testing.T) {
+func TestPostHandler_UpdatePost_Forbidden(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	token, err := jwtUtil.GenerateToken(1, "user")
-	assert.NoError(t, err)
+	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 2}, nil)
 
-	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 1}, nil)
-	mockPostUsecase.On("DeletePost", mock.Anything, 1).Return(nil)
+	postJSON, _ := json.Marshal(entity.Post{Title: "New title", Content: "New content"})
+	req, _ := http.NewRequest("PUT", "/posts/1", bytes.NewBuffer(postJSON))
+	req.Header.Set("Content-Type", "application/json")
 
-	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user", Permissions: []string{"post.create"}})
+
+	postHandler.UpdatePost(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	mockPostRepo.AssertExpectations(t)
+	mockPostUsecase.AssertNotCalled(t, "UpdatePost", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostHandler_UpdatePost_UpdateAnyPermission(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockPostRepo := new(mocks.PostRepository)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	updated := entity.Post{ID: 1, AuthorId: 2, Title: "New title", Content: "New content"}
+	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 2, Title: "Old", Content: "Old"}, nil)
+	mockPostUsecase.On("UpdatePost", mock.Anything, updated, 1).Return(&updated, nil)
+
+	postJSON, _ := json.Marshal(entity.Post{Title: "New title", Content: "New content"})
+	req, _ := http.NewRequest("PUT", "/posts/1", bytes.NewBuffer(postJSON))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "admin", Permissions: []string{"post.update.any"}})
 
-	postHandler.DeletePost(c)
+	postHandler.UpdatePost(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "New title")
 
 	mockPostRepo.AssertExpectations(t)
 	mockPostUsecase.AssertExpectations(t)
 }
 
-func TestPostHandler_DeletePost_Success_Admin(t *testing.T) {
+func TestPostHandler_GetPostRevisions_Author(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockPostUsecase := new(mocks.PostUsecase)
 	mockPostRepo := new(mocks.PostRepository)
-	jwtUtil := utils.NewJWTUtil("secret")
+	mockUserService := new(mocks.UserService)
 
-	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, jwtUtil, logger)
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
-	token, err := jwtUtil.GenerateToken(1, "admin")
-	assert.NoError(t, err)
+	revisions := []entity.PostRevision{
+		{ID: 1, PostId: 1, Revision: 1, Title: "Old", Content: "Old", EditorId: 1},
+		{ID: 2, PostId: 1, Revision: 2, Title: "New", Content: "New", EditorId: 1},
+	}
+	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 1}, nil)
+	mockPostUsecase.On("GetPostRevisions", mock.Anything, 1).Return(revisions, nil)
 
-	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(entity.Post{ID: 1, AuthorId: 2}, nil)
-	mockPostUsecase.On("DeletePost", mock.Anything, 1).Return(nil)
+	req, _ := http.NewRequest("GET", "/posts/1/revisions", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
-	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	postHandler.GetPostRevisions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result []entity.PostRevision
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Len(t, result, 2)
+
+	mockPostRepo.AssertExpectations(t)
+	mockPostUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPostRevisions_Forbidden(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockPostRepo := new(mocks.PostRepository)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
 
+	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 2}, nil)
+
+	req, _ := http.NewRequest("GET", "/posts/1/revisions", nil)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
-	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
 
-	postHandler.DeletePost(c)
+	postHandler.GetPostRevisions(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockPostUsecase.AssertNotCalled(t, "GetPostRevisions", mock.Anything, mock.Anything)
+}
+
+func TestPostHandler_DiffPostRevisions_Moderator(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockPostRepo := new(mocks.PostRepository)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	result := &entity.RevisionDiff{PostId: 1, From: 1, To: 3, Content: []entity.DiffLine{{Op: entity.DiffInsert, Text: "spam"}}}
+	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 2}, nil)
+	mockPostUsecase.On("DiffPostRevisions", mock.Anything, 1, 1, 0).Return(result, nil)
+
+	req, _ := http.NewRequest("GET", "/posts/1/revisions/diff?from=1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "moderator", Permissions: []string{authz.PostHistory}})
+
+	postHandler.DiffPostRevisions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"op":"+"`)
+
+	mockPostUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPostRevision_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockPostRepo := new(mocks.PostRepository)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 1}, nil)
+	mockPostUsecase.On("GetPostRevision", mock.Anything, 1, 9).Return(nil, usecase.ErrRevisionNotFound)
+
+	req, _ := http.NewRequest("GET", "/posts/1/revisions/9", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}, gin.Param{Key: "rev", Value: "9"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
+
+	postHandler.GetPostRevision(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockPostUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPost_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockPostRepo := new(mocks.PostRepository)
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	post := &entity.Post{ID: 1, AuthorId: 1, Title: "Title", Content: "Content", CreatedAt: created, UpdatedAt: created}
+	comments := []entity.Comment{{ID: 5, PostId: 1, AuthorId: 2, Content: "First!"}}
+
+	mockPostUsecase.On("GetPostByID", mock.Anything, 1).Return(post, nil)
+	mockCommentUsecase.On("GetTotalCommentsCount", mock.Anything, 1).Return(12, nil)
+	mockCommentUsecase.On("GetComments", mock.Anything, 1, 10, 0).Return(comments, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1, 2}).Return(map[int]string{1: "alice", 2: "bob"}, nil)
+
+	req, _ := http.NewRequest("GET", "/posts/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+
+	postHandler.GetPost(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Post struct {
+			Username  string    `json:"username"`
+			CreatedAt time.Time `json:"created_at"`
+		} `json:"post"`
+		CommentCount int                      `json:"comment_count"`
+		Comments     []map[string]interface{} `json:"comments"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "alice", response.Post.Username)
+	assert.True(t, created.Equal(response.Post.CreatedAt))
+	assert.Equal(t, 12, response.CommentCount)
+	if assert.Len(t, response.Comments, 1) {
+		assert.Equal(t, "bob", response.Comments[0]["username"])
+	}
 
 	mockPostUsecase.AssertExpectations(t)
+	mockCommentUsecase.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPost_AuthorCards(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+	mockAuthorService := new(mocks.AuthorService)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, mockAuthorService)
+
+	post := &entity.Post{ID: 1, AuthorId: 1, Title: "Title", Content: "Content"}
+	comments := []entity.Comment{{ID: 5, PostId: 1, AuthorId: 2, Content: "First!"}, {ID: 6, PostId: 1, AuthorId: 1, Content: "Reply"}}
+	alice := entity.AuthorCard{UserID: 1, Username: "alice", Role: "moderator", PostCount: 7}
+
+	mockPostUsecase.On("GetPostByID", mock.Anything, 1).Return(post, nil)
+	mockCommentUsecase.On("GetTotalCommentsCount", mock.Anything, 1).Return(2, nil)
+	mockCommentUsecase.On("GetComments", mock.Anything, 1, 10, 0).Return(comments, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1, 2}).Return(map[int]string{1: "alice", 2: "bob"}, nil)
+	// Карточка bob не нашлась - у его комментария поля author нет
+	mockAuthorService.On("GetAuthorCards", mock.Anything, []int{1, 2}).Return(map[int]entity.AuthorCard{1: alice}, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/posts/1", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+
+	postHandler.GetPost(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Post struct {
+			Author *entity.AuthorCard `json:"author"`
+		} `json:"post"`
+		Comments []struct {
+			Author *entity.AuthorCard `json:"author"`
+		} `json:"comments"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotNil(t, response.Post.Author) {
+		assert.Equal(t, alice, *response.Post.Author)
+	}
+	if assert.Len(t, response.Comments, 2) {
+		assert.Nil(t, response.Comments[0].Author)
+		assert.Equal(t, &alice, response.Comments[1].Author)
+	}
+
+	mockAuthorService.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPost_CommentsLimitClamped(t *testing.T) {
+	tests := []struct {
+		query string
+		limit int
+	}{
+		{query: "comments_limit=500", limit: maxCursorLimit},
+		{query: "comments_limit=0", limit: 10},
+		{query: "comments_limit=-5", limit: 10},
+		{query: "comments_limit=abc", limit: 10},
+		{query: "comments_limit=25", limit: 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			logger, _ := zap.NewProduction()
+
+			mockPostUsecase := new(mocks.PostUsecase)
+			mockCommentUsecase := new(mocks.CommentsUsecases)
+			mockUserService := new(mocks.UserService)
+
+			postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+			mockPostUsecase.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, AuthorId: 1}, nil)
+			mockCommentUsecase.On("GetTotalCommentsCount", mock.Anything, 1).Return(0, nil)
+			mockCommentUsecase.On("GetComments", mock.Anything, 1, tt.limit, 0).Return([]entity.Comment{}, nil)
+			mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "alice"}, nil)
+
+			req, _ := http.NewRequest("GET", "/posts/1?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{gin.Param{Key: "post_id", Value: "1"}}
+
+			postHandler.GetPost(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var response struct {
+				Pagination struct {
+					Limit int `json:"limit"`
+				} `json:"comments_pagination"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.limit, response.Pagination.Limit)
+			mockCommentUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostHandler_GetPost_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockPostRepo := new(mocks.PostRepository)
+	mockCommentUsecase := new(mocks.CommentsUsecases)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, mockCommentUsecase, new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	mockPostUsecase.On("GetPostByID", mock.Anything, 99).Return(nil, usecase.ErrPostNotFound)
+
+	req, _ := http.NewRequest("GET", "/posts/99", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "post_id", Value: "99"}}
+
+	postHandler.GetPost(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"post not found"}`, w.Body.String())
+	mockCommentUsecase.AssertNotCalled(t, "GetComments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostHandler_DeletePost_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockPostRepo := new(mocks.PostRepository)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, mockPostRepo, new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	mockPostRepo.On("GetPostByID", mock.Anything, 99).Return(nil, sql.ErrNoRows)
+
+	req, _ := http.NewRequest("DELETE", "/posts/99", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "99"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
+
+	postHandler.DeletePost(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockPostUsecase.AssertNotCalled(t, "DeletePost", mock.Anything, mock.Anything)
+}
+
+func TestPostHandler_CreatePost_UnknownCategory(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, new(mocks.UserService), nil)
+
+	mockPostUsecase.On("CreatePost", mock.Anything, mock.Anything).Return(nil, usecase.ErrCategoryNotFound)
+
+	req, _ := http.NewRequest("POST", "/posts", bytes.NewBufferString(`{"title":"Post","content":"Text","category_id":42}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	middleware.SetPrincipal(c, entity.Principal{UserID: 1, Role: "user"})
+
+	postHandler.CreatePost(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"category not found"}`, w.Body.String())
+}
+
+func TestPostHandler_GetPosts_ByTags(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	posts := []entity.Post{{ID: 1, Title: "Post 1", AuthorId: 1, Tags: []string{"go", "grpc"}}}
+
+	filter := entity.PostFilter{Tags: []string{"go", "grpc"}, MatchAllTags: true}
+	mockPostUsecase.On("ListPosts", mock.Anything, filter, 10, 0).Return(posts, nil)
+	mockPostUsecase.On("CountPosts", mock.Anything, filter).Return(1, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "alice"}, nil)
+
+	req, _ := http.NewRequest("GET", "/posts?tag=go&tag=grpc&tag_mode=and", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	postHandler.GetPosts(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Posts []map[string]interface{} `json:"posts"`
+		Total int                      `json:"total"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, []interface{}{"go", "grpc"}, response.Posts[0]["tags"])
+
+	mockPostUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPosts_InvalidTagMode(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, new(mocks.UserService), nil)
+
+	req, _ := http.NewRequest("GET", "/posts?tag=go&tag_mode=xor", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	postHandler.GetPosts(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockPostUsecase.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostHandler_GetTags(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, new(mocks.UserService), nil)
+
+	mockPostUsecase.On("GetTags", mock.Anything, 100).Return([]entity.TagCount{{Name: "go", Count: 3}, {Name: "grpc", Count: 1}}, nil)
+
+	req, _ := http.NewRequest("GET", "/tags", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	postHandler.GetTags(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"name":"go","count":3},{"name":"grpc","count":1}]`, w.Body.String())
+
+	mockPostUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPosts_Cursor(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	now := time.Now()
+	after := entity.Cursor{CreatedAt: now, ID: 10}
+	posts := []entity.Post{
+		{ID: 9, AuthorId: 1, CreatedAt: now},
+		{ID: 8, AuthorId: 1, CreatedAt: now},
+		{ID: 7, AuthorId: 1, CreatedAt: now},
+	}
+
+	mockPostUsecase.On("ListPosts", mock.Anything, mock.MatchedBy(func(f entity.PostFilter) bool {
+		return f.After != nil && f.After.ID == 10 && f.After.CreatedAt.Equal(now)
+	}), 3, 0).Return(posts, nil)
+	mockPostUsecase.On("CountPosts", mock.Anything, mock.Anything).Return(12, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "alice"}, nil)
+
+	req, _ := http.NewRequest("GET", "/posts?limit=2&cursor="+after.Encode(), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	postHandler.GetPosts(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Posts      []map[string]interface{} `json:"posts"`
+		NextCursor string                   `json:"next_cursor"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Posts, 2)
+	next, err := entity.DecodeCursor(response.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, next.ID)
+	assert.True(t, next.CreatedAt.Equal(now))
+
+	mockPostUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPosts_InvalidCursor(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, new(mocks.UserService), nil)
+
+	req, _ := http.NewRequest("GET", "/posts?cursor=not-a-cursor", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	postHandler.GetPosts(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), entity.ErrInvalidCursor.Error())
+}
+
+func TestPostHandler_GetPosts_SortAndFilters(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, mockUserService, nil)
+
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	filter := entity.PostFilter{Sort: entity.PostSortCommented, AuthorID: 3, Since: &since, Until: &until}
+	posts := []entity.Post{{ID: 4, AuthorId: 3, CommentCount: 7}}
+
+	mockPostUsecase.On("ListPosts", mock.Anything, filter, 10, 0).Return(posts, nil)
+	mockPostUsecase.On("CountPosts", mock.Anything, filter).Return(20, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{3}).Return(map[int]string{3: "bob"}, nil)
+
+	req, _ := http.NewRequest("GET", "/posts?sort=commented&author_id=3&since=2024-05-01&until=2024-05-31", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	postHandler.GetPosts(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// Для сортировок не по времени курсор не выдается
+	assert.Contains(t, w.Body.String(), `"next_cursor":null`)
+	assert.Contains(t, w.Body.String(), `"comment_count":7`)
+
+	mockPostUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPosts_InvalidSort(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), emptyReactions(), logger, new(mocks.UserService), nil)
+
+	mockPostUsecase.On("ListPosts", mock.Anything, entity.PostFilter{Sort: "random"}, 10, 0).Return(nil, usecase.ErrInvalidSort)
+
+	req, _ := http.NewRequest("GET", "/posts?sort=random", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	postHandler.GetPosts(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), usecase.ErrInvalidSort.Error())
+}
+
+func TestPostHandler_GetPosts_WithMyVotes(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockVoteUsecase := new(mocks.VoteUsecase)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), mockVoteUsecase, emptyReactions(), logger, mockUserService, nil)
+
+	posts := []entity.Post{
+		{ID: 1, Title: "Post 1", AuthorId: 1, Score: 3},
+		{ID: 2, Title: "Post 2", AuthorId: 1, Score: -1},
+	}
+
+	mockPostUsecase.On("ListPosts", mock.Anything, entity.PostFilter{}, 10, 0).Return(posts, nil)
+	mockPostUsecase.On("CountPosts", mock.Anything, entity.PostFilter{}).Return(2, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "alice"}, nil)
+	mockVoteUsecase.On("GetUserVotes", mock.Anything, 7, entity.VoteTargetPost, []int{1, 2}).Return(map[int]int{1: 1}, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/posts", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	postHandler.GetPosts(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Posts []map[string]interface{} `json:"posts"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(3), response.Posts[0]["score"])
+	assert.Equal(t, float64(1), response.Posts[0]["my_vote"])
+	assert.Equal(t, float64(-1), response.Posts[1]["score"])
+	assert.Equal(t, float64(0), response.Posts[1]["my_vote"])
+
+	mockVoteUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_VotePost_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockVoteUsecase := new(mocks.VoteUsecase)
+
+	postHandler := NewPostHandler(new(mocks.PostUsecase), new(mocks.PostRepository), new(mocks.CommentsUsecases), mockVoteUsecase, emptyReactions(), logger, new(mocks.UserService), nil)
+
+	vote := entity.Vote{UserID: 7, TargetType: entity.VoteTargetPost, TargetID: 1, Value: -1}
+	mockVoteUsecase.On("Vote", mock.Anything, vote).
+		Return(&entity.VoteResult{TargetType: entity.VoteTargetPost, TargetID: 1, Score: 2, MyVote: -1}, nil)
+
+	req, _ := http.NewRequest("PUT", "/posts/1/vote", bytes.NewBufferString(`{"value": -1}`))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	postHandler.VotePost(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result entity.VoteResult
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.Score)
+	assert.Equal(t, -1, result.MyVote)
+
+	mockVoteUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_VotePost_Errors(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockVoteUsecase := new(mocks.VoteUsecase)
+
+	postHandler := NewPostHandler(new(mocks.PostUsecase), new(mocks.PostRepository), new(mocks.CommentsUsecases), mockVoteUsecase, emptyReactions(), logger, new(mocks.UserService), nil)
+
+	mockVoteUsecase.On("Vote", mock.Anything, entity.Vote{UserID: 7, TargetType: entity.VoteTargetPost, TargetID: 1, Value: 5}).
+		Return(nil, usecase.ErrInvalidVote)
+	mockVoteUsecase.On("Vote", mock.Anything, entity.Vote{UserID: 7, TargetType: entity.VoteTargetPost, TargetID: 9, Value: 1}).
+		Return(nil, usecase.ErrPostNotFound)
+
+	cases := []struct {
+		postID string
+		body   string
+		status int
+	}{
+		{"1", `{}`, http.StatusBadRequest},
+		{"abc", `{"value": 1}`, http.StatusBadRequest},
+		{"1", `{"value": 5}`, http.StatusBadRequest},
+		{"9", `{"value": 1}`, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest("PUT", "/posts/"+tc.postID+"/vote", bytes.NewBufferString(tc.body))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{gin.Param{Key: "id", Value: tc.postID}}
+		middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+		postHandler.VotePost(c)
+
+		assert.Equal(t, tc.status, w.Code, tc.body)
+	}
+
+	mockVoteUsecase.AssertExpectations(t)
+}
+
+// emptyReactions - ReactionUsecase для тестов, где реакций нет
+func emptyReactions() *mocks.ReactionUsecase {
+	reactionUsecase := new(mocks.ReactionUsecase)
+	reactionUsecase.On("GetReactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(map[int][]entity.ReactionCount{}, nil).Maybe()
+	return reactionUsecase
+}
+
+func TestPostHandler_GetPosts_WithReactions(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostUsecase := new(mocks.PostUsecase)
+	mockReactionUsecase := new(mocks.ReactionUsecase)
+	mockUserService := new(mocks.UserService)
+
+	postHandler := NewPostHandler(mockPostUsecase, new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), mockReactionUsecase, logger, mockUserService, nil)
+
+	posts := []entity.Post{{ID: 1, Title: "Post 1", AuthorId: 1}, {ID: 2, Title: "Post 2", AuthorId: 1}}
+
+	mockPostUsecase.On("ListPosts", mock.Anything, entity.PostFilter{}, 10, 0).Return(posts, nil)
+	mockPostUsecase.On("CountPosts", mock.Anything, entity.PostFilter{}).Return(2, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{1}).Return(map[int]string{1: "alice"}, nil)
+	mockReactionUsecase.On("GetReactions", mock.Anything, 0, entity.ReactionTargetPost, []int{1, 2}).
+		Return(map[int][]entity.ReactionCount{1: {{Emoji: "👍", Count: 2}}}, nil)
+
+	req, _ := http.NewRequest("GET", "/posts", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	postHandler.GetPosts(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Posts []struct {
+			Reactions []entity.ReactionCount `json:"reactions"`
+		} `json:"posts"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []entity.ReactionCount{{Emoji: "👍", Count: 2}}, response.Posts[0].Reactions)
+	assert.Equal(t, []entity.ReactionCount{}, response.Posts[1].Reactions)
+
+	mockReactionUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_AddReaction_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockReactionUsecase := new(mocks.ReactionUsecase)
+
+	postHandler := NewPostHandler(new(mocks.PostUsecase), new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), mockReactionUsecase, logger, new(mocks.UserService), nil)
+
+	reaction := entity.Reaction{UserID: 7, TargetType: entity.ReactionTargetPost, TargetID: 1, Emoji: "🎉"}
+	mockReactionUsecase.On("AddReaction", mock.Anything, reaction).Return(&entity.ReactionResult{
+		TargetType: entity.ReactionTargetPost,
+		TargetID:   1,
+		Emoji:      "🎉",
+		Reactions:  []entity.ReactionCount{{Emoji: "🎉", Count: 1, ReactedByMe: true}},
+	}, nil)
+
+	req, _ := http.NewRequest("PUT", "/posts/1/reactions/🎉", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "emoji", Value: "🎉"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	postHandler.AddReaction(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result entity.ReactionResult
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []entity.ReactionCount{{Emoji: "🎉", Count: 1, ReactedByMe: true}}, result.Reactions)
+
+	mockReactionUsecase.AssertExpectations(t)
+}
+
+func TestPostHandler_AddReaction_InvalidEmoji(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockReactionUsecase := new(mocks.ReactionUsecase)
+
+	postHandler := NewPostHandler(new(mocks.PostUsecase), new(mocks.PostRepository), new(mocks.CommentsUsecases), new(mocks.VoteUsecase), mockReactionUsecase, logger, new(mocks.UserService), nil)
+
+	mockReactionUsecase.On("AddReaction", mock.Anything, mock.Anything).Return(nil, usecase.ErrInvalidEmoji)
+
+	req, _ := http.NewRequest("PUT", "/posts/1/reactions/x", nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "emoji", Value: "x"}}
+	middleware.SetPrincipal(c, entity.Principal{UserID: 7, Role: "user"})
+
+	postHandler.AddReaction(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), usecase.ErrInvalidEmoji.Error())
 }
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type SearchHandler struct {
+	searchUsecase usecase.SearchUsecase
+	logger        *zap.Logger
+	userClient    UserService
+}
+
+func NewSearchHandler(searchUsecase usecase.SearchUsecase, logger *zap.Logger, userClient UserService) *SearchHandler {
+	return &SearchHandler{searchUsecase: searchUsecase, logger: logger, userClient: userClient}
+}
+
+// Search godoc
+// @Summary Поиск по постам и комментариям
+// @Description Полнотекстовый поиск, самые релевантные результаты первыми. Все слова запроса обязательны, последнее ищется по префиксу. В title и snippet совпадения обернуты в <mark>, остальной текст экранирован
+// @Tags Поиск
+// @Produce json
+// @Param q query string true "Поисковый запрос"
+// @Param author_id query int false "Только результаты этого автора"
+// @Param since query string false "Созданы не раньше (YYYY-MM-DD или RFC3339)"
+// @Param until query string false "Созданы раньше (RFC3339) или в этот день (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{} "results and pagination info"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > usecase.MaxSearchLimit {
+		limit = usecase.MaxSearchLimit
+	}
+
+	filter := entity.SearchFilter{
+		Query:  c.Query("q"),
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+
+	if authorID := c.Query("author_id"); authorID != "" {
+		id, err := strconv.Atoi(authorID)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid author ID"})
+			return
+		}
+		filter.AuthorID = id
+	}
+
+	var err error
+	if filter.Since, err = parseDateQuery(c.Query("since"), false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since date"})
+		return
+	}
+	if filter.Until, err = parseDateQuery(c.Query("until"), true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid until date"})
+		return
+	}
+
+	results, total, err := h.searchUsecase.Search(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, usecase.ErrEmptySearchQuery) || errors.Is(err, usecase.ErrSearchQueryTooLong) ||
+			errors.Is(err, usecase.ErrInvalidDateRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to search", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorIDs := make([]int, len(results))
+	for i, result := range results {
+		authorIDs[i] = result.AuthorId
+	}
+	usernames := lookupUsernames(c.Request.Context(), h.userClient, h.logger, authorIDs)
+	for i := range results {
+		results[i].Username = usernames[results[i].AuthorId]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   filter.Query,
+		"results": results,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// parseDateQuery разбирает дату в формате YYYY-MM-DD или RFC3339. Для верхней границы
+// дата без времени означает конец дня. Пустая строка - границы нет
+func parseDateQuery(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestSearchHandler_Search_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockSearchUsecase := new(mocks.SearchUsecase)
+	mockUserService := new(mocks.UserService)
+
+	searchHandler := NewSearchHandler(mockSearchUsecase, logger, mockUserService)
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	filter := entity.SearchFilter{Query: "go", AuthorID: 3, Since: &since, Until: &until, Limit: 5, Offset: 5}
+	results := []entity.SearchResult{{Type: entity.SearchTypePost, PostId: 1, AuthorId: 3, Title: "<mark>Go</mark>"}}
+
+	mockSearchUsecase.On("Search", mock.Anything, filter).Return(results, 6, nil)
+	mockUserService.On("GetUsernames", mock.Anything, []int{3}).Return(map[int]string{3: "gopher"}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/search?q=go&author_id=3&since=2025-01-01&until=2025-01-31&page=2&limit=5", nil)
+
+	searchHandler.Search(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Results    []entity.SearchResult `json:"results"`
+		Pagination map[string]int        `json:"pagination"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 1)
+	assert.Equal(t, "gopher", response.Results[0].Username)
+	assert.Equal(t, map[string]int{"page": 2, "limit": 5, "total": 6}, response.Pagination)
+
+	mockSearchUsecase.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
+}
+
+func TestSearchHandler_Search_BadRequest(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockSearchUsecase := new(mocks.SearchUsecase)
+
+	searchHandler := NewSearchHandler(mockSearchUsecase, logger, new(mocks.UserService))
+
+	mockSearchUsecase.On("Search", mock.Anything, mock.Anything).Return(nil, 0, usecase.ErrEmptySearchQuery)
+
+	for _, url := range []string{"/search?q=go&since=yesterday", "/search?q=go&author_id=abc", "/search?q="} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", url, nil)
+
+		searchHandler.Search(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
+
+func TestSearchHandler_Search_Failure(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockSearchUsecase := new(mocks.SearchUsecase)
+
+	searchHandler := NewSearchHandler(mockSearchUsecase, logger, new(mocks.UserService))
+
+	mockSearchUsecase.On("Search", mock.Anything, mock.Anything).Return(nil, 0, errors.New("database error"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/search?q=go", nil)
+
+	searchHandler.Search(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockSearchUsecase.AssertExpectations(t)
+}
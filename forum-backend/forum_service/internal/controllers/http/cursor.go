@@ -0,0 +1,46 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// cursorQuery читает параметр cursor. ok = false, если параметра нет и клиент листает
+// по page/limit. Пустое значение cursor означает первую страницу
+func cursorQuery(c *gin.Context) (cursor *entity.Cursor, ok bool, err error) {
+	value, ok := c.GetQuery("cursor")
+	if !ok || value == "" {
+		return nil, ok, nil
+	}
+	cursor, err = entity.DecodeCursor(value)
+	return cursor, true, err
+}
+
+// maxCursorLimit - наибольший размер страницы при курсорной навигации
+const maxCursorLimit = 100
+
+// cursorLimit читает limit для курсорной навигации и ограничивает его maxCursorLimit
+func cursorLimit(c *gin.Context) int {
+	return queryLimit(c, "limit")
+}
+
+// queryLimit читает размер страницы из параметра key: 10 по умолчанию и при некорректном значении, не больше maxCursorLimit
+func queryLimit(c *gin.Context, key string) int {
+	limit, err := strconv.Atoi(c.DefaultQuery(key, "10"))
+	if err != nil || limit < 1 {
+		return 10
+	}
+	if limit > maxCursorLimit {
+		limit = maxCursorLimit
+	}
+	return limit
+}
+
+// nextCursor - курсор страницы, следующей за записью
+func nextCursor(createdAt time.Time, id int) *string {
+	next := entity.CursorAfter(createdAt, id).Encode()
+	return &next
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorLimit(t *testing.T) {
+	tests := map[string]int{
+		"":            10,
+		"?limit=abc":  10,
+		"?limit=0":    10,
+		"?limit=25":   25,
+		"?limit=100":  maxCursorLimit,
+		"?limit=5000": maxCursorLimit,
+	}
+	for query, expected := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/posts"+query, nil)
+
+		assert.Equal(t, expected, cursorLimit(c), query)
+	}
+}
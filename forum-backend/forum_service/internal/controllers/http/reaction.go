@@ -0,0 +1,83 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// setReaction обрабатывает PUT (add = true) и DELETE /<цель>/:id/reactions/:emoji и возвращает
+// результат вместе с id пользователя.
+// Ответ записывается в любом случае, ok = false при ошибке
+func setReaction(c *gin.Context, reactionUsecase usecase.ReactionUsecase, logger *zap.Logger, targetType string, add bool) (*entity.ReactionResult, int, bool) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		logger.Warn("Principal not found in context")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return nil, 0, false
+	}
+
+	targetIDStr := c.Param("id")
+	targetID, err := strconv.Atoi(targetIDStr)
+	if err != nil {
+		logger.Warn("Invalid reaction target ID", zap.String("targetType", targetType), zap.String("targetID", targetIDStr))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid " + targetType + " ID"})
+		return nil, 0, false
+	}
+
+	reaction := entity.Reaction{
+		UserID:     principal.UserID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Emoji:      c.Param("emoji"),
+	}
+	var result *entity.ReactionResult
+	if add {
+		result, err = reactionUsecase.AddReaction(c.Request.Context(), reaction)
+	} else {
+		result, err = reactionUsecase.RemoveReaction(c.Request.Context(), reaction)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidEmoji):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrPostNotFound), errors.Is(err, usecase.ErrCommentNotFound), errors.Is(err, usecase.ErrChatMessageNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			logger.Error("Failed to change reaction", zap.String("targetType", targetType), zap.Int("targetID", targetID), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to change reaction"})
+		}
+		return nil, 0, false
+	}
+
+	c.JSON(http.StatusOK, result)
+	return result, principal.UserID, true
+}
+
+// lookupReactions получает реакции на цели ids одним запросом, reacted_by_me считается
+// для текущего пользователя. При ошибке реакции остаются пустыми
+func lookupReactions(c *gin.Context, reactionUsecase usecase.ReactionUsecase, logger *zap.Logger, targetType string, ids []int) map[int][]entity.ReactionCount {
+	if len(ids) == 0 {
+		return map[int][]entity.ReactionCount{}
+	}
+	reactions, err := reactionUsecase.GetReactions(c.Request.Context(), currentUserID(c), targetType, ids)
+	if err != nil {
+		logger.Warn("Failed to get reactions", zap.String("targetType", targetType), zap.Error(err))
+		return map[int][]entity.ReactionCount{}
+	}
+	return reactions
+}
+
+// reactionsOf - реакции на цель id, пустой список вместо null
+func reactionsOf(reactions map[int][]entity.ReactionCount, id int) []entity.ReactionCount {
+	if counts := reactions[id]; counts != nil {
+		return counts
+	}
+	return []entity.ReactionCount{}
+}
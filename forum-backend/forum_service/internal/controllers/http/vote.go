@@ -0,0 +1,75 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/middleware"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// castVote обрабатывает PUT /<цель>/:id/vote для постов и комментариев
+func castVote(c *gin.Context, voteUsecase usecase.VoteUsecase, logger *zap.Logger, targetType string) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		logger.Warn("Principal not found in context")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+		return
+	}
+
+	targetIDStr := c.Param("id")
+	targetID, err := strconv.Atoi(targetIDStr)
+	if err != nil {
+		logger.Warn("Invalid vote target ID", zap.String("targetType", targetType), zap.String("targetID", targetIDStr))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid " + targetType + " ID"})
+		return
+	}
+
+	var req entity.VoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := voteUsecase.Vote(c.Request.Context(), entity.Vote{
+		UserID:     principal.UserID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Value:      *req.Value,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidVote):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrPostNotFound), errors.Is(err, usecase.ErrCommentNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			logger.Error("Failed to vote", zap.String("targetType", targetType), zap.Int("targetID", targetID), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to vote"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// lookupMyVotes возвращает голоса текущего пользователя за цели ids. Для анонимного
+// запроса возвращает nil, и my_vote в ответ не попадает. При ошибке голоса остаются пустыми
+func lookupMyVotes(c *gin.Context, voteUsecase usecase.VoteUsecase, logger *zap.Logger, targetType string, ids []int) map[int]int {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		return nil
+	}
+	if len(ids) == 0 {
+		return map[int]int{}
+	}
+	votes, err := voteUsecase.GetUserVotes(c.Request.Context(), principal.UserID, targetType, ids)
+	if err != nil {
+		logger.Warn("Failed to get user votes", zap.Int("userID", principal.UserID), zap.String("targetType", targetType), zap.Error(err))
+		return map[int]int{}
+	}
+	return votes
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Engls/forum-project2/forum_service/internal/authz"
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/grpc"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const principalKey = "principal"
+
+// TokenValidator проверяет токен и возвращает его владельца. Невалидный или отозванный токен
+// дает grpc.ErrInvalidToken, остальные ошибки означают, что проверить токен не удалось
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (entity.Principal, error)
+}
+
+type AuthMiddleware struct {
+	validator TokenValidator
+	logger    *zap.Logger
+}
+
+func NewAuthMiddleware(validator TokenValidator, logger *zap.Logger) *AuthMiddleware {
+	return &AuthMiddleware{validator: validator, logger: logger}
+}
+
+// RequireAuth пропускает запрос дальше только с валидным токеном
+func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			m.logger.Warn("Authorization header required", zap.String("path", c.FullPath()))
+			abortUnauthorized(c, "Authorization header required")
+			return
+		}
+		m.authenticate(c, false)
+	}
+}
+
+// OptionalAuth пропускает анонимные запросы. Запрос с невалидным или отозванным токеном
+// тоже обрабатывается как анонимный: публичные страницы не ломаются у клиента со старым токеном
+func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		m.authenticate(c, true)
+	}
+}
+
+// authenticate отвечает 401 только на невалидный токен. Если auth_service недоступен,
+// запрос отклоняется с 503, чтобы клиент не выходил из аккаунта из-за сбоя
+func (m *AuthMiddleware) authenticate(c *gin.Context, optional bool) {
+	authHeader := c.GetHeader("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader || tokenString == "" {
+		m.logger.Warn("Invalid Authorization header format", zap.String("path", c.FullPath()))
+		abortUnauthorized(c, "Invalid Authorization header format")
+		return
+	}
+
+	principal, err := m.validator.ValidateToken(c.Request.Context(), tokenString)
+	if errors.Is(err, grpc.ErrInvalidToken) {
+		m.logger.Warn("Invalid or revoked token", zap.Error(err), zap.String("path", c.FullPath()))
+		if optional {
+			c.Next()
+			return
+		}
+		abortUnauthorized(c, "Invalid or revoked token")
+		return
+	}
+	if err != nil {
+		m.logger.Error("Failed to validate token", zap.Error(err), zap.String("path", c.FullPath()))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, entity.ErrorResponse{Error: "Authentication service unavailable"})
+		return
+	}
+
+	c.Set(principalKey, principal)
+	c.Next()
+}
+
+// RequireRole должен стоять после RequireAuth
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
+			abortUnauthorized(c, "Authorization required")
+			return
+		}
+		for _, role := range roles {
+			if principal.Role == role {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, entity.ErrorResponse{Error: "Insufficient permissions"})
+	}
+}
+
+// RequirePermission должен стоять после RequireAuth
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
+			abortUnauthorized(c, "Authorization required")
+			return
+		}
+		if !authz.Can(principal.Permissions, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, entity.ErrorResponse{Error: "Insufficient permissions"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetPrincipal возвращает пользователя, установленного RequireAuth или OptionalAuth
+func GetPrincipal(c *gin.Context) (entity.Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return entity.Principal{}, false
+	}
+	principal, ok := value.(entity.Principal)
+	return principal, ok
+}
+
+// SetPrincipal нужен обработчикам, которые аутентифицируют запрос сами, и тестам
+func SetPrincipal(c *gin.Context, principal entity.Principal) {
+	c.Set(principalKey, principal)
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, entity.ErrorResponse{Error: message})
+}
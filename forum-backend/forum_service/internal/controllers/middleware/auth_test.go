@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/controllers/grpc"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestRouter(handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers = append(handlers, func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		c.JSON(http.StatusOK, gin.H{"authenticated": ok, "user_id": principal.UserID})
+	})
+	router.GET("/test", handlers...)
+	return router
+}
+
+func doRequest(router *gin.Engine, authHeader string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/test", nil)
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthMiddleware_RequireAuth_Success(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	mockValidator.On("ValidateToken", mock.Anything, "valid.jwt.token").
+		Return(entity.Principal{UserID: 7, Role: "user", Username: "alice", TokenID: 3}, nil)
+
+	w := doRequest(newTestRouter(authMiddleware.RequireAuth()), "Bearer valid.jwt.token")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"authenticated":true,"user_id":7}`, w.Body.String())
+	mockValidator.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_RequireAuth_MissingHeader(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	w := doRequest(newTestRouter(authMiddleware.RequireAuth()), "")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"Authorization header required"}`, w.Body.String())
+	mockValidator.AssertNotCalled(t, "ValidateToken", mock.Anything, mock.Anything)
+}
+
+func TestAuthMiddleware_RequireAuth_InvalidFormat(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	w := doRequest(newTestRouter(authMiddleware.RequireAuth()), "InvalidFormat")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"Invalid Authorization header format"}`, w.Body.String())
+	mockValidator.AssertNotCalled(t, "ValidateToken", mock.Anything, mock.Anything)
+}
+
+func TestAuthMiddleware_RequireAuth_InvalidToken(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	mockValidator.On("ValidateToken", mock.Anything, "revoked.jwt.token").
+		Return(entity.Principal{}, grpc.ErrInvalidToken)
+
+	w := doRequest(newTestRouter(authMiddleware.RequireAuth()), "Bearer revoked.jwt.token")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"Invalid or revoked token"}`, w.Body.String())
+	mockValidator.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_OptionalAuth_Anonymous(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	w := doRequest(newTestRouter(authMiddleware.OptionalAuth()), "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"authenticated":false,"user_id":0}`, w.Body.String())
+}
+
+func TestAuthMiddleware_OptionalAuth_InvalidToken(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	mockValidator.On("ValidateToken", mock.Anything, "bad.jwt.token").
+		Return(entity.Principal{}, grpc.ErrInvalidToken)
+
+	w := doRequest(newTestRouter(authMiddleware.OptionalAuth()), "Bearer bad.jwt.token")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"authenticated":false,"user_id":0}`, w.Body.String())
+	mockValidator.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_AuthServiceUnavailable(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	mockValidator.On("ValidateToken", mock.Anything, "valid.jwt.token").
+		Return(entity.Principal{}, status.Error(codes.Unavailable, "connection refused")).Once()
+	mockValidator.On("ValidateToken", mock.Anything, "valid.jwt.token").
+		Return(entity.Principal{}, status.Error(codes.DeadlineExceeded, "deadline exceeded")).Once()
+
+	// Сбой проверки не выдается за невалидный токен ни на закрытых, ни на публичных маршрутах
+	for _, handler := range []gin.HandlerFunc{authMiddleware.RequireAuth(), authMiddleware.OptionalAuth()} {
+		w := doRequest(newTestRouter(handler), "Bearer valid.jwt.token")
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.JSONEq(t, `{"error":"Authentication service unavailable"}`, w.Body.String())
+	}
+	mockValidator.AssertExpectations(t)
+}
+
+func TestRequireRole(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	mockValidator.On("ValidateToken", mock.Anything, "user.jwt.token").
+		Return(entity.Principal{UserID: 1, Role: "user"}, nil)
+	mockValidator.On("ValidateToken", mock.Anything, "admin.jwt.token").
+		Return(entity.Principal{UserID: 2, Role: "admin"}, nil)
+
+	router := newTestRouter(authMiddleware.RequireAuth(), RequireRole("admin"))
+
+	w := doRequest(router, "Bearer user.jwt.token")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":"Insufficient permissions"}`, w.Body.String())
+
+	w = doRequest(router, "Bearer admin.jwt.token")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequirePermission(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	mockValidator := new(mocks.TokenValidator)
+	authMiddleware := NewAuthMiddleware(mockValidator, logger)
+
+	mockValidator.On("ValidateToken", mock.Anything, "banned.jwt.token").
+		Return(entity.Principal{UserID: 1, Role: "user"}, nil)
+	mockValidator.On("ValidateToken", mock.Anything, "user.jwt.token").
+		Return(entity.Principal{UserID: 2, Role: "user", Permissions: []string{"comment.create", "post.create"}}, nil)
+
+	router := newTestRouter(authMiddleware.RequireAuth(), RequirePermission("post.create"))
+
+	w := doRequest(router, "Bearer banned.jwt.token")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":"Insufficient permissions"}`, w.Body.String())
+
+	w = doRequest(router, "Bearer user.jwt.token")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLogger - журнал запросов gin, в котором скрыт параметр token: WebSocket чата
+// принимает JWT в ?token=, и без этого токен попадал бы в журнал
+func AccessLogger() gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(param gin.LogFormatterParams) string {
+			return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+				param.TimeStamp.Format(time.RFC3339),
+				param.StatusCode,
+				param.Latency,
+				param.ClientIP,
+				param.Method,
+				redactToken(param.Path),
+				param.ErrorMessage,
+			)
+		},
+	})
+}
+
+// redactToken заменяет значение параметра token в пути с query
+func redactToken(path string) string {
+	base, rawQuery, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// Неразборный query не пишется в журнал целиком
+		return base
+	}
+	if !query.Has("token") {
+		return path
+	}
+	query.Set("token", "REDACTED")
+	return base + "?" + query.Encode()
+}
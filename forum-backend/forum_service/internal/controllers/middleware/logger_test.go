@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactToken(t *testing.T) {
+	assert.Equal(t, "/ws?room=1&token=REDACTED", redactToken("/ws?token=secret.jwt&room=1"))
+	assert.Equal(t, "/posts?page=2", redactToken("/posts?page=2"))
+	assert.Equal(t, "/ws", redactToken("/ws"))
+	assert.Equal(t, "/ws", redactToken("/ws?token=%zz"))
+}
+
+func TestAccessLogger_HidesToken(t *testing.T) {
+	var out bytes.Buffer
+	writer := gin.DefaultWriter
+	gin.DefaultWriter = &out
+	defer func() { gin.DefaultWriter = writer }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AccessLogger())
+	router.GET("/ws", func(c *gin.Context) { c.Status(http.StatusUnauthorized) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ws?token=secret.jwt", nil))
+
+	assert.Contains(t, out.String(), "/ws?token=REDACTED")
+	assert.NotContains(t, out.String(), "secret.jwt")
+}
@@ -0,0 +1,98 @@
+// Package diff строит построчную разницу между двумя текстами
+package diff
+
+import (
+	"strings"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+)
+
+// maxCells ограничивает размер таблицы LCS. Для текстов больше этого предела
+// дифф вырождается в "удалить все старое, вставить все новое"
+const maxCells = 4_000_000
+
+// Lines сравнивает тексты построчно (LCS) и возвращает строки с операциями
+// entity.DiffEqual, entity.DiffDelete и entity.DiffInsert
+func Lines(from, to string) []entity.DiffLine {
+	a := splitLines(from)
+	b := splitLines(to)
+
+	// общие начало и конец не участвуют в LCS
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]entity.DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		result = append(result, entity.DiffLine{Op: entity.DiffEqual, Text: line})
+	}
+	result = append(result, middle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, entity.DiffLine{Op: entity.DiffEqual, Text: line})
+	}
+	return result
+}
+
+func middle(a, b []string) []entity.DiffLine {
+	var result []entity.DiffLine
+	if len(a)*len(b) > maxCells {
+		for _, line := range a {
+			result = append(result, entity.DiffLine{Op: entity.DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			result = append(result, entity.DiffLine{Op: entity.DiffInsert, Text: line})
+		}
+		return result
+	}
+
+	// lcs[i][j] - длина общей подпоследовательности a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, entity.DiffLine{Op: entity.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, entity.DiffLine{Op: entity.DiffDelete, Text: a[i]})
+			i++
+		default:
+			result = append(result, entity.DiffLine{Op: entity.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, entity.DiffLine{Op: entity.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, entity.DiffLine{Op: entity.DiffInsert, Text: b[j]})
+	}
+	return result
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
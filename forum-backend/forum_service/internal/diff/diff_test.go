@@ -0,0 +1,51 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines_Changes(t *testing.T) {
+	result := Lines("first\nsecond\nthird\n", "first\nchanged\nthird\nfourth")
+
+	assert.Equal(t, []entity.DiffLine{
+		{Op: entity.DiffEqual, Text: "first"},
+		{Op: entity.DiffDelete, Text: "second"},
+		{Op: entity.DiffInsert, Text: "changed"},
+		{Op: entity.DiffEqual, Text: "third"},
+		{Op: entity.DiffInsert, Text: "fourth"},
+	}, result)
+}
+
+func TestLines_Identical(t *testing.T) {
+	result := Lines("a\r\nb", "a\nb")
+
+	assert.Equal(t, []entity.DiffLine{
+		{Op: entity.DiffEqual, Text: "a"},
+		{Op: entity.DiffEqual, Text: "b"},
+	}, result)
+}
+
+func TestLines_FromEmpty(t *testing.T) {
+	result := Lines("", "new")
+
+	assert.Equal(t, []entity.DiffLine{{Op: entity.DiffInsert, Text: "new"}}, result)
+}
+
+func TestLines_MovedLine(t *testing.T) {
+	result := Lines("a\nb\nc", "b\nc\na")
+
+	var deleted, inserted int
+	for _, line := range result {
+		switch line.Op {
+		case entity.DiffDelete:
+			deleted++
+		case entity.DiffInsert:
+			inserted++
+		}
+	}
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, 1, inserted)
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// AuthorCard - краткий профиль автора из auth_service для показа рядом с постами и комментариями
+type AuthorCard struct {
+	UserID       int       `json:"user_id"`
+	Username     string    `json:"username"`
+	Role         string    `json:"role"`
+	DisplayName  string    `json:"display_name"`
+	AvatarURL    string    `json:"avatar_url"`
+	Signature    string    `json:"signature"`
+	PostCount    int       `json:"post_count"`
+	CommentCount int       `json:"comment_count"`
+	JoinedAt     time.Time `json:"joined_at"`
+}
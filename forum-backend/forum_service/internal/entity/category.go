@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Category - раздел форума. PostCount и LastActivityAt считаются при выборке:
+// последняя активность - самый свежий пост или комментарий в разделе
+type Category struct {
+	ID             int        `json:"id" db:"id" example:"1"`
+	Slug           string     `json:"slug" db:"slug" example:"golang"`
+	Title          string     `json:"title" db:"title" example:"Go"`
+	Description    string     `json:"description" db:"description" example:"Все о Go"`
+	SortOrder      int        `json:"sort_order" db:"sort_order" example:"10"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	PostCount      int        `json:"post_count" db:"post_count" example:"42"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty" db:"last_activity_at"`
+}
+
+type CategoryRequest struct {
+	Slug        string `json:"slug" binding:"required" example:"golang"`
+	Title       string `json:"title" binding:"required" example:"Go"`
+	Description string `json:"description" example:"Все о Go"`
+	SortOrder   int    `json:"sort_order" example:"10"`
+}
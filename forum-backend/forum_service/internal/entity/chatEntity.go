@@ -4,8 +4,31 @@ import "time"
 
 type ChatMessage struct {
 	ID        int       `json:"id" db:"id"`
+	RoomID    int       `json:"room_id" db:"room_id"`
 	UserID    int       `json:"userID" db:"user_id"`
 	Username  string    `json:"username" db:"username"`
 	Content   string    `json:"content" db:"content"`
 	Timestamp time.Time `json:"timestamp" db:"timestamp"`
+	// ClientID - id, который сгенерировал клиент при отправке, см. ChatFrame
+	ClientID string `json:"client_id,omitempty" db:"client_id"`
+	// Reactions заполняется в истории GET /chat/messages
+	Reactions []ReactionCount `json:"reactions,omitempty" db:"-"`
+}
+
+// Действия в ReactionEvent
+const (
+	ReactionAdded   = "added"
+	ReactionRemoved = "removed"
+)
+
+// ReactionEvent - кадр, который хаб рассылает клиентам чата, когда на сообщение
+// поставили или сняли реакцию. Count - число реакций этим эмодзи после изменения
+type ReactionEvent struct {
+	V         int    `json:"v" example:"1"`
+	Type      string `json:"type" example:"reaction"`
+	MessageID int    `json:"message_id" example:"1"`
+	UserID    int    `json:"user_id" example:"1"`
+	Emoji     string `json:"emoji" example:"👍"`
+	Action    string `json:"action" example:"added"`
+	Count     int    `json:"count" example:"2"`
 }
@@ -0,0 +1,121 @@
+package entity
+
+import "time"
+
+// ChatProtocolVersion - версия протокола кадров WebSocket чата. Сервер пишет ее в
+// поле v каждого кадра, кадр клиента без v считается кадром текущей версии
+const ChatProtocolVersion = 1
+
+// Типы кадров
+const (
+	// Клиент и сервер
+	FrameMessage       = "message"
+	FrameDirectMessage = "dm"
+	FrameTyping        = "typing"
+	// Только клиент
+	FrameAuth           = "auth"
+	FrameHistoryRequest = "history_request"
+	// Только сервер
+	FramePresence   = "presence"
+	FrameAck        = "ack"
+	FrameError      = "error"
+	FrameHistory    = "history"
+	FrameReaction   = "reaction"
+	FrameDirectRead = "dm_read"
+)
+
+// Коды ошибок в кадре error
+const (
+	ChatErrBadFrame           = "bad_frame"
+	ChatErrUnsupportedVersion = "unsupported_version"
+	ChatErrUnknownType        = "unknown_type"
+	ChatErrReadOnly           = "read_only"
+	ChatErrNotSubscribed      = "not_subscribed"
+	ChatErrInvalid            = "invalid"
+	ChatErrInternal           = "internal"
+)
+
+// Статусы в кадре presence
+const (
+	PresenceOnline  = "online"
+	PresenceOffline = "offline"
+)
+
+// ChatFrame - кадр, который клиент отправляет по WebSocket. Какие поля нужны, зависит от Type:
+// message - RoomID, Content; dm - To, Content; typing - RoomID; auth - Token и Rooms -
+// закрытые комнаты, недоступные гостю при подключении; history_request - RoomID, Before, Limit. ClientID - id, который клиент генерирует
+// для message и dm: повторный кадр с тем же ClientID не создает второе сообщение
+type ChatFrame struct {
+	V        int    `json:"v" example:"1"`
+	Type     string `json:"type" example:"message"`
+	ClientID string `json:"client_id,omitempty" example:"6f1c2a"`
+	RoomID   int    `json:"room_id,omitempty" example:"1"`
+	To       int    `json:"to,omitempty" example:"2"`
+	Content  string `json:"content,omitempty" example:"Привет!"`
+	Token    string `json:"token,omitempty"`
+	Rooms    []int  `json:"rooms,omitempty" example:"3"`
+	Before   string `json:"before,omitempty"`
+	Limit    int    `json:"limit,omitempty" example:"50"`
+}
+
+// ChatMessageEvent - сообщение комнаты. Так же приходит история при подключении
+type ChatMessageEvent struct {
+	V    int    `json:"v" example:"1"`
+	Type string `json:"type" example:"message"`
+	ChatMessage
+}
+
+// ChatAck подтверждает, что сообщение из кадра ClientID сохранено. Duplicate - кадр с этим
+// ClientID уже приходил, ID и Timestamp относятся к сохраненному тогда сообщению
+type ChatAck struct {
+	V         int       `json:"v" example:"1"`
+	Type      string    `json:"type" example:"ack"`
+	ClientID  string    `json:"client_id,omitempty" example:"6f1c2a"`
+	ID        int       `json:"id" example:"42"`
+	Timestamp time.Time `json:"timestamp"`
+	Duplicate bool      `json:"duplicate" example:"false"`
+}
+
+// ChatError - ошибка обработки кадра клиента. ClientID - из кадра, если он был
+type ChatError struct {
+	V        int    `json:"v" example:"1"`
+	Type     string `json:"type" example:"error"`
+	ClientID string `json:"client_id,omitempty" example:"6f1c2a"`
+	Code     string `json:"code" example:"unknown_type"`
+	Error    string `json:"error" example:"unknown frame type \"foo\""`
+}
+
+// ChatHistoryEvent - ответ на history_request. NextCursor указывает на более ранние сообщения, nil - история закончилась
+type ChatHistoryEvent struct {
+	V          int           `json:"v" example:"1"`
+	Type       string        `json:"type" example:"history"`
+	RoomID     int           `json:"room_id" example:"1"`
+	Messages   []ChatMessage `json:"messages"`
+	NextCursor *string       `json:"next_cursor"`
+}
+
+// ChatTypingEvent - пользователь набирает сообщение в комнате. Кадры typing не сохраняются
+type ChatTypingEvent struct {
+	V        int    `json:"v" example:"1"`
+	Type     string `json:"type" example:"typing"`
+	RoomID   int    `json:"room_id" example:"1"`
+	UserID   int    `json:"user_id" example:"1"`
+	Username string `json:"username" example:"john_doe"`
+}
+
+// ChatPresenceEvent - пользователь появился в чате (первое соединение) или ушел (закрыто последнее)
+type ChatPresenceEvent struct {
+	V        int    `json:"v" example:"1"`
+	Type     string `json:"type" example:"presence"`
+	UserID   int    `json:"user_id" example:"1"`
+	Username string `json:"username" example:"john_doe"`
+	Status   string `json:"status" example:"online"`
+}
+
+// OnlineUser - пользователь, у которого открыто хотя бы одно соединение с чатом
+type OnlineUser struct {
+	UserID      int       `json:"user_id" example:"1"`
+	Username    string    `json:"username" example:"john_doe"`
+	Connections int       `json:"connections" example:"2"`
+	Since       time.Time `json:"since"`
+}
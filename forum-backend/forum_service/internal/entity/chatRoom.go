@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// DefaultChatRoomID - общая публичная комната, она создается миграцией
+const DefaultChatRoomID = 1
+
+// Роли участника комнаты
+const (
+	ChatRoleOwner  = "owner"
+	ChatRoleMember = "member"
+)
+
+// ChatRoom - комната чата. MemberCount считается при выборке
+type ChatRoom struct {
+	ID          int       `json:"id" db:"id" example:"1"`
+	Name        string    `json:"name" db:"name" example:"general"`
+	IsPrivate   bool      `json:"is_private" db:"is_private" example:"false"`
+	OwnerID     int       `json:"owner_id" db:"owner_id" example:"1"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	MemberCount int       `json:"member_count" db:"member_count" example:"3"`
+}
+
+type ChatRoomMember struct {
+	RoomID   int       `json:"room_id" db:"room_id" example:"1"`
+	UserID   int       `json:"user_id" db:"user_id" example:"1"`
+	Role     string    `json:"role" db:"role" example:"member"`
+	JoinedAt time.Time `json:"joined_at" db:"joined_at"`
+}
+
+type ChatRoomRequest struct {
+	Name      string `json:"name" binding:"required" example:"golang"`
+	IsPrivate bool   `json:"is_private" example:"false"`
+}
+
+type ChatRoomMemberRequest struct {
+	UserID int `json:"user_id" binding:"required" example:"2"`
+}
@@ -2,10 +2,35 @@ package entity
 
 import "time"
 
+// DeletedCommentContent - текст, который показывается вместо удаленного комментария
+const DeletedCommentContent = "[deleted]"
+
 type Comment struct {
-	ID        int       `json:"id" db:"id" exmaple:"1"`
-	AuthorId  int       `json:"author_id" db:"author_id" exmaple:"1"`
-	PostId    int       `json:"post_id" db:"post_id" exmaple:"1"`
-	Content   string    `json:"content" db:"content" exmaple:"текст комментария"`
-	CreatedAt time.Time `json:"created_at" exmaple:"22:00"`
+	ID        int        `json:"id" db:"id" exmaple:"1"`
+	AuthorId  int        `json:"author_id" db:"author_id" exmaple:"1"`
+	PostId    int        `json:"post_id" db:"post_id" exmaple:"1"`
+	ParentId  *int       `json:"parent_id,omitempty" db:"parent_id" example:"1"`
+	Depth     int        `json:"depth" db:"depth" example:"0"`
+	Content   string     `json:"content" db:"content" exmaple:"текст комментария"`
+	CreatedAt time.Time  `json:"created_at" exmaple:"22:00"`
+	EditedAt  *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	Score     int        `json:"score" db:"score" example:"3"`
+}
+
+// IsDeleted сообщает, что от комментария остался только "надгробный камень"
+func (c Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// CommentNode - комментарий в дереве обсуждения. ReplyCount - число прямых ответов,
+// Replies может быть пустым, если поддерево не загружено (см. GET /comments/{id}/replies).
+// MyVote заполняется только для аутентифицированного пользователя
+type CommentNode struct {
+	Comment
+	Username   string          `json:"username" example:"user"`
+	MyVote     *int            `json:"my_vote,omitempty" example:"1"`
+	Reactions  []ReactionCount `json:"reactions"`
+	ReplyCount int             `json:"reply_count" example:"2"`
+	Replies    []*CommentNode  `json:"replies"`
 }
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor - позиция в ленте, упорядоченной по (created_at, id). Следующая страница
+// начинается сразу после записи с этими значениями
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// CursorAfter возвращает курсор, указывающий на запись
+func CursorAfter(createdAt time.Time, id int) *Cursor {
+	return &Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// Encode возвращает непрозрачную строку для клиента
+func (c Cursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor разбирает строку, полученную из Encode
+func DecodeCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var nanos int64
+	var id int
+	if n, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil || n != 2 || id <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{CreatedAt: time.Unix(0, nanos), ID: id}, nil
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// DirectMessage - личное сообщение. ReadAt = nil, пока получатель его не прочитал
+type DirectMessage struct {
+	ID          int        `json:"id" db:"id" example:"1"`
+	SenderID    int        `json:"sender_id" db:"sender_id" example:"1"`
+	RecipientID int        `json:"recipient_id" db:"recipient_id" example:"2"`
+	Content     string     `json:"content" db:"content" example:"Привет!"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ReadAt      *time.Time `json:"read_at" db:"read_at"`
+	ClientID    string     `json:"client_id,omitempty" db:"client_id"`
+}
+
+// Conversation - переписка с пользователем UserID: последнее сообщение и число
+// непрочитанных сообщений от него
+type Conversation struct {
+	UserID      int           `json:"user_id" example:"2"`
+	Username    string        `json:"username" example:"john_doe"`
+	LastMessage DirectMessage `json:"last_message"`
+	UnreadCount int           `json:"unread_count" example:"3"`
+}
+
+// DirectMessageEvent - кадр, который хаб доставляет всем клиентам отправителя и
+// получателя. Клиент отправляет личное сообщение кадром {"type":"dm","to":2,"content":"..."}
+type DirectMessageEvent struct {
+	V    int    `json:"v" example:"1"`
+	Type string `json:"type" example:"dm"`
+	DirectMessage
+	Username string `json:"username" example:"john_doe"`
+}
+
+// DirectReadEvent - кадр для клиентов отправителя: UserID прочитал Count его сообщений
+type DirectReadEvent struct {
+	V      int    `json:"v" example:"1"`
+	Type   string `json:"type" example:"dm_read"`
+	UserID int    `json:"user_id" example:"2"`
+	Count  int    `json:"count" example:"3"`
+}
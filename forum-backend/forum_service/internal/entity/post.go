@@ -1,8 +1,49 @@
 package entity
 
+import "time"
+
 type Post struct {
-	ID       int    `json:"id" db:"id" example:"1" `
-	AuthorId int    `json:"author_id" db:"author_id" example:"1" `
-	Title    string `json:"title" db:"title" example:"Заголовк"`
-	Content  string `json:"content" db:"content" example:"Текст"`
+	ID            int        `json:"id" db:"id" example:"1" `
+	AuthorId      int        `json:"author_id" db:"author_id" example:"1" `
+	Title         string     `json:"title" db:"title" example:"Заголовк"`
+	Content       string     `json:"content" db:"content" example:"Текст"`
+	CategoryId    *int       `json:"category_id,omitempty" db:"category_id" example:"1"`
+	Tags          []string   `json:"tags" db:"-" example:"go,grpc"`
+	Score         int        `json:"score" db:"score" example:"5"`
+	CommentCount  int        `json:"comment_count" db:"comment_count" example:"3"`
+	LastCommentAt *time.Time `json:"last_comment_at,omitempty" db:"last_comment_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Сортировки ленты постов
+const (
+	PostSortNew       = "new"       // новые первыми
+	PostSortOld       = "old"       // старые первыми
+	PostSortTop       = "top"       // по рейтингу
+	PostSortActive    = "active"    // по времени последнего комментария
+	PostSortCommented = "commented" // по числу комментариев
+)
+
+// PostFilter - фильтры и сортировка ленты постов
+type PostFilter struct {
+	Sort         string
+	AuthorID     int
+	Since        *time.Time
+	Until        *time.Time
+	Tags         []string
+	MatchAllTags bool
+	// After - курсор, поддерживается только для PostSortNew и PostSortOld
+	After *Cursor
+}
+
+// TagCount - тег и число постов с ним
+type TagCount struct {
+	Name  string `json:"name" example:"go"`
+	Count int    `json:"count" example:"12"`
+}
+
+// SortedByTime - лента упорядочена по времени создания, и для нее работает курсор
+func (f PostFilter) SortedByTime() bool {
+	return f.Sort == "" || f.Sort == PostSortNew || f.Sort == PostSortOld
 }
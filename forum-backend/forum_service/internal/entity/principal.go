@@ -0,0 +1,10 @@
+package entity
+
+// Principal - аутентифицированный пользователь текущего запроса
+type Principal struct {
+	UserID      int      `json:"user_id"`
+	Role        string   `json:"role"`
+	Username    string   `json:"username"`
+	TokenID     int      `json:"token_id"`
+	Permissions []string `json:"permissions"`
+}
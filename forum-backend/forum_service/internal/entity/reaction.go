@@ -0,0 +1,32 @@
+package entity
+
+// Типы целей реакций. Посты и комментарии используют те же значения, что и голоса
+const (
+	ReactionTargetPost        = "post"
+	ReactionTargetComment     = "comment"
+	ReactionTargetChatMessage = "chat_message"
+)
+
+// Reaction - реакция пользователя эмодзи на пост, комментарий или сообщение чата
+type Reaction struct {
+	UserID     int    `json:"user_id"`
+	TargetType string `json:"target_type"`
+	TargetID   int    `json:"target_id"`
+	Emoji      string `json:"emoji"`
+}
+
+// ReactionCount - сколько раз цель отметили эмодзи и есть ли среди них отметка текущего пользователя
+type ReactionCount struct {
+	Emoji       string `json:"emoji" example:"👍"`
+	Count       int    `json:"count" example:"3"`
+	ReactedByMe bool   `json:"reacted_by_me" example:"true"`
+}
+
+// ReactionResult - реакции на цель после изменения. Emoji - поставленный или снятый эмодзи
+// в виде из конфига
+type ReactionResult struct {
+	TargetType string          `json:"target_type" example:"post"`
+	TargetID   int             `json:"target_id" example:"1"`
+	Emoji      string          `json:"emoji" example:"👍"`
+	Reactions  []ReactionCount `json:"reactions"`
+}
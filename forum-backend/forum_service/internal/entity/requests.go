@@ -5,3 +5,12 @@ type WSAuthRequest struct {
 	UserID string `form:"userID" binding:"required" example:"123"`
 	Auth   string `form:"auth" binding:"required" example:"true"`
 }
+
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required" example:"исправленный текст"`
+}
+
+// VoteRequest - голос за пост или комментарий: 1, -1 или 0, чтобы снять голос
+type VoteRequest struct {
+	Value *int `json:"value" binding:"required" example:"1"`
+}
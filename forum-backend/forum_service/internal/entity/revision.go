@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// PostRevision - одна версия поста. EditorId и CreatedAt - кто и когда получил эту версию,
+// для первой версии это автор и время создания поста
+type PostRevision struct {
+	ID        int       `json:"id" db:"id" example:"1"`
+	PostId    int       `json:"post_id" db:"post_id" example:"1"`
+	Revision  int       `json:"revision" db:"revision" example:"2"`
+	Title     string    `json:"title" db:"title" example:"Заголовок"`
+	Content   string    `json:"content" db:"content" example:"Текст"`
+	EditorId  int       `json:"editor_id" db:"editor_id" example:"1"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Операции строки диффа
+const (
+	DiffEqual  = "="
+	DiffDelete = "-"
+	DiffInsert = "+"
+)
+
+type DiffLine struct {
+	Op   string `json:"op" example:"+"`
+	Text string `json:"text" example:"добавленная строка"`
+}
+
+// RevisionDiff - построчная разница между двумя версиями поста
+type RevisionDiff struct {
+	PostId  int        `json:"post_id" example:"1"`
+	From    int        `json:"from" example:"1"`
+	To      int        `json:"to" example:"2"`
+	Title   []DiffLine `json:"title"`
+	Content []DiffLine `json:"content"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// Типы результатов поиска
+const (
+	SearchTypePost    = "post"
+	SearchTypeComment = "comment"
+)
+
+// SearchFilter - параметры полнотекстового поиска. Нулевые AuthorID, Since и Until не ограничивают выборку
+type SearchFilter struct {
+	Query    string
+	AuthorID int
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+	Offset   int
+}
+
+// SearchResult - найденный пост или комментарий. Title и Snippet - экранированный HTML,
+// совпадения обернуты в <mark>
+type SearchResult struct {
+	Type      string    `json:"type" example:"post"`
+	PostId    int       `json:"post_id" example:"1"`
+	CommentId *int      `json:"comment_id,omitempty" example:"3"`
+	AuthorId  int       `json:"author_id" example:"1"`
+	Username  string    `json:"username,omitempty" example:"user"`
+	Title     string    `json:"title" example:"Про <mark>go</mark>"`
+	Snippet   string    `json:"snippet" example:"…пишем на <mark>go</mark>…"`
+	Score     float64   `json:"score" example:"1.5"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+// Типы целей голосования
+const (
+	VoteTargetPost    = "post"
+	VoteTargetComment = "comment"
+)
+
+// Vote - голос пользователя. Value равен 1 или -1, 0 означает "голоса нет"
+type Vote struct {
+	UserID     int    `json:"user_id"`
+	TargetType string `json:"target_type"`
+	TargetID   int    `json:"target_id"`
+	Value      int    `json:"value"`
+}
+
+// VoteResult - рейтинг цели после голосования и голос текущего пользователя
+type VoteResult struct {
+	TargetType string `json:"target_type" example:"post"`
+	TargetID   int    `json:"target_id" example:"1"`
+	Score      int    `json:"score" example:"5"`
+	MyVote     int    `json:"my_vote" example:"1"`
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+)
+
+// ErrDuplicate - нарушено ограничение уникальности
+var ErrDuplicate = errors.New("duplicate record")
+
+type CategoryRepository interface {
+	// CreateCategory и UpdateCategory возвращают ErrDuplicate, если slug занят
+	CreateCategory(ctx context.Context, category entity.Category) (*entity.Category, error)
+	// GetCategories возвращает все категории в порядке sort_order со счетчиками постов
+	GetCategories(ctx context.Context) ([]entity.Category, error)
+	GetCategoryByID(ctx context.Context, id int) (*entity.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	// UpdateCategory меняет slug, название, описание и порядок. Если категории нет - sql.ErrNoRows
+	UpdateCategory(ctx context.Context, category entity.Category) error
+	// DeleteCategory удаляет категорию, ее посты остаются без категории. Если категории нет - sql.ErrNoRows
+	DeleteCategory(ctx context.Context, id int) error
+}
+
+// categorySelect выбирает категории вместе с числом постов и временем последнего
+// поста и комментария. Подзапросы возвращают сами колонки created_at, а не агрегат,
+// чтобы драйвер разобрал их как DATETIME
+const categorySelect = `
+	SELECT c.id, c.slug, c.title, c.description, c.sort_order, c.created_at,
+	       (SELECT COUNT(*) FROM posts p WHERE p.category_id = c.id) AS post_count,
+	       (SELECT p.created_at FROM posts p WHERE p.category_id = c.id
+	        ORDER BY p.created_at DESC LIMIT 1) AS last_post_at,
+	       (SELECT cm.created_at FROM comments cm JOIN posts p ON p.id = cm.post_id
+	        WHERE p.category_id = c.id ORDER BY cm.created_at DESC LIMIT 1) AS last_comment_at
+	FROM categories c`
+
+type categoryRepository struct {
+	db     DB
+	logger *zap.Logger
+}
+
+func NewCategoryRepository(db DB, logger *zap.Logger) CategoryRepository {
+	return &categoryRepository{db: db, logger: logger}
+}
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, category entity.Category) (*entity.Category, error) {
+	category.CreatedAt = time.Now().UTC()
+	query := `INSERT INTO categories (slug, title, description, sort_order, created_at) VALUES (?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, category.Slug, category.Title, category.Description, category.SortOrder, category.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create category", zap.Error(err), zap.String("slug", category.Slug))
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logger.Error("Failed to get last insert ID", zap.Error(err))
+		return nil, err
+	}
+
+	category.ID = int(id)
+	r.logger.Info("Category created successfully", zap.Int("categoryID", category.ID), zap.String("slug", category.Slug))
+	return &category, nil
+}
+
+func (r *categoryRepository) GetCategories(ctx context.Context) ([]entity.Category, error) {
+	rows, err := r.db.QueryContext(ctx, categorySelect+` ORDER BY c.sort_order, c.title`)
+	if err != nil {
+		r.logger.Error("Failed to get categories", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []entity.Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan category", zap.Error(err))
+			return nil, err
+		}
+		categories = append(categories, *category)
+	}
+	return categories, rows.Err()
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, id int) (*entity.Category, error) {
+	category, err := scanCategory(r.db.QueryRowContext(ctx, categorySelect+` WHERE c.id = ?`, id))
+	if err != nil && err != sql.ErrNoRows {
+		r.logger.Error("Failed to get category by ID", zap.Error(err), zap.Int("categoryID", id))
+	}
+	return category, err
+}
+
+func (r *categoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	category, err := scanCategory(r.db.QueryRowContext(ctx, categorySelect+` WHERE c.slug = ?`, slug))
+	if err != nil && err != sql.ErrNoRows {
+		r.logger.Error("Failed to get category by slug", zap.Error(err), zap.String("slug", slug))
+	}
+	return category, err
+}
+
+func (r *categoryRepository) UpdateCategory(ctx context.Context, category entity.Category) error {
+	query := `UPDATE categories SET slug = ?, title = ?, description = ?, sort_order = ? WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, category.Slug, category.Title, category.Description, category.SortOrder, category.ID)
+	if err != nil {
+		r.logger.Error("Failed to update category", zap.Error(err), zap.Int("categoryID", category.ID))
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	r.logger.Info("Category updated successfully", zap.Int("categoryID", category.ID))
+	return nil
+}
+
+func (r *categoryRepository) DeleteCategory(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Failed to begin transaction", zap.Error(err), zap.Int("categoryID", id))
+		return err
+	}
+	defer tx.Rollback()
+
+	// ON DELETE SET NULL работает только с включенным PRAGMA foreign_keys, поэтому посты отвязываем явно
+	if _, err := tx.ExecContext(ctx, `UPDATE posts SET category_id = NULL WHERE category_id = ?`, id); err != nil {
+		r.logger.Error("Failed to detach category posts", zap.Error(err), zap.Int("categoryID", id))
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = ?`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete category", zap.Error(err), zap.Int("categoryID", id))
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Failed to commit category delete", zap.Error(err), zap.Int("categoryID", id))
+		return err
+	}
+	r.logger.Info("Category deleted successfully", zap.Int("categoryID", id))
+	return nil
+}
+
+func scanCategory(row rowScanner) (*entity.Category, error) {
+	var category entity.Category
+	var lastPostAt, lastCommentAt sql.NullTime
+	err := row.Scan(&category.ID, &category.Slug, &category.Title, &category.Description, &category.SortOrder,
+		&category.CreatedAt, &category.PostCount, &lastPostAt, &lastCommentAt)
+	if err != nil {
+		return nil, err
+	}
+	if lastPostAt.Valid {
+		category.LastActivityAt = &lastPostAt.Time
+	}
+	if lastCommentAt.Valid && (category.LastActivityAt == nil || lastCommentAt.Time.After(*category.LastActivityAt)) {
+		category.LastActivityAt = &lastCommentAt.Time
+	}
+	return &category, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
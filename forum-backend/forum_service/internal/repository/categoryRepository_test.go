@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository/adapters"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCategoryRepository_GetCategories_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	categoryRepo := NewCategoryRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastPost := created.Add(time.Hour)
+	lastComment := created.Add(2 * time.Hour)
+
+	mock.ExpectQuery(`SELECT c.id, c.slug, c.title, .* FROM categories c ORDER BY c.sort_order, c.title`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug", "title", "description", "sort_order", "created_at", "post_count", "last_post_at", "last_comment_at"}).
+			AddRow(1, "news", "News", "", 1, created, 0, nil, nil).
+			AddRow(2, "golang", "Go", "Все о Go", 2, created, 3, lastPost, lastComment))
+
+	result, err := categoryRepo.GetCategories(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Category{
+		{ID: 1, Slug: "news", Title: "News", SortOrder: 1, CreatedAt: created},
+		{ID: 2, Slug: "golang", Title: "Go", Description: "Все о Go", SortOrder: 2, CreatedAt: created, PostCount: 3, LastActivityAt: &lastComment},
+	}, result)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_CreateCategory_Duplicate(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	categoryRepo := NewCategoryRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectExec(`INSERT INTO categories \(slug, title, description, sort_order, created_at\)`).
+		WithArgs("golang", "Go", "", 0, sqlmock.AnyArg()).
+		WillReturnError(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique})
+
+	result, err := categoryRepo.CreateCategory(context.Background(), entity.Category{Slug: "golang", Title: "Go"})
+
+	assert.ErrorIs(t, err, ErrDuplicate)
+	assert.Nil(t, result)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_DeleteCategory_DetachesPosts(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	categoryRepo := NewCategoryRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE posts SET category_id = NULL WHERE category_id = \?`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM categories WHERE id = \?`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = categoryRepo.DeleteCategory(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_DeleteCategory_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	categoryRepo := NewCategoryRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE posts SET category_id = NULL`).WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM categories WHERE id = \?`).WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = categoryRepo.DeleteCategory(context.Background(), 9)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -17,13 +17,23 @@ type DB interface {
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
 }
 
 type ChatRepository interface {
-	StoreMessage(ctx context.Context, msg entity.ChatMessage) error
-	GetRecentMessages(ctx context.Context, limit int) ([]entity.ChatMessage, error)
+	// StoreMessage сохраняет сообщение. Если у автора уже есть сообщение с тем же ClientID,
+	// новое не сохраняется, возвращается сохраненное и created = false
+	StoreMessage(ctx context.Context, msg entity.ChatMessage) (stored *entity.ChatMessage, created bool, err error)
+	GetRecentMessages(ctx context.Context, roomID, limit int) ([]entity.ChatMessage, error)
+	// GetMessagesBefore возвращает limit сообщений комнаты, отправленных раньше курсора, в
+	// хронологическом порядке. Без курсора - последние сообщения
+	GetMessagesBefore(ctx context.Context, roomID int, before *entity.Cursor, limit int) ([]entity.ChatMessage, error)
+	// GetMessageByID возвращает сообщение, если его нет - sql.ErrNoRows
+	GetMessageByID(ctx context.Context, id int) (*entity.ChatMessage, error)
 }
 
+const chatMessageColumns = `id, room_id, user_id, username, content, timestamp, COALESCE(client_id, '') AS client_id`
+
 type chatRepo struct {
 	db     DB
 	logger *zap.Logger
@@ -33,33 +43,69 @@ func NewChatRepository(db DB, logger *zap.Logger) ChatRepository {
 	return &chatRepo{db: db, logger: logger}
 }
 
-func (r *chatRepo) StoreMessage(ctx context.Context, msg entity.ChatMessage) error {
+func (r *chatRepo) StoreMessage(ctx context.Context, msg entity.ChatMessage) (*entity.ChatMessage, bool, error) {
 	r.logger.Info("Saving message",
+		zap.Int("roomID", msg.RoomID),
 		zap.Int("userID", msg.UserID),
 		zap.String("username", msg.Username),
 		zap.String("content", msg.Content),
 		zap.Time("timestamp", msg.Timestamp),
+		zap.String("clientID", msg.ClientID),
 	)
 
-	query := `INSERT INTO chat_messages (user_id, username, content, timestamp) VALUES (?, ?, ?, ?)`
-	_, err := r.db.ExecContext(ctx, query, msg.UserID, msg.Username, msg.Content, msg.Timestamp.Format(time.RFC3339))
+	query := `
+        INSERT INTO chat_messages (room_id, user_id, username, content, timestamp, client_id)
+        VALUES (?, ?, ?, ?, ?, ?)
+        ON CONFLICT DO NOTHING`
+	result, err := r.db.ExecContext(ctx, query, msg.RoomID, msg.UserID, msg.Username, msg.Content,
+		msg.Timestamp.Format(time.RFC3339), nullIfEmpty(msg.ClientID))
 	if err != nil {
 		r.logger.Error("Failed to store message", zap.Error(err))
-		return err
+		return nil, false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if affected == 0 {
+		// Повторная отправка: ON CONFLICT сработал на уникальном (user_id, client_id)
+		var existing entity.ChatMessage
+		query = `SELECT ` + chatMessageColumns + ` FROM chat_messages WHERE user_id = ? AND client_id = ?`
+		if err := r.db.GetContext(ctx, &existing, query, msg.UserID, msg.ClientID); err != nil {
+			r.logger.Error("Failed to get duplicate message", zap.Error(err), zap.String("clientID", msg.ClientID))
+			return nil, false, err
+		}
+		return &existing, false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logger.Error("Failed to get last insert ID", zap.Error(err))
+		return nil, false, err
 	}
-	return nil
+	msg.ID = int(id)
+	return &msg, true, nil
 }
 
-func (r *chatRepo) GetRecentMessages(ctx context.Context, limit int) ([]entity.ChatMessage, error) {
+// nullIfEmpty сохраняет пустую строку как NULL
+func nullIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (r *chatRepo) GetRecentMessages(ctx context.Context, roomID, limit int) ([]entity.ChatMessage, error) {
 	query := `
-        SELECT id, user_id, username, content,
-               timestamp
+        SELECT ` + chatMessageColumns + `
         FROM chat_messages
+        WHERE room_id = ?
         ORDER BY timestamp DESC
         LIMIT ?`
 
 	var messages []entity.ChatMessage
-	err := r.db.SelectContext(ctx, &messages, query, limit)
+	err := r.db.SelectContext(ctx, &messages, query, roomID, limit)
 	if err != nil {
 		r.logger.Error("Failed to get recent messages", zap.Error(err))
 		return nil, err
@@ -72,3 +118,40 @@ func (r *chatRepo) GetRecentMessages(ctx context.Context, limit int) ([]entity.C
 	r.logger.Info("Recent messages retrieved successfully", zap.Int("count", len(messages)))
 	return messages, nil
 }
+
+func (r *chatRepo) GetMessagesBefore(ctx context.Context, roomID int, before *entity.Cursor, limit int) ([]entity.ChatMessage, error) {
+	where, args := "room_id = ?", []any{roomID}
+	if before != nil {
+		// timestamp хранится в RFC3339 в локальной зоне сервера, как его пишет StoreMessage
+		cond, cursorArgs := keysetBefore("timestamp", "id", before.CreatedAt.Local().Format(time.RFC3339), before)
+		where, args = where+" AND "+cond, append(args, cursorArgs...)
+	}
+	query := `
+        SELECT ` + chatMessageColumns + `
+        FROM chat_messages
+        WHERE ` + where + `
+        ORDER BY timestamp DESC, id DESC
+        LIMIT ?`
+
+	var messages []entity.ChatMessage
+	err := r.db.SelectContext(ctx, &messages, query, append(args, limit)...)
+	if err != nil {
+		r.logger.Error("Failed to get chat history", zap.Error(err))
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+func (r *chatRepo) GetMessageByID(ctx context.Context, id int) (*entity.ChatMessage, error) {
+	query := `SELECT ` + chatMessageColumns + ` FROM chat_messages WHERE id = ?`
+
+	var message entity.ChatMessage
+	if err := r.db.GetContext(ctx, &message, query, id); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
@@ -2,11 +2,11 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/mocks"
 	"github.com/stretchr/testify/assert"
@@ -23,17 +23,22 @@ func TestChatRepo_StoreMessage_Success(t *testing.T) {
 	chatRepo := NewChatRepository(mockDB, logger)
 
 	msg := entity.ChatMessage{
+		RoomID:    1,
 		UserID:    1,
 		Username:  "testuser",
 		Content:   "This is a test message",
 		Timestamp: time.Date(2025, time.April, 22, 23, 51, 38, 843016900, time.Local),
+		ClientID:  "c-1",
 	}
 
-	mockDB.On("ExecContext", mock.Anything, mock.Anything, msg.UserID, msg.Username, msg.Content, msg.Timestamp.Format(time.RFC3339)).Return(sql.Result(nil), nil)
+	mockDB.On("ExecContext", mock.Anything, mock.Anything, msg.RoomID, msg.UserID, msg.Username, msg.Content, msg.Timestamp.Format(time.RFC3339), "c-1").Return(sqlmock.NewResult(7, 1), nil)
 
-	err := chatRepo.StoreMessage(context.Background(), msg)
+	stored, created, err := chatRepo.StoreMessage(context.Background(), msg)
 
 	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, 7, stored.ID)
+	assert.Equal(t, "c-1", stored.ClientID)
 
 	mockDB.AssertExpectations(t)
 }
@@ -47,21 +52,48 @@ func TestChatRepo_StoreMessage_Failure(t *testing.T) {
 	chatRepo := NewChatRepository(mockDB, logger)
 
 	msg := entity.ChatMessage{
+		RoomID:    1,
 		UserID:    1,
 		Username:  "testuser",
 		Content:   "This is a test message",
 		Timestamp: time.Now(),
 	}
 
-	mockDB.On("ExecContext", mock.Anything, mock.Anything, msg.UserID, msg.Username, msg.Content, msg.Timestamp.Format(time.RFC3339)).Return(nil, errors.New("failed to store message"))
+	mockDB.On("ExecContext", mock.Anything, mock.Anything, msg.RoomID, msg.UserID, msg.Username, msg.Content, msg.Timestamp.Format(time.RFC3339), nil).Return(nil, errors.New("failed to store message"))
 
-	err := chatRepo.StoreMessage(context.Background(), msg)
+	_, _, err := chatRepo.StoreMessage(context.Background(), msg)
 
 	assert.Error(t, err)
 
 	mockDB.AssertExpectations(t)
 }
 
+func TestChatRepo_StoreMessage_Duplicate(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	chatRepo := NewChatRepository(mockDB, logger)
+
+	msg := entity.ChatMessage{RoomID: 1, UserID: 1, Username: "testuser", Content: "again", Timestamp: time.Now(), ClientID: "c-1"}
+
+	mockDB.On("ExecContext", mock.Anything, mock.Anything, msg.RoomID, msg.UserID, msg.Username, msg.Content, msg.Timestamp.Format(time.RFC3339), "c-1").Return(sqlmock.NewResult(0, 0), nil)
+	mockDB.On("GetContext", mock.Anything, mock.AnythingOfType("*entity.ChatMessage"), mock.Anything, 1, "c-1").
+		Run(func(args mock.Arguments) {
+			*args.Get(1).(*entity.ChatMessage) = entity.ChatMessage{ID: 3, RoomID: 1, UserID: 1, Content: "first", ClientID: "c-1"}
+		}).Return(nil)
+
+	stored, created, err := chatRepo.StoreMessage(context.Background(), msg)
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, 3, stored.ID)
+	assert.Equal(t, "first", stored.Content)
+
+	mockDB.AssertExpectations(t)
+}
+
 func TestChatRepo_GetRecentMessages_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
@@ -76,12 +108,12 @@ func TestChatRepo_GetRecentMessages_Success(t *testing.T) {
 		{ID: 2, UserID: 2, Username: "user2", Content: "Message 2", Timestamp: time.Now()},
 	}
 
-	mockDB.On("SelectContext", mock.Anything, mock.Anything, mock.Anything, limit).Return(nil).Run(func(args mock.Arguments) {
+	mockDB.On("SelectContext", mock.Anything, mock.Anything, mock.Anything, 1, limit).Return(nil).Run(func(args mock.Arguments) {
 		dest := args.Get(1).(*[]entity.ChatMessage)
 		*dest = messages
 	})
 
-	result, err := chatRepo.GetRecentMessages(context.Background(), limit)
+	result, err := chatRepo.GetRecentMessages(context.Background(), 1, limit)
 
 	assert.NoError(t, err)
 	assert.Equal(t, messages, result)
@@ -99,12 +131,40 @@ func TestChatRepo_GetRecentMessages_Failure(t *testing.T) {
 
 	limit := 10
 
-	mockDB.On("SelectContext", mock.Anything, mock.Anything, mock.Anything, limit).Return(errors.New("failed to get recent messages"))
+	mockDB.On("SelectContext", mock.Anything, mock.Anything, mock.Anything, 1, limit).Return(errors.New("failed to get recent messages"))
 
-	result, err := chatRepo.GetRecentMessages(context.Background(), limit)
+	result, err := chatRepo.GetRecentMessages(context.Background(), 1, limit)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 
 	mockDB.AssertExpectations(t)
 }
+
+func TestChatRepo_GetMessagesBefore_Chronological(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDB := new(mocks.DB)
+
+	chatRepo := NewChatRepository(mockDB, logger)
+
+	before := &entity.Cursor{CreatedAt: time.Now(), ID: 10}
+	newestFirst := []entity.ChatMessage{
+		{ID: 9, Content: "Message 9"},
+		{ID: 8, Content: "Message 8"},
+	}
+
+	mockDB.On("SelectContext", mock.Anything, mock.Anything, mock.Anything, 3,
+		before.CreatedAt.Format(time.RFC3339), before.CreatedAt.Format(time.RFC3339), 10, 2).Return(nil).Run(func(args mock.Arguments) {
+		dest := args.Get(1).(*[]entity.ChatMessage)
+		*dest = append([]entity.ChatMessage(nil), newestFirst...)
+	})
+
+	result, err := chatRepo.GetMessagesBefore(context.Background(), 3, before, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.ChatMessage{newestFirst[1], newestFirst[0]}, result)
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"go.uber.org/zap"
+)
+
+type ChatRoomRepository interface {
+	// CreateRoom создает комнату и записывает владельца ее участником. Если имя занято - ErrDuplicate
+	CreateRoom(ctx context.Context, room entity.ChatRoom) (*entity.ChatRoom, error)
+	// GetRoom возвращает комнату, если ее нет - sql.ErrNoRows
+	GetRoom(ctx context.Context, id int) (*entity.ChatRoom, error)
+	// ListRooms возвращает публичные комнаты и приватные комнаты, где userID участник
+	ListRooms(ctx context.Context, userID int) ([]entity.ChatRoom, error)
+	// DeleteRoom удаляет комнату вместе с участниками и сообщениями. Если комнаты нет - sql.ErrNoRows
+	DeleteRoom(ctx context.Context, id int) error
+	// AddMember добавляет участника, повторное добавление ничего не меняет
+	AddMember(ctx context.Context, member entity.ChatRoomMember) error
+	// RemoveMember удаляет участника. Если он не состоит в комнате - sql.ErrNoRows
+	RemoveMember(ctx context.Context, roomID, userID int) error
+	// GetMember возвращает участника, если он не состоит в комнате - sql.ErrNoRows
+	GetMember(ctx context.Context, roomID, userID int) (*entity.ChatRoomMember, error)
+	ListMembers(ctx context.Context, roomID int) ([]entity.ChatRoomMember, error)
+}
+
+const chatRoomSelect = `
+	SELECT r.id, r.name, r.is_private, r.owner_id, r.created_at,
+	       (SELECT COUNT(*) FROM chat_room_members m WHERE m.room_id = r.id) AS member_count
+	FROM chat_rooms r`
+
+type chatRoomRepository struct {
+	db     DB
+	logger *zap.Logger
+}
+
+func NewChatRoomRepository(db DB, logger *zap.Logger) ChatRoomRepository {
+	return &chatRoomRepository{db: db, logger: logger}
+}
+
+func (r *chatRoomRepository) CreateRoom(ctx context.Context, room entity.ChatRoom) (*entity.ChatRoom, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	room.CreatedAt = time.Now().UTC()
+	query := `INSERT INTO chat_rooms (name, is_private, owner_id, created_at) VALUES (?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, query, room.Name, room.IsPrivate, room.OwnerID, room.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create chat room", zap.Error(err), zap.String("name", room.Name))
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logger.Error("Failed to get last insert ID", zap.Error(err))
+		return nil, err
+	}
+	room.ID = int(id)
+
+	query = `INSERT INTO chat_room_members (room_id, user_id, role, joined_at) VALUES (?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, room.ID, room.OwnerID, entity.ChatRoleOwner, room.CreatedAt); err != nil {
+		r.logger.Error("Failed to add chat room owner", zap.Error(err), zap.Int("roomID", room.ID))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	room.MemberCount = 1
+	r.logger.Info("Chat room created successfully", zap.Int("roomID", room.ID), zap.String("name", room.Name))
+	return &room, nil
+}
+
+func (r *chatRoomRepository) GetRoom(ctx context.Context, id int) (*entity.ChatRoom, error) {
+	var room entity.ChatRoom
+	err := r.db.GetContext(ctx, &room, chatRoomSelect+` WHERE r.id = ?`, id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			r.logger.Error("Failed to get chat room", zap.Error(err), zap.Int("roomID", id))
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (r *chatRoomRepository) ListRooms(ctx context.Context, userID int) ([]entity.ChatRoom, error) {
+	query := chatRoomSelect + `
+	WHERE r.is_private = 0
+	   OR r.id IN (SELECT room_id FROM chat_room_members WHERE user_id = ?)
+	ORDER BY r.id`
+
+	rooms := []entity.ChatRoom{}
+	if err := r.db.SelectContext(ctx, &rooms, query, userID); err != nil {
+		r.logger.Error("Failed to list chat rooms", zap.Error(err), zap.Int("userID", userID))
+		return nil, err
+	}
+	return rooms, nil
+}
+
+func (r *chatRoomRepository) DeleteRoom(ctx context.Context, id int) error {
+	// Участников и сообщения удаляет триггер chat_rooms_ad
+	result, err := r.db.ExecContext(ctx, `DELETE FROM chat_rooms WHERE id = ?`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete chat room", zap.Error(err), zap.Int("roomID", id))
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	r.logger.Info("Chat room deleted successfully", zap.Int("roomID", id))
+	return nil
+}
+
+func (r *chatRoomRepository) AddMember(ctx context.Context, member entity.ChatRoomMember) error {
+	query := `INSERT OR IGNORE INTO chat_room_members (room_id, user_id, role, joined_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, member.RoomID, member.UserID, member.Role, time.Now().UTC())
+	if err != nil {
+		r.logger.Error("Failed to add chat room member", zap.Error(err),
+			zap.Int("roomID", member.RoomID), zap.Int("userID", member.UserID))
+		return err
+	}
+	return nil
+}
+
+func (r *chatRoomRepository) RemoveMember(ctx context.Context, roomID, userID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM chat_room_members WHERE room_id = ? AND user_id = ?`, roomID, userID)
+	if err != nil {
+		r.logger.Error("Failed to remove chat room member", zap.Error(err), zap.Int("roomID", roomID), zap.Int("userID", userID))
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *chatRoomRepository) GetMember(ctx context.Context, roomID, userID int) (*entity.ChatRoomMember, error) {
+	query := `SELECT room_id, user_id, role, joined_at FROM chat_room_members WHERE room_id = ? AND user_id = ?`
+
+	var member entity.ChatRoomMember
+	if err := r.db.GetContext(ctx, &member, query, roomID, userID); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *chatRoomRepository) ListMembers(ctx context.Context, roomID int) ([]entity.ChatRoomMember, error) {
+	query := `
+	SELECT room_id, user_id, role, joined_at
+	FROM chat_room_members
+	WHERE room_id = ?
+	ORDER BY joined_at, user_id`
+
+	members := []entity.ChatRoomMember{}
+	if err := r.db.SelectContext(ctx, &members, query, roomID); err != nil {
+		r.logger.Error("Failed to list chat room members", zap.Error(err), zap.Int("roomID", roomID))
+		return nil, err
+	}
+	return members, nil
+}
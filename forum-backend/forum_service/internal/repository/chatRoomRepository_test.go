@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository/adapters"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestChatRoomRepository_CreateRoom_AddsOwner(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	roomRepo := NewChatRoomRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO chat_rooms \(name, is_private, owner_id, created_at\) VALUES \(\?, \?, \?, \?\)`).
+		WithArgs("team", true, 7, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(`INSERT INTO chat_room_members \(room_id, user_id, role, joined_at\) VALUES \(\?, \?, \?, \?\)`).
+		WithArgs(5, 7, entity.ChatRoleOwner, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	room, err := roomRepo.CreateRoom(context.Background(), entity.ChatRoom{Name: "team", IsPrivate: true, OwnerID: 7})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, room.ID)
+	assert.Equal(t, 1, room.MemberCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChatRoomRepository_CreateRoom_Duplicate(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	roomRepo := NewChatRoomRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO chat_rooms`).
+		WillReturnError(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique})
+	mock.ExpectRollback()
+
+	_, err = roomRepo.CreateRoom(context.Background(), entity.ChatRoom{Name: "general", OwnerID: 7})
+
+	assert.True(t, errors.Is(err, ErrDuplicate))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChatRoomRepository_ListRooms(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	roomRepo := NewChatRoomRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectQuery(`WHERE r.is_private = 0\s+OR r.id IN \(SELECT room_id FROM chat_room_members WHERE user_id = \?\)`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_private", "owner_id", "created_at", "member_count"}).
+			AddRow(1, "general", false, 0, time.Now(), 0).
+			AddRow(5, "team", true, 7, time.Now(), 2))
+
+	rooms, err := roomRepo.ListRooms(context.Background(), 7)
+
+	assert.NoError(t, err)
+	if assert.Len(t, rooms, 2) {
+		assert.Equal(t, "general", rooms[0].Name)
+		assert.True(t, rooms[1].IsPrivate)
+		assert.Equal(t, 2, rooms[1].MemberCount)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChatRoomRepository_RemoveMember_NotMember(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	roomRepo := NewChatRoomRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectExec(`DELETE FROM chat_room_members WHERE room_id = \? AND user_id = \?`).
+		WithArgs(5, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = roomRepo.RemoveMember(context.Background(), 5, 9)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"github.com/Engls/forum-project2/forum_service/internal/repository/adapters"
 	"testing"
@@ -20,7 +21,7 @@ func TestCommentsRepository_CreateComment_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
-	dbAdapter := adapters.DbAdapter{db}
+	dbAdapter := adapters.DbAdapter{DB: db}
 
 	commentsRepo := NewCommentsRepository(&dbAdapter, logger)
 
@@ -33,8 +34,8 @@ func TestCommentsRepository_CreateComment_Success(t *testing.T) {
 	createdComment.ID = 1
 	createdComment.CreatedAt = time.Now()
 
-	mock.ExpectQuery(`INSERT INTO comments \(post_id, author_id, content\) VALUES \(\$1, \$2, \$3\) RETURNING id, created_at`).
-		WithArgs(comment.PostId, comment.AuthorId, comment.Content).
+	mock.ExpectQuery(`INSERT INTO comments \(post_id, author_id, content, parent_id, depth\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, created_at`).
+		WithArgs(comment.PostId, comment.AuthorId, comment.Content, comment.ParentId, comment.Depth).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(createdComment.ID, createdComment.CreatedAt))
 
 	result, err := commentsRepo.CreateComment(context.Background(), comment)
@@ -53,7 +54,7 @@ func TestCommentsRepository_CreateComment_Failure(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	dbAdapter := adapters.DbAdapter{db}
+	dbAdapter := adapters.DbAdapter{DB: db}
 
 	commentsRepo := NewCommentsRepository(&dbAdapter, logger)
 
@@ -63,8 +64,8 @@ func TestCommentsRepository_CreateComment_Failure(t *testing.T) {
 		Content:  "This is a test comment",
 	}
 
-	mock.ExpectQuery(`INSERT INTO comments \(post_id, author_id, content\) VALUES \(\$1, \$2, \$3\) RETURNING id, created_at`).
-		WithArgs(comment.PostId, comment.AuthorId, comment.Content).
+	mock.ExpectQuery(`INSERT INTO comments \(post_id, author_id, content, parent_id, depth\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, created_at`).
+		WithArgs(comment.PostId, comment.AuthorId, comment.Content, comment.ParentId, comment.Depth).
 		WillReturnError(errors.New("failed to create comment"))
 
 	result, err := commentsRepo.CreateComment(context.Background(), comment)
@@ -75,7 +76,7 @@ func TestCommentsRepository_CreateComment_Failure(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestCommentsRepository_GetCommentsByPostID_Success(t *testing.T) {
+func TestCommentsRepository_GetComments_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
@@ -83,25 +84,26 @@ func TestCommentsRepository_GetCommentsByPostID_Success(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	dbAdapter := adapters.DbAdapter{db}
+	dbAdapter := adapters.DbAdapter{DB: db}
 
 	commentsRepo := NewCommentsRepository(&dbAdapter, logger)
 
 	postID := 1
+	parentID := 1
 	comments := []entity.Comment{
+		{ID: 2, PostId: postID, AuthorId: 2, ParentId: &parentID, Depth: 1, Content: "Comment 2", CreatedAt: time.Now(), Score: 3},
 		{ID: 1, PostId: postID, AuthorId: 1, Content: "Comment 1", CreatedAt: time.Now()},
-		{ID: 2, PostId: postID, AuthorId: 2, Content: "Comment 2", CreatedAt: time.Now()},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "post_id", "author_id", "content", "created_at"})
+	rows := sqlmock.NewRows([]string{"id", "content", "author_id", "post_id", "parent_id", "depth", "created_at", "edited_at", "deleted_at", "score"})
 	for _, comment := range comments {
-		rows.AddRow(comment.ID, comment.PostId, comment.AuthorId, comment.Content, comment.CreatedAt)
+		rows.AddRow(comment.ID, comment.Content, comment.AuthorId, comment.PostId, comment.ParentId, comment.Depth, comment.CreatedAt, nil, nil, comment.Score)
 	}
-	mock.ExpectQuery(`SELECT id, post_id, author_id, content, created_at FROM comments WHERE post_id = \$1 ORDER BY created_at ASC`).
-		WithArgs(postID).
+	mock.ExpectQuery(`SELECT id, content, author_id, post_id, parent_id, depth, created_at, edited_at, deleted_at, score FROM comments WHERE post_id = \$1 ORDER BY created_at DESC, id DESC LIMIT \$2 OFFSET \$3`).
+		WithArgs(postID, 10, 0).
 		WillReturnRows(rows)
 
-	result, err := commentsRepo.GetCommentsByPostID(context.Background(), postID)
+	result, err := commentsRepo.GetComments(context.Background(), postID, 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, comments, result)
@@ -109,7 +111,7 @@ func TestCommentsRepository_GetCommentsByPostID_Success(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestCommentsRepository_GetCommentsByPostID_Failure(t *testing.T) {
+func TestCommentsRepository_GetComments_Failure(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
@@ -117,20 +119,143 @@ func TestCommentsRepository_GetCommentsByPostID_Failure(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	dbAdapter := adapters.DbAdapter{db}
+	dbAdapter := adapters.DbAdapter{DB: db}
 
 	commentsRepo := NewCommentsRepository(&dbAdapter, logger)
 
 	postID := 1
 
-	mock.ExpectQuery(`SELECT id, post_id, author_id, content, created_at FROM comments WHERE post_id = \$1 ORDER BY created_at ASC`).
-		WithArgs(postID).
+	mock.ExpectQuery(`FROM comments WHERE post_id = \$1`).
+		WithArgs(postID, 10, 0).
 		WillReturnError(errors.New("failed to get comments"))
 
-	result, err := commentsRepo.GetCommentsByPostID(context.Background(), postID)
+	result, err := commentsRepo.GetComments(context.Background(), postID, 10, 0)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCommentsRepository_GetCommentTree_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dbAdapter := adapters.DbAdapter{DB: db}
+
+	commentsRepo := NewCommentsRepository(&dbAdapter, logger)
+
+	now := time.Now()
+	columns := []string{"id", "content", "author_id", "post_id", "parent_id", "depth", "created_at", "edited_at", "deleted_at", "score", "reply_count"}
+
+	mock.ExpectQuery(`FROM comments c WHERE c.post_id = \$1 AND c.parent_id IS NULL`).
+		WithArgs(1, 10, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, "Root 2", 1, 1, nil, 0, now, nil, nil, 0, 0).
+			AddRow(1, "", 1, 1, nil, 0, now, nil, now, 0, 1))
+	mock.ExpectQuery(`WITH RECURSIVE thread\(id, lvl\) AS \( SELECT id, 1 FROM comments WHERE parent_id IN \(\?, \?\)`).
+		WithArgs(2, 1, 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, "Reply", 2, 1, 1, 1, now, now, nil, 2, 1).
+			AddRow(4, "Nested", 1, 1, 3, 2, now, nil, nil, 0, 0))
+
+	result, err := commentsRepo.GetCommentTree(context.Background(), 1, 10, 0, 2)
+
+	assert.NoError(t, err)
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, 2, result[0].ID)
+		assert.Empty(t, result[0].Replies)
+		assert.Equal(t, 1, result[1].ReplyCount)
+		assert.Equal(t, entity.DeletedCommentContent, result[1].Content)
+		assert.True(t, result[1].IsDeleted())
+		if assert.Len(t, result[1].Replies, 1) {
+			reply := result[1].Replies[0]
+			assert.Equal(t, 3, reply.ID)
+			assert.Equal(t, 2, reply.Score)
+			assert.Equal(t, 1, *reply.ParentId)
+			if assert.Len(t, reply.Replies, 1) {
+				assert.Equal(t, 4, reply.Replies[0].ID)
+			}
+		}
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentsRepository_GetReplies_NoDepth(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dbAdapter := adapters.DbAdapter{DB: db}
+
+	commentsRepo := NewCommentsRepository(&dbAdapter, logger)
+
+	columns := []string{"id", "content", "author_id", "post_id", "parent_id", "depth", "created_at", "edited_at", "deleted_at", "score", "reply_count"}
+	mock.ExpectQuery(`FROM comments c WHERE c.parent_id = \$1 ORDER BY c.created_at ASC`).
+		WithArgs(1, 10, 0).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "Reply", 2, 1, 1, 1, time.Now(), nil, nil, 0, 4))
+
+	result, err := commentsRepo.GetReplies(context.Background(), 1, 10, 0, 0)
+
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, 4, result[0].ReplyCount)
+		assert.Empty(t, result[0].Replies)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentsRepository_UpdateComment_Deleted(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dbAdapter := adapters.DbAdapter{DB: db}
+
+	commentsRepo := NewCommentsRepository(&dbAdapter, logger)
+
+	editedAt := time.Now().UTC()
+	mock.ExpectExec(`UPDATE comments SET content = \$1, edited_at = \$2 WHERE id = \$3 AND deleted_at IS NULL`).
+		WithArgs("Edited", editedAt, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = commentsRepo.UpdateComment(context.Background(), 1, "Edited", editedAt)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentsRepository_SoftDeleteComment_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dbAdapter := adapters.DbAdapter{DB: db}
+
+	commentsRepo := NewCommentsRepository(&dbAdapter, logger)
+
+	deletedAt := time.Now().UTC()
+	mock.ExpectExec(`UPDATE comments SET content = '', deleted_at = \$1 WHERE id = \$2 AND deleted_at IS NULL`).
+		WithArgs(deletedAt, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = commentsRepo.SoftDeleteComment(context.Background(), 1, deletedAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
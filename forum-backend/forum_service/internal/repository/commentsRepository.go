@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"strings"
+	"time"
+
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"go.uber.org/zap"
 )
@@ -9,7 +13,61 @@ import (
 type CommentsRepository interface {
 	CreateComment(ctx context.Context, comment entity.Comment) (entity.Comment, error)
 	GetComments(ctx context.Context, postID, limit, offset int) ([]entity.Comment, error)
+	// GetCommentsAfter возвращает limit комментариев поста, следующих за курсором (новые первыми).
+	// Без курсора - первую страницу
+	GetCommentsAfter(ctx context.Context, postID int, after *entity.Cursor, limit int) ([]entity.Comment, error)
 	GetTotalCommentsCount(ctx context.Context, postID int) (int, error)
+	GetCommentByID(ctx context.Context, id int) (*entity.Comment, error)
+	// GetCommentTree возвращает страницу комментариев верхнего уровня с ответами
+	// на depth уровней вглубь
+	GetCommentTree(ctx context.Context, postID, limit, offset, depth int) ([]*entity.CommentNode, error)
+	// GetCommentTreeAfter - то же, что GetCommentTree, но страница верхнего уровня
+	// начинается после курсора
+	GetCommentTreeAfter(ctx context.Context, postID int, after *entity.Cursor, limit, depth int) ([]*entity.CommentNode, error)
+	GetTopLevelCommentsCount(ctx context.Context, postID int) (int, error)
+	// GetReplies возвращает страницу прямых ответов на комментарий с их ответами
+	// на depth уровней вглубь
+	GetReplies(ctx context.Context, parentID, limit, offset, depth int) ([]*entity.CommentNode, error)
+	GetRepliesCount(ctx context.Context, parentID int) (int, error)
+	// UpdateComment меняет текст комментария. Удаленный комментарий не редактируется: sql.ErrNoRows
+	UpdateComment(ctx context.Context, id int, content string, editedAt time.Time) error
+	// SoftDeleteComment стирает текст и помечает комментарий удаленным, ответы остаются на месте
+	SoftDeleteComment(ctx context.Context, id int, deletedAt time.Time) error
+}
+
+// commentColumns - колонки комментария в порядке scanComment
+const commentColumns = `id, content, author_id, post_id, parent_id, depth, created_at, edited_at, deleted_at, score`
+
+// commentNodeColumns - колонки узла дерева, c - алиас таблицы comments
+const commentNodeColumns = `c.id, c.content, c.author_id, c.post_id, c.parent_id, c.depth, c.created_at, c.edited_at, c.deleted_at, c.score,
+        (SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id) AS reply_count`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanComment читает колонки commentColumns (и extra после них). Текст удаленного
+// комментария заменяется на entity.DeletedCommentContent
+func scanComment(row rowScanner, comment *entity.Comment, extra ...any) error {
+	dest := append([]any{
+		&comment.ID,
+		&comment.Content,
+		&comment.AuthorId,
+		&comment.PostId,
+		&comment.ParentId,
+		&comment.Depth,
+		&comment.CreatedAt,
+		&comment.EditedAt,
+		&comment.DeletedAt,
+		&comment.Score,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	if comment.IsDeleted() {
+		comment.Content = entity.DeletedCommentContent
+	}
+	return nil
 }
 
 type commentsRepository struct {
@@ -23,11 +81,11 @@ func NewCommentsRepository(db DB, logger *zap.Logger) CommentsRepository {
 
 func (r *commentsRepository) CreateComment(ctx context.Context, comment entity.Comment) (entity.Comment, error) {
 	query := `
-		INSERT INTO comments (post_id, author_id, content)
-		VALUES ($1, $2, $3)
+		INSERT INTO comments (post_id, author_id, content, parent_id, depth)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at
 	`
-	err := r.db.QueryRowContext(ctx, query, comment.PostId, comment.AuthorId, comment.Content).Scan(&comment.ID, &comment.CreatedAt)
+	err := r.db.QueryRowContext(ctx, query, comment.PostId, comment.AuthorId, comment.Content, comment.ParentId, comment.Depth).Scan(&comment.ID, &comment.CreatedAt)
 	if err != nil {
 		r.logger.Error("Failed to create comment", zap.Error(err), zap.Int("postID", comment.PostId), zap.Int("authorID", comment.AuthorId))
 		return entity.Comment{}, err
@@ -38,33 +96,51 @@ func (r *commentsRepository) CreateComment(ctx context.Context, comment entity.C
 
 func (r *commentsRepository) GetComments(ctx context.Context, postID, limit, offset int) ([]entity.Comment, error) {
 	query := `
-        SELECT id, content, author_id, post_id, created_at 
-        FROM comments 
-        WHERE post_id = $1 
-        ORDER BY created_at DESC 
+        SELECT ` + commentColumns + `
+        FROM comments
+        WHERE post_id = $1
+        ORDER BY created_at DESC, id DESC
         LIMIT $2 OFFSET $3
     `
 	rows, err := r.db.QueryContext(ctx, query, postID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
+	return scanComments(rows)
+}
+
+func (r *commentsRepository) GetCommentsAfter(ctx context.Context, postID int, after *entity.Cursor, limit int) ([]entity.Comment, error) {
+	where, args := "1 = 1", []any{}
+	if after != nil {
+		where, args = keysetBefore("created_at", "id", sqliteTimestamp(after.CreatedAt), after)
+	}
+	query := `
+        SELECT ` + commentColumns + `
+        FROM comments
+        WHERE post_id = ? AND ` + where + `
+        ORDER BY created_at DESC, id DESC
+        LIMIT ?
+    `
+	rows, err := r.db.QueryContext(ctx, query, append(append([]any{postID}, args...), limit)...)
+	if err != nil {
+		r.logger.Error("Failed to get comments after cursor", zap.Error(err), zap.Int("postID", postID))
+		return nil, err
+	}
+	return scanComments(rows)
+}
+
+func scanComments(rows *sql.Rows) ([]entity.Comment, error) {
 	defer rows.Close()
 
 	var comments []entity.Comment
 	for rows.Next() {
 		var comment entity.Comment
-		if err := rows.Scan(
-			&comment.ID,
-			&comment.Content,
-			&comment.AuthorId,
-			&comment.PostId,
-			&comment.CreatedAt,
-		); err != nil {
+		if err := scanComment(rows, &comment); err != nil {
 			return nil, err
 		}
 		comments = append(comments, comment)
 	}
-	return comments, nil
+	return comments, rows.Err()
 }
 
 func (r *commentsRepository) GetTotalCommentsCount(ctx context.Context, postID int) (int, error) {
@@ -73,3 +149,186 @@ func (r *commentsRepository) GetTotalCommentsCount(ctx context.Context, postID i
 	err := r.db.QueryRowContext(ctx, query, postID).Scan(&count)
 	return count, err
 }
+
+func (r *commentsRepository) GetCommentByID(ctx context.Context, id int) (*entity.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE id = $1`
+	var comment entity.Comment
+	err := scanComment(r.db.QueryRowContext(ctx, query, id), &comment)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			r.logger.Error("Failed to get comment by ID", zap.Error(err), zap.Int("commentID", id))
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentsRepository) GetCommentTree(ctx context.Context, postID, limit, offset, depth int) ([]*entity.CommentNode, error) {
+	query := `
+        SELECT ` + commentNodeColumns + `
+        FROM comments c
+        WHERE c.post_id = $1 AND c.parent_id IS NULL
+        ORDER BY c.created_at DESC, c.id DESC
+        LIMIT $2 OFFSET $3
+    `
+	roots, err := r.queryNodes(ctx, query, postID, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to get comment tree", zap.Error(err), zap.Int("postID", postID))
+		return nil, err
+	}
+	if err := r.loadDescendants(ctx, roots, depth); err != nil {
+		r.logger.Error("Failed to load comment replies", zap.Error(err), zap.Int("postID", postID))
+		return nil, err
+	}
+	return roots, nil
+}
+
+func (r *commentsRepository) GetCommentTreeAfter(ctx context.Context, postID int, after *entity.Cursor, limit, depth int) ([]*entity.CommentNode, error) {
+	where, args := "1 = 1", []any{}
+	if after != nil {
+		where, args = keysetBefore("c.created_at", "c.id", sqliteTimestamp(after.CreatedAt), after)
+	}
+	query := `
+        SELECT ` + commentNodeColumns + `
+        FROM comments c
+        WHERE c.post_id = ? AND c.parent_id IS NULL AND ` + where + `
+        ORDER BY c.created_at DESC, c.id DESC
+        LIMIT ?
+    `
+	roots, err := r.queryNodes(ctx, query, append(append([]any{postID}, args...), limit)...)
+	if err != nil {
+		r.logger.Error("Failed to get comment tree after cursor", zap.Error(err), zap.Int("postID", postID))
+		return nil, err
+	}
+	if err := r.loadDescendants(ctx, roots, depth); err != nil {
+		r.logger.Error("Failed to load comment replies", zap.Error(err), zap.Int("postID", postID))
+		return nil, err
+	}
+	return roots, nil
+}
+
+func (r *commentsRepository) GetTopLevelCommentsCount(ctx context.Context, postID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM comments WHERE post_id = $1 AND parent_id IS NULL`
+	err := r.db.QueryRowContext(ctx, query, postID).Scan(&count)
+	return count, err
+}
+
+func (r *commentsRepository) GetReplies(ctx context.Context, parentID, limit, offset, depth int) ([]*entity.CommentNode, error) {
+	query := `
+        SELECT ` + commentNodeColumns + `
+        FROM comments c
+        WHERE c.parent_id = $1
+        ORDER BY c.created_at ASC, c.id ASC
+        LIMIT $2 OFFSET $3
+    `
+	replies, err := r.queryNodes(ctx, query, parentID, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to get replies", zap.Error(err), zap.Int("commentID", parentID))
+		return nil, err
+	}
+	if err := r.loadDescendants(ctx, replies, depth); err != nil {
+		r.logger.Error("Failed to load nested replies", zap.Error(err), zap.Int("commentID", parentID))
+		return nil, err
+	}
+	return replies, nil
+}
+
+func (r *commentsRepository) GetRepliesCount(ctx context.Context, parentID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM comments WHERE parent_id = $1`
+	err := r.db.QueryRowContext(ctx, query, parentID).Scan(&count)
+	return count, err
+}
+
+func (r *commentsRepository) UpdateComment(ctx context.Context, id int, content string, editedAt time.Time) error {
+	query := `UPDATE comments SET content = $1, edited_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, content, editedAt, id)
+	if err != nil {
+		r.logger.Error("Failed to update comment", zap.Error(err), zap.Int("commentID", id))
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return sql.ErrNoRows
+	}
+	r.logger.Info("Comment updated successfully", zap.Int("commentID", id))
+	return nil
+}
+
+func (r *commentsRepository) SoftDeleteComment(ctx context.Context, id int, deletedAt time.Time) error {
+	query := `UPDATE comments SET content = '', deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, deletedAt, id)
+	if err != nil {
+		r.logger.Error("Failed to delete comment", zap.Error(err), zap.Int("commentID", id))
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return sql.ErrNoRows
+	}
+	r.logger.Info("Comment deleted successfully", zap.Int("commentID", id))
+	return nil
+}
+
+// loadDescendants одним рекурсивным запросом достает ответы на узлы nodes
+// не глубже depth уровней и раскладывает их по родителям
+func (r *commentsRepository) loadDescendants(ctx context.Context, nodes []*entity.CommentNode, depth int) error {
+	if depth <= 0 || len(nodes) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*entity.CommentNode, len(nodes))
+	args := make([]any, 0, len(nodes)+1)
+	for _, node := range nodes {
+		byID[node.ID] = node
+		args = append(args, node.ID)
+	}
+	args = append(args, depth)
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(nodes)), ", ")
+	query := `
+        WITH RECURSIVE thread(id, lvl) AS (
+            SELECT id, 1 FROM comments WHERE parent_id IN (` + placeholders + `)
+            UNION ALL
+            SELECT c.id, t.lvl + 1 FROM comments c JOIN thread t ON c.parent_id = t.id
+            WHERE t.lvl < ?
+        )
+        SELECT ` + commentNodeColumns + `
+        FROM thread t JOIN comments c ON c.id = t.id
+        ORDER BY c.created_at ASC, c.id ASC
+    `
+	descendants, err := r.queryNodes(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range descendants {
+		byID[node.ID] = node
+	}
+	for _, node := range descendants {
+		if node.ParentId == nil {
+			continue
+		}
+		if parent, ok := byID[*node.ParentId]; ok {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+	return nil
+}
+
+func (r *commentsRepository) queryNodes(ctx context.Context, query string, args ...any) ([]*entity.CommentNode, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := []*entity.CommentNode{}
+	for rows.Next() {
+		node := &entity.CommentNode{Replies: []*entity.CommentNode{}}
+		if err := scanComment(rows, &node.Comment, &node.ReplyCount); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, rows.Err()
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+)
+
+// sqliteTimestampLayout - формат CURRENT_TIMESTAMP, в котором SQLite заполняет created_at
+const sqliteTimestampLayout = "2006-01-02 15:04:05"
+
+// sqliteTimestamp приводит время к виду, в котором оно хранится в колонках created_at,
+// чтобы сравнение строк в SQLite совпадало со сравнением времени
+func sqliteTimestamp(t time.Time) string {
+	return t.UTC().Format(sqliteTimestampLayout)
+}
+
+// keysetBefore - условие "строка идет после курсора" для ленты, отсортированной по
+// (timeColumn, idColumn) по убыванию. at - время курсора в формате колонки
+func keysetBefore(timeColumn, idColumn string, at any, cursor *entity.Cursor) (string, []any) {
+	return `(` + timeColumn + ` < ? OR (` + timeColumn + ` = ? AND ` + idColumn + ` < ?))`, []any{at, at, cursor.ID}
+}
+
+// keysetAfter - то же для ленты, отсортированной по возрастанию
+func keysetAfter(timeColumn, idColumn string, at any, cursor *entity.Cursor) (string, []any) {
+	return `(` + timeColumn + ` > ? OR (` + timeColumn + ` = ? AND ` + idColumn + ` > ?))`, []any{at, at, cursor.ID}
+}
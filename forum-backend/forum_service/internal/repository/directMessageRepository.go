@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"go.uber.org/zap"
+)
+
+type DirectMessageRepository interface {
+	// StoreMessage сохраняет сообщение и возвращает его с id и created_at. Если у отправителя уже есть
+	// сообщение с тем же ClientID, новое не сохраняется, возвращается сохраненное и created = false
+	StoreMessage(ctx context.Context, msg entity.DirectMessage) (stored *entity.DirectMessage, created bool, err error)
+	// GetConversations возвращает переписки userID, начиная с последней. Username не заполняется
+	GetConversations(ctx context.Context, userID int) ([]entity.Conversation, error)
+	// GetMessagesBefore возвращает limit сообщений между userID и peerID, отправленных раньше
+	// курсора, в хронологическом порядке. Без курсора - последние сообщения
+	GetMessagesBefore(ctx context.Context, userID, peerID int, before *entity.Cursor, limit int) ([]entity.DirectMessage, error)
+	// MarkRead отмечает прочитанными сообщения от peerID к userID и возвращает их число
+	MarkRead(ctx context.Context, userID, peerID int) (int, error)
+}
+
+const directMessageColumns = `id, sender_id, recipient_id, content, created_at, read_at, COALESCE(client_id, '') AS client_id`
+
+type directMessageRepository struct {
+	db     DB
+	logger *zap.Logger
+}
+
+func NewDirectMessageRepository(db DB, logger *zap.Logger) DirectMessageRepository {
+	return &directMessageRepository{db: db, logger: logger}
+}
+
+func (r *directMessageRepository) StoreMessage(ctx context.Context, msg entity.DirectMessage) (*entity.DirectMessage, bool, error) {
+	query := `
+		INSERT INTO direct_messages (sender_id, recipient_id, content, client_id)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRowContext(ctx, query, msg.SenderID, msg.RecipientID, msg.Content, nullIfEmpty(msg.ClientID)).Scan(&msg.ID, &msg.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Повторная отправка: ON CONFLICT сработал на уникальном (sender_id, client_id)
+		var existing entity.DirectMessage
+		query = `SELECT ` + directMessageColumns + ` FROM direct_messages WHERE sender_id = ? AND client_id = ?`
+		if err := r.db.GetContext(ctx, &existing, query, msg.SenderID, msg.ClientID); err != nil {
+			r.logger.Error("Failed to get duplicate direct message", zap.Error(err), zap.String("clientID", msg.ClientID))
+			return nil, false, err
+		}
+		return &existing, false, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to store direct message", zap.Error(err),
+			zap.Int("senderID", msg.SenderID), zap.Int("recipientID", msg.RecipientID))
+		return nil, false, err
+	}
+	return &msg, true, nil
+}
+
+func (r *directMessageRepository) GetConversations(ctx context.Context, userID int) ([]entity.Conversation, error) {
+	// Последнее сообщение переписки - с наибольшим id
+	query := `
+        SELECT m.id, m.sender_id, m.recipient_id, m.content, m.created_at, m.read_at, c.peer_id, c.unread_count
+        FROM (
+            SELECT CASE WHEN sender_id = ? THEN recipient_id ELSE sender_id END AS peer_id,
+                   MAX(id) AS last_id,
+                   SUM(recipient_id = ? AND read_at IS NULL) AS unread_count
+            FROM direct_messages
+            WHERE sender_id = ? OR recipient_id = ?
+            GROUP BY peer_id
+        ) c
+        JOIN direct_messages m ON m.id = c.last_id
+        ORDER BY m.id DESC
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID, userID, userID, userID)
+	if err != nil {
+		r.logger.Error("Failed to get conversations", zap.Error(err), zap.Int("userID", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := []entity.Conversation{}
+	for rows.Next() {
+		var conversation entity.Conversation
+		msg := &conversation.LastMessage
+		if err := rows.Scan(&msg.ID, &msg.SenderID, &msg.RecipientID, &msg.Content, &msg.CreatedAt, &msg.ReadAt,
+			&conversation.UserID, &conversation.UnreadCount); err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, conversation)
+	}
+	return conversations, rows.Err()
+}
+
+func (r *directMessageRepository) GetMessagesBefore(ctx context.Context, userID, peerID int, before *entity.Cursor, limit int) ([]entity.DirectMessage, error) {
+	where := "((sender_id = ? AND recipient_id = ?) OR (sender_id = ? AND recipient_id = ?))"
+	args := []any{userID, peerID, peerID, userID}
+	if before != nil {
+		cond, cursorArgs := keysetBefore("created_at", "id", sqliteTimestamp(before.CreatedAt), before)
+		where, args = where+" AND "+cond, append(args, cursorArgs...)
+	}
+	query := `
+        SELECT ` + directMessageColumns + `
+        FROM direct_messages
+        WHERE ` + where + `
+        ORDER BY created_at DESC, id DESC
+        LIMIT ?`
+
+	var messages []entity.DirectMessage
+	if err := r.db.SelectContext(ctx, &messages, query, append(args, limit)...); err != nil {
+		r.logger.Error("Failed to get direct messages", zap.Error(err), zap.Int("userID", userID), zap.Int("peerID", peerID))
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+func (r *directMessageRepository) MarkRead(ctx context.Context, userID, peerID int) (int, error) {
+	query := `UPDATE direct_messages SET read_at = CURRENT_TIMESTAMP WHERE recipient_id = ? AND sender_id = ? AND read_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, userID, peerID)
+	if err != nil {
+		r.logger.Error("Failed to mark direct messages read", zap.Error(err), zap.Int("userID", userID), zap.Int("peerID", peerID))
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository/adapters"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestDirectMessageRepository_StoreMessage(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dmRepo := NewDirectMessageRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO direct_messages \(sender_id, recipient_id, content, client_id\)`).
+		WithArgs(1, 2, "hello", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(4, createdAt))
+
+	msg, created, err := dmRepo.StoreMessage(context.Background(), entity.DirectMessage{SenderID: 1, RecipientID: 2, Content: "hello"})
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, &entity.DirectMessage{ID: 4, SenderID: 1, RecipientID: 2, Content: "hello", CreatedAt: createdAt}, msg)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDirectMessageRepository_StoreMessage_Duplicate(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// Повторное сообщение читается в структуру, это умеет только sqlx
+	dmRepo := NewDirectMessageRepository(sqlx.NewDb(db, "sqlmock"), logger)
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO direct_messages`).
+		WithArgs(1, 2, "hello again", "c-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+	mock.ExpectQuery(`SELECT .* FROM direct_messages WHERE sender_id = \? AND client_id = \?`).
+		WithArgs(1, "c-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "recipient_id", "content", "created_at", "read_at", "client_id"}).
+			AddRow(4, 1, 2, "hello", createdAt, nil, "c-1"))
+
+	msg, created, err := dmRepo.StoreMessage(context.Background(), entity.DirectMessage{SenderID: 1, RecipientID: 2, Content: "hello again", ClientID: "c-1"})
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, &entity.DirectMessage{ID: 4, SenderID: 1, RecipientID: 2, Content: "hello", CreatedAt: createdAt, ClientID: "c-1"}, msg)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDirectMessageRepository_GetConversations(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dmRepo := NewDirectMessageRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT m.id, m.sender_id, m.recipient_id, m.content, m.created_at, m.read_at, c.peer_id, c.unread_count`).
+		WithArgs(7, 7, 7, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "recipient_id", "content", "created_at", "read_at", "peer_id", "unread_count"}).
+			AddRow(9, 3, 7, "hi", createdAt, nil, 3, 2).
+			AddRow(5, 7, 4, "bye", createdAt, createdAt, 4, 0))
+
+	conversations, err := dmRepo.GetConversations(context.Background(), 7)
+
+	assert.NoError(t, err)
+	if assert.Len(t, conversations, 2) {
+		assert.Equal(t, entity.Conversation{
+			UserID:      3,
+			LastMessage: entity.DirectMessage{ID: 9, SenderID: 3, RecipientID: 7, Content: "hi", CreatedAt: createdAt},
+			UnreadCount: 2,
+		}, conversations[0])
+		assert.Equal(t, 4, conversations[1].UserID)
+		assert.Equal(t, &createdAt, conversations[1].LastMessage.ReadAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDirectMessageRepository_MarkRead(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dmRepo := NewDirectMessageRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectExec(`UPDATE direct_messages SET read_at = CURRENT_TIMESTAMP WHERE recipient_id = \? AND sender_id = \? AND read_at IS NULL`).
+		WithArgs(7, 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	marked, err := dmRepo.MarkRead(context.Background(), 7, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, marked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,6 @@
+//go:build !sqlite_fts5
+
+package repository
+
+// fts5Built - тесты собраны с тегом sqlite_fts5
+const fts5Built = false
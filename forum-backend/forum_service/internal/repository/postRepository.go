@@ -2,6 +2,12 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"go.uber.org/zap"
 )
@@ -14,11 +20,36 @@ type DBposts interface {
 type PostRepository interface {
 	CreatePost(ctx context.Context, post entity.Post) (*entity.Post, error)
 	GetPosts(ctx context.Context, limit, offset int) ([]entity.Post, error)
+	// ListPosts возвращает страницу ленты с фильтрами и сортировкой filter. При filter.After
+	// страница начинается после курсора, offset при этом обычно 0
+	ListPosts(ctx context.Context, filter entity.PostFilter, limit, offset int) ([]entity.Post, error)
+	// CountPosts считает посты, подходящие под filter (курсор не учитывается)
+	CountPosts(ctx context.Context, filter entity.PostFilter) (int, error)
 	GetPostByID(ctx context.Context, id int) (*entity.Post, error)
-	UpdatePost(ctx context.Context, post entity.Post) (*entity.Post, error)
+	// UpdatePost сохраняет новую версию поста и пишет ее в историю правок от имени editorID.
+	// Теги заменяются, только если post.Tags не nil. Если поста нет - sql.ErrNoRows
+	UpdatePost(ctx context.Context, post entity.Post, editorID int) (*entity.Post, error)
 	DeletePost(ctx context.Context, id int) error
-	GetUserIDByToken(ctx context.Context, token string) (int, error)
 	GetTotalPostsCount(ctx context.Context) (int, error)
+	GetPostsByCategory(ctx context.Context, categoryID, limit, offset int) ([]entity.Post, error)
+	GetCategoryPostsCount(ctx context.Context, categoryID int) (int, error)
+	// GetTags возвращает используемые теги, самые популярные первыми
+	GetTags(ctx context.Context, limit int) ([]entity.TagCount, error)
+	GetPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error)
+	GetPostRevision(ctx context.Context, postID, revision int) (*entity.PostRevision, error)
+}
+
+// postColumns - колонки поста в порядке scanPost
+const postColumns = `id, title, content, author_id, category_id, score, comment_count, last_comment_at, created_at, updated_at`
+
+// postSortOrders - допустимые сортировки ленты. Под каждую есть индекс (миграции 015 и 016),
+// id в конце делает порядок однозначным
+var postSortOrders = map[string]string{
+	entity.PostSortNew:       "created_at DESC, id DESC",
+	entity.PostSortOld:       "created_at ASC, id ASC",
+	entity.PostSortTop:       "score DESC, id DESC",
+	entity.PostSortActive:    "COALESCE(last_comment_at, created_at) DESC, id DESC",
+	entity.PostSortCommented: "comment_count DESC, id DESC",
 }
 
 type postRepository struct {
@@ -31,8 +62,15 @@ func NewPostRepository(db DB, logger *zap.Logger) PostRepository {
 }
 
 func (r *postRepository) CreatePost(ctx context.Context, post entity.Post) (*entity.Post, error) {
-	query := `INSERT INTO posts (author_id, title, content) VALUES (?, ?, ?)`
-	result, err := r.db.ExecContext(ctx, query, post.AuthorId, post.Title, post.Content)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Failed to begin transaction", zap.Error(err), zap.Int("authorID", post.AuthorId))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO posts (author_id, title, content, category_id) VALUES (?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, query, post.AuthorId, post.Title, post.Content, post.CategoryId)
 	if err != nil {
 		r.logger.Error("Failed to create post", zap.Error(err), zap.Int("authorID", post.AuthorId))
 		return nil, err
@@ -43,31 +81,264 @@ func (r *postRepository) CreatePost(ctx context.Context, post entity.Post) (*ent
 		r.logger.Error("Failed to get last insert ID", zap.Error(err))
 		return nil, err
 	}
-
 	post.ID = int(id)
+
+	if len(post.Tags) > 0 {
+		if err := r.setPostTags(ctx, tx, post.ID, post.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Failed to commit post creation", zap.Error(err), zap.Int("postID", post.ID))
+		return nil, err
+	}
+
+	if post.Tags == nil {
+		post.Tags = []string{}
+	}
 	r.logger.Info("Post created successfully", zap.Int("postID", post.ID), zap.Int("authorID", post.AuthorId))
 	return &post, nil
 }
 
 func (r *postRepository) GetPosts(ctx context.Context, limit, offset int) ([]entity.Post, error) {
-	query := `SELECT id, title, content, author_id FROM posts ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	query := `SELECT ` + postColumns + ` FROM posts ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
+	return r.scanPostsWithTags(ctx, rows)
+}
+
+func (r *postRepository) ListPosts(ctx context.Context, filter entity.PostFilter, limit, offset int) ([]entity.Post, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.PostSortNew
+	}
+	order, ok := postSortOrders[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown post sort %q", filter.Sort)
+	}
+
+	where, args := postFilterConditions(filter)
+	if filter.After != nil {
+		var keyset string
+		var keysetArgs []any
+		switch filter.Sort {
+		case entity.PostSortNew:
+			keyset, keysetArgs = keysetBefore("created_at", "id", sqliteTimestamp(filter.After.CreatedAt), filter.After)
+		case entity.PostSortOld:
+			keyset, keysetArgs = keysetAfter("created_at", "id", sqliteTimestamp(filter.After.CreatedAt), filter.After)
+		default:
+			return nil, fmt.Errorf("cursor is not supported for post sort %q", filter.Sort)
+		}
+		where = append(where, keyset)
+		args = append(args, keysetArgs...)
+	}
+
+	query := `SELECT ` + postColumns + ` FROM posts` + whereClause(where) + ` ORDER BY ` + order + ` LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		r.logger.Error("Failed to list posts", zap.Error(err), zap.String("sort", filter.Sort))
+		return nil, err
+	}
+	return r.scanPostsWithTags(ctx, rows)
+}
+
+func (r *postRepository) CountPosts(ctx context.Context, filter entity.PostFilter) (int, error) {
+	where, args := postFilterConditions(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM posts`+whereClause(where), args...).Scan(&count)
+	return count, err
+}
+
+// postFilterConditions - условия WHERE для фильтров ленты, кроме курсора
+func postFilterConditions(filter entity.PostFilter) ([]string, []any) {
+	var where []string
+	var args []any
+	if filter.AuthorID > 0 {
+		where = append(where, `author_id = ?`)
+		args = append(args, filter.AuthorID)
+	}
+	if filter.Since != nil {
+		where = append(where, `created_at >= ?`)
+		args = append(args, sqliteTimestamp(*filter.Since))
+	}
+	if filter.Until != nil {
+		where = append(where, `created_at < ?`)
+		args = append(args, sqliteTimestamp(*filter.Until))
+	}
+	if len(filter.Tags) > 0 {
+		tagWhere, tagArgs := tagFilter(filter.Tags, filter.MatchAllTags)
+		where = append(where, tagWhere)
+		args = append(args, tagArgs...)
+	}
+	return where, args
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(conditions, ` AND `)
+}
+
+func (r *postRepository) GetPostsByCategory(ctx context.Context, categoryID, limit, offset int) ([]entity.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts
+              WHERE category_id = ? ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, categoryID, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to get category posts", zap.Error(err), zap.Int("categoryID", categoryID))
+		return nil, err
+	}
+	return r.scanPostsWithTags(ctx, rows)
+}
+
+func (r *postRepository) GetTags(ctx context.Context, limit int) ([]entity.TagCount, error) {
+	// JOIN с posts отсекает связи удаленных постов, если в SQLite выключены внешние ключи
+	query := `
+		SELECT t.name, COUNT(*) AS usage
+		FROM tags t
+		JOIN post_tags pt ON pt.tag_id = t.id
+		JOIN posts p ON p.id = pt.post_id
+		GROUP BY t.id
+		ORDER BY usage DESC, t.name
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("Failed to get tags", zap.Error(err))
+		return nil, err
+	}
 	defer rows.Close()
 
+	tags := []entity.TagCount{}
+	for rows.Next() {
+		var tag entity.TagCount
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func (r *postRepository) GetCategoryPostsCount(ctx context.Context, categoryID int) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM posts WHERE category_id = ?`, categoryID).Scan(&count)
+	return count, err
+}
+
+func scanPost(row rowScanner, post *entity.Post) error {
+	return row.Scan(&post.ID, &post.Title, &post.Content, &post.AuthorId, &post.CategoryId,
+		&post.Score, &post.CommentCount, &post.LastCommentAt, &post.CreatedAt, &post.UpdatedAt)
+}
+
+// scanPostsWithTags читает строки с колонками postColumns и подгружает теги постов одним запросом
+func (r *postRepository) scanPostsWithTags(ctx context.Context, rows *sql.Rows) ([]entity.Post, error) {
 	var posts []entity.Post
 	for rows.Next() {
 		var post entity.Post
-		if err := rows.Scan(&post.ID, &post.Title, &post.Content, &post.AuthorId); err != nil {
+		if err := scanPost(rows, &post); err != nil {
+			rows.Close()
 			return nil, err
 		}
 		posts = append(posts, post)
 	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(posts) == 0 {
+		return posts, nil
+	}
+	ids := make([]int, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	tags, err := r.postTags(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range posts {
+		posts[i].Tags = tags[posts[i].ID]
+	}
 	return posts, nil
 }
 
+// postTags возвращает теги постов по алфавиту; у поста без тегов - пустой срез
+func (r *postRepository) postTags(ctx context.Context, postIDs []int) (map[int][]string, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(postIDs)), ", ")
+	args := make([]any, len(postIDs))
+	result := make(map[int][]string, len(postIDs))
+	for i, id := range postIDs {
+		args[i] = id
+		result[id] = []string{}
+	}
+
+	query := `SELECT pt.post_id, t.name FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+              WHERE pt.post_id IN (` + placeholders + `) ORDER BY t.name`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get post tags", zap.Error(err), zap.Ints("postIDs", postIDs))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID int
+		var name string
+		if err := rows.Scan(&postID, &name); err != nil {
+			return nil, err
+		}
+		result[postID] = append(result[postID], name)
+	}
+	return result, rows.Err()
+}
+
+// setPostTags заменяет теги поста, недостающие теги создаются
+func (r *postRepository) setPostTags(ctx context.Context, tx *sql.Tx, postID int, tags []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = ?`, postID); err != nil {
+		r.logger.Error("Failed to clear post tags", zap.Error(err), zap.Int("postID", postID))
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?), ", len(tags)), ", ")
+	args := make([]any, len(tags))
+	for i, tag := range tags {
+		args[i] = tag
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO tags (name) VALUES `+placeholders, args...); err != nil {
+		r.logger.Error("Failed to create tags", zap.Error(err), zap.Strings("tags", tags))
+		return err
+	}
+
+	query := `INSERT INTO post_tags (post_id, tag_id)
+              SELECT ?, id FROM tags WHERE name IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(tags)), ", ") + `)`
+	if _, err := tx.ExecContext(ctx, query, append([]any{postID}, args...)...); err != nil {
+		r.logger.Error("Failed to set post tags", zap.Error(err), zap.Int("postID", postID))
+		return err
+	}
+	return nil
+}
+
+// tagFilter - условие на posts.id для фильтра по тегам
+func tagFilter(tags []string, matchAll bool) (string, []any) {
+	args := make([]any, len(tags))
+	for i, tag := range tags {
+		args[i] = tag
+	}
+	filter := `id IN (SELECT pt.post_id FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+              WHERE t.name IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(tags)), ", ") + `)`
+	if matchAll {
+		filter += ` GROUP BY pt.post_id HAVING COUNT(*) = ?`
+		args = append(args, len(tags))
+	}
+	return filter + `)`, args
+}
+
 func (r *postRepository) GetTotalPostsCount(ctx context.Context) (int, error) {
 	var count int
 	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM posts`).Scan(&count)
@@ -75,26 +346,132 @@ func (r *postRepository) GetTotalPostsCount(ctx context.Context) (int, error) {
 }
 
 func (r *postRepository) GetPostByID(ctx context.Context, id int) (*entity.Post, error) {
-	query := `SELECT id, author_id, title, content FROM posts WHERE id = ?`
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = ?`
 	var post entity.Post
-	err := r.db.GetContext(ctx, &post, query, id)
+	err := scanPost(r.db.QueryRowContext(ctx, query, id), &post)
 	if err != nil {
-		r.logger.Error("Failed to get post by ID", zap.Error(err), zap.Int("postID", id))
+		if err != sql.ErrNoRows {
+			r.logger.Error("Failed to get post by ID", zap.Error(err), zap.Int("postID", id))
+		}
 		return nil, err
 	}
+	tags, err := r.postTags(ctx, []int{post.ID})
+	if err != nil {
+		return nil, err
+	}
+	post.Tags = tags[post.ID]
 	r.logger.Info("Post retrieved successfully", zap.Int("postID", id))
 	return &post, nil
 }
 
-func (r *postRepository) UpdatePost(ctx context.Context, post entity.Post) (*entity.Post, error) {
-	query := `UPDATE posts SET title = ?, content = ? WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, post.Title, post.Content, post.ID) //TODO посмотреть что происходит при обновлении не сущ. записи
+// UpdatePost сохраняет правку и возвращает пост, перечитанный после коммита.
+// updated_at меняется при любом изменении, в том числе при правке только тегов
+func (r *postRepository) UpdatePost(ctx context.Context, post entity.Post, editorID int) (*entity.Post, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Failed to begin transaction", zap.Error(err), zap.Int("postID", post.ID))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var title, content string
+	err = tx.QueryRowContext(ctx, `SELECT title, content FROM posts WHERE id = ?`, post.ID).Scan(&title, &content)
 	if err != nil {
+		if err != sql.ErrNoRows {
+			r.logger.Error("Failed to get post for update", zap.Error(err), zap.Int("postID", post.ID))
+		}
+		return nil, err
+	}
+	changed := title != post.Title || content != post.Content
+	tagsChanged := false
+	if post.Tags != nil {
+		if tagsChanged, err = r.tagsDiffer(ctx, tx, post.ID, post.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if !changed && !tagsChanged {
+		// Транзакция закрывается до чтения, чтобы не держать второе соединение
+		tx.Rollback()
+		return r.GetPostByID(ctx, post.ID)
+	}
+
+	now := time.Now().UTC()
+	if changed {
+		if err := r.saveRevision(ctx, tx, post, editorID, now); err != nil {
+			return nil, err
+		}
+	} else if _, err := tx.ExecContext(ctx, `UPDATE posts SET updated_at = ? WHERE id = ?`, now, post.ID); err != nil {
 		r.logger.Error("Failed to update post", zap.Error(err), zap.Int("postID", post.ID))
 		return nil, err
 	}
-	r.logger.Info("Post updated successfully", zap.Int("postID", post.ID))
-	return &post, nil
+	if tagsChanged {
+		if err := r.setPostTags(ctx, tx, post.ID, post.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Failed to commit post update", zap.Error(err), zap.Int("postID", post.ID))
+		return nil, err
+	}
+	r.logger.Info("Post updated successfully", zap.Int("postID", post.ID), zap.Int("editorID", editorID))
+	return r.GetPostByID(ctx, post.ID)
+}
+
+// tagsDiffer сравнивает теги поста с новым набором без учета порядка
+func (r *postRepository) tagsDiffer(ctx context.Context, tx *sql.Tx, postID int, tags []string) (bool, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT t.name FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+              WHERE pt.post_id = ? ORDER BY t.name`, postID)
+	if err != nil {
+		r.logger.Error("Failed to get post tags", zap.Error(err), zap.Int("postID", postID))
+		return false, err
+	}
+	defer rows.Close()
+
+	current := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, err
+		}
+		current = append(current, name)
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	sorted := slices.Clone(tags)
+	slices.Sort(sorted)
+	return !slices.Equal(current, sorted), nil
+}
+
+// saveRevision обновляет текст поста и добавляет новую версию в историю
+func (r *postRepository) saveRevision(ctx context.Context, tx *sql.Tx, post entity.Post, editorID int, now time.Time) error {
+	// Посты, созданные до появления истории, получают первую версию при первой правке
+	backfill := `
+		INSERT INTO post_revisions (post_id, revision, title, content, editor_id, created_at)
+		SELECT id, 1, title, content, author_id, COALESCE(created_at, CURRENT_TIMESTAMP) FROM posts
+		WHERE id = ? AND NOT EXISTS (SELECT 1 FROM post_revisions WHERE post_id = ?)
+	`
+	if _, err := tx.ExecContext(ctx, backfill, post.ID, post.ID); err != nil {
+		r.logger.Error("Failed to save initial revision", zap.Error(err), zap.Int("postID", post.ID))
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE posts SET title = ?, content = ?, updated_at = ? WHERE id = ?`,
+		post.Title, post.Content, now, post.ID); err != nil {
+		r.logger.Error("Failed to update post", zap.Error(err), zap.Int("postID", post.ID))
+		return err
+	}
+
+	revision := `
+		INSERT INTO post_revisions (post_id, revision, title, content, editor_id, created_at)
+		SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ? FROM post_revisions WHERE post_id = ?
+	`
+	if _, err := tx.ExecContext(ctx, revision, post.ID, post.Title, post.Content, editorID, now, post.ID); err != nil {
+		r.logger.Error("Failed to save revision", zap.Error(err), zap.Int("postID", post.ID))
+		return err
+	}
+	return nil
 }
 
 func (r *postRepository) DeletePost(ctx context.Context, id int) error {
@@ -108,14 +485,45 @@ func (r *postRepository) DeletePost(ctx context.Context, id int) error {
 	return nil
 }
 
-func (r *postRepository) GetUserIDByToken(ctx context.Context, token string) (int, error) {
-	query := `SELECT user_id FROM tokens WHERE token = ?`
-	var userID int
-	err := r.db.GetContext(ctx, &userID, query, token)
+func (r *postRepository) GetPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error) {
+	query := `
+		SELECT id, post_id, revision, title, content, COALESCE(editor_id, 0), created_at
+		FROM post_revisions
+		WHERE post_id = ?
+		ORDER BY revision ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		r.logger.Error("Failed to get post revisions", zap.Error(err), zap.Int("postID", postID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []entity.PostRevision{}
+	for rows.Next() {
+		var rev entity.PostRevision
+		if err := rows.Scan(&rev.ID, &rev.PostId, &rev.Revision, &rev.Title, &rev.Content, &rev.EditorId, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *postRepository) GetPostRevision(ctx context.Context, postID, revision int) (*entity.PostRevision, error) {
+	query := `
+		SELECT id, post_id, revision, title, content, COALESCE(editor_id, 0), created_at
+		FROM post_revisions
+		WHERE post_id = ? AND revision = ?
+	`
+	var rev entity.PostRevision
+	err := r.db.QueryRowContext(ctx, query, postID, revision).
+		Scan(&rev.ID, &rev.PostId, &rev.Revision, &rev.Title, &rev.Content, &rev.EditorId, &rev.CreatedAt)
 	if err != nil {
-		r.logger.Error("Failed to get user ID by token", zap.Error(err), zap.String("token", token))
-		return 0, err
+		if err != sql.ErrNoRows {
+			r.logger.Error("Failed to get post revision", zap.Error(err), zap.Int("postID", postID), zap.Int("revision", revision))
+		}
+		return nil, err
 	}
-	r.logger.Info("User ID retrieved successfully", zap.String("token", token), zap.Int("userID", userID))
-	return userID, nil
+	return &rev, nil
 }
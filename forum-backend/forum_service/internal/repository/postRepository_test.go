@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"testing"
+	"time"
 )
 
 func TestPostRepository_CreatePost_Success(t *testing.T) {
@@ -19,7 +21,7 @@ func TestPostRepository_CreatePost_Success(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	dbAdapter := adapters.DbAdapter{db}
+	dbAdapter := adapters.DbAdapter{DB: db}
 
 	postRepo := NewPostRepository(&dbAdapter, logger)
 
@@ -30,10 +32,13 @@ func TestPostRepository_CreatePost_Success(t *testing.T) {
 	}
 	createdPost := post
 	createdPost.ID = 1
+	createdPost.Tags = []string{}
 
-	mock.ExpectExec(`INSERT INTO posts \(author_id, title, content\) VALUES \(\?, \?, \?\)`).
-		WithArgs(post.AuthorId, post.Title, post.Content).
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO posts \(author_id, title, content, category_id\) VALUES \(\?, \?, \?, \?\)`).
+		WithArgs(post.AuthorId, post.Title, post.Content, post.CategoryId).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	result, err := postRepo.CreatePost(context.Background(), post)
 
@@ -61,9 +66,11 @@ func TestPostRepository_CreatePost_Failure(t *testing.T) {
 		Content:  "This is a test post",
 	}
 
-	mock.ExpectExec(`INSERT INTO posts \(author_id, title, content\) VALUES \(\?, \?, \?\)`).
-		WithArgs(post.AuthorId, post.Title, post.Content).
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO posts \(author_id, title, content, category_id\) VALUES \(\?, \?, \?, \?\)`).
+		WithArgs(post.AuthorId, post.Title, post.Content, post.CategoryId).
 		WillReturnError(errors.New("failed to create post"))
+	mock.ExpectRollback()
 
 	result, err := postRepo.CreatePost(context.Background(), post)
 
@@ -85,19 +92,24 @@ func TestPostRepository_GetPosts_Success(t *testing.T) {
 
 	postRepo := NewPostRepository(dbAdapter, logger)
 
+	now := time.Now()
 	posts := []entity.Post{
-		{ID: 1, AuthorId: 1, Title: "Post 1", Content: "Content 1"},
-		{ID: 2, AuthorId: 2, Title: "Post 2", Content: "Content 2"},
+		{ID: 1, AuthorId: 1, Title: "Post 1", Content: "Content 1", Tags: []string{"go", "grpc"}, CreatedAt: now, UpdatedAt: now},
+		{ID: 2, AuthorId: 2, Title: "Post 2", Content: "Content 2", Tags: []string{}, CreatedAt: now, UpdatedAt: now},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "author_id", "title", "content"})
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "category_id", "score", "comment_count", "last_comment_at", "created_at", "updated_at"})
 	for _, post := range posts {
-		rows.AddRow(post.ID, post.AuthorId, post.Title, post.Content)
+		rows.AddRow(post.ID, post.Title, post.Content, post.AuthorId, post.CategoryId, post.Score, post.CommentCount, post.LastCommentAt, post.CreatedAt, post.UpdatedAt)
 	}
-	mock.ExpectQuery(`SELECT id, author_id, title, content FROM posts`).
+	mock.ExpectQuery(`SELECT id, title, content, author_id, category_id, score, comment_count, last_comment_at, created_at, updated_at FROM posts`).
+		WithArgs(10, 0).
 		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT pt.post_id, t.name FROM post_tags pt JOIN tags t`).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "name"}).AddRow(1, "go").AddRow(1, "grpc"))
 
-	result, err := postRepo.GetPosts(context.Background())
+	result, err := postRepo.GetPosts(context.Background(), 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, posts, result)
@@ -117,10 +129,11 @@ func TestPostRepository_GetPosts_Failure(t *testing.T) {
 
 	postRepo := NewPostRepository(dbAdapter, logger)
 
-	mock.ExpectQuery(`SELECT id, author_id, title, content FROM posts`).
+	mock.ExpectQuery(`SELECT id, title, content, author_id, category_id, score, comment_count, last_comment_at, created_at, updated_at FROM posts`).
+		WithArgs(10, 0).
 		WillReturnError(errors.New("failed to get posts"))
 
-	result, err := postRepo.GetPosts(context.Background())
+	result, err := postRepo.GetPosts(context.Background(), 10, 0)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -142,7 +155,7 @@ func TestPostRepository_GetPostByID_Failure(t *testing.T) {
 
 	postID := 1
 
-	mock.ExpectQuery(`SELECT id, author_id, title, content FROM posts WHERE id = \?`).
+	mock.ExpectQuery(`SELECT id, title, content, author_id, category_id, score, comment_count, last_comment_at, created_at, updated_at FROM posts WHERE id = \?`).
 		WithArgs(postID).
 		WillReturnError(errors.New("failed to get post"))
 
@@ -154,6 +167,71 @@ func TestPostRepository_GetPostByID_Failure(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestPostRepository_GetPostByID_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dbAdapter := &adapters.DbAdapter{DB: db}
+
+	postRepo := NewPostRepository(dbAdapter, logger)
+
+	created := time.Now().Add(-time.Hour)
+	updated := time.Now()
+	mock.ExpectQuery(`SELECT id, title, content, author_id, category_id, score, comment_count, last_comment_at, created_at, updated_at FROM posts WHERE id = \?`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "category_id", "score", "comment_count", "last_comment_at", "created_at", "updated_at"}).
+			AddRow(1, "Post", "Content", 2, 3, 5, 4, updated, created, updated))
+	mock.ExpectQuery(`SELECT pt.post_id, t.name FROM post_tags pt JOIN tags t`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "name"}).AddRow(1, "go"))
+
+	result, err := postRepo.GetPostByID(context.Background(), 1)
+
+	categoryID := 3
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.Post{ID: 1, AuthorId: 2, Title: "Post", Content: "Content", CategoryId: &categoryID, Tags: []string{"go"}, Score: 5, CommentCount: 4, LastCommentAt: &updated, CreatedAt: created, UpdatedAt: updated}, result)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_GetPostsByCategory(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dbAdapter := &adapters.DbAdapter{DB: db}
+
+	postRepo := NewPostRepository(dbAdapter, logger)
+
+	// Посты, созданные в одну секунду, упорядочены по id, чтобы страницы не пересекались
+	created := time.Now()
+	mock.ExpectQuery(`FROM posts\s+WHERE category_id = \? ORDER BY created_at DESC, id DESC LIMIT \? OFFSET \?`).
+		WithArgs(3, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "category_id", "score", "comment_count", "last_comment_at", "created_at", "updated_at"}).
+			AddRow(2, "Second", "Content", 1, 3, 0, 0, nil, created, created).
+			AddRow(1, "First", "Content", 1, 3, 0, 0, nil, created, created))
+	mock.ExpectQuery(`SELECT pt.post_id, t.name FROM post_tags pt JOIN tags t`).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "name"}))
+
+	posts, err := postRepo.GetPostsByCategory(context.Background(), 3, 10, 0)
+
+	assert.NoError(t, err)
+	if assert.Len(t, posts, 2) {
+		assert.Equal(t, 2, posts[0].ID)
+		assert.Equal(t, 1, posts[1].ID)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestPostRepository_UpdatePost_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
@@ -173,14 +251,125 @@ func TestPostRepository_UpdatePost_Success(t *testing.T) {
 		Content:  "This is an updated post",
 	}
 
-	mock.ExpectExec(`UPDATE posts SET title = \?, content = \? WHERE id = \?`).
-		WithArgs(post.Title, post.Content, post.ID).
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT title, content FROM posts WHERE id = \?`).
+		WithArgs(post.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "content"}).AddRow("Post", "This is a post"))
+	mock.ExpectExec(`INSERT INTO post_revisions .* SELECT id, 1, title, content, author_id`).
+		WithArgs(post.ID, post.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE posts SET title = \?, content = \?, updated_at = \? WHERE id = \?`).
+		WithArgs(post.Title, post.Content, sqlmock.AnyArg(), post.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO post_revisions .* COALESCE\(MAX\(revision\), 0\) \+ 1`).
+		WithArgs(post.ID, post.Title, post.Content, 2, sqlmock.AnyArg(), post.ID).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+	// После коммита пост перечитывается вместе с новым updated_at
+	created := time.Now().Add(-time.Hour)
+	updated := time.Now()
+	expectPostReread(mock, post.ID, post.Title, post.Content, created, updated)
 
-	result, err := postRepo.UpdatePost(context.Background(), post)
+	result, err := postRepo.UpdatePost(context.Background(), post, 2)
 
 	assert.NoError(t, err)
-	assert.Equal(t, &post, result)
+	assert.Equal(t, &entity.Post{ID: 1, AuthorId: 1, Title: post.Title, Content: post.Content, Tags: []string{}, CreatedAt: created, UpdatedAt: updated}, result)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectPostReread ожидает чтение поста через GetPostByID
+func expectPostReread(mock sqlmock.Sqlmock, postID int, title, content string, created, updated time.Time, tags ...string) {
+	mock.ExpectQuery(`SELECT id, title, content, author_id, category_id, score, comment_count, last_comment_at, created_at, updated_at FROM posts WHERE id = \?`).
+		WithArgs(postID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "category_id", "score", "comment_count", "last_comment_at", "created_at", "updated_at"}).
+			AddRow(postID, title, content, 1, nil, 0, 0, nil, created, updated))
+	tagRows := sqlmock.NewRows([]string{"post_id", "name"})
+	for _, tag := range tags {
+		tagRows.AddRow(postID, tag)
+	}
+	mock.ExpectQuery(`SELECT pt.post_id, t.name FROM post_tags pt JOIN tags t`).
+		WithArgs(postID).
+		WillReturnRows(tagRows)
+}
+
+func TestPostRepository_UpdatePost_TagsOnly(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dbAdapter := &adapters.DbAdapter{DB: db}
+
+	postRepo := NewPostRepository(dbAdapter, logger)
+
+	post := entity.Post{ID: 1, Title: "Post", Content: "Content", Tags: []string{"sql", "go"}}
+	created := time.Now().Add(-time.Hour)
+	updated := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT title, content FROM posts WHERE id = \?`).
+		WithArgs(post.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "content"}).AddRow("Post", "Content"))
+	mock.ExpectQuery(`SELECT t.name FROM post_tags pt JOIN tags t`).
+		WithArgs(post.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("go"))
+	mock.ExpectExec(`UPDATE posts SET updated_at = \? WHERE id = \?`).
+		WithArgs(sqlmock.AnyArg(), post.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM post_tags WHERE post_id = \?`).
+		WithArgs(post.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT OR IGNORE INTO tags`).
+		WithArgs("sql", "go").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec(`INSERT INTO post_tags`).
+		WithArgs(post.ID, "sql", "go").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	expectPostReread(mock, post.ID, post.Title, post.Content, created, updated, "go", "sql")
+
+	result, err := postRepo.UpdatePost(context.Background(), post, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "sql"}, result.Tags)
+	assert.Equal(t, updated, result.UpdatedAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_UpdatePost_Unchanged(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dbAdapter := &adapters.DbAdapter{DB: db}
+
+	postRepo := NewPostRepository(dbAdapter, logger)
+
+	post := entity.Post{ID: 1, Title: "Post", Content: "Content", Tags: []string{"sql", "go"}}
+	created := time.Now().Add(-time.Hour)
+
+	// Тот же текст и те же теги в другом порядке: ничего не пишется, updated_at не меняется
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT title, content FROM posts WHERE id = \?`).
+		WithArgs(post.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "content"}).AddRow("Post", "Content"))
+	mock.ExpectQuery(`SELECT t.name FROM post_tags pt JOIN tags t`).
+		WithArgs(post.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("go").AddRow("sql"))
+	mock.ExpectRollback()
+	expectPostReread(mock, post.ID, post.Title, post.Content, created, created, "go", "sql")
+
+	result, err := postRepo.UpdatePost(context.Background(), post, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, created, result.UpdatedAt)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -204,11 +393,19 @@ func TestPostRepository_UpdatePost_Failure(t *testing.T) {
 		Content:  "This is an updated post",
 	}
 
-	mock.ExpectExec(`UPDATE posts SET title = \?, content = \? WHERE id = \?`).
-		WithArgs(post.Title, post.Content, post.ID).
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT title, content FROM posts WHERE id = \?`).
+		WithArgs(post.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "content"}).AddRow("Post", "This is a post"))
+	mock.ExpectExec(`INSERT INTO post_revisions`).
+		WithArgs(post.ID, post.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE posts SET title = \?, content = \?, updated_at = \? WHERE id = \?`).
+		WithArgs(post.Title, post.Content, sqlmock.AnyArg(), post.ID).
 		WillReturnError(errors.New("failed to update post"))
+	mock.ExpectRollback()
 
-	result, err := postRepo.UpdatePost(context.Background(), post)
+	result, err := postRepo.UpdatePost(context.Background(), post, 1)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -266,7 +463,7 @@ func TestPostRepository_DeletePost_Failure(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestPostRepository_GetUserIDByToken_Success(t *testing.T) {
+func TestPostRepository_UpdatePost_NotFound(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
@@ -278,44 +475,162 @@ func TestPostRepository_GetUserIDByToken_Success(t *testing.T) {
 
 	postRepo := NewPostRepository(dbAdapter, logger)
 
-	token := "test-token"
-	userID := 1
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT title, content FROM posts WHERE id = \?`).
+		WithArgs(42).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
 
-	rows := sqlmock.NewRows([]string{"user_id"}).AddRow(userID)
-	mock.ExpectQuery(`SELECT user_id FROM tokens WHERE token = \?`).
-		WithArgs(token).
-		WillReturnRows(rows)
+	result, err := postRepo.UpdatePost(context.Background(), entity.Post{ID: 42, Title: "T"}, 1)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	result, err := postRepo.GetUserIDByToken(context.Background(), token)
+func TestPostRepository_GetPostRevisions_Success(t *testing.T) {
 
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
-	assert.Equal(t, userID, result)
+	defer db.Close()
+
+	dbAdapter := &adapters.DbAdapter{DB: db}
+
+	postRepo := NewPostRepository(dbAdapter, logger)
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM post_revisions WHERE post_id = \? ORDER BY revision ASC`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "revision", "title", "content", "editor_id", "created_at"}).
+			AddRow(1, 1, 1, "Post", "Old", 1, now).
+			AddRow(2, 1, 2, "Post", "New", 2, now))
 
+	result, err := postRepo.GetPostRevisions(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.PostRevision{
+		{ID: 1, PostId: 1, Revision: 1, Title: "Post", Content: "Old", EditorId: 1, CreatedAt: now},
+		{ID: 2, PostId: 1, Revision: 2, Title: "Post", Content: "New", EditorId: 2, CreatedAt: now},
+	}, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestPostRepository_GetUserIDByToken_Failure(t *testing.T) {
+func TestPostRepository_CreatePost_WithTags(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	postRepo := NewPostRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	post := entity.Post{AuthorId: 1, Title: "Test Post", Content: "Content", Tags: []string{"go", "grpc"}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO posts`).
+		WithArgs(post.AuthorId, post.Title, post.Content, post.CategoryId).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(`DELETE FROM post_tags WHERE post_id = \?`).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT OR IGNORE INTO tags \(name\) VALUES \(\?\), \(\?\)`).
+		WithArgs("go", "grpc").
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectExec(`INSERT INTO post_tags \(post_id, tag_id\)\s+SELECT \?, id FROM tags WHERE name IN \(\?, \?\)`).
+		WithArgs(5, "go", "grpc").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	result, err := postRepo.CreatePost(context.Background(), post)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.ID)
+	assert.Equal(t, []string{"go", "grpc"}, result.Tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
+func TestPostRepository_ListPosts_MatchAllTags(t *testing.T) {
 	logger, _ := zap.NewProduction()
 
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	dbAdapter := &adapters.DbAdapter{DB: db}
+	postRepo := NewPostRepository(&adapters.DbAdapter{DB: db}, logger)
 
-	postRepo := NewPostRepository(dbAdapter, logger)
+	now := time.Now()
+	mock.ExpectQuery(`FROM posts WHERE id IN \(SELECT pt.post_id .* WHERE t.name IN \(\?, \?\) GROUP BY pt.post_id HAVING COUNT\(\*\) = \?\) ORDER BY created_at DESC, id DESC LIMIT \? OFFSET \?`).
+		WithArgs("go", "grpc", 2, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "category_id", "score", "comment_count", "last_comment_at", "created_at", "updated_at"}).
+			AddRow(3, "Post", "Content", 1, nil, 0, 0, nil, now, now))
+	mock.ExpectQuery(`SELECT pt.post_id, t.name FROM post_tags pt JOIN tags t`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "name"}).AddRow(3, "go").AddRow(3, "grpc"))
 
-	token := "test-token"
+	result, err := postRepo.ListPosts(context.Background(), entity.PostFilter{Tags: []string{"go", "grpc"}, MatchAllTags: true}, 10, 0)
 
-	mock.ExpectQuery(`SELECT user_id FROM tokens WHERE token = \?`).
-		WithArgs(token).
-		WillReturnError(errors.New("failed to get user ID by token"))
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Post{{ID: 3, AuthorId: 1, Title: "Post", Content: "Content", Tags: []string{"go", "grpc"}, CreatedAt: now, UpdatedAt: now}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	result, err := postRepo.GetUserIDByToken(context.Background(), token)
+func TestPostRepository_ListPosts_CursorAndFilters(t *testing.T) {
+	logger, _ := zap.NewProduction()
 
-	assert.Error(t, err)
-	assert.Equal(t, 0, result)
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	postRepo := NewPostRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	cursor := &entity.Cursor{CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), ID: 7}
+	since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM posts WHERE author_id = \? AND created_at >= \? AND \(created_at > \? OR \(created_at = \? AND id > \?\)\) ORDER BY created_at ASC, id ASC LIMIT \? OFFSET \?`).
+		WithArgs(5, "2024-04-01 00:00:00", "2024-05-01 12:30:00", "2024-05-01 12:30:00", 7, 3, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "category_id", "score", "comment_count", "last_comment_at", "created_at", "updated_at"}))
+
+	filter := entity.PostFilter{Sort: entity.PostSortOld, AuthorID: 5, Since: &since, After: cursor}
+	result, err := postRepo.ListPosts(context.Background(), filter, 3, 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_ListPosts_SortByActivity(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	postRepo := NewPostRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectQuery(`FROM posts ORDER BY COALESCE\(last_comment_at, created_at\) DESC, id DESC LIMIT \? OFFSET \?`).
+		WithArgs(10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "category_id", "score", "comment_count", "last_comment_at", "created_at", "updated_at"}))
+
+	result, err := postRepo.ListPosts(context.Background(), entity.PostFilter{Sort: entity.PostSortActive}, 10, 20)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_ListPosts_UnknownSort(t *testing.T) {
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
 
+	postRepo := NewPostRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	result, err := postRepo.ListPosts(context.Background(), entity.PostFilter{Sort: "title; DROP TABLE posts"}, 10, 0)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
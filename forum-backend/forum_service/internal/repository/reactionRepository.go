@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"go.uber.org/zap"
+)
+
+type ReactionRepository interface {
+	// AddReaction ставит реакцию, повторная такая же реакция ничего не меняет. Если цели нет - sql.ErrNoRows
+	AddReaction(ctx context.Context, reaction entity.Reaction) error
+	// RemoveReaction снимает реакцию, если она была. Если цели нет - sql.ErrNoRows
+	RemoveReaction(ctx context.Context, reaction entity.Reaction) error
+	// GetReactionCounts возвращает реакции на цели targetIDs по эмодзи в порядке первой
+	// реакции. ReactedByMe считается для userID (0 - анонимный запрос). Цели без реакций в результате нет
+	GetReactionCounts(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int][]entity.ReactionCount, error)
+}
+
+// reactionTargetTables - таблицы целей реакций
+var reactionTargetTables = map[string]string{
+	entity.ReactionTargetPost:        "posts",
+	entity.ReactionTargetComment:     "comments",
+	entity.ReactionTargetChatMessage: "chat_messages",
+}
+
+type reactionRepository struct {
+	db     DB
+	logger *zap.Logger
+}
+
+func NewReactionRepository(db DB, logger *zap.Logger) ReactionRepository {
+	return &reactionRepository{db: db, logger: logger}
+}
+
+func (r *reactionRepository) AddReaction(ctx context.Context, reaction entity.Reaction) error {
+	if err := r.checkTarget(ctx, reaction); err != nil {
+		return err
+	}
+
+	query := `INSERT OR IGNORE INTO reactions (user_id, target_type, target_id, emoji) VALUES (?, ?, ?, ?)`
+	if _, err := r.db.ExecContext(ctx, query, reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Emoji); err != nil {
+		r.logger.Error("Failed to add reaction", zap.Error(err), zap.Int("userID", reaction.UserID))
+		return err
+	}
+	return nil
+}
+
+func (r *reactionRepository) RemoveReaction(ctx context.Context, reaction entity.Reaction) error {
+	if err := r.checkTarget(ctx, reaction); err != nil {
+		return err
+	}
+
+	query := `DELETE FROM reactions WHERE user_id = ? AND target_type = ? AND target_id = ? AND emoji = ?`
+	if _, err := r.db.ExecContext(ctx, query, reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Emoji); err != nil {
+		r.logger.Error("Failed to remove reaction", zap.Error(err), zap.Int("userID", reaction.UserID))
+		return err
+	}
+	return nil
+}
+
+// checkTarget проверяет, что цель реакции существует
+func (r *reactionRepository) checkTarget(ctx context.Context, reaction entity.Reaction) error {
+	table, ok := reactionTargetTables[reaction.TargetType]
+	if !ok {
+		return fmt.Errorf("unknown reaction target %q", reaction.TargetType)
+	}
+	var id int
+	return r.db.QueryRowContext(ctx, `SELECT id FROM `+table+` WHERE id = ?`, reaction.TargetID).Scan(&id)
+}
+
+func (r *reactionRepository) GetReactionCounts(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int][]entity.ReactionCount, error) {
+	counts := make(map[int][]entity.ReactionCount, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+
+	args := make([]any, 0, len(targetIDs)+2)
+	args = append(args, userID, targetType)
+	for _, id := range targetIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(targetIDs)), ", ")
+	query := `
+        SELECT target_id, emoji, COUNT(*), MAX(user_id = ?)
+        FROM reactions
+        WHERE target_type = ? AND target_id IN (` + placeholders + `)
+        GROUP BY target_id, emoji
+        ORDER BY target_id, MIN(id)
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get reaction counts", zap.Error(err), zap.String("targetType", targetType))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var targetID int
+		var count entity.ReactionCount
+		if err := rows.Scan(&targetID, &count.Emoji, &count.Count, &count.ReactedByMe); err != nil {
+			return nil, err
+		}
+		counts[targetID] = append(counts[targetID], count)
+	}
+	return counts, rows.Err()
+}
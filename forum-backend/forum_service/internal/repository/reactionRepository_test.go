@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository/adapters"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestReactionRepository_AddReaction_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	reactionRepo := NewReactionRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectQuery(`SELECT id FROM chat_messages WHERE id = \?`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectExec(`INSERT OR IGNORE INTO reactions \(user_id, target_type, target_id, emoji\) VALUES \(\?, \?, \?, \?\)`).
+		WithArgs(2, entity.ReactionTargetChatMessage, 4, "🎉").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = reactionRepo.AddReaction(context.Background(), entity.Reaction{UserID: 2, TargetType: entity.ReactionTargetChatMessage, TargetID: 4, Emoji: "🎉"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReactionRepository_RemoveReaction_TargetNotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	reactionRepo := NewReactionRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectQuery(`SELECT id FROM comments WHERE id = \?`).
+		WithArgs(9).
+		WillReturnError(sql.ErrNoRows)
+
+	err = reactionRepo.RemoveReaction(context.Background(), entity.Reaction{UserID: 2, TargetType: entity.ReactionTargetComment, TargetID: 9, Emoji: "👍"})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReactionRepository_GetReactionCounts(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	reactionRepo := NewReactionRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectQuery(`SELECT target_id, emoji, COUNT\(\*\), MAX\(user_id = \?\) FROM reactions WHERE target_type = \? AND target_id IN \(\?, \?\) GROUP BY target_id, emoji`).
+		WithArgs(2, entity.ReactionTargetPost, 1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"target_id", "emoji", "count", "mine"}).
+			AddRow(1, "👍", 3, 1).
+			AddRow(1, "🎉", 1, 0).
+			AddRow(3, "❤️", 2, 0))
+
+	counts, err := reactionRepo.GetReactionCounts(context.Background(), 2, entity.ReactionTargetPost, []int{1, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int][]entity.ReactionCount{
+		1: {{Emoji: "👍", Count: 3, ReactedByMe: true}, {Emoji: "🎉", Count: 1}},
+		3: {{Emoji: "❤️", Count: 2}},
+	}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// SearchRepository ищет по FTS5-индексам posts_fts и comments_fts (миграция 012).
+// Драйвер sqlite3 должен быть собран с тегом sqlite_fts5
+type SearchRepository interface {
+	// Search возвращает страницу результатов, самые релевантные (bm25) первыми
+	Search(ctx context.Context, filter entity.SearchFilter) ([]entity.SearchResult, error)
+	CountResults(ctx context.Context, filter entity.SearchFilter) (int, error)
+}
+
+// ErrFTS5Unavailable - драйвер sqlite3 собран без модуля FTS5, нужен тег сборки sqlite_fts5
+var ErrFTS5Unavailable = errors.New("sqlite3 driver is built without FTS5, build with -tags sqlite_fts5")
+
+// CheckFTS5 проверяет, что драйвер поддерживает FTS5 и поиск будет работать
+func CheckFTS5(db *sqlx.DB) error {
+	// Пробная таблица создается в транзакции и откатывается вместе с ней
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`CREATE VIRTUAL TABLE temp.fts5_probe USING fts5(content)`); err != nil {
+		return fmt.Errorf("%w: %v", ErrFTS5Unavailable, err)
+	}
+	return nil
+}
+
+// Маркеры совпадений внутри highlight()/snippet() - char(2) и char(3) в SQL. После экранирования
+// текста их можно заменить на теги: кроме <mark> в результат ничего не попадет
+const (
+	markStart = "\x02"
+	markEnd   = "\x03"
+)
+
+type searchRepository struct {
+	db     DB
+	logger *zap.Logger
+}
+
+func NewSearchRepository(db DB, logger *zap.Logger) SearchRepository {
+	return &searchRepository{db: db, logger: logger}
+}
+
+func (r *searchRepository) Search(ctx context.Context, filter entity.SearchFilter) ([]entity.SearchResult, error) {
+	union, args := searchUnion(filter)
+	query := `SELECT type, post_id, comment_id, author_id, title, snippet, score, created_at FROM (` + union + `)
+              ORDER BY score DESC, created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to search", zap.Error(err), zap.String("query", filter.Query))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []entity.SearchResult{}
+	for rows.Next() {
+		var result entity.SearchResult
+		if err := rows.Scan(&result.Type, &result.PostId, &result.CommentId, &result.AuthorId,
+			&result.Title, &result.Snippet, &result.Score, &result.CreatedAt); err != nil {
+			r.logger.Error("Failed to scan search result", zap.Error(err))
+			return nil, err
+		}
+		result.Title = highlightHTML(result.Title)
+		result.Snippet = highlightHTML(result.Snippet)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Failed to iterate search results", zap.Error(err))
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *searchRepository) CountResults(ctx context.Context, filter entity.SearchFilter) (int, error) {
+	union, args := searchUnion(filter)
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+union+`)`, args...).Scan(&count)
+	if err != nil {
+		r.logger.Error("Failed to count search results", zap.Error(err), zap.String("query", filter.Query))
+		return 0, err
+	}
+	return count, nil
+}
+
+// searchUnion собирает выборку постов и комментариев с одинаковым набором колонок.
+// score = -bm25, чтобы больше значило релевантнее; совпадение в заголовке поста весит в 10 раз больше
+func searchUnion(filter entity.SearchFilter) (string, []any) {
+	match := matchQuery(filter.Query)
+
+	postWhere, postArgs := searchConditions("p", filter)
+	posts := `SELECT 'post' AS type, p.id AS post_id, NULL AS comment_id, p.author_id AS author_id,
+                     highlight(posts_fts, 0, char(2), char(3)) AS title,
+                     snippet(posts_fts, 1, char(2), char(3), '…', 16) AS snippet,
+                     -bm25(posts_fts, 10.0, 1.0) AS score,
+                     p.created_at AS created_at
+              FROM posts_fts JOIN posts p ON p.id = posts_fts.rowid
+              WHERE posts_fts MATCH ?` + postWhere
+
+	commentWhere, commentArgs := searchConditions("c", filter)
+	comments := `SELECT 'comment', c.post_id, c.id, c.author_id,
+                     p.title,
+                     snippet(comments_fts, 0, char(2), char(3), '…', 16),
+                     -bm25(comments_fts),
+                     c.created_at
+              FROM comments_fts
+              JOIN comments c ON c.id = comments_fts.rowid
+              JOIN posts p ON p.id = c.post_id
+              WHERE comments_fts MATCH ? AND c.deleted_at IS NULL` + commentWhere
+
+	args := append([]any{match}, postArgs...)
+	args = append(args, match)
+	args = append(args, commentArgs...)
+	return posts + ` UNION ALL ` + comments, args
+}
+
+func searchConditions(alias string, filter entity.SearchFilter) (string, []any) {
+	var where strings.Builder
+	var args []any
+	if filter.AuthorID > 0 {
+		where.WriteString(` AND ` + alias + `.author_id = ?`)
+		args = append(args, filter.AuthorID)
+	}
+	if filter.Since != nil {
+		where.WriteString(` AND ` + alias + `.created_at >= ?`)
+		args = append(args, sqliteTimestamp(*filter.Since))
+	}
+	if filter.Until != nil {
+		where.WriteString(` AND ` + alias + `.created_at < ?`)
+		args = append(args, sqliteTimestamp(*filter.Until))
+	}
+	return where.String(), args
+}
+
+// matchQuery превращает пользовательский ввод в запрос FTS5: каждое слово - отдельная
+// фраза в кавычках (операторы FTS5 не срабатывают), все слова обязательны,
+// последнее ищется по префиксу
+func matchQuery(q string) string {
+	terms := strings.Fields(q)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	if len(terms) > 0 {
+		terms[len(terms)-1] += "*"
+	}
+	return strings.Join(terms, " ")
+}
+
+// highlightHTML экранирует текст и заменяет маркеры совпадений на <mark>
+func highlightHTML(text string) string {
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, markStart, "<mark>")
+	return strings.ReplaceAll(text, markEnd, "</mark>")
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository/adapters"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestSearchRepository_Search_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	searchRepo := NewSearchRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := since.Add(time.Hour)
+	commentID := 7
+	filter := entity.SearchFilter{Query: "go lang", AuthorID: 3, Since: &since, Limit: 10, Offset: 20}
+
+	mock.ExpectQuery(`posts_fts MATCH \? AND p.author_id = \? AND p.created_at >= \?.*comments_fts MATCH \? AND c.deleted_at IS NULL AND c.author_id = \? AND c.created_at >= \?.*LIMIT \? OFFSET \?`).
+		WithArgs(`"go" "lang"*`, 3, "2025-01-01 00:00:00", `"go" "lang"*`, 3, "2025-01-01 00:00:00", 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "post_id", "comment_id", "author_id", "title", "snippet", "score", "created_at"}).
+			AddRow("post", 1, nil, 3, "About \x02Go\x03 <3", "\x02go\x03 \x02lang\x03uage", 2.5, createdAt).
+			AddRow("comment", 1, commentID, 3, "About Go <3", "I like \x02go\x03", 1.0, createdAt))
+
+	results, err := searchRepo.Search(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.SearchResult{
+		{Type: "post", PostId: 1, AuthorId: 3, Title: "About <mark>Go</mark> &lt;3", Snippet: "<mark>go</mark> <mark>lang</mark>uage", Score: 2.5, CreatedAt: createdAt},
+		{Type: "comment", PostId: 1, CommentId: &commentID, AuthorId: 3, Title: "About Go &lt;3", Snippet: "I like <mark>go</mark>", Score: 1.0, CreatedAt: createdAt},
+	}, results)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepository_Search_Failure(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	searchRepo := NewSearchRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectQuery(`posts_fts MATCH`).WillReturnError(errors.New("no such module: fts5"))
+
+	results, err := searchRepo.Search(context.Background(), entity.SearchFilter{Query: "go", Limit: 10})
+
+	assert.Error(t, err)
+	assert.Nil(t, results)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepository_CountResults_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	searchRepo := NewSearchRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM \(.*posts_fts MATCH \?.*comments_fts MATCH \?`).
+		WithArgs(`"go"*`, `"go"*`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	count, err := searchRepo.CountResults(context.Background(), entity.SearchFilter{Query: "go"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMatchQuery_EscapesOperators(t *testing.T) {
+	assert.Equal(t, `"title:go" "OR" "say" """hi"""*`, matchQuery(`title:go OR say "hi"`))
+}
+
+func TestCheckFTS5(t *testing.T) {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	err = CheckFTS5(db)
+	if fts5Built {
+		assert.NoError(t, err)
+	} else {
+		assert.ErrorIs(t, err, ErrFTS5Unavailable)
+	}
+}
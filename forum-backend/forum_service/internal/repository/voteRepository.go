@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"go.uber.org/zap"
+)
+
+type VoteRepository interface {
+	// SetVote ставит, меняет или (при vote.Value == 0) снимает голос и в той же транзакции
+	// пересчитывает score цели. Возвращает новый score. Если цели нет - sql.ErrNoRows
+	SetVote(ctx context.Context, vote entity.Vote) (int, error)
+	// GetUserVotes возвращает голоса пользователя за цели targetIDs: id цели -> 1 или -1.
+	// Целей, за которые пользователь не голосовал, в результате нет
+	GetUserVotes(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int]int, error)
+}
+
+// voteTargetTables - таблицы целей голосования со столбцом score
+var voteTargetTables = map[string]string{
+	entity.VoteTargetPost:    "posts",
+	entity.VoteTargetComment: "comments",
+}
+
+type voteRepository struct {
+	db     DB
+	logger *zap.Logger
+}
+
+func NewVoteRepository(db DB, logger *zap.Logger) VoteRepository {
+	return &voteRepository{db: db, logger: logger}
+}
+
+func (r *voteRepository) SetVote(ctx context.Context, vote entity.Vote) (int, error) {
+	table, ok := voteTargetTables[vote.TargetType]
+	if !ok {
+		return 0, fmt.Errorf("unknown vote target %q", vote.TargetType)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Failed to begin transaction", zap.Error(err), zap.Int("userID", vote.UserID))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var score int
+	if err := tx.QueryRowContext(ctx, `SELECT score FROM `+table+` WHERE id = ?`, vote.TargetID).Scan(&score); err != nil {
+		return 0, err
+	}
+
+	var previous int
+	err = tx.QueryRowContext(ctx, `SELECT value FROM votes WHERE user_id = ? AND target_type = ? AND target_id = ?`,
+		vote.UserID, vote.TargetType, vote.TargetID).Scan(&previous)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.logger.Error("Failed to get vote", zap.Error(err), zap.Int("userID", vote.UserID))
+		return 0, err
+	}
+	if previous == vote.Value {
+		return score, nil
+	}
+
+	if vote.Value == 0 {
+		_, err = tx.ExecContext(ctx, `DELETE FROM votes WHERE user_id = ? AND target_type = ? AND target_id = ?`,
+			vote.UserID, vote.TargetType, vote.TargetID)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO votes (user_id, target_type, target_id, value) VALUES (?, ?, ?, ?)
+            ON CONFLICT (user_id, target_type, target_id)
+            DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP`,
+			vote.UserID, vote.TargetType, vote.TargetID, vote.Value)
+	}
+	if err != nil {
+		r.logger.Error("Failed to save vote", zap.Error(err), zap.Int("userID", vote.UserID))
+		return 0, err
+	}
+
+	delta := vote.Value - previous
+	if _, err := tx.ExecContext(ctx, `UPDATE `+table+` SET score = score + ? WHERE id = ?`, delta, vote.TargetID); err != nil {
+		r.logger.Error("Failed to update score", zap.Error(err), zap.String("targetType", vote.TargetType), zap.Int("targetID", vote.TargetID))
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Failed to commit vote", zap.Error(err), zap.Int("userID", vote.UserID))
+		return 0, err
+	}
+
+	r.logger.Info("Vote saved",
+		zap.Int("userID", vote.UserID),
+		zap.String("targetType", vote.TargetType),
+		zap.Int("targetID", vote.TargetID),
+		zap.Int("value", vote.Value))
+	return score + delta, nil
+}
+
+func (r *voteRepository) GetUserVotes(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int]int, error) {
+	votes := make(map[int]int, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return votes, nil
+	}
+
+	args := make([]any, 0, len(targetIDs)+2)
+	args = append(args, userID, targetType)
+	for _, id := range targetIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(targetIDs)), ", ")
+	query := `SELECT target_id, value FROM votes WHERE user_id = ? AND target_type = ? AND target_id IN (` + placeholders + `)`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get user votes", zap.Error(err), zap.Int("userID", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var targetID, value int
+		if err := rows.Scan(&targetID, &value); err != nil {
+			return nil, err
+		}
+		votes[targetID] = value
+	}
+	return votes, rows.Err()
+}
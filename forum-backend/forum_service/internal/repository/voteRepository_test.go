@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository/adapters"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestVoteRepository_SetVote_ChangesVote(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	voteRepo := NewVoteRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT score FROM posts WHERE id = \?`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(4))
+	mock.ExpectQuery(`SELECT value FROM votes WHERE user_id = \? AND target_type = \? AND target_id = \?`).
+		WithArgs(2, entity.VoteTargetPost, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(1))
+	mock.ExpectExec(`INSERT INTO votes \(user_id, target_type, target_id, value\) VALUES \(\?, \?, \?, \?\) ON CONFLICT`).
+		WithArgs(2, entity.VoteTargetPost, 1, -1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE posts SET score = score \+ \? WHERE id = \?`).
+		WithArgs(-2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	score, err := voteRepo.SetVote(context.Background(), entity.Vote{UserID: 2, TargetType: entity.VoteTargetPost, TargetID: 1, Value: -1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, score)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVoteRepository_SetVote_RemovesVote(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	voteRepo := NewVoteRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT score FROM comments WHERE id = \?`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(-1))
+	mock.ExpectQuery(`SELECT value FROM votes`).
+		WithArgs(2, entity.VoteTargetComment, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(-1))
+	mock.ExpectExec(`DELETE FROM votes WHERE user_id = \? AND target_type = \? AND target_id = \?`).
+		WithArgs(2, entity.VoteTargetComment, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE comments SET score = score \+ \? WHERE id = \?`).
+		WithArgs(1, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	score, err := voteRepo.SetVote(context.Background(), entity.Vote{UserID: 2, TargetType: entity.VoteTargetComment, TargetID: 5, Value: 0})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, score)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVoteRepository_SetVote_SameVote(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	voteRepo := NewVoteRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT score FROM posts WHERE id = \?`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(3))
+	mock.ExpectQuery(`SELECT value FROM votes`).
+		WithArgs(2, entity.VoteTargetPost, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(1))
+	mock.ExpectRollback()
+
+	score, err := voteRepo.SetVote(context.Background(), entity.Vote{UserID: 2, TargetType: entity.VoteTargetPost, TargetID: 1, Value: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, score)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVoteRepository_SetVote_TargetNotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	voteRepo := NewVoteRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT score FROM posts WHERE id = \?`).
+		WithArgs(9).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = voteRepo.SetVote(context.Background(), entity.Vote{UserID: 2, TargetType: entity.VoteTargetPost, TargetID: 9, Value: 1})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVoteRepository_GetUserVotes(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	voteRepo := NewVoteRepository(&adapters.DbAdapter{DB: db}, logger)
+
+	mock.ExpectQuery(`SELECT target_id, value FROM votes WHERE user_id = \? AND target_type = \? AND target_id IN \(\?, \?, \?\)`).
+		WithArgs(2, entity.VoteTargetPost, 1, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"target_id", "value"}).AddRow(1, 1).AddRow(3, -1))
+
+	votes, err := voteRepo.GetUserVotes(context.Background(), 2, entity.VoteTargetPost, []int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 1, 3: -1}, votes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrInvalidSlug       = errors.New("slug must be 1-64 lowercase letters, digits or hyphens")
+	ErrCategorySlugTaken = errors.New("category slug is already taken")
+	ErrEmptyCategoryName = errors.New("category title is required")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+const maxSlugLength = 64
+
+type CategoryUsecase interface {
+	CreateCategory(ctx context.Context, req entity.CategoryRequest) (*entity.Category, error)
+	GetCategories(ctx context.Context) ([]entity.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	UpdateCategory(ctx context.Context, id int, req entity.CategoryRequest) (*entity.Category, error)
+	DeleteCategory(ctx context.Context, id int) error
+}
+
+type categoryUsecase struct {
+	categoryRepo repository.CategoryRepository
+	logger       *zap.Logger
+}
+
+func NewCategoryUsecase(categoryRepo repository.CategoryRepository, logger *zap.Logger) CategoryUsecase {
+	return &categoryUsecase{categoryRepo: categoryRepo, logger: logger}
+}
+
+func (u *categoryUsecase) CreateCategory(ctx context.Context, req entity.CategoryRequest) (*entity.Category, error) {
+	category, err := categoryFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := u.categoryRepo.CreateCategory(ctx, category)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrCategorySlugTaken
+	}
+	if err != nil {
+		u.logger.Error("Failed to create category", zap.Error(err))
+		return nil, err
+	}
+	return created, nil
+}
+
+func (u *categoryUsecase) GetCategories(ctx context.Context) ([]entity.Category, error) {
+	return u.categoryRepo.GetCategories(ctx)
+}
+
+func (u *categoryUsecase) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	category, err := u.categoryRepo.GetCategoryBySlug(ctx, strings.ToLower(slug))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+func (u *categoryUsecase) UpdateCategory(ctx context.Context, id int, req entity.CategoryRequest) (*entity.Category, error) {
+	category, err := categoryFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	category.ID = id
+
+	err = u.categoryRepo.UpdateCategory(ctx, category)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrCategorySlugTaken
+	}
+	if err != nil {
+		u.logger.Error("Failed to update category", zap.Error(err), zap.Int("categoryID", id))
+		return nil, err
+	}
+	return u.categoryRepo.GetCategoryByID(ctx, id)
+}
+
+func (u *categoryUsecase) DeleteCategory(ctx context.Context, id int) error {
+	err := u.categoryRepo.DeleteCategory(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+// categoryFromRequest нормализует и проверяет поля категории
+func categoryFromRequest(req entity.CategoryRequest) (entity.Category, error) {
+	category := entity.Category{
+		Slug:        strings.ToLower(strings.TrimSpace(req.Slug)),
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		SortOrder:   req.SortOrder,
+	}
+	if len(category.Slug) > maxSlugLength || !slugPattern.MatchString(category.Slug) {
+		return entity.Category{}, ErrInvalidSlug
+	}
+	if category.Title == "" {
+		return entity.Category{}, ErrEmptyCategoryName
+	}
+	return category, nil
+}
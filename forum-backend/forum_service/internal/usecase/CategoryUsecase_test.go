@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestCategoryUsecase_CreateCategory_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCategoryRepo := new(mocks.CategoryRepository)
+
+	categoryUsecase := NewCategoryUsecase(mockCategoryRepo, logger)
+
+	expected := entity.Category{Slug: "golang", Title: "Go", Description: "Все о Go", SortOrder: 1}
+	created := expected
+	created.ID = 1
+	mockCategoryRepo.On("CreateCategory", mock.Anything, expected).Return(&created, nil)
+
+	result, err := categoryUsecase.CreateCategory(context.Background(),
+		entity.CategoryRequest{Slug: " GoLang ", Title: " Go ", Description: "Все о Go", SortOrder: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &created, result)
+
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+func TestCategoryUsecase_CreateCategory_Invalid(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCategoryRepo := new(mocks.CategoryRepository)
+
+	categoryUsecase := NewCategoryUsecase(mockCategoryRepo, logger)
+
+	for _, slug := range []string{"", "two words", "trailing-", "--", "кириллица"} {
+		_, err := categoryUsecase.CreateCategory(context.Background(), entity.CategoryRequest{Slug: slug, Title: "Title"})
+		assert.ErrorIs(t, err, ErrInvalidSlug, slug)
+	}
+
+	_, err := categoryUsecase.CreateCategory(context.Background(), entity.CategoryRequest{Slug: "go", Title: "  "})
+	assert.ErrorIs(t, err, ErrEmptyCategoryName)
+
+	mockCategoryRepo.AssertNotCalled(t, "CreateCategory", mock.Anything, mock.Anything)
+}
+
+func TestCategoryUsecase_CreateCategory_SlugTaken(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCategoryRepo := new(mocks.CategoryRepository)
+
+	categoryUsecase := NewCategoryUsecase(mockCategoryRepo, logger)
+
+	mockCategoryRepo.On("CreateCategory", mock.Anything, mock.Anything).Return(nil, repository.ErrDuplicate)
+
+	_, err := categoryUsecase.CreateCategory(context.Background(), entity.CategoryRequest{Slug: "go", Title: "Go"})
+
+	assert.ErrorIs(t, err, ErrCategorySlugTaken)
+}
+
+func TestCategoryUsecase_UpdateCategory_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCategoryRepo := new(mocks.CategoryRepository)
+
+	categoryUsecase := NewCategoryUsecase(mockCategoryRepo, logger)
+
+	mockCategoryRepo.On("UpdateCategory", mock.Anything, entity.Category{ID: 5, Slug: "go", Title: "Go"}).Return(sql.ErrNoRows)
+
+	_, err := categoryUsecase.UpdateCategory(context.Background(), 5, entity.CategoryRequest{Slug: "go", Title: "Go"})
+
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+func TestCategoryUsecase_GetCategoryBySlug_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCategoryRepo := new(mocks.CategoryRepository)
+
+	categoryUsecase := NewCategoryUsecase(mockCategoryRepo, logger)
+
+	mockCategoryRepo.On("GetCategoryBySlug", mock.Anything, "golang").Return(nil, sql.ErrNoRows)
+
+	result, err := categoryUsecase.GetCategoryBySlug(context.Background(), "GoLang")
+
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	assert.Nil(t, result)
+	mockCategoryRepo.AssertExpectations(t)
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrChatRoomNotFound    = errors.New("chat room not found")
+	ErrChatRoomNameTaken   = errors.New("chat room name is already taken")
+	ErrInvalidChatRoomName = errors.New("chat room name must be 1-64 characters")
+	ErrChatRoomForbidden   = errors.New("no access to this chat room")
+	ErrNotChatRoomMember   = errors.New("user is not a member of this chat room")
+)
+
+const maxChatRoomNameLength = 64
+
+type ChatRoomUsecase interface {
+	// CreateRoom создает комнату, ее владелец - ownerID
+	CreateRoom(ctx context.Context, ownerID int, req entity.ChatRoomRequest) (*entity.ChatRoom, error)
+	// ListRooms возвращает комнаты, доступные userID (0 - анонимный запрос)
+	ListRooms(ctx context.Context, userID int) ([]entity.ChatRoom, error)
+	// GetRoom возвращает комнату, если userID может ее читать
+	GetRoom(ctx context.Context, roomID, userID int) (*entity.ChatRoom, error)
+	// CheckAccess проверяет, что userID может читать комнату и писать в нее: публичные
+	// комнаты открыты всем, приватные - только участникам
+	CheckAccess(ctx context.Context, roomID, userID int) error
+	// DeleteRoom удаляет комнату, это может только владелец
+	DeleteRoom(ctx context.Context, roomID, userID int) error
+	// AddMember добавляет userID в комнату. Владелец приглашает кого угодно,
+	// остальные могут только сами вступить в публичную комнату
+	AddMember(ctx context.Context, roomID, actorID, userID int) error
+	// RemoveMember удаляет userID из комнаты. Владелец удаляет любого, кроме себя,
+	// остальные могут только выйти сами
+	RemoveMember(ctx context.Context, roomID, actorID, userID int) error
+	ListMembers(ctx context.Context, roomID, userID int) ([]entity.ChatRoomMember, error)
+}
+
+type chatRoomUsecase struct {
+	repo   repository.ChatRoomRepository
+	logger *zap.Logger
+}
+
+func NewChatRoomUsecase(repo repository.ChatRoomRepository, logger *zap.Logger) ChatRoomUsecase {
+	return &chatRoomUsecase{repo: repo, logger: logger}
+}
+
+func (u *chatRoomUsecase) CreateRoom(ctx context.Context, ownerID int, req entity.ChatRoomRequest) (*entity.ChatRoom, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxChatRoomNameLength {
+		return nil, ErrInvalidChatRoomName
+	}
+
+	room, err := u.repo.CreateRoom(ctx, entity.ChatRoom{Name: name, IsPrivate: req.IsPrivate, OwnerID: ownerID})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrChatRoomNameTaken
+	}
+	if err != nil {
+		u.logger.Error("Failed to create chat room", zap.Error(err), zap.Int("ownerID", ownerID))
+		return nil, err
+	}
+	return room, nil
+}
+
+func (u *chatRoomUsecase) ListRooms(ctx context.Context, userID int) ([]entity.ChatRoom, error) {
+	return u.repo.ListRooms(ctx, userID)
+}
+
+func (u *chatRoomUsecase) GetRoom(ctx context.Context, roomID, userID int) (*entity.ChatRoom, error) {
+	room, err := u.getRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkRoomAccess(ctx, room, userID); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+func (u *chatRoomUsecase) CheckAccess(ctx context.Context, roomID, userID int) error {
+	_, err := u.GetRoom(ctx, roomID, userID)
+	return err
+}
+
+func (u *chatRoomUsecase) DeleteRoom(ctx context.Context, roomID, userID int) error {
+	room, err := u.getRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if room.ID == entity.DefaultChatRoomID || room.OwnerID != userID {
+		return ErrChatRoomForbidden
+	}
+
+	err = u.repo.DeleteRoom(ctx, roomID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrChatRoomNotFound
+	}
+	return err
+}
+
+func (u *chatRoomUsecase) AddMember(ctx context.Context, roomID, actorID, userID int) error {
+	room, err := u.getRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if room.OwnerID != actorID && (room.IsPrivate || actorID != userID) {
+		return ErrChatRoomForbidden
+	}
+
+	return u.repo.AddMember(ctx, entity.ChatRoomMember{RoomID: roomID, UserID: userID, Role: entity.ChatRoleMember})
+}
+
+func (u *chatRoomUsecase) RemoveMember(ctx context.Context, roomID, actorID, userID int) error {
+	room, err := u.getRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	// Владелец не выходит из своей комнаты, а удаляет ее
+	if userID == room.OwnerID || (actorID != room.OwnerID && actorID != userID) {
+		return ErrChatRoomForbidden
+	}
+
+	err = u.repo.RemoveMember(ctx, roomID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotChatRoomMember
+	}
+	return err
+}
+
+func (u *chatRoomUsecase) ListMembers(ctx context.Context, roomID, userID int) ([]entity.ChatRoomMember, error) {
+	if err := u.CheckAccess(ctx, roomID, userID); err != nil {
+		return nil, err
+	}
+	return u.repo.ListMembers(ctx, roomID)
+}
+
+func (u *chatRoomUsecase) getRoom(ctx context.Context, roomID int) (*entity.ChatRoom, error) {
+	room, err := u.repo.GetRoom(ctx, roomID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrChatRoomNotFound
+	}
+	return room, err
+}
+
+// checkRoomAccess пускает в приватную комнату только ее участников
+func (u *chatRoomUsecase) checkRoomAccess(ctx context.Context, room *entity.ChatRoom, userID int) error {
+	if !room.IsPrivate {
+		return nil
+	}
+	if userID == 0 {
+		return ErrChatRoomForbidden
+	}
+	_, err := u.repo.GetMember(ctx, room.ID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrChatRoomForbidden
+	}
+	if err != nil {
+		u.logger.Error("Failed to check chat room membership", zap.Error(err), zap.Int("roomID", room.ID), zap.Int("userID", userID))
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestChatRoomUsecase_CreateRoom(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomRepo := new(mocks.ChatRoomRepository)
+
+	roomUsecase := NewChatRoomUsecase(mockRoomRepo, logger)
+
+	created := &entity.ChatRoom{ID: 5, Name: "team", IsPrivate: true, OwnerID: 7, MemberCount: 1}
+	mockRoomRepo.On("CreateRoom", mock.Anything, entity.ChatRoom{Name: "team", IsPrivate: true, OwnerID: 7}).Return(created, nil)
+	mockRoomRepo.On("CreateRoom", mock.Anything, entity.ChatRoom{Name: "general", OwnerID: 7}).Return(nil, repository.ErrDuplicate)
+
+	room, err := roomUsecase.CreateRoom(context.Background(), 7, entity.ChatRoomRequest{Name: "  team ", IsPrivate: true})
+	assert.NoError(t, err)
+	assert.Equal(t, created, room)
+
+	_, err = roomUsecase.CreateRoom(context.Background(), 7, entity.ChatRoomRequest{Name: "general"})
+	assert.ErrorIs(t, err, ErrChatRoomNameTaken)
+
+	_, err = roomUsecase.CreateRoom(context.Background(), 7, entity.ChatRoomRequest{Name: "   "})
+	assert.ErrorIs(t, err, ErrInvalidChatRoomName)
+
+	mockRoomRepo.AssertExpectations(t)
+}
+
+func TestChatRoomUsecase_CheckAccess(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomRepo := new(mocks.ChatRoomRepository)
+
+	roomUsecase := NewChatRoomUsecase(mockRoomRepo, logger)
+
+	mockRoomRepo.On("GetRoom", mock.Anything, 1).Return(&entity.ChatRoom{ID: 1, Name: "general"}, nil)
+	mockRoomRepo.On("GetRoom", mock.Anything, 5).Return(&entity.ChatRoom{ID: 5, Name: "team", IsPrivate: true, OwnerID: 7}, nil)
+	mockRoomRepo.On("GetRoom", mock.Anything, 9).Return(nil, sql.ErrNoRows)
+	mockRoomRepo.On("GetMember", mock.Anything, 5, 7).Return(&entity.ChatRoomMember{RoomID: 5, UserID: 7, Role: entity.ChatRoleOwner}, nil)
+	mockRoomRepo.On("GetMember", mock.Anything, 5, 8).Return(nil, sql.ErrNoRows)
+
+	assert.NoError(t, roomUsecase.CheckAccess(context.Background(), 1, 0))
+	assert.NoError(t, roomUsecase.CheckAccess(context.Background(), 5, 7))
+	assert.ErrorIs(t, roomUsecase.CheckAccess(context.Background(), 5, 8), ErrChatRoomForbidden)
+	assert.ErrorIs(t, roomUsecase.CheckAccess(context.Background(), 5, 0), ErrChatRoomForbidden)
+	assert.ErrorIs(t, roomUsecase.CheckAccess(context.Background(), 9, 7), ErrChatRoomNotFound)
+}
+
+func TestChatRoomUsecase_DeleteRoom_OnlyOwner(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomRepo := new(mocks.ChatRoomRepository)
+
+	roomUsecase := NewChatRoomUsecase(mockRoomRepo, logger)
+
+	mockRoomRepo.On("GetRoom", mock.Anything, 1).Return(&entity.ChatRoom{ID: 1, Name: "general"}, nil)
+	mockRoomRepo.On("GetRoom", mock.Anything, 5).Return(&entity.ChatRoom{ID: 5, Name: "team", OwnerID: 7}, nil)
+	mockRoomRepo.On("DeleteRoom", mock.Anything, 5).Return(nil).Once()
+
+	assert.ErrorIs(t, roomUsecase.DeleteRoom(context.Background(), 5, 8), ErrChatRoomForbidden)
+	assert.ErrorIs(t, roomUsecase.DeleteRoom(context.Background(), 1, 0), ErrChatRoomForbidden)
+	assert.NoError(t, roomUsecase.DeleteRoom(context.Background(), 5, 7))
+
+	mockRoomRepo.AssertExpectations(t)
+}
+
+func TestChatRoomUsecase_AddMember(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomRepo := new(mocks.ChatRoomRepository)
+
+	roomUsecase := NewChatRoomUsecase(mockRoomRepo, logger)
+
+	mockRoomRepo.On("GetRoom", mock.Anything, 4).Return(&entity.ChatRoom{ID: 4, Name: "public", OwnerID: 7}, nil)
+	mockRoomRepo.On("GetRoom", mock.Anything, 5).Return(&entity.ChatRoom{ID: 5, Name: "team", IsPrivate: true, OwnerID: 7}, nil)
+	mockRoomRepo.On("AddMember", mock.Anything, entity.ChatRoomMember{RoomID: 4, UserID: 8, Role: entity.ChatRoleMember}).Return(nil).Once()
+	mockRoomRepo.On("AddMember", mock.Anything, entity.ChatRoomMember{RoomID: 5, UserID: 8, Role: entity.ChatRoleMember}).Return(nil).Once()
+
+	// вступить в публичную комнату можно самому, в приватную - только по приглашению владельца
+	assert.NoError(t, roomUsecase.AddMember(context.Background(), 4, 8, 8))
+	assert.ErrorIs(t, roomUsecase.AddMember(context.Background(), 5, 8, 8), ErrChatRoomForbidden)
+	assert.ErrorIs(t, roomUsecase.AddMember(context.Background(), 4, 8, 9), ErrChatRoomForbidden)
+	assert.NoError(t, roomUsecase.AddMember(context.Background(), 5, 7, 8))
+
+	mockRoomRepo.AssertExpectations(t)
+}
+
+func TestChatRoomUsecase_RemoveMember(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockRoomRepo := new(mocks.ChatRoomRepository)
+
+	roomUsecase := NewChatRoomUsecase(mockRoomRepo, logger)
+
+	mockRoomRepo.On("GetRoom", mock.Anything, 5).Return(&entity.ChatRoom{ID: 5, Name: "team", IsPrivate: true, OwnerID: 7}, nil)
+	mockRoomRepo.On("RemoveMember", mock.Anything, 5, 8).Return(nil).Once()
+	mockRoomRepo.On("RemoveMember", mock.Anything, 5, 9).Return(sql.ErrNoRows).Once()
+
+	assert.ErrorIs(t, roomUsecase.RemoveMember(context.Background(), 5, 7, 7), ErrChatRoomForbidden)
+	assert.ErrorIs(t, roomUsecase.RemoveMember(context.Background(), 5, 9, 8), ErrChatRoomForbidden)
+	assert.NoError(t, roomUsecase.RemoveMember(context.Background(), 5, 8, 8))
+	assert.ErrorIs(t, roomUsecase.RemoveMember(context.Background(), 5, 7, 9), ErrNotChatRoomMember)
+
+	mockRoomRepo.AssertExpectations(t)
+}
@@ -2,16 +2,42 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/repository"
 	"go.uber.org/zap"
 )
 
+// MaxChatHistoryLimit - наибольший размер страницы истории чата
+const MaxChatHistoryLimit = 100
+
+// maxClientIDLength - наибольшая длина id, который клиент генерирует для сообщения
+const maxClientIDLength = 64
+
+// MaxChatMessageLength - наибольшая длина сообщения чата и личного сообщения в символах
+const MaxChatMessageLength = 2000
+
+var (
+	ErrChatMessageNotFound = errors.New("chat message not found")
+	ErrEmptyChatMessage    = errors.New("chat message content is empty")
+	ErrInvalidClientID     = errors.New("client_id must be at most 64 characters")
+	ErrChatMessageTooLong  = errors.New("chat message must be at most 2000 characters")
+)
+
 type ChatUsecase interface {
-	HandleMessage(ctx context.Context, userID int, username, content string) error
-	GetRecentMessages(ctx context.Context, limit int) ([]entity.ChatMessage, error)
+	// HandleMessage сохраняет сообщение. Повторное сообщение с тем же clientID не сохраняется:
+	// возвращается сохраненное ранее и created = false
+	HandleMessage(ctx context.Context, roomID, userID int, username, content, clientID string) (msg *entity.ChatMessage, created bool, err error)
+	GetRecentMessages(ctx context.Context, roomID, limit int) ([]entity.ChatMessage, error)
+	// GetHistory возвращает сообщения комнаты, отправленные раньше курсора, в хронологическом порядке
+	GetHistory(ctx context.Context, roomID int, before *entity.Cursor, limit int) ([]entity.ChatMessage, error)
+	// GetMessage возвращает сообщение, если его нет - ErrChatMessageNotFound
+	GetMessage(ctx context.Context, id int) (*entity.ChatMessage, error)
 }
 
 type chatUsecase struct {
@@ -23,34 +49,49 @@ func NewChatUsecase(repo repository.ChatRepository, logger *zap.Logger) ChatUsec
 	return &chatUsecase{repo: repo, logger: logger}
 }
 
-func (uc *chatUsecase) HandleMessage(ctx context.Context, userID int, username, content string) error {
+func (uc *chatUsecase) HandleMessage(ctx context.Context, roomID, userID int, username, content, clientID string) (*entity.ChatMessage, bool, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, false, ErrEmptyChatMessage
+	}
+	if utf8.RuneCountInString(content) > MaxChatMessageLength {
+		return nil, false, ErrChatMessageTooLong
+	}
+	if len(clientID) > maxClientIDLength {
+		return nil, false, ErrInvalidClientID
+	}
+
+	// timestamp хранится с точностью до секунды, подтверждение должно совпадать с историей
 	message := entity.ChatMessage{
+		RoomID:    roomID,
 		UserID:    userID,
 		Username:  username,
 		Content:   content,
-		Timestamp: time.Now(),
+		Timestamp: time.Now().Truncate(time.Second),
+		ClientID:  clientID,
 	}
 
 	uc.logger.Info("Handling message",
+		zap.Int("roomID", roomID),
 		zap.Int("userID", userID),
 		zap.String("username", username),
 		zap.String("content", content),
 	)
 
-	err := uc.repo.StoreMessage(ctx, message)
+	stored, created, err := uc.repo.StoreMessage(ctx, message)
 	if err != nil {
 		uc.logger.Error("Failed to store message", zap.Error(err))
-		return err
+		return nil, false, err
 	}
 
-	uc.logger.Info("Message stored successfully", zap.Int("userID", userID), zap.String("username", username))
-	return nil
+	uc.logger.Info("Message stored successfully", zap.Int("messageID", stored.ID), zap.Int("userID", userID),
+		zap.String("username", username), zap.Bool("created", created))
+	return stored, created, nil
 }
 
-func (uc *chatUsecase) GetRecentMessages(ctx context.Context, limit int) ([]entity.ChatMessage, error) {
-	uc.logger.Info("Fetching recent messages", zap.Int("limit", limit))
+func (uc *chatUsecase) GetRecentMessages(ctx context.Context, roomID, limit int) ([]entity.ChatMessage, error) {
+	uc.logger.Info("Fetching recent messages", zap.Int("roomID", roomID), zap.Int("limit", limit))
 
-	messages, err := uc.repo.GetRecentMessages(ctx, limit)
+	messages, err := uc.repo.GetRecentMessages(ctx, roomID, limit)
 	if err != nil {
 		uc.logger.Error("Failed to get recent messages", zap.Error(err))
 		return nil, err
@@ -59,3 +100,24 @@ func (uc *chatUsecase) GetRecentMessages(ctx context.Context, limit int) ([]enti
 	uc.logger.Info("Recent messages fetched successfully", zap.Int("count", len(messages)))
 	return messages, nil
 }
+
+func (uc *chatUsecase) GetHistory(ctx context.Context, roomID int, before *entity.Cursor, limit int) ([]entity.ChatMessage, error) {
+	messages, err := uc.repo.GetMessagesBefore(ctx, roomID, before, limit)
+	if err != nil {
+		uc.logger.Error("Failed to get chat history", zap.Error(err))
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (uc *chatUsecase) GetMessage(ctx context.Context, id int) (*entity.ChatMessage, error) {
+	message, err := uc.repo.GetMessageByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrChatMessageNotFound
+	}
+	if err != nil {
+		uc.logger.Error("Failed to get chat message", zap.Error(err), zap.Int("messageID", id))
+		return nil, err
+	}
+	return message, nil
+}
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,17 +27,23 @@ func TestChatUsecase_HandleMessage_Success(t *testing.T) {
 	username := "testuser"
 	content := "This is a test message"
 	message := entity.ChatMessage{
+		RoomID:    1,
 		UserID:    userID,
 		Username:  username,
 		Content:   content,
 		Timestamp: time.Now(),
 	}
 
-	mockChatRepo.On("StoreMessage", mock.Anything, message).Return(nil)
+	message.ClientID = "c-1"
+	stored := &entity.ChatMessage{ID: 5, RoomID: 1, UserID: userID, Username: username, Content: content, ClientID: "c-1"}
 
-	err := chatUsecase.HandleMessage(context.Background(), userID, username, content)
+	mockChatRepo.On("StoreMessage", mock.Anything, sameMessage(message)).Return(stored, true, nil)
+
+	msg, created, err := chatUsecase.HandleMessage(context.Background(), 1, userID, username, content, "c-1")
 
 	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, stored, msg)
 
 	mockChatRepo.AssertExpectations(t)
 }
@@ -52,21 +60,42 @@ func TestChatUsecase_HandleMessage_Failure(t *testing.T) {
 	username := "testuser"
 	content := "This is a test message"
 	message := entity.ChatMessage{
+		RoomID:    1,
 		UserID:    userID,
 		Username:  username,
 		Content:   content,
 		Timestamp: time.Now(),
 	}
 
-	mockChatRepo.On("StoreMessage", mock.Anything, message).Return(errors.New("failed to store message"))
+	mockChatRepo.On("StoreMessage", mock.Anything, sameMessage(message)).Return(nil, false, errors.New("failed to store message"))
 
-	err := chatUsecase.HandleMessage(context.Background(), userID, username, content)
+	_, _, err := chatUsecase.HandleMessage(context.Background(), 1, userID, username, content, "")
 
 	assert.Error(t, err)
 
 	mockChatRepo.AssertExpectations(t)
 }
 
+func TestChatUsecase_HandleMessage_Invalid(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockChatRepo := new(mocks.ChatRepository)
+
+	chatUsecase := NewChatUsecase(mockChatRepo, logger)
+
+	_, _, err := chatUsecase.HandleMessage(context.Background(), 1, 1, "testuser", "  ", "c-1")
+	assert.ErrorIs(t, err, ErrEmptyChatMessage)
+
+	_, _, err = chatUsecase.HandleMessage(context.Background(), 1, 1, "testuser", strings.Repeat("я", MaxChatMessageLength+1), "c-1")
+	assert.ErrorIs(t, err, ErrChatMessageTooLong)
+
+	_, _, err = chatUsecase.HandleMessage(context.Background(), 1, 1, "testuser", "hello", strings.Repeat("x", maxClientIDLength+1))
+	assert.ErrorIs(t, err, ErrInvalidClientID)
+
+	mockChatRepo.AssertNotCalled(t, "StoreMessage", mock.Anything, mock.Anything)
+}
+
 func TestChatUsecase_GetRecentMessages_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
@@ -81,9 +110,9 @@ func TestChatUsecase_GetRecentMessages_Success(t *testing.T) {
 		{UserID: 2, Username: "user2", Content: "Message 2", Timestamp: time.Now()},
 	}
 
-	mockChatRepo.On("GetRecentMessages", mock.Anything, limit).Return(messages, nil)
+	mockChatRepo.On("GetRecentMessages", mock.Anything, 1, limit).Return(messages, nil)
 
-	result, err := chatUsecase.GetRecentMessages(context.Background(), limit)
+	result, err := chatUsecase.GetRecentMessages(context.Background(), 1, limit)
 
 	assert.NoError(t, err)
 	assert.Equal(t, messages, result)
@@ -101,12 +130,36 @@ func TestChatUsecase_GetRecentMessages_Failure(t *testing.T) {
 
 	limit := 10
 
-	mockChatRepo.On("GetRecentMessages", mock.Anything, limit).Return(nil, errors.New("failed to get recent messages"))
+	mockChatRepo.On("GetRecentMessages", mock.Anything, 1, limit).Return(nil, errors.New("failed to get recent messages"))
 
-	result, err := chatUsecase.GetRecentMessages(context.Background(), limit)
+	result, err := chatUsecase.GetRecentMessages(context.Background(), 1, limit)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 
 	mockChatRepo.AssertExpectations(t)
 }
+
+// sameMessage сравнивает сообщения без учета времени: его выставляет usecase
+func sameMessage(expected entity.ChatMessage) interface{} {
+	return mock.MatchedBy(func(msg entity.ChatMessage) bool {
+		return msg.RoomID == expected.RoomID && msg.UserID == expected.UserID && msg.Username == expected.Username &&
+			msg.Content == expected.Content && msg.ClientID == expected.ClientID && !msg.Timestamp.IsZero()
+	})
+}
+
+func TestChatUsecase_GetMessage_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockChatRepo := new(mocks.ChatRepository)
+
+	chatUsecase := NewChatUsecase(mockChatRepo, logger)
+
+	mockChatRepo.On("GetMessageByID", mock.Anything, 9).Return(nil, sql.ErrNoRows)
+
+	_, err := chatUsecase.GetMessage(context.Background(), 9)
+
+	assert.ErrorIs(t, err, ErrChatMessageNotFound)
+	mockChatRepo.AssertExpectations(t)
+}
@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/mocks"
@@ -18,7 +20,7 @@ func TestCommentsUsecases_CreateComment_Success(t *testing.T) {
 
 	mockCommentRepo := new(mocks.CommentsRepository)
 
-	commentsUsecases := NewCommentsUsecases(mockCommentRepo, logger)
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
 
 	comment := entity.Comment{
 		PostId:   1,
@@ -44,7 +46,7 @@ func TestCommentsUsecases_CreateComment_Failure(t *testing.T) {
 
 	mockCommentRepo := new(mocks.CommentsRepository)
 
-	commentsUsecases := NewCommentsUsecases(mockCommentRepo, logger)
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
 
 	comment := entity.Comment{
 		PostId:   1,
@@ -62,22 +64,22 @@ func TestCommentsUsecases_CreateComment_Failure(t *testing.T) {
 	mockCommentRepo.AssertExpectations(t)
 }
 
-func TestCommentsUsecases_GetCommentByPostID_Success(t *testing.T) {
+func TestCommentsUsecases_GetComments_Success(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentRepo := new(mocks.CommentsRepository)
 
-	commentsUsecases := NewCommentsUsecases(mockCommentRepo, logger)
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
 
 	comments := []entity.Comment{
 		{ID: 1, PostId: 1, AuthorId: 1, Content: "Comment 1"},
 		{ID: 2, PostId: 1, AuthorId: 2, Content: "Comment 2"},
 	}
 
-	mockCommentRepo.On("GetCommentsByPostID", mock.Anything, 1).Return(comments, nil)
+	mockCommentRepo.On("GetComments", mock.Anything, 1, 10, 0).Return(comments, nil)
 
-	result, err := commentsUsecases.GetCommentByPostID(context.Background(), 1)
+	result, err := commentsUsecases.GetComments(context.Background(), 1, 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, comments, result)
@@ -85,20 +87,190 @@ func TestCommentsUsecases_GetCommentByPostID_Success(t *testing.T) {
 	mockCommentRepo.AssertExpectations(t)
 }
 
-func TestCommentsUsecases_GetCommentByPostID_Failure(t *testing.T) {
+func TestCommentsUsecases_GetComments_Failure(t *testing.T) {
 
 	logger, _ := zap.NewProduction()
 
 	mockCommentRepo := new(mocks.CommentsRepository)
 
-	commentsUsecases := NewCommentsUsecases(mockCommentRepo, logger)
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
 
-	mockCommentRepo.On("GetCommentsByPostID", mock.Anything, 1).Return(nil, errors.New("failed to get comments"))
+	mockCommentRepo.On("GetComments", mock.Anything, 1, 10, 0).Return(nil, errors.New("failed to get comments"))
 
-	result, err := commentsUsecases.GetCommentByPostID(context.Background(), 1)
+	result, err := commentsUsecases.GetComments(context.Background(), 1, 10, 0)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 
 	mockCommentRepo.AssertExpectations(t)
 }
+
+func TestCommentsUsecases_CreateComment_Reply(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	parentID := 5
+	comment := entity.Comment{PostId: 1, AuthorId: 1, ParentId: &parentID, Content: "Reply"}
+	expected := comment
+	expected.Depth = 2
+
+	mockCommentRepo.On("GetCommentByID", mock.Anything, parentID).Return(&entity.Comment{ID: parentID, PostId: 1, Depth: 1}, nil)
+	mockCommentRepo.On("CreateComment", mock.Anything, expected).Return(expected, nil)
+
+	result, err := commentsUsecases.CreateComment(context.Background(), comment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Depth)
+
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentsUsecases_CreateComment_ReplyTooDeep(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	parentID := 5
+	comment := entity.Comment{PostId: 1, AuthorId: 1, ParentId: &parentID, Content: "Reply"}
+
+	mockCommentRepo.On("GetCommentByID", mock.Anything, parentID).Return(&entity.Comment{ID: parentID, PostId: 1, Depth: 2}, nil)
+
+	_, err := commentsUsecases.CreateComment(context.Background(), comment)
+
+	assert.ErrorIs(t, err, ErrMaxDepthExceeded)
+	mockCommentRepo.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentsUsecases_CreateComment_ParentFromAnotherPost(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	parentID := 5
+	comment := entity.Comment{PostId: 1, AuthorId: 1, ParentId: &parentID, Content: "Reply"}
+
+	mockCommentRepo.On("GetCommentByID", mock.Anything, parentID).Return(&entity.Comment{ID: parentID, PostId: 2}, nil)
+
+	_, err := commentsUsecases.CreateComment(context.Background(), comment)
+
+	assert.ErrorIs(t, err, ErrInvalidParent)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentsUsecases_GetCommentTree_ClampsDepth(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	mockCommentRepo.On("GetCommentTree", mock.Anything, 1, 10, 0, 2).Return([]*entity.CommentNode{}, nil)
+
+	_, err := commentsUsecases.GetCommentTree(context.Background(), 1, 10, 0, 10)
+
+	assert.NoError(t, err)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentsUsecases_GetReplies_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	mockCommentRepo.On("GetCommentByID", mock.Anything, 42).Return(nil, sql.ErrNoRows)
+
+	result, err := commentsUsecases.GetReplies(context.Background(), 42, 10, 0, 1)
+
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+	assert.Nil(t, result)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentsUsecases_UpdateComment_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	editedAt := time.Now()
+	updated := &entity.Comment{ID: 1, PostId: 1, AuthorId: 1, Content: "Edited", EditedAt: &editedAt}
+
+	mockCommentRepo.On("UpdateComment", mock.Anything, 1, "Edited", mock.AnythingOfType("time.Time")).Return(nil)
+	mockCommentRepo.On("GetCommentByID", mock.Anything, 1).Return(updated, nil)
+
+	result, err := commentsUsecases.UpdateComment(context.Background(), 1, "Edited")
+
+	assert.NoError(t, err)
+	assert.Equal(t, updated, result)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentsUsecases_UpdateComment_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	mockCommentRepo.On("UpdateComment", mock.Anything, 1, "Edited", mock.AnythingOfType("time.Time")).Return(sql.ErrNoRows)
+
+	result, err := commentsUsecases.UpdateComment(context.Background(), 1, "Edited")
+
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+	assert.Nil(t, result)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentsUsecases_DeleteComment_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	mockCommentRepo.On("SoftDeleteComment", mock.Anything, 1, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := commentsUsecases.DeleteComment(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentsUsecases_CreateComment_ReplyToDeleted(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockCommentRepo := new(mocks.CommentsRepository)
+
+	commentsUsecases := NewCommentsUsecases(mockCommentRepo, 2, logger)
+
+	parentID := 5
+	deletedAt := time.Now()
+	comment := entity.Comment{PostId: 1, AuthorId: 1, ParentId: &parentID, Content: "Reply"}
+
+	mockCommentRepo.On("GetCommentByID", mock.Anything, parentID).Return(&entity.Comment{ID: parentID, PostId: 1, DeletedAt: &deletedAt}, nil)
+
+	_, err := commentsUsecases.CreateComment(context.Background(), comment)
+
+	assert.ErrorIs(t, err, ErrInvalidParent)
+	mockCommentRepo.AssertExpectations(t)
+}
@@ -2,24 +2,46 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/repository"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrInvalidParent    = errors.New("parent comment not found in this post")
+	ErrMaxDepthExceeded = errors.New("maximum reply depth exceeded")
+	ErrCommentDeleted   = errors.New("comment has been deleted")
+)
+
 type CommentsUsecases interface {
 	CreateComment(ctx context.Context, comment entity.Comment) (entity.Comment, error)
 	GetComments(ctx context.Context, postID, limit, offset int) ([]entity.Comment, error)
+	GetCommentsAfter(ctx context.Context, postID int, after *entity.Cursor, limit int) ([]entity.Comment, error)
 	GetTotalCommentsCount(ctx context.Context, postID int) (int, error)
+	GetCommentTree(ctx context.Context, postID, limit, offset, depth int) ([]*entity.CommentNode, error)
+	GetCommentTreeAfter(ctx context.Context, postID int, after *entity.Cursor, limit, depth int) ([]*entity.CommentNode, error)
+	GetTopLevelCommentsCount(ctx context.Context, postID int) (int, error)
+	GetReplies(ctx context.Context, commentID, limit, offset, depth int) ([]*entity.CommentNode, error)
+	GetRepliesCount(ctx context.Context, commentID int) (int, error)
+	GetCommentByID(ctx context.Context, id int) (*entity.Comment, error)
+	UpdateComment(ctx context.Context, id int, content string) (*entity.Comment, error)
+	DeleteComment(ctx context.Context, id int) error
 }
 
 type commentsUsecases struct {
 	commentRepo repository.CommentsRepository
-	logger      *zap.Logger
+	// maxDepth - максимальная глубина вложенности ответов (0 - ответы запрещены)
+	maxDepth int
+	logger   *zap.Logger
 }
 
-func NewCommentsUsecases(commentRepo repository.CommentsRepository, logger *zap.Logger) CommentsUsecases {
-	return &commentsUsecases{commentRepo: commentRepo, logger: logger}
+func NewCommentsUsecases(commentRepo repository.CommentsRepository, maxDepth int, logger *zap.Logger) CommentsUsecases {
+	return &commentsUsecases{commentRepo: commentRepo, maxDepth: maxDepth, logger: logger}
 }
 
 func (u *commentsUsecases) CreateComment(ctx context.Context, comment entity.Comment) (entity.Comment, error) {
@@ -29,6 +51,25 @@ func (u *commentsUsecases) CreateComment(ctx context.Context, comment entity.Com
 		zap.String("content", comment.Content),
 	)
 
+	comment.Depth = 0
+	if comment.ParentId != nil {
+		parent, err := u.commentRepo.GetCommentByID(ctx, *comment.ParentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Comment{}, ErrInvalidParent
+		}
+		if err != nil {
+			u.logger.Error("Failed to get parent comment", zap.Error(err), zap.Int("parentID", *comment.ParentId))
+			return entity.Comment{}, err
+		}
+		if parent.PostId != comment.PostId || parent.IsDeleted() {
+			return entity.Comment{}, ErrInvalidParent
+		}
+		if parent.Depth+1 > u.maxDepth {
+			return entity.Comment{}, ErrMaxDepthExceeded
+		}
+		comment.Depth = parent.Depth + 1
+	}
+
 	createdComment, err := u.commentRepo.CreateComment(ctx, comment)
 	if err != nil {
 		u.logger.Error("Failed to create comment", zap.Error(err))
@@ -43,6 +84,84 @@ func (u *commentsUsecases) GetComments(ctx context.Context, postID, limit, offse
 	return u.commentRepo.GetComments(ctx, postID, limit, offset)
 }
 
+func (u *commentsUsecases) GetCommentsAfter(ctx context.Context, postID int, after *entity.Cursor, limit int) ([]entity.Comment, error) {
+	return u.commentRepo.GetCommentsAfter(ctx, postID, after, limit)
+}
+
 func (u *commentsUsecases) GetTotalCommentsCount(ctx context.Context, postID int) (int, error) {
 	return u.commentRepo.GetTotalCommentsCount(ctx, postID)
 }
+
+func (u *commentsUsecases) GetCommentTree(ctx context.Context, postID, limit, offset, depth int) ([]*entity.CommentNode, error) {
+	return u.commentRepo.GetCommentTree(ctx, postID, limit, offset, u.clampDepth(depth))
+}
+
+func (u *commentsUsecases) GetCommentTreeAfter(ctx context.Context, postID int, after *entity.Cursor, limit, depth int) ([]*entity.CommentNode, error) {
+	return u.commentRepo.GetCommentTreeAfter(ctx, postID, after, limit, u.clampDepth(depth))
+}
+
+func (u *commentsUsecases) GetTopLevelCommentsCount(ctx context.Context, postID int) (int, error) {
+	return u.commentRepo.GetTopLevelCommentsCount(ctx, postID)
+}
+
+func (u *commentsUsecases) GetReplies(ctx context.Context, commentID, limit, offset, depth int) ([]*entity.CommentNode, error) {
+	if _, err := u.commentRepo.GetCommentByID(ctx, commentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return u.commentRepo.GetReplies(ctx, commentID, limit, offset, u.clampDepth(depth))
+}
+
+func (u *commentsUsecases) GetRepliesCount(ctx context.Context, commentID int) (int, error) {
+	return u.commentRepo.GetRepliesCount(ctx, commentID)
+}
+
+func (u *commentsUsecases) GetCommentByID(ctx context.Context, id int) (*entity.Comment, error) {
+	comment, err := u.commentRepo.GetCommentByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	return comment, err
+}
+
+func (u *commentsUsecases) UpdateComment(ctx context.Context, id int, content string) (*entity.Comment, error) {
+	u.logger.Info("Updating comment", zap.Int("commentID", id))
+
+	err := u.commentRepo.UpdateComment(ctx, id, content, time.Now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		// комментария нет или он уже удален
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		u.logger.Error("Failed to update comment", zap.Error(err), zap.Int("commentID", id))
+		return nil, err
+	}
+	return u.GetCommentByID(ctx, id)
+}
+
+func (u *commentsUsecases) DeleteComment(ctx context.Context, id int) error {
+	u.logger.Info("Deleting comment", zap.Int("commentID", id))
+
+	err := u.commentRepo.SoftDeleteComment(ctx, id, time.Now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		u.logger.Error("Failed to delete comment", zap.Error(err), zap.Int("commentID", id))
+		return err
+	}
+	return nil
+}
+
+// clampDepth ограничивает глубину загрузки поддерева: глубже maxDepth ответов не бывает
+func (u *commentsUsecases) clampDepth(depth int) int {
+	if depth < 0 {
+		return 0
+	}
+	if depth > u.maxDepth {
+		return u.maxDepth
+	}
+	return depth
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidRecipient     = errors.New("direct message recipient must be another existing user")
+	ErrEmptyDirectMessage   = errors.New("direct message content is empty")
+	ErrDirectMessageTooLong = errors.New("direct message must be at most 2000 characters")
+)
+
+// UserDirectory - пользователи auth_service. Неизвестные ID в результат GetUsernames не попадают
+type UserDirectory interface {
+	GetUsernames(ctx context.Context, userIDs []int) (map[int]string, error)
+}
+
+type DirectMessageUsecase interface {
+	// SendMessage сохраняет личное сообщение от senderID к recipientID. Повторное сообщение с тем же
+	// clientID не сохраняется: возвращается сохраненное ранее и created = false
+	SendMessage(ctx context.Context, senderID, recipientID int, content, clientID string) (msg *entity.DirectMessage, created bool, err error)
+	// GetConversations возвращает переписки userID с числом непрочитанных сообщений, начиная с последней
+	GetConversations(ctx context.Context, userID int) ([]entity.Conversation, error)
+	// GetMessages возвращает сообщения между userID и peerID, отправленные раньше курсора, в хронологическом порядке
+	GetMessages(ctx context.Context, userID, peerID int, before *entity.Cursor, limit int) ([]entity.DirectMessage, error)
+	// MarkRead отмечает прочитанными сообщения от peerID к userID и возвращает их число
+	MarkRead(ctx context.Context, userID, peerID int) (int, error)
+}
+
+type directMessageUsecase struct {
+	repo   repository.DirectMessageRepository
+	users  UserDirectory
+	logger *zap.Logger
+}
+
+func NewDirectMessageUsecase(repo repository.DirectMessageRepository, users UserDirectory, logger *zap.Logger) DirectMessageUsecase {
+	return &directMessageUsecase{repo: repo, users: users, logger: logger}
+}
+
+func (u *directMessageUsecase) SendMessage(ctx context.Context, senderID, recipientID int, content, clientID string) (*entity.DirectMessage, bool, error) {
+	if recipientID < 1 || recipientID == senderID {
+		return nil, false, ErrInvalidRecipient
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, false, ErrEmptyDirectMessage
+	}
+	if utf8.RuneCountInString(content) > MaxChatMessageLength {
+		return nil, false, ErrDirectMessageTooLong
+	}
+	if len(clientID) > maxClientIDLength {
+		return nil, false, ErrInvalidClientID
+	}
+	// Сообщение несуществующему пользователю создало бы пустую переписку в списке отправителя
+	usernames, err := u.users.GetUsernames(ctx, []int{recipientID})
+	if err != nil {
+		u.logger.Error("Failed to look up direct message recipient", zap.Error(err), zap.Int("recipientID", recipientID))
+		return nil, false, err
+	}
+	if _, ok := usernames[recipientID]; !ok {
+		return nil, false, ErrInvalidRecipient
+	}
+
+	msg, created, err := u.repo.StoreMessage(ctx, entity.DirectMessage{
+		SenderID:    senderID,
+		RecipientID: recipientID,
+		Content:     content,
+		ClientID:    clientID,
+	})
+	if err != nil {
+		u.logger.Error("Failed to send direct message", zap.Error(err),
+			zap.Int("senderID", senderID), zap.Int("recipientID", recipientID))
+		return nil, false, err
+	}
+	u.logger.Info("Direct message stored successfully", zap.Int("messageID", msg.ID),
+		zap.Int("senderID", senderID), zap.Bool("created", created))
+	return msg, created, nil
+}
+
+func (u *directMessageUsecase) GetConversations(ctx context.Context, userID int) ([]entity.Conversation, error) {
+	return u.repo.GetConversations(ctx, userID)
+}
+
+func (u *directMessageUsecase) GetMessages(ctx context.Context, userID, peerID int, before *entity.Cursor, limit int) ([]entity.DirectMessage, error) {
+	if peerID < 1 || peerID == userID {
+		return nil, ErrInvalidRecipient
+	}
+	return u.repo.GetMessagesBefore(ctx, userID, peerID, before, limit)
+}
+
+func (u *directMessageUsecase) MarkRead(ctx context.Context, userID, peerID int) (int, error) {
+	if peerID < 1 || peerID == userID {
+		return 0, ErrInvalidRecipient
+	}
+	return u.repo.MarkRead(ctx, userID, peerID)
+}
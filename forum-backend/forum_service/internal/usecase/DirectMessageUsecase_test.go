@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestDirectMessageUsecase_SendMessage_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDMRepo := new(mocks.DirectMessageRepository)
+	mockUsers := new(mocks.UserService)
+
+	dmUsecase := NewDirectMessageUsecase(mockDMRepo, mockUsers, logger)
+
+	mockUsers.On("GetUsernames", mock.Anything, []int{2}).Return(map[int]string{2: "bob"}, nil)
+	stored := &entity.DirectMessage{ID: 4, SenderID: 1, RecipientID: 2, Content: "hello"}
+	mockDMRepo.On("StoreMessage", mock.Anything, entity.DirectMessage{SenderID: 1, RecipientID: 2, Content: "hello", ClientID: "c-1"}).Return(stored, true, nil)
+
+	msg, created, err := dmUsecase.SendMessage(context.Background(), 1, 2, "  hello ", "c-1")
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, stored, msg)
+	mockDMRepo.AssertExpectations(t)
+}
+
+func TestDirectMessageUsecase_SendMessage_Invalid(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDMRepo := new(mocks.DirectMessageRepository)
+	mockUsers := new(mocks.UserService)
+
+	dmUsecase := NewDirectMessageUsecase(mockDMRepo, mockUsers, logger)
+
+	_, _, err := dmUsecase.SendMessage(context.Background(), 1, 1, "hello", "")
+	assert.ErrorIs(t, err, ErrInvalidRecipient)
+
+	_, _, err = dmUsecase.SendMessage(context.Background(), 1, 0, "hello", "")
+	assert.ErrorIs(t, err, ErrInvalidRecipient)
+
+	_, _, err = dmUsecase.SendMessage(context.Background(), 1, 2, "   ", "")
+	assert.ErrorIs(t, err, ErrEmptyDirectMessage)
+
+	_, _, err = dmUsecase.SendMessage(context.Background(), 1, 2, strings.Repeat("x", MaxChatMessageLength+1), "")
+	assert.ErrorIs(t, err, ErrDirectMessageTooLong)
+
+	_, _, err = dmUsecase.SendMessage(context.Background(), 1, 2, "hello", strings.Repeat("x", maxClientIDLength+1))
+	assert.ErrorIs(t, err, ErrInvalidClientID)
+
+	mockDMRepo.AssertNotCalled(t, "StoreMessage", mock.Anything, mock.Anything)
+	mockUsers.AssertNotCalled(t, "GetUsernames", mock.Anything, mock.Anything)
+}
+
+func TestDirectMessageUsecase_SendMessage_UnknownRecipient(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockDMRepo := new(mocks.DirectMessageRepository)
+	mockUsers := new(mocks.UserService)
+
+	dmUsecase := NewDirectMessageUsecase(mockDMRepo, mockUsers, logger)
+
+	mockUsers.On("GetUsernames", mock.Anything, []int{42}).Return(map[int]string{}, nil).Once()
+	_, _, err := dmUsecase.SendMessage(context.Background(), 1, 42, "hello", "")
+	assert.ErrorIs(t, err, ErrInvalidRecipient)
+
+	lookupErr := errors.New("auth service unavailable")
+	mockUsers.On("GetUsernames", mock.Anything, []int{42}).Return(nil, lookupErr).Once()
+	_, _, err = dmUsecase.SendMessage(context.Background(), 1, 42, "hello", "")
+	assert.ErrorIs(t, err, lookupErr)
+
+	mockDMRepo.AssertNotCalled(t, "StoreMessage", mock.Anything, mock.Anything)
+	mockUsers.AssertExpectations(t)
+}
@@ -2,27 +2,59 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Engls/forum-project2/forum_service/internal/diff"
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/internal/repository"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrPostNotFound       = errors.New("post not found")
+	ErrRevisionNotFound   = errors.New("revision not found")
+	ErrInvalidTag         = fmt.Errorf("tag must be at most %d characters", MaxTagLength)
+	ErrTooManyTags        = fmt.Errorf("a post can have at most %d tags", MaxPostTags)
+	ErrInvalidSort        = errors.New("sort must be one of new, old, top, active, commented")
+	ErrCursorNotSupported = errors.New("cursor pagination is only available for sort=new and sort=old")
+)
+
+const (
+	MaxTagLength = 32
+	MaxPostTags  = 10
+)
+
 type PostUsecase interface {
 	CreatePost(ctx context.Context, post entity.Post) (*entity.Post, error)
 	GetPosts(ctx context.Context, limit, offset int) ([]entity.Post, error)
+	// ListPosts возвращает страницу ленты с фильтрами; пустая сортировка - новые первыми
+	ListPosts(ctx context.Context, filter entity.PostFilter, limit, offset int) ([]entity.Post, error)
+	CountPosts(ctx context.Context, filter entity.PostFilter) (int, error)
 	GetPostByID(ctx context.Context, id int) (*entity.Post, error)
-	UpdatePost(ctx context.Context, post entity.Post) (*entity.Post, error)
+	UpdatePost(ctx context.Context, post entity.Post, editorID int) (*entity.Post, error)
 	DeletePost(ctx context.Context, id int) error
 	GetTotalPostsCount(ctx context.Context) (int, error)
+	GetPostsByCategory(ctx context.Context, categoryID, limit, offset int) ([]entity.Post, error)
+	GetCategoryPostsCount(ctx context.Context, categoryID int) (int, error)
+	GetTags(ctx context.Context, limit int) ([]entity.TagCount, error)
+	GetPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error)
+	GetPostRevision(ctx context.Context, postID, revision int) (*entity.PostRevision, error)
+	// DiffPostRevisions сравнивает версии from и to; to = 0 означает последнюю версию
+	DiffPostRevisions(ctx context.Context, postID, from, to int) (*entity.RevisionDiff, error)
 }
 
 type postUsecase struct {
-	postRepo repository.PostRepository
-	logger   *zap.Logger
+	postRepo     repository.PostRepository
+	categoryRepo repository.CategoryRepository
+	logger       *zap.Logger
 }
 
-func NewPostUsecase(postRepo repository.PostRepository, logger *zap.Logger) PostUsecase {
-	return &postUsecase{postRepo: postRepo, logger: logger}
+func NewPostUsecase(postRepo repository.PostRepository, categoryRepo repository.CategoryRepository, logger *zap.Logger) PostUsecase {
+	return &postUsecase{postRepo: postRepo, categoryRepo: categoryRepo, logger: logger}
 }
 
 func (u *postUsecase) CreatePost(ctx context.Context, post entity.Post) (*entity.Post, error) {
@@ -32,6 +64,23 @@ func (u *postUsecase) CreatePost(ctx context.Context, post entity.Post) (*entity
 		zap.String("content", post.Content),
 	)
 
+	tags, err := NormalizeTags(post.Tags)
+	if err != nil {
+		return nil, err
+	}
+	post.Tags = tags
+
+	if post.CategoryId != nil {
+		_, err := u.categoryRepo.GetCategoryByID(ctx, *post.CategoryId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		if err != nil {
+			u.logger.Error("Failed to check post category", zap.Error(err), zap.Int("categoryID", *post.CategoryId))
+			return nil, err
+		}
+	}
+
 	createdPost, err := u.postRepo.CreatePost(ctx, post)
 	if err != nil {
 		u.logger.Error("Failed to create post", zap.Error(err))
@@ -46,6 +95,82 @@ func (u *postUsecase) GetPosts(ctx context.Context, limit, offset int) ([]entity
 	return u.postRepo.GetPosts(ctx, limit, offset)
 }
 
+func (u *postUsecase) ListPosts(ctx context.Context, filter entity.PostFilter, limit, offset int) ([]entity.Post, error) {
+	filter, err := normalizePostFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return u.postRepo.ListPosts(ctx, filter, limit, offset)
+}
+
+func (u *postUsecase) CountPosts(ctx context.Context, filter entity.PostFilter) (int, error) {
+	filter, err := normalizePostFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	return u.postRepo.CountPosts(ctx, filter)
+}
+
+// normalizePostFilter проверяет сортировку, даты и курсор и нормализует теги
+func normalizePostFilter(filter entity.PostFilter) (entity.PostFilter, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = entity.PostSortNew
+	case entity.PostSortNew, entity.PostSortOld, entity.PostSortTop, entity.PostSortActive, entity.PostSortCommented:
+	default:
+		return filter, ErrInvalidSort
+	}
+	if filter.After != nil && !filter.SortedByTime() {
+		return filter, ErrCursorNotSupported
+	}
+	if filter.Since != nil && filter.Until != nil && !filter.Since.Before(*filter.Until) {
+		return filter, ErrInvalidDateRange
+	}
+	tags, err := NormalizeTags(filter.Tags)
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = tags
+	return filter, nil
+}
+
+func (u *postUsecase) GetPostsByCategory(ctx context.Context, categoryID, limit, offset int) ([]entity.Post, error) {
+	return u.postRepo.GetPostsByCategory(ctx, categoryID, limit, offset)
+}
+
+func (u *postUsecase) GetCategoryPostsCount(ctx context.Context, categoryID int) (int, error) {
+	return u.postRepo.GetCategoryPostsCount(ctx, categoryID)
+}
+
+func (u *postUsecase) GetTags(ctx context.Context, limit int) ([]entity.TagCount, error) {
+	return u.postRepo.GetTags(ctx, limit)
+}
+
+// NormalizeTags приводит теги к нижнему регистру, обрезает пробелы по краям и заменяет
+// пробелы внутри на дефис. Пустые теги и повторы отбрасываются, порядок сохраняется
+func NormalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, ErrInvalidTag
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > MaxPostTags {
+		return nil, ErrTooManyTags
+	}
+	return result, nil
+}
+
 func (u *postUsecase) GetTotalPostsCount(ctx context.Context) (int, error) {
 	return u.postRepo.GetTotalPostsCount(ctx)
 }
@@ -54,6 +179,9 @@ func (u *postUsecase) GetPostByID(ctx context.Context, id int) (*entity.Post, er
 	u.logger.Info("Fetching post by ID", zap.Int("postID", id))
 
 	post, err := u.postRepo.GetPostByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
 	if err != nil {
 		u.logger.Error("Failed to get post by ID", zap.Error(err), zap.Int("postID", id))
 		return nil, err
@@ -63,14 +191,24 @@ func (u *postUsecase) GetPostByID(ctx context.Context, id int) (*entity.Post, er
 	return post, nil
 }
 
-func (u *postUsecase) UpdatePost(ctx context.Context, post entity.Post) (*entity.Post, error) {
+func (u *postUsecase) UpdatePost(ctx context.Context, post entity.Post, editorID int) (*entity.Post, error) {
 	u.logger.Info("Updating post",
 		zap.Int("postID", post.ID),
+		zap.Int("editorID", editorID),
 		zap.String("title", post.Title),
 		zap.String("content", post.Content),
 	)
 
-	updatedPost, err := u.postRepo.UpdatePost(ctx, post)
+	tags, err := NormalizeTags(post.Tags)
+	if err != nil {
+		return nil, err
+	}
+	post.Tags = tags
+
+	updatedPost, err := u.postRepo.UpdatePost(ctx, post, editorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
 	if err != nil {
 		u.logger.Error("Failed to update post", zap.Error(err), zap.Int("postID", post.ID))
 		return nil, err
@@ -92,3 +230,45 @@ func (u *postUsecase) DeletePost(ctx context.Context, id int) error {
 	u.logger.Info("Post deleted successfully", zap.Int("postID", id))
 	return nil
 }
+
+func (u *postUsecase) GetPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error) {
+	return u.postRepo.GetPostRevisions(ctx, postID)
+}
+
+func (u *postUsecase) GetPostRevision(ctx context.Context, postID, revision int) (*entity.PostRevision, error) {
+	rev, err := u.postRepo.GetPostRevision(ctx, postID, revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+func (u *postUsecase) DiffPostRevisions(ctx context.Context, postID, from, to int) (*entity.RevisionDiff, error) {
+	if to == 0 {
+		revisions, err := u.postRepo.GetPostRevisions(ctx, postID)
+		if err != nil {
+			return nil, err
+		}
+		if len(revisions) == 0 {
+			return nil, ErrRevisionNotFound
+		}
+		to = revisions[len(revisions)-1].Revision
+	}
+
+	fromRev, err := u.GetPostRevision(ctx, postID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := u.GetPostRevision(ctx, postID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.RevisionDiff{
+		PostId:  postID,
+		From:    fromRev.Revision,
+		To:      toRev.Revision,
+		Title:   diff.Lines(fromRev.Title, toRev.Title),
+		Content: diff.Lines(fromRev.Content, toRev.Content),
+	}, nil
+}
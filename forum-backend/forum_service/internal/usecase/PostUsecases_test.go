@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Engls/forum-project2/forum_service/internal/entity"
 	"github.com/Engls/forum-project2/forum_service/mocks"
@@ -18,7 +22,7 @@ func TestPostUsecase_CreatePost_Success(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	post := entity.Post{
 		AuthorId: 1,
@@ -44,7 +48,7 @@ func TestPostUsecase_CreatePost_Failure(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	post := entity.Post{
 		AuthorId: 1,
@@ -68,16 +72,16 @@ func TestPostUsecase_GetPosts_Success(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	posts := []entity.Post{
 		{ID: 1, AuthorId: 1, Title: "Post 1", Content: "Content 1"},
 		{ID: 2, AuthorId: 2, Title: "Post 2", Content: "Content 2"},
 	}
 
-	mockPostRepo.On("GetPosts", mock.Anything).Return(posts, nil)
+	mockPostRepo.On("GetPosts", mock.Anything, 10, 0).Return(posts, nil)
 
-	result, err := postUsecase.GetPosts(context.Background())
+	result, err := postUsecase.GetPosts(context.Background(), 10, 0)
 
 	assert.NoError(t, err)
 	assert.Equal(t, posts, result)
@@ -91,11 +95,11 @@ func TestPostUsecase_GetPosts_Failure(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
-	mockPostRepo.On("GetPosts", mock.Anything).Return(nil, errors.New("failed to get posts"))
+	mockPostRepo.On("GetPosts", mock.Anything, 10, 0).Return(nil, errors.New("failed to get posts"))
 
-	result, err := postUsecase.GetPosts(context.Background())
+	result, err := postUsecase.GetPosts(context.Background(), 10, 0)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -109,7 +113,7 @@ func TestPostUsecase_GetPostByID_Success(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	post := entity.Post{
 		ID:       1,
@@ -134,7 +138,7 @@ func TestPostUsecase_GetPostByID_Failure(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	mockPostRepo.On("GetPostByID", mock.Anything, 1).Return(nil, errors.New("failed to get post"))
 
@@ -152,7 +156,7 @@ func TestPostUsecase_UpdatePost_Success(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	post := entity.Post{
 		ID:       1,
@@ -161,9 +165,9 @@ func TestPostUsecase_UpdatePost_Success(t *testing.T) {
 		Content:  "This is an updated post",
 	}
 
-	mockPostRepo.On("UpdatePost", mock.Anything, post).Return(&post, nil)
+	mockPostRepo.On("UpdatePost", mock.Anything, post, 1).Return(&post, nil)
 
-	result, err := postUsecase.UpdatePost(context.Background(), post)
+	result, err := postUsecase.UpdatePost(context.Background(), post, 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, &post, result)
@@ -177,7 +181,7 @@ func TestPostUsecase_UpdatePost_Failure(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	post := entity.Post{
 		ID:       1,
@@ -186,9 +190,9 @@ func TestPostUsecase_UpdatePost_Failure(t *testing.T) {
 		Content:  "This is an updated post",
 	}
 
-	mockPostRepo.On("UpdatePost", mock.Anything, post).Return(nil, errors.New("failed to update post"))
+	mockPostRepo.On("UpdatePost", mock.Anything, post, 1).Return(nil, errors.New("failed to update post"))
 
-	result, err := postUsecase.UpdatePost(context.Background(), post)
+	result, err := postUsecase.UpdatePost(context.Background(), post, 1)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -202,7 +206,7 @@ func TestPostUsecase_DeletePost_Success(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	mockPostRepo.On("DeletePost", mock.Anything, 1).Return(nil)
 
@@ -219,7 +223,7 @@ func TestPostUsecase_DeletePost_Failure(t *testing.T) {
 
 	mockPostRepo := new(mocks.PostRepository)
 
-	postUsecase := NewPostUsecase(mockPostRepo, logger)
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
 
 	mockPostRepo.On("DeletePost", mock.Anything, 1).Return(errors.New("failed to delete post"))
 
@@ -229,3 +233,179 @@ func TestPostUsecase_DeletePost_Failure(t *testing.T) {
 
 	mockPostRepo.AssertExpectations(t)
 }
+
+func TestPostUsecase_UpdatePost_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostRepo := new(mocks.PostRepository)
+
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
+
+	post := entity.Post{ID: 42, Title: "Updated Post"}
+
+	mockPostRepo.On("UpdatePost", mock.Anything, post, 1).Return(nil, sql.ErrNoRows)
+
+	result, err := postUsecase.UpdatePost(context.Background(), post, 1)
+
+	assert.ErrorIs(t, err, ErrPostNotFound)
+	assert.Nil(t, result)
+
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostUsecase_DiffPostRevisions_Latest(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostRepo := new(mocks.PostRepository)
+
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
+
+	first := entity.PostRevision{PostId: 1, Revision: 1, Title: "Title", Content: "line one\nline two"}
+	second := entity.PostRevision{PostId: 1, Revision: 2, Title: "Title", Content: "line one\nline 2"}
+
+	mockPostRepo.On("GetPostRevisions", mock.Anything, 1).Return([]entity.PostRevision{first, second}, nil)
+	mockPostRepo.On("GetPostRevision", mock.Anything, 1, 1).Return(&first, nil)
+	mockPostRepo.On("GetPostRevision", mock.Anything, 1, 2).Return(&second, nil)
+
+	result, err := postUsecase.DiffPostRevisions(context.Background(), 1, 1, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.To)
+	assert.Equal(t, []entity.DiffLine{{Op: entity.DiffEqual, Text: "Title"}}, result.Title)
+	assert.Equal(t, []entity.DiffLine{
+		{Op: entity.DiffEqual, Text: "line one"},
+		{Op: entity.DiffDelete, Text: "line two"},
+		{Op: entity.DiffInsert, Text: "line 2"},
+	}, result.Content)
+
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostUsecase_GetPostRevision_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostRepo := new(mocks.PostRepository)
+
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
+
+	mockPostRepo.On("GetPostRevision", mock.Anything, 1, 5).Return(nil, sql.ErrNoRows)
+
+	result, err := postUsecase.GetPostRevision(context.Background(), 1, 5)
+
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+	assert.Nil(t, result)
+
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostUsecase_GetPostByID_NotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostRepo := new(mocks.PostRepository)
+
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
+
+	mockPostRepo.On("GetPostByID", mock.Anything, 99).Return(nil, sql.ErrNoRows)
+
+	result, err := postUsecase.GetPostByID(context.Background(), 99)
+
+	assert.ErrorIs(t, err, ErrPostNotFound)
+	assert.Nil(t, result)
+
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostUsecase_CreatePost_UnknownCategory(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostRepo := new(mocks.PostRepository)
+	mockCategoryRepo := new(mocks.CategoryRepository)
+
+	postUsecase := NewPostUsecase(mockPostRepo, mockCategoryRepo, logger)
+
+	categoryID := 7
+	mockCategoryRepo.On("GetCategoryByID", mock.Anything, categoryID).Return(nil, sql.ErrNoRows)
+
+	result, err := postUsecase.CreatePost(context.Background(), entity.Post{AuthorId: 1, Title: "Post", CategoryId: &categoryID})
+
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	assert.Nil(t, result)
+
+	mockCategoryRepo.AssertExpectations(t)
+	mockPostRepo.AssertNotCalled(t, "CreatePost", mock.Anything, mock.Anything)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Go ", "gRPC", "go", "", "Web  Sockets"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "grpc", "web-sockets"}, tags)
+
+	tags, err = NormalizeTags(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, tags)
+
+	_, err = NormalizeTags([]string{strings.Repeat("a", MaxTagLength+1)})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+
+	many := make([]string, MaxPostTags+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag%d", i)
+	}
+	_, err = NormalizeTags(many)
+	assert.ErrorIs(t, err, ErrTooManyTags)
+}
+
+func TestPostUsecase_ListPosts_NormalizesFilter(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostRepo := new(mocks.PostRepository)
+
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
+
+	posts := []entity.Post{{ID: 1, Title: "Post", Tags: []string{"go", "grpc"}}}
+	expected := entity.PostFilter{Sort: entity.PostSortNew, Tags: []string{"go", "grpc"}, MatchAllTags: true}
+	mockPostRepo.On("ListPosts", mock.Anything, expected, 10, 0).Return(posts, nil)
+
+	result, err := postUsecase.ListPosts(context.Background(), entity.PostFilter{Tags: []string{"GO", " grpc", "go"}, MatchAllTags: true}, 10, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, posts, result)
+
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostUsecase_ListPosts_InvalidFilter(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockPostRepo := new(mocks.PostRepository)
+
+	postUsecase := NewPostUsecase(mockPostRepo, new(mocks.CategoryRepository), logger)
+
+	since := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		filter entity.PostFilter
+		err    error
+	}{
+		{"unknown sort", entity.PostFilter{Sort: "random"}, ErrInvalidSort},
+		{"cursor with top", entity.PostFilter{Sort: entity.PostSortTop, After: &entity.Cursor{ID: 1}}, ErrCursorNotSupported},
+		{"reversed dates", entity.PostFilter{Since: &since, Until: &until}, ErrInvalidDateRange},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := postUsecase.ListPosts(context.Background(), tc.filter, 10, 0)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Nil(t, result)
+		})
+	}
+
+	mockPostRepo.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidEmoji = errors.New("emoji is not in the allowed reaction set")
+
+type ReactionUsecase interface {
+	// AddReaction ставит реакцию и возвращает реакции на цель после изменения
+	AddReaction(ctx context.Context, reaction entity.Reaction) (*entity.ReactionResult, error)
+	// RemoveReaction снимает реакцию и возвращает реакции на цель после изменения
+	RemoveReaction(ctx context.Context, reaction entity.Reaction) (*entity.ReactionResult, error)
+	// GetReactions возвращает реакции на цели targetIDs, userID = 0 для анонимного запроса
+	GetReactions(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int][]entity.ReactionCount, error)
+	// AllowedEmoji - эмодзи из конфига, которыми можно реагировать
+	AllowedEmoji() []string
+}
+
+type reactionUsecase struct {
+	repo    repository.ReactionRepository
+	emoji   []string
+	allowed map[string]string
+	logger  *zap.Logger
+}
+
+func NewReactionUsecase(repo repository.ReactionRepository, allowedEmoji []string, logger *zap.Logger) ReactionUsecase {
+	allowed := make(map[string]string, len(allowedEmoji))
+	for _, emoji := range allowedEmoji {
+		allowed[emojiKey(emoji)] = emoji
+	}
+	return &reactionUsecase{repo: repo, emoji: allowedEmoji, allowed: allowed, logger: logger}
+}
+
+// emojiKey сравнивает эмодзи без селектора варианта U+FE0F: клиенты
+// присылают "❤" и "❤️" для одной и той же реакции
+func emojiKey(emoji string) string {
+	return strings.ReplaceAll(strings.TrimSpace(emoji), "\uFE0F", "")
+}
+
+func (u *reactionUsecase) AddReaction(ctx context.Context, reaction entity.Reaction) (*entity.ReactionResult, error) {
+	return u.changeReaction(ctx, reaction, u.repo.AddReaction)
+}
+
+func (u *reactionUsecase) RemoveReaction(ctx context.Context, reaction entity.Reaction) (*entity.ReactionResult, error) {
+	return u.changeReaction(ctx, reaction, u.repo.RemoveReaction)
+}
+
+func (u *reactionUsecase) changeReaction(ctx context.Context, reaction entity.Reaction, change func(context.Context, entity.Reaction) error) (*entity.ReactionResult, error) {
+	emoji, ok := u.allowed[emojiKey(reaction.Emoji)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEmoji, strings.Join(u.emoji, " "))
+	}
+	reaction.Emoji = emoji
+
+	if err := change(ctx, reaction); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, reactionTargetNotFound(reaction.TargetType)
+		}
+		u.logger.Error("Failed to change reaction", zap.Error(err),
+			zap.String("targetType", reaction.TargetType), zap.Int("targetID", reaction.TargetID))
+		return nil, err
+	}
+
+	counts, err := u.repo.GetReactionCounts(ctx, reaction.UserID, reaction.TargetType, []int{reaction.TargetID})
+	if err != nil {
+		return nil, err
+	}
+	result := &entity.ReactionResult{
+		TargetType: reaction.TargetType,
+		TargetID:   reaction.TargetID,
+		Emoji:      reaction.Emoji,
+		Reactions:  counts[reaction.TargetID],
+	}
+	if result.Reactions == nil {
+		result.Reactions = []entity.ReactionCount{}
+	}
+	return result, nil
+}
+
+func reactionTargetNotFound(targetType string) error {
+	switch targetType {
+	case entity.ReactionTargetComment:
+		return ErrCommentNotFound
+	case entity.ReactionTargetChatMessage:
+		return ErrChatMessageNotFound
+	default:
+		return ErrPostNotFound
+	}
+}
+
+func (u *reactionUsecase) GetReactions(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int][]entity.ReactionCount, error) {
+	return u.repo.GetReactionCounts(ctx, userID, targetType, targetIDs)
+}
+
+func (u *reactionUsecase) AllowedEmoji() []string {
+	return u.emoji
+}
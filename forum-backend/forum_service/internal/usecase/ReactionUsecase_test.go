@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestReactionUsecase_AddReaction_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockReactionRepo := new(mocks.ReactionRepository)
+
+	reactionUsecase := NewReactionUsecase(mockReactionRepo, []string{"👍", "❤️"}, logger)
+
+	// "❤" без селектора варианта сохраняется в виде из конфига
+	expected := entity.Reaction{UserID: 2, TargetType: entity.ReactionTargetPost, TargetID: 1, Emoji: "❤️"}
+	counts := []entity.ReactionCount{{Emoji: "❤️", Count: 2, ReactedByMe: true}}
+	mockReactionRepo.On("AddReaction", mock.Anything, expected).Return(nil)
+	mockReactionRepo.On("GetReactionCounts", mock.Anything, 2, entity.ReactionTargetPost, []int{1}).
+		Return(map[int][]entity.ReactionCount{1: counts}, nil)
+
+	result, err := reactionUsecase.AddReaction(context.Background(),
+		entity.Reaction{UserID: 2, TargetType: entity.ReactionTargetPost, TargetID: 1, Emoji: "❤"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.ReactionResult{TargetType: entity.ReactionTargetPost, TargetID: 1, Emoji: "❤️", Reactions: counts}, result)
+
+	mockReactionRepo.AssertExpectations(t)
+}
+
+func TestReactionUsecase_AddReaction_InvalidEmoji(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockReactionRepo := new(mocks.ReactionRepository)
+
+	reactionUsecase := NewReactionUsecase(mockReactionRepo, []string{"👍"}, logger)
+
+	_, err := reactionUsecase.AddReaction(context.Background(),
+		entity.Reaction{UserID: 2, TargetType: entity.ReactionTargetPost, TargetID: 1, Emoji: "🦄"})
+
+	assert.ErrorIs(t, err, ErrInvalidEmoji)
+	mockReactionRepo.AssertNotCalled(t, "AddReaction", mock.Anything, mock.Anything)
+}
+
+func TestReactionUsecase_RemoveReaction_LastReaction(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockReactionRepo := new(mocks.ReactionRepository)
+
+	reactionUsecase := NewReactionUsecase(mockReactionRepo, []string{"👍"}, logger)
+
+	reaction := entity.Reaction{UserID: 2, TargetType: entity.ReactionTargetChatMessage, TargetID: 5, Emoji: "👍"}
+	mockReactionRepo.On("RemoveReaction", mock.Anything, reaction).Return(nil)
+	mockReactionRepo.On("GetReactionCounts", mock.Anything, 2, entity.ReactionTargetChatMessage, []int{5}).
+		Return(map[int][]entity.ReactionCount{}, nil)
+
+	result, err := reactionUsecase.RemoveReaction(context.Background(), reaction)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.ReactionCount{}, result.Reactions)
+
+	mockReactionRepo.AssertExpectations(t)
+}
+
+func TestReactionUsecase_AddReaction_TargetNotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockReactionRepo := new(mocks.ReactionRepository)
+
+	reactionUsecase := NewReactionUsecase(mockReactionRepo, []string{"👍"}, logger)
+
+	mockReactionRepo.On("AddReaction", mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+
+	_, err := reactionUsecase.AddReaction(context.Background(),
+		entity.Reaction{UserID: 2, TargetType: entity.ReactionTargetChatMessage, TargetID: 9, Emoji: "👍"})
+	assert.ErrorIs(t, err, ErrChatMessageNotFound)
+
+	_, err = reactionUsecase.AddReaction(context.Background(),
+		entity.Reaction{UserID: 2, TargetType: entity.ReactionTargetComment, TargetID: 9, Emoji: "👍"})
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"go.uber.org/zap"
+)
+
+const maxSearchQueryLength = 200
+
+// MaxSearchLimit - наибольший размер страницы поиска
+const MaxSearchLimit = 50
+
+var (
+	ErrEmptySearchQuery   = errors.New("search query is required")
+	ErrSearchQueryTooLong = errors.New("search query is too long")
+	ErrInvalidDateRange   = errors.New("since must be before until")
+)
+
+type SearchUsecase interface {
+	// Search ищет по постам и комментариям и возвращает страницу результатов вместе с общим числом
+	Search(ctx context.Context, filter entity.SearchFilter) ([]entity.SearchResult, int, error)
+}
+
+type searchUsecase struct {
+	searchRepo repository.SearchRepository
+	logger     *zap.Logger
+}
+
+func NewSearchUsecase(searchRepo repository.SearchRepository, logger *zap.Logger) SearchUsecase {
+	return &searchUsecase{searchRepo: searchRepo, logger: logger}
+}
+
+func (u *searchUsecase) Search(ctx context.Context, filter entity.SearchFilter) ([]entity.SearchResult, int, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query == "" {
+		return nil, 0, ErrEmptySearchQuery
+	}
+	if utf8.RuneCountInString(filter.Query) > maxSearchQueryLength {
+		return nil, 0, ErrSearchQueryTooLong
+	}
+	if filter.Since != nil && filter.Until != nil && !filter.Since.Before(*filter.Until) {
+		return nil, 0, ErrInvalidDateRange
+	}
+	if filter.Limit <= 0 || filter.Limit > MaxSearchLimit {
+		filter.Limit = MaxSearchLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	results, err := u.searchRepo.Search(ctx, filter)
+	if err != nil {
+		u.logger.Error("Failed to search", zap.Error(err), zap.String("query", filter.Query))
+		return nil, 0, err
+	}
+	total, err := u.searchRepo.CountResults(ctx, filter)
+	if err != nil {
+		u.logger.Error("Failed to count search results", zap.Error(err), zap.String("query", filter.Query))
+		return nil, 0, err
+	}
+	return results, total, nil
+}
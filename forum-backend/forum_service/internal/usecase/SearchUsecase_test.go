@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestSearchUsecase_Search_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockSearchRepo := new(mocks.SearchRepository)
+
+	searchUsecase := NewSearchUsecase(mockSearchRepo, logger)
+
+	expectedFilter := entity.SearchFilter{Query: "golang", Limit: MaxSearchLimit}
+	results := []entity.SearchResult{{Type: entity.SearchTypePost, PostId: 1, Title: "<mark>golang</mark>"}}
+
+	mockSearchRepo.On("Search", mock.Anything, expectedFilter).Return(results, nil)
+	mockSearchRepo.On("CountResults", mock.Anything, expectedFilter).Return(1, nil)
+
+	result, total, err := searchUsecase.Search(context.Background(), entity.SearchFilter{Query: "  golang ", Limit: 500, Offset: -5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, results, result)
+	assert.Equal(t, 1, total)
+
+	mockSearchRepo.AssertExpectations(t)
+}
+
+func TestSearchUsecase_Search_InvalidInput(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockSearchRepo := new(mocks.SearchRepository)
+
+	searchUsecase := NewSearchUsecase(mockSearchRepo, logger)
+
+	since := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, -1)
+
+	_, _, err := searchUsecase.Search(context.Background(), entity.SearchFilter{Query: "   "})
+	assert.ErrorIs(t, err, ErrEmptySearchQuery)
+
+	_, _, err = searchUsecase.Search(context.Background(), entity.SearchFilter{Query: string(make([]rune, maxSearchQueryLength+1))})
+	assert.ErrorIs(t, err, ErrSearchQueryTooLong)
+
+	_, _, err = searchUsecase.Search(context.Background(), entity.SearchFilter{Query: "go", Since: &since, Until: &until})
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	mockSearchRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+}
+
+func TestSearchUsecase_Search_Failure(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockSearchRepo := new(mocks.SearchRepository)
+
+	searchUsecase := NewSearchUsecase(mockSearchRepo, logger)
+
+	mockSearchRepo.On("Search", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	result, total, err := searchUsecase.Search(context.Background(), entity.SearchFilter{Query: "go", Limit: 10})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, 0, total)
+
+	mockSearchRepo.AssertExpectations(t)
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/internal/repository"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidVote = errors.New("vote value must be 1, -1 or 0")
+
+type VoteUsecase interface {
+	// Vote ставит голос vote.Value за цель, 0 снимает голос. Повторный такой же голос ничего не меняет
+	Vote(ctx context.Context, vote entity.Vote) (*entity.VoteResult, error)
+	// GetUserVotes возвращает голоса пользователя за цели targetIDs: id цели -> 1 или -1
+	GetUserVotes(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int]int, error)
+}
+
+type voteUsecase struct {
+	voteRepo repository.VoteRepository
+	logger   *zap.Logger
+}
+
+func NewVoteUsecase(voteRepo repository.VoteRepository, logger *zap.Logger) VoteUsecase {
+	return &voteUsecase{voteRepo: voteRepo, logger: logger}
+}
+
+func (u *voteUsecase) Vote(ctx context.Context, vote entity.Vote) (*entity.VoteResult, error) {
+	if vote.Value < -1 || vote.Value > 1 {
+		return nil, ErrInvalidVote
+	}
+
+	score, err := u.voteRepo.SetVote(ctx, vote)
+	if errors.Is(err, sql.ErrNoRows) {
+		if vote.TargetType == entity.VoteTargetComment {
+			return nil, ErrCommentNotFound
+		}
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		u.logger.Error("Failed to vote", zap.Error(err),
+			zap.String("targetType", vote.TargetType), zap.Int("targetID", vote.TargetID))
+		return nil, err
+	}
+
+	return &entity.VoteResult{
+		TargetType: vote.TargetType,
+		TargetID:   vote.TargetID,
+		Score:      score,
+		MyVote:     vote.Value,
+	}, nil
+}
+
+func (u *voteUsecase) GetUserVotes(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int]int, error) {
+	return u.voteRepo.GetUserVotes(ctx, userID, targetType, targetIDs)
+}
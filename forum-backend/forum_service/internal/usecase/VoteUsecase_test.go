@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Engls/forum-project2/forum_service/internal/entity"
+	"github.com/Engls/forum-project2/forum_service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestVoteUsecase_Vote_Success(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockVoteRepo := new(mocks.VoteRepository)
+
+	voteUsecase := NewVoteUsecase(mockVoteRepo, logger)
+
+	vote := entity.Vote{UserID: 2, TargetType: entity.VoteTargetPost, TargetID: 1, Value: -1}
+	mockVoteRepo.On("SetVote", mock.Anything, vote).Return(4, nil)
+
+	result, err := voteUsecase.Vote(context.Background(), vote)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.VoteResult{TargetType: entity.VoteTargetPost, TargetID: 1, Score: 4, MyVote: -1}, result)
+
+	mockVoteRepo.AssertExpectations(t)
+}
+
+func TestVoteUsecase_Vote_InvalidValue(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockVoteRepo := new(mocks.VoteRepository)
+
+	voteUsecase := NewVoteUsecase(mockVoteRepo, logger)
+
+	for _, value := range []int{2, -2, 10} {
+		_, err := voteUsecase.Vote(context.Background(), entity.Vote{UserID: 2, TargetType: entity.VoteTargetPost, TargetID: 1, Value: value})
+		assert.ErrorIs(t, err, ErrInvalidVote)
+	}
+
+	mockVoteRepo.AssertNotCalled(t, "SetVote", mock.Anything, mock.Anything)
+}
+
+func TestVoteUsecase_Vote_TargetNotFound(t *testing.T) {
+
+	logger, _ := zap.NewProduction()
+
+	mockVoteRepo := new(mocks.VoteRepository)
+
+	voteUsecase := NewVoteUsecase(mockVoteRepo, logger)
+
+	mockVoteRepo.On("SetVote", mock.Anything, mock.Anything).Return(0, sql.ErrNoRows)
+
+	_, err := voteUsecase.Vote(context.Background(), entity.Vote{UserID: 2, TargetType: entity.VoteTargetPost, TargetID: 9, Value: 1})
+	assert.ErrorIs(t, err, ErrPostNotFound)
+
+	_, err = voteUsecase.Vote(context.Background(), entity.Vote{UserID: 2, TargetType: entity.VoteTargetComment, TargetID: 9, Value: 1})
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+}
@@ -14,9 +14,69 @@ type ChatRepository struct {
 	mock.Mock
 }
 
-// GetRecentMessages provides a mock function with given fields: ctx, limit
-func (_m *ChatRepository) GetRecentMessages(ctx context.Context, limit int) ([]entity.ChatMessage, error) {
-	ret := _m.Called(ctx, limit)
+// GetMessageByID provides a mock function with given fields: ctx, id
+func (_m *ChatRepository) GetMessageByID(ctx context.Context, id int) (*entity.ChatMessage, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessageByID")
+	}
+
+	var r0 *entity.ChatMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.ChatMessage, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.ChatMessage); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ChatMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessagesBefore provides a mock function with given fields: ctx, roomID, before, limit
+func (_m *ChatRepository) GetMessagesBefore(ctx context.Context, roomID int, before *entity.Cursor, limit int) ([]entity.ChatMessage, error) {
+	ret := _m.Called(ctx, roomID, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessagesBefore")
+	}
+
+	var r0 []entity.ChatMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int) ([]entity.ChatMessage, error)); ok {
+		return rf(ctx, roomID, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int) []entity.ChatMessage); ok {
+		r0 = rf(ctx, roomID, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ChatMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *entity.Cursor, int) error); ok {
+		r1 = rf(ctx, roomID, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRecentMessages provides a mock function with given fields: ctx, roomID, limit
+func (_m *ChatRepository) GetRecentMessages(ctx context.Context, roomID int, limit int) ([]entity.ChatMessage, error) {
+	ret := _m.Called(ctx, roomID, limit)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetRecentMessages")
@@ -24,19 +84,19 @@ func (_m *ChatRepository) GetRecentMessages(ctx context.Context, limit int) ([]e
 
 	var r0 []entity.ChatMessage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.ChatMessage, error)); ok {
-		return rf(ctx, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]entity.ChatMessage, error)); ok {
+		return rf(ctx, roomID, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.ChatMessage); ok {
-		r0 = rf(ctx, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []entity.ChatMessage); ok {
+		r0 = rf(ctx, roomID, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.ChatMessage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, roomID, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -45,21 +105,40 @@ func (_m *ChatRepository) GetRecentMessages(ctx context.Context, limit int) ([]e
 }
 
 // StoreMessage provides a mock function with given fields: ctx, msg
-func (_m *ChatRepository) StoreMessage(ctx context.Context, msg entity.ChatMessage) error {
+func (_m *ChatRepository) StoreMessage(ctx context.Context, msg entity.ChatMessage) (*entity.ChatMessage, bool, error) {
 	ret := _m.Called(ctx, msg)
 
 	if len(ret) == 0 {
 		panic("no return value specified for StoreMessage")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, entity.ChatMessage) error); ok {
+	var r0 *entity.ChatMessage
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ChatMessage) (*entity.ChatMessage, bool, error)); ok {
+		return rf(ctx, msg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ChatMessage) *entity.ChatMessage); ok {
 		r0 = rf(ctx, msg)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ChatMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ChatMessage) bool); ok {
+		r1 = rf(ctx, msg)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.ChatMessage) error); ok {
+		r2 = rf(ctx, msg)
+	} else {
+		r2 = ret.Error(2)
 	}
 
-	return r0
+	return r0, r1, r2
 }
 
 // NewChatRepository creates a new instance of ChatRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -14,29 +14,59 @@ type ChatUsecase struct {
 	mock.Mock
 }
 
-// GetRecentMessages provides a mock function with given fields: ctx, limit
-func (_m *ChatUsecase) GetRecentMessages(ctx context.Context, limit int) ([]entity.ChatMessage, error) {
-	ret := _m.Called(ctx, limit)
+// GetHistory provides a mock function with given fields: ctx, roomID, before, limit
+func (_m *ChatUsecase) GetHistory(ctx context.Context, roomID int, before *entity.Cursor, limit int) ([]entity.ChatMessage, error) {
+	ret := _m.Called(ctx, roomID, before, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRecentMessages")
+		panic("no return value specified for GetHistory")
 	}
 
 	var r0 []entity.ChatMessage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.ChatMessage, error)); ok {
-		return rf(ctx, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int) ([]entity.ChatMessage, error)); ok {
+		return rf(ctx, roomID, before, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.ChatMessage); ok {
-		r0 = rf(ctx, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int) []entity.ChatMessage); ok {
+		r0 = rf(ctx, roomID, before, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.ChatMessage)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, int, *entity.Cursor, int) error); ok {
+		r1 = rf(ctx, roomID, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessage provides a mock function with given fields: ctx, id
+func (_m *ChatUsecase) GetMessage(ctx context.Context, id int) (*entity.ChatMessage, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessage")
+	}
+
+	var r0 *entity.ChatMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.ChatMessage, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.ChatMessage); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ChatMessage)
+		}
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, limit)
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -44,22 +74,71 @@ func (_m *ChatUsecase) GetRecentMessages(ctx context.Context, limit int) ([]enti
 	return r0, r1
 }
 
-// HandleMessage provides a mock function with given fields: ctx, userID, username, content
-func (_m *ChatUsecase) HandleMessage(ctx context.Context, userID int, username string, content string) error {
-	ret := _m.Called(ctx, userID, username, content)
+// GetRecentMessages provides a mock function with given fields: ctx, roomID, limit
+func (_m *ChatUsecase) GetRecentMessages(ctx context.Context, roomID int, limit int) ([]entity.ChatMessage, error) {
+	ret := _m.Called(ctx, roomID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentMessages")
+	}
+
+	var r0 []entity.ChatMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]entity.ChatMessage, error)); ok {
+		return rf(ctx, roomID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []entity.ChatMessage); ok {
+		r0 = rf(ctx, roomID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ChatMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, roomID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HandleMessage provides a mock function with given fields: ctx, roomID, userID, username, content, clientID
+func (_m *ChatUsecase) HandleMessage(ctx context.Context, roomID int, userID int, username string, content string, clientID string) (*entity.ChatMessage, bool, error) {
+	ret := _m.Called(ctx, roomID, userID, username, content, clientID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for HandleMessage")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
-		r0 = rf(ctx, userID, username, content)
+	var r0 *entity.ChatMessage
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string, string) (*entity.ChatMessage, bool, error)); ok {
+		return rf(ctx, roomID, userID, username, content, clientID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string, string) *entity.ChatMessage); ok {
+		r0 = rf(ctx, roomID, userID, username, content, clientID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ChatMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string, string, string) bool); ok {
+		r1 = rf(ctx, roomID, userID, username, content, clientID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, string, string, string) error); ok {
+		r2 = rf(ctx, roomID, userID, username, content, clientID)
 	} else {
-		r0 = ret.Error(0)
+		r2 = ret.Error(2)
 	}
 
-	return r0
+	return r0, r1, r2
 }
 
 // NewChatUsecase creates a new instance of ChatUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	entity "github.com/Engls/forum-project2/forum_service/internal/entity"
 	mock "github.com/stretchr/testify/mock"
@@ -42,27 +43,261 @@ func (_m *CommentsRepository) CreateComment(ctx context.Context, comment entity.
 	return r0, r1
 }
 
-// GetCommentsByPostID provides a mock function with given fields: ctx, postID
-func (_m *CommentsRepository) GetCommentsByPostID(ctx context.Context, postID int) ([]entity.Comment, error) {
-	ret := _m.Called(ctx, postID)
+// GetCommentByID provides a mock function with given fields: ctx, id
+func (_m *CommentsRepository) GetCommentByID(ctx context.Context, id int) (*entity.Comment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentByID")
+	}
+
+	var r0 *entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.Comment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.Comment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentTree provides a mock function with given fields: ctx, postID, limit, offset, depth
+func (_m *CommentsRepository) GetCommentTree(ctx context.Context, postID int, limit int, offset int, depth int) ([]*entity.CommentNode, error) {
+	ret := _m.Called(ctx, postID, limit, offset, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentTree")
+	}
+
+	var r0 []*entity.CommentNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) ([]*entity.CommentNode, error)); ok {
+		return rf(ctx, postID, limit, offset, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) []*entity.CommentNode); ok {
+		r0 = rf(ctx, postID, limit, offset, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CommentNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, int) error); ok {
+		r1 = rf(ctx, postID, limit, offset, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentTreeAfter provides a mock function with given fields: ctx, postID, after, limit, depth
+func (_m *CommentsRepository) GetCommentTreeAfter(ctx context.Context, postID int, after *entity.Cursor, limit int, depth int) ([]*entity.CommentNode, error) {
+	ret := _m.Called(ctx, postID, after, limit, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentTreeAfter")
+	}
+
+	var r0 []*entity.CommentNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int, int) ([]*entity.CommentNode, error)); ok {
+		return rf(ctx, postID, after, limit, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int, int) []*entity.CommentNode); ok {
+		r0 = rf(ctx, postID, after, limit, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CommentNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *entity.Cursor, int, int) error); ok {
+		r1 = rf(ctx, postID, after, limit, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComments provides a mock function with given fields: ctx, postID, limit, offset
+func (_m *CommentsRepository) GetComments(ctx context.Context, postID int, limit int, offset int) ([]entity.Comment, error) {
+	ret := _m.Called(ctx, postID, limit, offset)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCommentsByPostID")
+		panic("no return value specified for GetComments")
 	}
 
 	var r0 []entity.Comment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.Comment, error)); ok {
-		return rf(ctx, postID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]entity.Comment, error)); ok {
+		return rf(ctx, postID, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.Comment); ok {
-		r0 = rf(ctx, postID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []entity.Comment); ok {
+		r0 = rf(ctx, postID, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Comment)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, postID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentsAfter provides a mock function with given fields: ctx, postID, after, limit
+func (_m *CommentsRepository) GetCommentsAfter(ctx context.Context, postID int, after *entity.Cursor, limit int) ([]entity.Comment, error) {
+	ret := _m.Called(ctx, postID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentsAfter")
+	}
+
+	var r0 []entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int) ([]entity.Comment, error)); ok {
+		return rf(ctx, postID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int) []entity.Comment); ok {
+		r0 = rf(ctx, postID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *entity.Cursor, int) error); ok {
+		r1 = rf(ctx, postID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReplies provides a mock function with given fields: ctx, parentID, limit, offset, depth
+func (_m *CommentsRepository) GetReplies(ctx context.Context, parentID int, limit int, offset int, depth int) ([]*entity.CommentNode, error) {
+	ret := _m.Called(ctx, parentID, limit, offset, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReplies")
+	}
+
+	var r0 []*entity.CommentNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) ([]*entity.CommentNode, error)); ok {
+		return rf(ctx, parentID, limit, offset, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) []*entity.CommentNode); ok {
+		r0 = rf(ctx, parentID, limit, offset, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CommentNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, int) error); ok {
+		r1 = rf(ctx, parentID, limit, offset, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRepliesCount provides a mock function with given fields: ctx, parentID
+func (_m *CommentsRepository) GetRepliesCount(ctx context.Context, parentID int) (int, error) {
+	ret := _m.Called(ctx, parentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepliesCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, parentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, parentID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopLevelCommentsCount provides a mock function with given fields: ctx, postID
+func (_m *CommentsRepository) GetTopLevelCommentsCount(ctx context.Context, postID int) (int, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopLevelCommentsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTotalCommentsCount provides a mock function with given fields: ctx, postID
+func (_m *CommentsRepository) GetTotalCommentsCount(ctx context.Context, postID int) (int, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTotalCommentsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
 		r1 = rf(ctx, postID)
 	} else {
@@ -72,6 +307,42 @@ func (_m *CommentsRepository) GetCommentsByPostID(ctx context.Context, postID in
 	return r0, r1
 }
 
+// SoftDeleteComment provides a mock function with given fields: ctx, id, deletedAt
+func (_m *CommentsRepository) SoftDeleteComment(ctx context.Context, id int, deletedAt time.Time) error {
+	ret := _m.Called(ctx, id, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDeleteComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, id, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateComment provides a mock function with given fields: ctx, id, content, editedAt
+func (_m *CommentsRepository) UpdateComment(ctx context.Context, id int, content string, editedAt time.Time) error {
+	ret := _m.Called(ctx, id, content, editedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) error); ok {
+		r0 = rf(ctx, id, content, editedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewCommentsRepository creates a new instance of CommentsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCommentsRepository(t interface {
@@ -42,29 +42,311 @@ func (_m *CommentsUsecases) CreateComment(ctx context.Context, comment entity.Co
 	return r0, r1
 }
 
-// GetCommentByPostID provides a mock function with given fields: ctx, postId
-func (_m *CommentsUsecases) GetCommentByPostID(ctx context.Context, postId int) ([]entity.Comment, error) {
-	ret := _m.Called(ctx, postId)
+// DeleteComment provides a mock function with given fields: ctx, id
+func (_m *CommentsUsecases) DeleteComment(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCommentByPostID")
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCommentByID provides a mock function with given fields: ctx, id
+func (_m *CommentsUsecases) GetCommentByID(ctx context.Context, id int) (*entity.Comment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentByID")
+	}
+
+	var r0 *entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.Comment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.Comment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentTree provides a mock function with given fields: ctx, postID, limit, offset, depth
+func (_m *CommentsUsecases) GetCommentTree(ctx context.Context, postID int, limit int, offset int, depth int) ([]*entity.CommentNode, error) {
+	ret := _m.Called(ctx, postID, limit, offset, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentTree")
+	}
+
+	var r0 []*entity.CommentNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) ([]*entity.CommentNode, error)); ok {
+		return rf(ctx, postID, limit, offset, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) []*entity.CommentNode); ok {
+		r0 = rf(ctx, postID, limit, offset, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CommentNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, int) error); ok {
+		r1 = rf(ctx, postID, limit, offset, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentTreeAfter provides a mock function with given fields: ctx, postID, after, limit, depth
+func (_m *CommentsUsecases) GetCommentTreeAfter(ctx context.Context, postID int, after *entity.Cursor, limit int, depth int) ([]*entity.CommentNode, error) {
+	ret := _m.Called(ctx, postID, after, limit, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentTreeAfter")
+	}
+
+	var r0 []*entity.CommentNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int, int) ([]*entity.CommentNode, error)); ok {
+		return rf(ctx, postID, after, limit, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int, int) []*entity.CommentNode); ok {
+		r0 = rf(ctx, postID, after, limit, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CommentNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *entity.Cursor, int, int) error); ok {
+		r1 = rf(ctx, postID, after, limit, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComments provides a mock function with given fields: ctx, postID, limit, offset
+func (_m *CommentsUsecases) GetComments(ctx context.Context, postID int, limit int, offset int) ([]entity.Comment, error) {
+	ret := _m.Called(ctx, postID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetComments")
 	}
 
 	var r0 []entity.Comment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.Comment, error)); ok {
-		return rf(ctx, postId)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]entity.Comment, error)); ok {
+		return rf(ctx, postID, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.Comment); ok {
-		r0 = rf(ctx, postId)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []entity.Comment); ok {
+		r0 = rf(ctx, postID, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Comment)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, postID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentsAfter provides a mock function with given fields: ctx, postID, after, limit
+func (_m *CommentsUsecases) GetCommentsAfter(ctx context.Context, postID int, after *entity.Cursor, limit int) ([]entity.Comment, error) {
+	ret := _m.Called(ctx, postID, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentsAfter")
+	}
+
+	var r0 []entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int) ([]entity.Comment, error)); ok {
+		return rf(ctx, postID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.Cursor, int) []entity.Comment); ok {
+		r0 = rf(ctx, postID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *entity.Cursor, int) error); ok {
+		r1 = rf(ctx, postID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReplies provides a mock function with given fields: ctx, commentID, limit, offset, depth
+func (_m *CommentsUsecases) GetReplies(ctx context.Context, commentID int, limit int, offset int, depth int) ([]*entity.CommentNode, error) {
+	ret := _m.Called(ctx, commentID, limit, offset, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReplies")
+	}
+
+	var r0 []*entity.CommentNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) ([]*entity.CommentNode, error)); ok {
+		return rf(ctx, commentID, limit, offset, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) []*entity.CommentNode); ok {
+		r0 = rf(ctx, commentID, limit, offset, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CommentNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, int) error); ok {
+		r1 = rf(ctx, commentID, limit, offset, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRepliesCount provides a mock function with given fields: ctx, commentID
+func (_m *CommentsUsecases) GetRepliesCount(ctx context.Context, commentID int) (int, error) {
+	ret := _m.Called(ctx, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepliesCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, commentID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, postId)
+		r1 = rf(ctx, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopLevelCommentsCount provides a mock function with given fields: ctx, postID
+func (_m *CommentsUsecases) GetTopLevelCommentsCount(ctx context.Context, postID int) (int, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopLevelCommentsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTotalCommentsCount provides a mock function with given fields: ctx, postID
+func (_m *CommentsUsecases) GetTotalCommentsCount(ctx context.Context, postID int) (int, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTotalCommentsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateComment provides a mock function with given fields: ctx, id, content
+func (_m *CommentsUsecases) UpdateComment(ctx context.Context, id int, content string) (*entity.Comment, error) {
+	ret := _m.Called(ctx, id, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateComment")
+	}
+
+	var r0 *entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*entity.Comment, error)); ok {
+		return rf(ctx, id, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *entity.Comment); ok {
+		r0 = rf(ctx, id, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, content)
 	} else {
 		r1 = ret.Error(1)
 	}
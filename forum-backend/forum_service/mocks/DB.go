@@ -4,10 +4,9 @@ package mocks
 
 import (
 	context "context"
+	sql "database/sql"
 
 	mock "github.com/stretchr/testify/mock"
-
-	sql "database/sql"
 )
 
 // DB is an autogenerated mock type for the DB type
@@ -15,6 +14,36 @@ type DB struct {
 	mock.Mock
 }
 
+// BeginTx provides a mock function with given fields: ctx, opts
+func (_m *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginTx")
+	}
+
+	var r0 *sql.Tx
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.TxOptions) (*sql.Tx, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.TxOptions) *sql.Tx); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Tx)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.TxOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Exec provides a mock function with given fields: query, args
 func (_m *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
 	var _ca []interface{}
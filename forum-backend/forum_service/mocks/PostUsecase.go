@@ -14,6 +14,34 @@ type PostUsecase struct {
 	mock.Mock
 }
 
+// CountPosts provides a mock function with given fields: ctx, filter
+func (_m *PostUsecase) CountPosts(ctx context.Context, filter entity.PostFilter) (int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPosts")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PostFilter) (int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PostFilter) int); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.PostFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreatePost provides a mock function with given fields: ctx, post
 func (_m *PostUsecase) CreatePost(ctx context.Context, post entity.Post) (*entity.Post, error) {
 	ret := _m.Called(ctx, post)
@@ -62,6 +90,64 @@ func (_m *PostUsecase) DeletePost(ctx context.Context, id int) error {
 	return r0
 }
 
+// DiffPostRevisions provides a mock function with given fields: ctx, postID, from, to
+func (_m *PostUsecase) DiffPostRevisions(ctx context.Context, postID int, from int, to int) (*entity.RevisionDiff, error) {
+	ret := _m.Called(ctx, postID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffPostRevisions")
+	}
+
+	var r0 *entity.RevisionDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*entity.RevisionDiff, error)); ok {
+		return rf(ctx, postID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *entity.RevisionDiff); ok {
+		r0 = rf(ctx, postID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RevisionDiff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, postID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategoryPostsCount provides a mock function with given fields: ctx, categoryID
+func (_m *PostUsecase) GetCategoryPostsCount(ctx context.Context, categoryID int) (int, error) {
+	ret := _m.Called(ctx, categoryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategoryPostsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, categoryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, categoryID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, categoryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPostByID provides a mock function with given fields: ctx, id
 func (_m *PostUsecase) GetPostByID(ctx context.Context, id int) (*entity.Post, error) {
 	ret := _m.Called(ctx, id)
@@ -92,9 +178,69 @@ func (_m *PostUsecase) GetPostByID(ctx context.Context, id int) (*entity.Post, e
 	return r0, r1
 }
 
-// GetPosts provides a mock function with given fields: ctx
-func (_m *PostUsecase) GetPosts(ctx context.Context) ([]entity.Post, error) {
-	ret := _m.Called(ctx)
+// GetPostRevision provides a mock function with given fields: ctx, postID, revision
+func (_m *PostUsecase) GetPostRevision(ctx context.Context, postID int, revision int) (*entity.PostRevision, error) {
+	ret := _m.Called(ctx, postID, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostRevision")
+	}
+
+	var r0 *entity.PostRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*entity.PostRevision, error)); ok {
+		return rf(ctx, postID, revision)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *entity.PostRevision); ok {
+		r0 = rf(ctx, postID, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PostRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, postID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPostRevisions provides a mock function with given fields: ctx, postID
+func (_m *PostUsecase) GetPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostRevisions")
+	}
+
+	var r0 []entity.PostRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.PostRevision, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.PostRevision); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PostRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPosts provides a mock function with given fields: ctx, limit, offset
+func (_m *PostUsecase) GetPosts(ctx context.Context, limit int, offset int) ([]entity.Post, error) {
+	ret := _m.Called(ctx, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetPosts")
@@ -102,17 +248,105 @@ func (_m *PostUsecase) GetPosts(ctx context.Context) ([]entity.Post, error) {
 
 	var r0 []entity.Post
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Post, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]entity.Post, error)); ok {
+		return rf(ctx, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []entity.Post); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []entity.Post); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPostsByCategory provides a mock function with given fields: ctx, categoryID, limit, offset
+func (_m *PostUsecase) GetPostsByCategory(ctx context.Context, categoryID int, limit int, offset int) ([]entity.Post, error) {
+	ret := _m.Called(ctx, categoryID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostsByCategory")
+	}
+
+	var r0 []entity.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]entity.Post, error)); ok {
+		return rf(ctx, categoryID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []entity.Post); ok {
+		r0 = rf(ctx, categoryID, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Post)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, categoryID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTags provides a mock function with given fields: ctx, limit
+func (_m *PostUsecase) GetTags(ctx context.Context, limit int) ([]entity.TagCount, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTags")
+	}
+
+	var r0 []entity.TagCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.TagCount, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.TagCount); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.TagCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTotalPostsCount provides a mock function with given fields: ctx
+func (_m *PostUsecase) GetTotalPostsCount(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTotalPostsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
 		r1 = rf(ctx)
 	} else {
@@ -122,9 +356,39 @@ func (_m *PostUsecase) GetPosts(ctx context.Context) ([]entity.Post, error) {
 	return r0, r1
 }
 
-// UpdatePost provides a mock function with given fields: ctx, post
-func (_m *PostUsecase) UpdatePost(ctx context.Context, post entity.Post) (*entity.Post, error) {
-	ret := _m.Called(ctx, post)
+// ListPosts provides a mock function with given fields: ctx, filter, limit, offset
+func (_m *PostUsecase) ListPosts(ctx context.Context, filter entity.PostFilter, limit int, offset int) ([]entity.Post, error) {
+	ret := _m.Called(ctx, filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPosts")
+	}
+
+	var r0 []entity.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PostFilter, int, int) ([]entity.Post, error)); ok {
+		return rf(ctx, filter, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PostFilter, int, int) []entity.Post); ok {
+		r0 = rf(ctx, filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.PostFilter, int, int) error); ok {
+		r1 = rf(ctx, filter, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePost provides a mock function with given fields: ctx, post, editorID
+func (_m *PostUsecase) UpdatePost(ctx context.Context, post entity.Post, editorID int) (*entity.Post, error) {
+	ret := _m.Called(ctx, post, editorID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdatePost")
@@ -132,19 +396,19 @@ func (_m *PostUsecase) UpdatePost(ctx context.Context, post entity.Post) (*entit
 
 	var r0 *entity.Post
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, entity.Post) (*entity.Post, error)); ok {
-		return rf(ctx, post)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Post, int) (*entity.Post, error)); ok {
+		return rf(ctx, post, editorID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, entity.Post) *entity.Post); ok {
-		r0 = rf(ctx, post)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Post, int) *entity.Post); ok {
+		r0 = rf(ctx, post, editorID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Post)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, entity.Post) error); ok {
-		r1 = rf(ctx, post)
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Post, int) error); ok {
+		r1 = rf(ctx, post, editorID)
 	} else {
 		r1 = ret.Error(1)
 	}
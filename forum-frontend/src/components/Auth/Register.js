@@ -44,13 +44,17 @@ const Register = () => {
               { 
                 username: username,
                  password: password,
-                 role: "user",
                  });
             localStorage.setItem('token', response.data.token);
             navigate('/Login');
         } catch (error) {
             console.error('Registration failed:', error);
-            alert('Registration failed. Please try again.');
+            const fields = error.response?.data?.fields;
+            if (fields && fields.length > 0) {
+                alert(fields.map((f) => `${f.field}: ${f.message}`).join('\n'));
+            } else {
+                alert('Registration failed. Please try again.');
+            }
         }
     };
 
@@ -106,7 +106,6 @@ const StatusIndicator = styled.div.attrs({
     const { user, isAuthenticated } = useAuth();
     const ws = useRef(null);
     const messagesEndRef = useRef(null);
-    const lastMessageRef = useRef(null); // Для отслеживания последнего сообщения
   
     // Форматирование даты
     const formatDate = (timestamp) => {
@@ -122,7 +121,11 @@ const StatusIndicator = styled.div.attrs({
     // Подключение WebSocket
     useEffect(() => {
       const connectWebSocket = () => {
-        const wsUrl = `ws://localhost:8081/ws?userID=${user?.id || 0}&username=${encodeURIComponent(user?.username || 'Guest')}&auth=${isAuthenticated}`;
+        // Пользователя сервер определяет по токену, без токена чат открывается только для чтения
+        const token = isAuthenticated ? (user?.token || localStorage.getItem('token')) : null;
+        const wsUrl = token
+          ? `ws://localhost:8081/ws?token=${encodeURIComponent(token)}`
+          : 'ws://localhost:8081/ws';
         ws.current = new WebSocket(wsUrl);
   
         ws.current.onopen = () => {
@@ -141,31 +144,42 @@ const StatusIndicator = styled.div.attrs({
         ws.current.onmessage = (e) => {
           try {
             const data = typeof e.data === 'string' ? e.data : new TextDecoder().decode(e.data);
-            const message = JSON.parse(data);
-            
-            // Проверяем, не является ли это сообщение тем, которое мы только что отправили
-            if (lastMessageRef.current && 
-                lastMessageRef.current.content === message.content && 
-                lastMessageRef.current.userID === message.userID) {
-              lastMessageRef.current = null;
-              return;
+            const frame = JSON.parse(data);
+
+            switch (frame.type) {
+              case 'message':
+                setMessages(prev => {
+                  // Свое сообщение уже добавлено локально, заменяем его сохраненным
+                  const own = frame.client_id ? prev.findIndex(m => m.clientID === frame.client_id) : -1;
+                  const message = {
+                    id: frame.id,
+                    clientID: frame.client_id,
+                    userID: frame.userID || 0,
+                    username: frame.username || 'Unknown',
+                    content: frame.content || '',
+                    timestamp: frame.timestamp || new Date().toISOString()
+                  };
+                  if (own >= 0) {
+                    return prev.map((m, i) => (i === own ? message : m));
+                  }
+                  return prev.some(m => m.id === frame.id) ? prev : [...prev, message];
+                });
+                break;
+              case 'ack':
+                setMessages(prev => prev.map(m => (
+                  m.clientID === frame.client_id ? { ...m, id: frame.id, timestamp: frame.timestamp, pending: false } : m
+                )));
+                break;
+              case 'error':
+                console.error(`Chat error ${frame.code}: ${frame.error}`);
+                if (frame.client_id) {
+                  setMessages(prev => prev.filter(m => m.clientID !== frame.client_id));
+                }
+                break;
+              default:
+                // Остальные кадры (dm, typing, reaction, ...) этот компонент не показывает
+                break;
             }
-  
-            setMessages(prev => {
-              // Проверяем дубликаты по содержанию и времени
-              const isDuplicate = prev.some(
-                m => m.content === message.content && 
-                     Math.abs(new Date(m.timestamp) - new Date(message.timestamp)) < 1000
-              );
-              
-              return isDuplicate ? prev : [...prev, {
-                id: message.id || Date.now(),
-                userID: message.userID || 0,
-                username: message.username || 'Unknown',
-                content: message.content || '',
-                timestamp: message.timestamp || new Date().toISOString()
-              }];
-            });
           } catch (err) {
             console.error('Error processing message:', err);
           }
@@ -196,23 +210,22 @@ const StatusIndicator = styled.div.attrs({
       if (!newMessage.trim() || !isConnected || !isAuthenticated || !ws.current) return;
   
       try {
-        const message = {
+        // client_id связывает локальное сообщение с подтверждением сервера и защищает от повторной отправки
+        const clientID = window.crypto?.randomUUID ? window.crypto.randomUUID() : `${Date.now()}-${Math.random()}`;
+        const content = newMessage.trim();
+
+        // Добавляем сообщение локально сразу, id и время придут в подтверждении
+        setMessages(prev => [...prev, {
+          id: clientID,
+          clientID,
           userID: user.id,
           username: user.username,
-          content: newMessage.trim(),
-          timestamp: new Date().toISOString()
-        };
-        
-        // Сохраняем ссылку на последнее отправленное сообщение
-        lastMessageRef.current = message;
-        
-        // Добавляем сообщение локально сразу
-        setMessages(prev => [...prev, {
-          ...message,
-          id: Date.now()
+          content,
+          timestamp: new Date().toISOString(),
+          pending: true
         }]);
-        
-        ws.current.send(JSON.stringify(message));
+
+        ws.current.send(JSON.stringify({ v: 1, type: 'message', client_id: clientID, content }));
         setNewMessage('');
       } catch (err) {
         console.error('Error sending message:', err);